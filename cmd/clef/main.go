@@ -441,7 +441,12 @@ func signer(c *cli.Context) error {
 
 		// start http server
 		httpEndpoint := fmt.Sprintf("%s:%d", c.GlobalString(utils.RPCListenAddrFlag.Name), c.Int(rpcPortFlag.Name))
-		listener, _, err := rpc.StartHTTPEndpoint(httpEndpoint, rpcAPI, []string{"account"}, cors, vhosts, rpc.DefaultHTTPTimeouts)
+		listener, _, err := rpc.StartHTTPEndpoint(httpEndpoint, rpcAPI, rpc.HTTPEndpointConfig{
+			Modules:  []string{"account"},
+			Cors:     cors,
+			Vhosts:   vhosts,
+			Timeouts: rpc.DefaultHTTPTimeouts,
+		})
 		if err != nil {
 			utils.Fatalf("Could not start RPC api: %v", err)
 		}
@@ -0,0 +1,381 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of go-PaloAltoAi.
+//
+// go-PaloAltoAi is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-PaloAltoAi is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-PaloAltoAi. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/dnsdisc"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dnsCommand = cli.Command{
+		Name:  "dns",
+		Usage: "DNS Discovery Commands",
+		Subcommands: []cli.Command{
+			dnsSyncCommand,
+			dnsSignCommand,
+			dnsTXTCommand,
+		},
+	}
+	dnsSyncCommand = cli.Command{
+		Name:      "sync",
+		Usage:     "Download a DNS discovery tree",
+		ArgsUsage: "<url> [ <directory> ]",
+		Action:    dnsSync,
+		Flags:     []cli.Flag{dnsTimeoutFlag},
+	}
+	dnsSignCommand = cli.Command{
+		Name:      "sign",
+		Usage:     "Sign a DNS discovery tree",
+		ArgsUsage: "<tree-directory> <key-file>",
+		Action:    dnsSign,
+		Flags:     []cli.Flag{dnsDomainFlag, dnsSeqFlag},
+	}
+	dnsTXTCommand = cli.Command{
+		Name:      "to-txt",
+		Usage:     "Create a DNS TXT records for a discovery tree",
+		ArgsUsage: "<tree-directory> [ <output-file> ]",
+		Action:    dnsToTXT,
+	}
+)
+
+var (
+	dnsTimeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Timeout for DNS lookups",
+	}
+	dnsDomainFlag = cli.StringFlag{
+		Name:  "domain",
+		Usage: "Domain name of the tree",
+	}
+	dnsSeqFlag = cli.UintFlag{
+		Name:  "seq",
+		Usage: "New sequence number of the tree",
+	}
+)
+
+const (
+	treeInfoFile  = "enrtree-info.json"
+	treeNodesFile = "nodes.json"
+)
+
+// dnsSync performs dnsSyncCommand.
+func dnsSync(ctx *cli.Context) error {
+	var (
+		c      = dnsClient(ctx)
+		url    = ctx.Args().Get(0)
+		outdir = ctx.Args().Get(1)
+	)
+	domain, _, err := dnsdisc.ParseURL(url)
+	if err != nil {
+		return err
+	}
+	if outdir == "" {
+		outdir = domain
+	}
+
+	t, err := c.SyncTree(url)
+	if err != nil {
+		return err
+	}
+	def := treeToDefinition(url, t)
+	def.Meta.LastModified = time.Now()
+	writeTreeMetadata(outdir, def)
+	writeTreeNodes(outdir, def)
+	return nil
+}
+
+// dnsSign performs dnsSignCommand.
+func dnsSign(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("need tree definition directory and key file as arguments")
+	}
+	var (
+		defdir  = ctx.Args().Get(0)
+		keyfile = ctx.Args().Get(1)
+		def     = loadTreeDefinition(defdir)
+		domain  = directoryName(defdir)
+	)
+	if def.Meta.URL != "" {
+		d, _, err := dnsdisc.ParseURL(def.Meta.URL)
+		if err != nil {
+			return fmt.Errorf("invalid 'url' field: %v", err)
+		}
+		domain = d
+	}
+	if ctx.IsSet(dnsDomainFlag.Name) {
+		domain = ctx.String(dnsDomainFlag.Name)
+	}
+	if ctx.IsSet(dnsSeqFlag.Name) {
+		def.Meta.Seq = ctx.Uint(dnsSeqFlag.Name)
+	} else {
+		def.Meta.Seq++ // Auto-bump sequence number if not supplied via flag.
+	}
+	t, err := dnsdisc.MakeTree(def.Meta.Seq, def.Nodes, def.Meta.Links)
+	if err != nil {
+		return err
+	}
+
+	key, err := crypto.LoadECDSA(keyfile)
+	if err != nil {
+		return fmt.Errorf("can't load key: %v", err)
+	}
+	url, err := t.Sign(key, domain)
+	if err != nil {
+		return fmt.Errorf("can't sign: %v", err)
+	}
+
+	def = treeToDefinition(url, t)
+	def.Meta.LastModified = time.Now()
+	writeTreeMetadata(defdir, def)
+	return nil
+}
+
+// dnsToTXT performs dnsTXTCommand.
+func dnsToTXT(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree definition directory as argument")
+	}
+	output := ctx.Args().Get(1)
+	if output == "" {
+		output = "-" // default to stdout
+	}
+	domain, t, err := loadTreeDefinitionForExport(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	writeTXTJSON(output, t.ToTXT(domain))
+	return nil
+}
+
+func dnsClient(ctx *cli.Context) *dnsdisc.Client {
+	var cfg dnsdisc.Config
+	if ctx.IsSet(dnsTimeoutFlag.Name) {
+		cfg.Timeout = ctx.Duration(dnsTimeoutFlag.Name)
+	}
+	return dnsdisc.NewClient(cfg)
+}
+
+// Tree definition files.
+
+// dnsDefinition is the on-disk representation of a tree: enrtree-info.json
+// holds dnsMetaJSON and nodes.json holds the list of node records.
+type dnsDefinition struct {
+	Meta  dnsMetaJSON
+	Nodes []*enode.Node
+}
+
+type dnsMetaJSON struct {
+	URL          string    `json:"url,omitempty"`
+	Seq          uint      `json:"seq"`
+	Sig          string    `json:"signature,omitempty"`
+	Links        []string  `json:"links"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+func treeToDefinition(url string, t *dnsdisc.Tree) *dnsDefinition {
+	meta := dnsMetaJSON{
+		URL:   url,
+		Seq:   t.Seq(),
+		Sig:   t.Signature(),
+		Links: t.Links(),
+	}
+	if meta.Links == nil {
+		meta.Links = []string{}
+	}
+	return &dnsDefinition{Meta: meta, Nodes: t.Nodes()}
+}
+
+// loadTreeDefinition loads a directory in 'definition' format.
+func loadTreeDefinition(directory string) *dnsDefinition {
+	metaFile, nodesFile := treeDefinitionFiles(directory)
+	var def dnsDefinition
+	if err := readJSON(metaFile, &def.Meta); err != nil && !os.IsNotExist(err) {
+		exit(err)
+	}
+	if def.Meta.Links == nil {
+		def.Meta.Links = []string{}
+	}
+	def.Nodes = loadNodesJSON(nodesFile)
+	return &def
+}
+
+// loadTreeDefinitionForExport loads a DNS tree and ensures it is signed.
+func loadTreeDefinitionForExport(dir string) (domain string, t *dnsdisc.Tree, err error) {
+	metaFile, _ := treeDefinitionFiles(dir)
+	def := loadTreeDefinition(dir)
+	if def.Meta.URL == "" {
+		return "", nil, fmt.Errorf("missing 'url' field in %v", metaFile)
+	}
+	domain, pubkey, err := dnsdisc.ParseURL(def.Meta.URL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid 'url' field in %v: %v", metaFile, err)
+	}
+	if t, err = dnsdisc.MakeTree(def.Meta.Seq, def.Nodes, def.Meta.Links); err != nil {
+		return "", nil, err
+	}
+	if err := t.SetSignature(pubkey, def.Meta.Sig); err != nil {
+		return "", nil, fmt.Errorf("invalid signature in %v: %v (tree needs to be signed again)", metaFile, err)
+	}
+	return domain, t, nil
+}
+
+func writeTreeMetadata(directory string, def *dnsDefinition) {
+	metaJSON, err := json.MarshalIndent(&def.Meta, "", jsonIndent)
+	if err != nil {
+		exit(err)
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		exit(err)
+	}
+	metaFile, _ := treeDefinitionFiles(directory)
+	if err := ioutil.WriteFile(metaFile, metaJSON, 0644); err != nil {
+		exit(err)
+	}
+}
+
+func writeTreeNodes(directory string, def *dnsDefinition) {
+	_, nodesFile := treeDefinitionFiles(directory)
+	writeNodesJSON(nodesFile, def.Nodes)
+}
+
+func treeDefinitionFiles(directory string) (string, string) {
+	meta := filepath.Join(directory, treeInfoFile)
+	nodes := filepath.Join(directory, treeNodesFile)
+	return meta, nodes
+}
+
+// Node list files.
+
+// loadNodesJSON reads a list of node records from a JSON file. A missing
+// file yields an empty list.
+func loadNodesJSON(file string) []*enode.Node {
+	var enrs []string
+	if err := readJSON(file, &enrs); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		exit(err)
+	}
+	nodes := make([]*enode.Node, len(enrs))
+	for i, s := range enrs {
+		n, err := parseENR(s)
+		if err != nil {
+			exit(fmt.Errorf("%s: invalid record at index %d: %v", file, i, err))
+		}
+		nodes[i] = n
+	}
+	return nodes
+}
+
+func writeNodesJSON(file string, nodes []*enode.Node) {
+	enrs := make([]string, len(nodes))
+	for i, n := range nodes {
+		enrs[i] = encodeENR(n)
+	}
+	nodesJSON, err := json.MarshalIndent(enrs, "", jsonIndent)
+	if err != nil {
+		exit(err)
+	}
+	if err := ioutil.WriteFile(file, nodesJSON, 0644); err != nil {
+		exit(err)
+	}
+}
+
+// encodeENR returns the text form ("enr:<base64>") of a node record.
+func encodeENR(n *enode.Node) string {
+	enc, err := rlp.EncodeToBytes(n.Record())
+	if err != nil {
+		panic(err)
+	}
+	return "enr:" + base64.RawURLEncoding.EncodeToString(enc)
+}
+
+// parseENR decodes the text form of a node record.
+func parseENR(s string) (*enode.Node, error) {
+	if !strings.HasPrefix(s, "enr:") {
+		return nil, fmt.Errorf("missing 'enr:' prefix")
+	}
+	enc, err := base64.RawURLEncoding.DecodeString(s[4:])
+	if err != nil {
+		return nil, err
+	}
+	var r enr.Record
+	if err := rlp.DecodeBytes(enc, &r); err != nil {
+		return nil, err
+	}
+	return enode.New(enode.ValidSchemes, &r)
+}
+
+// Helpers.
+
+const jsonIndent = "    "
+
+func writeTXTJSON(file string, txt map[string]string) {
+	txtJSON, err := json.MarshalIndent(txt, "", jsonIndent)
+	if err != nil {
+		exit(err)
+	}
+	if file == "-" {
+		os.Stdout.Write(txtJSON)
+		fmt.Println()
+		return
+	}
+	if err := ioutil.WriteFile(file, txtJSON, 0644); err != nil {
+		exit(err)
+	}
+}
+
+func readJSON(file string, v interface{}) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+func directoryName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		exit(err)
+	}
+	return filepath.Base(abs)
+}
+
+func exit(err interface{}) {
+	if err == nil {
+		os.Exit(0)
+	}
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
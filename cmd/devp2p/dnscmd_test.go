@@ -0,0 +1,76 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of go-PaloAltoAi.
+//
+// go-PaloAltoAi is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-PaloAltoAi is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-PaloAltoAi. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/dnsdisc"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
+)
+
+// This test checks that a signed tree survives the round trip through the
+// definition directory and exports the same TXT records.
+func TestTreeDefinitionRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devp2p-dns-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var nodes []*enode.Node
+	for i := 0; i < 5; i++ {
+		var r enr.Record
+		r.Set(enr.IP(net.IP{127, 0, 0, byte(i)}))
+		key, _ := crypto.GenerateKey()
+		if err := enode.SignV4(&r, key); err != nil {
+			t.Fatal(err)
+		}
+		n, _ := enode.New(enode.ValidSchemes, &r)
+		nodes = append(nodes, n)
+	}
+	tree, err := dnsdisc.MakeTree(3, nodes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := crypto.GenerateKey()
+	url, err := tree.Sign(key, "nodes.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := treeToDefinition(url, tree)
+	writeTreeMetadata(dir, def)
+	writeTreeNodes(dir, def)
+
+	domain, loaded, err := loadTreeDefinitionForExport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain != "nodes.example.org" {
+		t.Errorf("wrong domain %q", domain)
+	}
+	if !reflect.DeepEqual(loaded.ToTXT(domain), tree.ToTXT(domain)) {
+		t.Error("exported TXT records differ from original tree")
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of go-PaloAltoAi.
+//
+// go-PaloAltoAi is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-PaloAltoAi is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-PaloAltoAi. If not, see <http://www.gnu.org/licenses/>.
+
+// devp2p is a utility for node operators working with the p2p layer.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Git SHA1 commit hash of the release (set via linker flags)
+var gitCommit = ""
+
+var app *cli.App
+
+func init() {
+	app = utils.NewApp(gitCommit, "go-PaloAltoAi devp2p tool")
+	app.Commands = []cli.Command{
+		dnsCommand,
+	}
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
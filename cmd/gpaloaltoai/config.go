@@ -180,6 +180,11 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	if cfg.Paastats.URL != "" {
 		utils.RegisterPaaStatsService(stack, cfg.Paastats.URL)
 	}
+	// Add the GraphQL endpoint if requested.
+	utils.MakeGraphQLService(ctx, stack)
+
+	// Add the health check endpoints if requested.
+	utils.MakeHealthService(ctx, stack)
 	return stack
 }
 
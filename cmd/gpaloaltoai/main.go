@@ -38,6 +38,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
 	"github.com/PaloAltoAi/go-PaloAltoAi/node"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
 	cli "gopkg.in/urfave/cli.v1"
 )
 
@@ -100,6 +101,7 @@ var (
 		utils.MinerThreadsFlag,
 		utils.MinerLegacyThreadsFlag,
 		utils.MinerNotifyFlag,
+		utils.MinerStratumFlag,
 		utils.MinerGasTargetFlag,
 		utils.MinerLegacyGasTargetFlag,
 		utils.MinerGasLimitFlag,
@@ -114,7 +116,11 @@ var (
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
+		utils.DiscoveryV51Flag,
+		utils.DNSDiscoveryFlag,
 		utils.NetrestrictFlag,
+		utils.NetBlacklistFlag,
+		utils.MaxPeersPerSubnetFlag,
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
 		utils.DeveloperFlag,
@@ -143,13 +149,33 @@ var (
 		utils.RPCListenAddrFlag,
 		utils.RPCPortFlag,
 		utils.RPCApiFlag,
+		utils.RPCPathPrefixFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
 		utils.WSPortFlag,
 		utils.WSApiFlag,
+		utils.WSPathPrefixFlag,
 		utils.WSAllowedOriginsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
+		utils.RPCJWTSecretFlag,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitBurstFlag,
+		utils.RPCRateLimitMethodsFlag,
+		utils.RPCRateLimitCostFlag,
+		utils.RPCBatchLimitFlag,
+		utils.RPCResponseLimitFlag,
+		utils.RPCGasCapFlag,
+		utils.RPCEVMTimeoutFlag,
+		utils.RPCLogsLimitFlag,
+		utils.RPCLogsRangeFlag,
+		utils.GraphQLEnabledFlag,
+		utils.GraphQLCORSDomainFlag,
+		utils.GraphQLVirtualHostsFlag,
+		utils.HealthEnabledFlag,
+		utils.HealthMinPeersFlag,
+		utils.HealthMaxBlockAgeFlag,
+		utils.HealthMaxSyncLagFlag,
 	}
 
 	whisperFlags = []cli.Flag{
@@ -166,6 +192,10 @@ var (
 		utils.MetricsInfluxDBUsernameFlag,
 		utils.MetricsInfluxDBPasswordFlag,
 		utils.MetricsInfluxDBTagsFlag,
+		utils.TracingEnabledFlag,
+		utils.TracingEndpointFlag,
+		utils.TracingServiceFlag,
+		utils.TracingSampleRateFlag,
 	}
 )
 
@@ -238,6 +268,9 @@ func init() {
 		// Start metrics export if enabled
 		utils.SetupMetrics(ctx)
 
+		// Start reporting traces if enabled
+		utils.SetupTracing(ctx)
+
 		// Start system runtime metrics collection
 		go metrics.CollectProcessMetrics(3 * time.Second)
 
@@ -246,6 +279,7 @@ func init() {
 
 	app.After = func(ctx *cli.Context) error {
 		debug.Exit()
+		tracing.Exit()
 		console.Stdin.Close() // Resets terminal mode.
 		return nil
 	}
@@ -153,15 +153,35 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCListenAddrFlag,
 			utils.RPCPortFlag,
 			utils.RPCApiFlag,
+			utils.RPCPathPrefixFlag,
 			utils.WSEnabledFlag,
 			utils.WSListenAddrFlag,
 			utils.WSPortFlag,
 			utils.WSApiFlag,
+			utils.WSPathPrefixFlag,
 			utils.WSAllowedOriginsFlag,
 			utils.IPCDisabledFlag,
 			utils.IPCPathFlag,
 			utils.RPCCORSDomainFlag,
 			utils.RPCVirtualHostsFlag,
+			utils.RPCJWTSecretFlag,
+			utils.RPCRateLimitFlag,
+			utils.RPCRateLimitBurstFlag,
+			utils.RPCRateLimitMethodsFlag,
+			utils.RPCRateLimitCostFlag,
+			utils.RPCBatchLimitFlag,
+			utils.RPCResponseLimitFlag,
+			utils.RPCGasCapFlag,
+			utils.RPCEVMTimeoutFlag,
+			utils.RPCLogsLimitFlag,
+			utils.RPCLogsRangeFlag,
+			utils.GraphQLEnabledFlag,
+			utils.GraphQLCORSDomainFlag,
+			utils.GraphQLVirtualHostsFlag,
+			utils.HealthEnabledFlag,
+			utils.HealthMinPeersFlag,
+			utils.HealthMaxBlockAgeFlag,
+			utils.HealthMaxSyncLagFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
@@ -179,7 +199,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.NATFlag,
 			utils.NoDiscoverFlag,
 			utils.DiscoveryV5Flag,
+			utils.DiscoveryV51Flag,
+			utils.DNSDiscoveryFlag,
 			utils.NetrestrictFlag,
+			utils.NetBlacklistFlag,
+			utils.MaxPeersPerSubnetFlag,
 			utils.NodeKeyFileFlag,
 			utils.NodeKeyHexFlag,
 		},
@@ -190,6 +214,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MiningEnabledFlag,
 			utils.MinerThreadsFlag,
 			utils.MinerNotifyFlag,
+			utils.MinerStratumFlag,
 			utils.MinerGasPriceFlag,
 			utils.MinerGasTargetFlag,
 			utils.MinerGasLimitFlag,
@@ -231,6 +256,10 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MetricsInfluxDBUsernameFlag,
 			utils.MetricsInfluxDBPasswordFlag,
 			utils.MetricsInfluxDBTagsFlag,
+			utils.TracingEnabledFlag,
+			utils.TracingEndpointFlag,
+			utils.TracingServiceFlag,
+			utils.TracingSampleRateFlag,
 		},
 	},
 	{
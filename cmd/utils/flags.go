@@ -19,6 +19,7 @@ package utils
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -34,12 +35,15 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/common/fdlimit"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/clique"
+	istanbulBackend "github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul/backend"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/paaash"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/vm"
 	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
 	"github.com/PaloAltoAi/go-PaloAltoAi/dashboard"
+	"github.com/PaloAltoAi/go-PaloAltoAi/graphql"
+	"github.com/PaloAltoAi/go-PaloAltoAi/health"
 	"github.com/PaloAltoAi/go-PaloAltoAi/paa"
 	"github.com/PaloAltoAi/go-PaloAltoAi/paa/downloader"
 	"github.com/PaloAltoAi/go-PaloAltoAi/paa/gasprice"
@@ -56,6 +60,8 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/nat"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/netutil"
 	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
 	whisper "github.com/PaloAltoAi/go-PaloAltoAi/whisper/whisperv6"
 	cli "gopkg.in/urfave/cli.v1"
 )
@@ -343,6 +349,10 @@ var (
 		Name:  "miner.notify",
 		Usage: "Comma separated HTTP URL list to notify of new work packages",
 	}
+	MinerStratumFlag = cli.StringFlag{
+		Name:  "miner.stratum",
+		Usage: "Listening address of the built-in stratum server for remote miners (e.g. 0.0.0.0:8008)",
+	}
 	MinerGasTargetFlag = cli.Uint64Flag{
 		Name:  "miner.gastarget",
 		Usage: "Target gas floor for mined blocks",
@@ -454,6 +464,62 @@ var (
 		Usage: "API's offered over the HTTP-RPC interface",
 		Value: "",
 	}
+	RPCPathPrefixFlag = cli.StringFlag{
+		Name:  "rpc.prefix",
+		Usage: "Path prefix the HTTP-RPC API is served at, leaving other paths to services like GraphQL",
+		Value: "",
+	}
+	RPCJWTSecretFlag = cli.StringFlag{
+		Name:  "rpc.jwtsecret",
+		Usage: "Path to a hex encoded 32 byte secret for authenticating HTTP and WS-RPC clients by JWT bearer tokens (generated if missing)",
+		Value: "",
+	}
+	RPCRateLimitFlag = cli.Float64Flag{
+		Name:  "rpc.ratelimit",
+		Usage: "Requests per second allowed per HTTP and WS-RPC client and method (0 = no default limit)",
+	}
+	RPCRateLimitBurstFlag = cli.IntFlag{
+		Name:  "rpc.ratelimit.burst",
+		Usage: "Maximum burst of requests allowed per HTTP and WS-RPC client and method",
+		Value: 100,
+	}
+	RPCRateLimitMethodsFlag = cli.StringFlag{
+		Name:  "rpc.ratelimit.methods",
+		Usage: "Comma separated per method or namespace limits overriding the default (<method>=<rate>[:<burst>])",
+		Value: "",
+	}
+	RPCRateLimitCostFlag = cli.DurationFlag{
+		Name:  "rpc.ratelimit.costunit",
+		Usage: "Execution time charged as one extra request against the client's limit (0 = disabled)",
+	}
+	RPCBatchLimitFlag = cli.IntFlag{
+		Name:  "rpc.batchlimit",
+		Usage: "Maximum number of requests in an HTTP or WS-RPC batch (0 = no limit)",
+		Value: node.DefaultConfig.RPCBatchLimit,
+	}
+	RPCResponseLimitFlag = cli.IntFlag{
+		Name:  "rpc.responselimit",
+		Usage: "Maximum size in bytes of an HTTP or WS-RPC (batch) response (0 = no limit)",
+		Value: node.DefaultConfig.RPCResponseSizeLimit,
+	}
+	RPCGasCapFlag = cli.Uint64Flag{
+		Name:  "rpc.gascap",
+		Usage: "Maximum gas of paa_call and paa_estimateGas executions (0 = no cap)",
+	}
+	RPCEVMTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc.evmtimeout",
+		Usage: "Maximum execution time of paa_call and paa_estimateGas (0 = no timeout)",
+		Value: paa.DefaultConfig.RPCEVMTimeout,
+	}
+	RPCLogsLimitFlag = cli.IntFlag{
+		Name:  "rpc.logslimit",
+		Usage: "Maximum number of logs returned by a log filter query (0 = no limit)",
+		Value: paa.DefaultConfig.RPCLogsLimit,
+	}
+	RPCLogsRangeFlag = cli.Uint64Flag{
+		Name:  "rpc.logsrange",
+		Usage: "Maximum number of blocks a log filter query may span (0 = no limit)",
+	}
 	IPCDisabledFlag = cli.BoolFlag{
 		Name:  "ipcdisable",
 		Usage: "Disable the IPC-RPC server",
@@ -486,6 +552,44 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	WSPathPrefixFlag = cli.StringFlag{
+		Name:  "ws.prefix",
+		Usage: "Path prefix the WS-RPC API is served at (shares the HTTP-RPC listener if on the same address and port)",
+		Value: "",
+	}
+	GraphQLEnabledFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable the GraphQL query endpoint at /graphql on the HTTP-RPC server (requires --rpc)",
+	}
+	GraphQLCORSDomainFlag = cli.StringFlag{
+		Name:  "graphql.corsdomain",
+		Usage: "Comma separated list of domains from which to accept cross origin GraphQL requests (browser enforced)",
+		Value: "",
+	}
+	GraphQLVirtualHostsFlag = cli.StringFlag{
+		Name:  "graphql.vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept GraphQL requests (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.HTTPVirtualHosts, ","),
+	}
+	HealthEnabledFlag = cli.BoolFlag{
+		Name:  "health",
+		Usage: "Enable the /health and /ready endpoints on the HTTP-RPC server (requires --rpc)",
+	}
+	HealthMinPeersFlag = cli.IntFlag{
+		Name:  "health.minpeers",
+		Usage: "Minimum number of peers for the node to be reported ready",
+		Value: health.DefaultConfig.MinPeers,
+	}
+	HealthMaxBlockAgeFlag = cli.DurationFlag{
+		Name:  "health.maxblockage",
+		Usage: "Maximum age of the head block for the node to be reported ready (0 = unchecked)",
+		Value: health.DefaultConfig.MaxBlockAge,
+	}
+	HealthMaxSyncLagFlag = cli.Uint64Flag{
+		Name:  "health.maxsynclag",
+		Usage: "Maximum number of blocks the node may trail the network by to be reported ready",
+		Value: health.DefaultConfig.MaxSyncLag,
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -547,10 +651,27 @@ var (
 		Name:  "v5disc",
 		Usage: "Enables the experimental RLPx V5 (Topic Discovery) mechanism",
 	}
+	DiscoveryV51Flag = cli.BoolFlag{
+		Name:  "v51disc",
+		Usage: "Enables the ENR-based discovery v5.1 protocol alongside v4 (--nodiscover disables only v4)",
+	}
+	DNSDiscoveryFlag = cli.StringFlag{
+		Name:  "discovery.dns",
+		Usage: "Comma separated enrtree:// URLs of DNS node lists used as dial candidates",
+	}
 	NetrestrictFlag = cli.StringFlag{
 		Name:  "netrestrict",
 		Usage: "Restricts network communication to the given IP networks (CIDR masks)",
 	}
+	NetBlacklistFlag = cli.StringFlag{
+		Name:  "netblacklist",
+		Usage: "Denies network communication with the given IP networks (CIDR masks)",
+	}
+	MaxPeersPerSubnetFlag = cli.IntFlag{
+		Name:  "maxpeerspersubnet",
+		Usage: "Maximum number of network peers from a single /24 subnet (no limit if set to 0)",
+		Value: node.DefaultConfig.P2P.MaxPeersPerSubnet,
+	}
 
 	// ATM the url is left to the user and deployment to
 	JSpathFlag = cli.StringFlag{
@@ -628,6 +749,27 @@ var (
 		Value: "host=localhost",
 	}
 
+	// Tracing flags
+	TracingEnabledFlag = cli.BoolFlag{
+		Name:  "tracing",
+		Usage: "Enable tracing of RPC requests down to state and database access",
+	}
+	TracingEndpointFlag = cli.StringFlag{
+		Name:  "tracing.endpoint",
+		Usage: "Jaeger agent address to report spans to",
+		Value: tracing.DefaultConfig.Endpoint,
+	}
+	TracingServiceFlag = cli.StringFlag{
+		Name:  "tracing.svc",
+		Usage: "Service name spans are reported under",
+		Value: tracing.DefaultConfig.ServiceName,
+	}
+	TracingSampleRateFlag = cli.Float64Flag{
+		Name:  "tracing.samplerate",
+		Usage: "Fraction of requests to trace (1 = all)",
+		Value: tracing.DefaultConfig.SampleRate,
+	}
+
 	EWASMInterpreterFlag = cli.StringFlag{
 		Name:  "vm.ewasm",
 		Usage: "External ewasm configuration (default = built-in interpreter)",
@@ -805,6 +947,9 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(RPCVirtualHostsFlag.Name) {
 		cfg.HTTPVirtualHosts = splitAndTrim(ctx.GlobalString(RPCVirtualHostsFlag.Name))
 	}
+	if ctx.GlobalIsSet(RPCPathPrefixFlag.Name) {
+		cfg.HTTPPathPrefix = ctx.GlobalString(RPCPathPrefixFlag.Name)
+	}
 }
 
 // setWS creates the WebSocket RPC listener interface string from the set
@@ -826,6 +971,52 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(WSApiFlag.Name) {
 		cfg.WSModules = splitAndTrim(ctx.GlobalString(WSApiFlag.Name))
 	}
+	if ctx.GlobalIsSet(WSPathPrefixFlag.Name) {
+		cfg.WSPathPrefix = ctx.GlobalString(WSPathPrefixFlag.Name)
+	}
+}
+
+// setRateLimit creates the per-client RPC rate limits from the set command line
+// flags, leaving rate limiting disabled if none were specified.
+func setRateLimit(ctx *cli.Context, cfg *node.Config) {
+	if !ctx.GlobalIsSet(RPCRateLimitFlag.Name) && !ctx.GlobalIsSet(RPCRateLimitMethodsFlag.Name) {
+		return
+	}
+	burst := ctx.GlobalInt(RPCRateLimitBurstFlag.Name)
+	if burst < 1 {
+		Fatalf("Invalid rate limit burst: %d", burst)
+	}
+	rate := ctx.GlobalFloat64(RPCRateLimitFlag.Name)
+	if rate < 0 {
+		Fatalf("Invalid rate limit: %v", rate)
+	}
+	limits := &rpc.RateLimitConfig{
+		Default:  rpc.RateLimit{Rate: rate, Burst: burst},
+		Methods:  make(map[string]rpc.RateLimit),
+		CostUnit: ctx.GlobalDuration(RPCRateLimitCostFlag.Name),
+	}
+	if ctx.GlobalIsSet(RPCRateLimitMethodsFlag.Name) {
+		for _, entry := range splitAndTrim(ctx.GlobalString(RPCRateLimitMethodsFlag.Name)) {
+			parts := strings.Split(entry, "=")
+			if len(parts) != 2 {
+				Fatalf("Invalid rate limit entry: %s", entry)
+			}
+			limit := rpc.RateLimit{Burst: burst}
+			spec := strings.Split(parts[1], ":")
+			rate, err := strconv.ParseFloat(spec[0], 64)
+			if err != nil || rate < 0 || len(spec) > 2 {
+				Fatalf("Invalid rate limit %s: %v", entry, err)
+			}
+			limit.Rate = rate
+			if len(spec) == 2 {
+				if limit.Burst, err = strconv.Atoi(spec[1]); err != nil || limit.Burst < 1 {
+					Fatalf("Invalid rate limit burst %s: %v", entry, err)
+				}
+			}
+			limits.Methods[parts[0]] = limit
+		}
+	}
+	cfg.RPCRateLimit = limits
 }
 
 // setIPC creates an IPC path configuration from the set command line flags,
@@ -967,6 +1158,12 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	} else if forceV5Discovery {
 		cfg.DiscoveryV5 = true
 	}
+	if ctx.GlobalIsSet(DiscoveryV51Flag.Name) {
+		cfg.DiscoveryV51 = ctx.GlobalBool(DiscoveryV51Flag.Name)
+	}
+	if ctx.GlobalIsSet(DNSDiscoveryFlag.Name) {
+		cfg.DNSDiscovery = splitAndTrim(ctx.GlobalString(DNSDiscoveryFlag.Name))
+	}
 
 	if netrestrict := ctx.GlobalString(NetrestrictFlag.Name); netrestrict != "" {
 		list, err := netutil.ParseNetlist(netrestrict)
@@ -975,6 +1172,16 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		}
 		cfg.NetRestrict = list
 	}
+	if netblacklist := ctx.GlobalString(NetBlacklistFlag.Name); netblacklist != "" {
+		list, err := netutil.ParseNetlist(netblacklist)
+		if err != nil {
+			Fatalf("Option %q: %v", NetBlacklistFlag.Name, err)
+		}
+		cfg.NetBlacklist = list
+	}
+	if ctx.GlobalIsSet(MaxPeersPerSubnetFlag.Name) {
+		cfg.MaxPeersPerSubnet = ctx.GlobalInt(MaxPeersPerSubnetFlag.Name)
+	}
 
 	if ctx.GlobalBool(DeveloperFlag.Name) {
 		// --dev mode can't use p2p networking.
@@ -982,6 +1189,8 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		cfg.ListenAddr = ":0"
 		cfg.NoDiscovery = true
 		cfg.DiscoveryV5 = false
+		cfg.DiscoveryV51 = false
+		cfg.DNSDiscovery = nil
 	}
 }
 
@@ -991,9 +1200,19 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setRateLimit(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 	setDataDir(ctx, cfg)
 
+	if ctx.GlobalIsSet(RPCJWTSecretFlag.Name) {
+		cfg.JWTSecret = ctx.GlobalString(RPCJWTSecretFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCBatchLimitFlag.Name) {
+		cfg.RPCBatchLimit = ctx.GlobalInt(RPCBatchLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCResponseLimitFlag.Name) {
+		cfg.RPCResponseSizeLimit = ctx.GlobalInt(RPCResponseLimitFlag.Name)
+	}
 	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.GlobalString(KeyStoreDirFlag.Name)
 	}
@@ -1091,6 +1310,9 @@ func setPaaash(ctx *cli.Context, cfg *paa.Config) {
 	if ctx.GlobalIsSet(PaaashDatasetsOnDiskFlag.Name) {
 		cfg.Paaash.DatasetsOnDisk = ctx.GlobalInt(PaaashDatasetsOnDiskFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerStratumFlag.Name) {
+		cfg.Paaash.StratumAddr = ctx.GlobalString(MinerStratumFlag.Name)
+	}
 }
 
 func setWhitelist(ctx *cli.Context, cfg *paa.Config) {
@@ -1257,6 +1479,19 @@ func SetPaaConfig(ctx *cli.Context, stack *node.Node, cfg *paa.Config) {
 		cfg.EVMInterpreter = ctx.GlobalString(EVMInterpreterFlag.Name)
 	}
 
+	if ctx.GlobalIsSet(RPCGasCapFlag.Name) {
+		cfg.RPCGasCap = ctx.GlobalUint64(RPCGasCapFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCEVMTimeoutFlag.Name) {
+		cfg.RPCEVMTimeout = ctx.GlobalDuration(RPCEVMTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCLogsLimitFlag.Name) {
+		cfg.RPCLogsLimit = ctx.GlobalInt(RPCLogsLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCLogsRangeFlag.Name) {
+		cfg.RPCLogsBlockRange = ctx.GlobalUint64(RPCLogsRangeFlag.Name)
+	}
+
 	// Override any default configs for hard coded networks.
 	switch {
 	case ctx.GlobalBool(TestnetFlag.Name):
@@ -1297,6 +1532,8 @@ func SetPaaConfig(ctx *cli.Context, stack *node.Node, cfg *paa.Config) {
 		log.Info("Using developer account", "address", developer.Address)
 
 		cfg.Genesis = core.DeveloperGenesisBlock(uint64(ctx.GlobalInt(DeveloperPeriodFlag.Name)), developer.Address)
+		// Blocks are mined on demand only if no fixed period was requested
+		cfg.DevMode = ctx.GlobalInt(DeveloperPeriodFlag.Name) == 0
 		if !ctx.GlobalIsSet(MinerGasPriceFlag.Name) && !ctx.GlobalIsSet(MinerLegacyGasPriceFlag.Name) {
 			cfg.MinerGasPrice = big.NewInt(1)
 		}
@@ -1369,6 +1606,78 @@ func RegisterPaaStatsService(stack *node.Node, url string) {
 	}
 }
 
+// RegisterGraphQLService is a utility function to construct a new service and
+// register it against the node, serving GraphQL queries on its HTTP-RPC server.
+func RegisterGraphQLService(stack *node.Node, cors, vhosts []string) {
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		// Try to construct the GraphQL service backed by a full node
+		var paaServ *paa.PaloAltoAi
+		if err := ctx.Service(&paaServ); err == nil {
+			return graphql.New(paaServ.APIBackend, cors, vhosts)
+		}
+		// Try to construct the GraphQL service backed by a light node
+		var lesServ *les.LightPaloAltoAi
+		if err := ctx.Service(&lesServ); err == nil {
+			return graphql.New(lesServ.ApiBackend, cors, vhosts)
+		}
+		// Well, this should not have happened, bail out
+		return nil, errors.New("no PaloAltoAi service")
+	}); err != nil {
+		Fatalf("Failed to register the GraphQL service: %v", err)
+	}
+}
+
+// MakeGraphQLService creates the GraphQL service if enabled on the command line,
+// registering it on the given node.
+func MakeGraphQLService(ctx *cli.Context, stack *node.Node) {
+	if !ctx.GlobalBool(GraphQLEnabledFlag.Name) {
+		return
+	}
+	if !ctx.GlobalBool(RPCEnabledFlag.Name) {
+		Fatalf("GraphQL requires the HTTP-RPC server to be enabled (--%s)", RPCEnabledFlag.Name)
+	}
+	cors := splitAndTrim(ctx.GlobalString(GraphQLCORSDomainFlag.Name))
+	vhosts := splitAndTrim(ctx.GlobalString(GraphQLVirtualHostsFlag.Name))
+	RegisterGraphQLService(stack, cors, vhosts)
+}
+
+// RegisterHealthService is a utility function to construct a new service and
+// register it against the node, serving health checks on its HTTP-RPC server.
+func RegisterHealthService(stack *node.Node, config health.Config) {
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		// Try to construct the health service backed by a full node
+		var paaServ *paa.PaloAltoAi
+		if err := ctx.Service(&paaServ); err == nil {
+			return health.New(paaServ.APIBackend, config), nil
+		}
+		// Try to construct the health service backed by a light node
+		var lesServ *les.LightPaloAltoAi
+		if err := ctx.Service(&lesServ); err == nil {
+			return health.New(lesServ.ApiBackend, config), nil
+		}
+		// Well, this should not have happened, bail out
+		return nil, errors.New("no PaloAltoAi service")
+	}); err != nil {
+		Fatalf("Failed to register the health service: %v", err)
+	}
+}
+
+// MakeHealthService creates the health service if enabled on the command line,
+// registering it on the given node.
+func MakeHealthService(ctx *cli.Context, stack *node.Node) {
+	if !ctx.GlobalBool(HealthEnabledFlag.Name) {
+		return
+	}
+	if !ctx.GlobalBool(RPCEnabledFlag.Name) {
+		Fatalf("Health checks require the HTTP-RPC server to be enabled (--%s)", RPCEnabledFlag.Name)
+	}
+	RegisterHealthService(stack, health.Config{
+		MinPeers:    ctx.GlobalInt(HealthMinPeersFlag.Name),
+		MaxBlockAge: ctx.GlobalDuration(HealthMaxBlockAgeFlag.Name),
+		MaxSyncLag:  ctx.GlobalUint64(HealthMaxSyncLagFlag.Name),
+	})
+}
+
 func SetupMetrics(ctx *cli.Context) {
 	if metrics.Enabled {
 		log.Info("Enabling metrics collection")
@@ -1390,6 +1699,22 @@ func SetupMetrics(ctx *cli.Context) {
 	}
 }
 
+// SetupTracing starts reporting spans to the configured Jaeger agent if tracing
+// is enabled on the command line.
+func SetupTracing(ctx *cli.Context) {
+	if !ctx.GlobalBool(TracingEnabledFlag.Name) {
+		return
+	}
+	config := tracing.Config{
+		Endpoint:    ctx.GlobalString(TracingEndpointFlag.Name),
+		ServiceName: ctx.GlobalString(TracingServiceFlag.Name),
+		SampleRate:  ctx.GlobalFloat64(TracingSampleRateFlag.Name),
+	}
+	if err := tracing.Setup(config); err != nil {
+		Fatalf("Failed to set up tracing: %v", err)
+	}
+}
+
 func SplitTagsFlag(tagsFlag string) map[string]string {
 	tags := strings.Split(tagsFlag, ",")
 	tagsMap := map[string]string{}
@@ -1450,10 +1775,18 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 	var engine consensus.Engine
 	if config.Clique != nil {
 		engine = clique.New(config.Clique, chainDb)
+	} else if config.Istanbul != nil {
+		// The chain is only verified, never sealed, so any key will do
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			Fatalf("%v", err)
+		}
+		engine = istanbulBackend.New(config.Istanbul, key, chainDb)
 	} else {
 		engine = paaash.NewFaker()
 		if !ctx.GlobalBool(FakePoWFlag.Name) {
-			engine = paaash.New(paaash.Config{
+			var err error
+			engine, err = paaash.New(paaash.Config{
 				CacheDir:       stack.ResolvePath(paa.DefaultConfig.Paaash.CacheDir),
 				CachesInMem:    paa.DefaultConfig.Paaash.CachesInMem,
 				CachesOnDisk:   paa.DefaultConfig.Paaash.CachesOnDisk,
@@ -1461,6 +1794,9 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 				DatasetsInMem:  paa.DefaultConfig.Paaash.DatasetsInMem,
 				DatasetsOnDisk: paa.DefaultConfig.Paaash.DatasetsOnDisk,
 			}, nil, false)
+			if err != nil {
+				Fatalf("Failed to create the consensus engine: %v", err)
+			}
 		}
 	}
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
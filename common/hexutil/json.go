@@ -67,6 +67,19 @@ func (b *Bytes) UnmarshalText(input []byte) error {
 	return err
 }
 
+// ImplementsGraphQLType returns true if Bytes implements the specified GraphQL type.
+func (b Bytes) ImplementsGraphQLType(name string) bool { return name == "Bytes" }
+
+// UnmarshalGraphQL unmarshals the provided GraphQL query data.
+func (b *Bytes) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		return b.UnmarshalText([]byte(input))
+	default:
+		return fmt.Errorf("unexpected type %T for Bytes", input)
+	}
+}
+
 // String returns the hex encoding of b.
 func (b Bytes) String() string {
 	return Encode(b)
@@ -177,6 +190,22 @@ func (b *Big) UnmarshalText(input []byte) error {
 	return nil
 }
 
+// ImplementsGraphQLType returns true if Big implements the specified GraphQL type.
+func (b Big) ImplementsGraphQLType(name string) bool { return name == "BigInt" }
+
+// UnmarshalGraphQL unmarshals the provided GraphQL query data.
+func (b *Big) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		return b.UnmarshalText([]byte(input))
+	case int32:
+		(*big.Int)(b).SetInt64(int64(input))
+		return nil
+	default:
+		return fmt.Errorf("unexpected type %T for BigInt", input)
+	}
+}
+
 // ToInt converts b to a big.Int.
 func (b *Big) ToInt() *big.Int {
 	return (*big.Int)(b)
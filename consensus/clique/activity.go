@@ -0,0 +1,233 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
+)
+
+// activityWindow is the number of most recent blocks the sealing activity of
+// the signers is tracked over.
+const activityWindow = 256
+
+var (
+	inturnBlocksGauge    = metrics.NewRegisteredGauge("clique/blocks/inturn", nil)
+	outOfTurnBlocksGauge = metrics.NewRegisteredGauge("clique/blocks/outofturn", nil)
+)
+
+// SignerStatus is the sealing activity of a single signer over the window.
+type SignerStatus struct {
+	InTurn    uint64 `json:"inTurn"`    // Number of blocks sealed in-turn
+	OutOfTurn uint64 `json:"outOfTurn"` // Number of blocks sealed out-of-turn
+	Missed    uint64 `json:"missed"`    // Number of in-turn slots sealed by another signer
+}
+
+// Status is the sealing activity of the signers over a window of recent blocks.
+type Status struct {
+	From          uint64                           `json:"from"`          // First block of the window
+	To            uint64                           `json:"to"`            // Last block of the window
+	InTurnPercent float64                          `json:"inTurnPercent"` // Share of blocks sealed in-turn
+	Signers       map[common.Address]*SignerStatus `json:"signers"`       // Activity of each signer seen in the window
+}
+
+// sealRecord is the sealing outcome of a single block.
+type sealRecord struct {
+	number uint64
+	hash   common.Hash    // Hash of the block, to detect records of side chains
+	signer common.Address // Signer that sealed the block
+	inturn common.Address // Signer whose turn it was
+}
+
+// signerActivity tracks who sealed the recent blocks in a sliding window,
+// indexed by block number so that reorged blocks replace their predecessors.
+// Snapshots are also computed for side chains, so the window may temporarily
+// hold non-canonical records until status replaces them.
+type signerActivity struct {
+	records [activityWindow]*sealRecord
+	head    uint64 // Highest block number recorded
+	gauges  map[common.Address]*signerGauges
+	lock    sync.RWMutex
+}
+
+// signerGauges are the metrics published for a single signer.
+type signerGauges struct {
+	inturn, outOfTurn, missed metrics.Gauge
+}
+
+func newSignerActivity() *signerActivity {
+	return &signerActivity{
+		gauges: make(map[common.Address]*signerGauges),
+	}
+}
+
+// record inserts the sealing outcomes of a batch of blocks into the window.
+func (a *signerActivity) record(records []*sealRecord) {
+	if len(records) == 0 {
+		return
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, rec := range records {
+		// Skip blocks that already slid out of the window
+		if rec.number+activityWindow <= a.head {
+			continue
+		}
+		if rec.number > a.head {
+			a.head = rec.number
+		}
+		a.records[rec.number%activityWindow] = rec
+	}
+	if metrics.Enabled {
+		a.publish(a.status(a.head, activityWindow))
+	}
+}
+
+// status aggregates the sealing activity of the given number of blocks up to
+// and including head. The caller must hold the lock.
+func (a *signerActivity) status(head uint64, window uint64) *Status {
+	if window > activityWindow {
+		window = activityWindow
+	}
+	status := &Status{
+		To:      head,
+		Signers: make(map[common.Address]*SignerStatus),
+	}
+	if head+1 >= window {
+		status.From = head + 1 - window
+	}
+	if status.From == 0 {
+		status.From = 1 // The genesis is not sealed
+	}
+	var blocks, inturn uint64
+	for number := status.From; number <= head; number++ {
+		rec := a.records[number%activityWindow]
+		if rec == nil || rec.number != number {
+			continue
+		}
+		signer := status.signer(rec.signer)
+		if rec.signer == rec.inturn {
+			signer.InTurn++
+			inturn++
+		} else {
+			signer.OutOfTurn++
+			status.signer(rec.inturn).Missed++
+		}
+		blocks++
+	}
+	if blocks > 0 {
+		status.InTurnPercent = float64(inturn) * 100 / float64(blocks)
+	}
+	return status
+}
+
+// has reports whether the sealing outcome of the given block is recorded.
+func (a *signerActivity) has(number uint64, hash common.Hash) bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	rec := a.records[number%activityWindow]
+	return rec != nil && rec.number == number && rec.hash == hash
+}
+
+// publish updates the metrics of the signers from an activity status. The
+// caller must hold the lock.
+func (a *signerActivity) publish(status *Status) {
+	var inturn, outOfTurn int64
+	for signer, gauges := range a.gauges {
+		// Reset the signers that slid out of the window
+		if _, ok := status.Signers[signer]; !ok {
+			gauges.inturn.Update(0)
+			gauges.outOfTurn.Update(0)
+			gauges.missed.Update(0)
+		}
+	}
+	for signer, stats := range status.Signers {
+		gauges, ok := a.gauges[signer]
+		if !ok {
+			prefix := fmt.Sprintf("clique/signers/%x/", signer)
+			gauges = &signerGauges{
+				inturn:    metrics.GetOrRegisterGauge(prefix+"inturn", nil),
+				outOfTurn: metrics.GetOrRegisterGauge(prefix+"outofturn", nil),
+				missed:    metrics.GetOrRegisterGauge(prefix+"missed", nil),
+			}
+			a.gauges[signer] = gauges
+		}
+		gauges.inturn.Update(int64(stats.InTurn))
+		gauges.outOfTurn.Update(int64(stats.OutOfTurn))
+		gauges.missed.Update(int64(stats.Missed))
+
+		inturn += int64(stats.InTurn)
+		outOfTurn += int64(stats.OutOfTurn)
+	}
+	inturnBlocksGauge.Update(inturn)
+	outOfTurnBlocksGauge.Update(outOfTurn)
+}
+
+// signer retrieves the activity entry of a signer, creating it if needed.
+func (s *Status) signer(addr common.Address) *SignerStatus {
+	stats, ok := s.Signers[addr]
+	if !ok {
+		stats = new(SignerStatus)
+		s.Signers[addr] = stats
+	}
+	return stats
+}
+
+// status reports the sealing activity of the signers over the window of blocks
+// ending at the given header, filling in any canonical blocks not tracked yet
+// (e.g. the ones sealed before a restart or replaced by a reorg).
+func (c *Clique) status(chain consensus.ChainReader, head *types.Header) (*Status, error) {
+	number := head.Number.Uint64()
+
+	from := uint64(1)
+	if number >= activityWindow {
+		from = number - activityWindow + 1
+	}
+	var records []*sealRecord
+	for n := from; n <= number; n++ {
+		header := chain.GetHeaderByNumber(n)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		hash := header.Hash()
+		if c.activity.has(n, hash) {
+			continue
+		}
+		snap, err := c.snapshot(chain, n-1, header.ParentHash, nil)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ecrecover(header, c.signatures)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &sealRecord{number: n, hash: hash, signer: signer, inturn: snap.inturnSigner(n)})
+	}
+	c.activity.record(records)
+
+	c.activity.lock.RLock()
+	defer c.activity.lock.RUnlock()
+
+	return c.activity.status(number, activityWindow), nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/vm"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+)
+
+// Tests that the in-turn, out-of-turn and missed slots of the signers are
+// tracked as blocks are imported, and recomputed from the chain if unknown.
+func TestSignerStatus(t *testing.T) {
+	accounts := newTesterAccountPool()
+
+	names := []string{"A", "B", "C"}
+	sort.Slice(names, func(i, j int) bool {
+		return bytes.Compare(accounts.address(names[i]).Bytes(), accounts.address(names[j]).Bytes()) < 0
+	})
+	signers := make([]common.Address, len(names))
+	for j, name := range names {
+		signers[j] = accounts.address(name)
+	}
+
+	genesis := &core.Genesis{
+		ExtraData: make([]byte, extraVanity+common.AddressLength*len(signers)+extraSeal),
+	}
+	for j, signer := range signers {
+		copy(genesis.ExtraData[extraVanity+j*common.AddressLength:], signer[:])
+	}
+	db := paadb.NewMemDatabase()
+	genesis.Commit(db)
+
+	config := *params.TestChainConfig
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+	engine := New(config.Clique, db)
+	engine.fakeDiff = true
+
+	// Seal the blocks by signer index: 1, 2 and 0 are in-turn, 1 again is in-turn
+	// in block 4, then 0 seals the slot of 2 and 1 the slot of 0
+	sealers := []int{1, 2, 0, 1, 0, 1}
+
+	blocks, _ := core.GenerateChain(&config, genesis.ToBlock(db), engine, db, len(sealers), nil)
+	for j, block := range blocks {
+		header := block.Header()
+		if j > 0 {
+			header.ParentHash = blocks[j-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+		header.Difficulty = diffInTurn
+
+		accounts.sign(header, names[sealers[j]])
+		blocks[j] = block.WithSeal(header)
+	}
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	want := map[common.Address]SignerStatus{
+		signers[0]: {InTurn: 1, OutOfTurn: 1, Missed: 1},
+		signers[1]: {InTurn: 2, OutOfTurn: 1, Missed: 0},
+		signers[2]: {InTurn: 1, OutOfTurn: 0, Missed: 1},
+	}
+	check := func(name string, engine *Clique) {
+		status, err := engine.status(chain, chain.CurrentHeader())
+		if err != nil {
+			t.Fatalf("%s: failed to retrieve status: %v", name, err)
+		}
+		if status.From != 1 || status.To != uint64(len(blocks)) {
+			t.Errorf("%s: window mismatch: have [%d, %d], want [1, %d]", name, status.From, status.To, len(blocks))
+		}
+		if status.InTurnPercent < 66 || status.InTurnPercent > 67 {
+			t.Errorf("%s: in-turn percent mismatch: have %f, want 66.6", name, status.InTurnPercent)
+		}
+		if len(status.Signers) != len(want) {
+			t.Errorf("%s: signer count mismatch: have %d, want %d", name, len(status.Signers), len(want))
+		}
+		for signer, stats := range want {
+			if have := status.Signers[signer]; have == nil || *have != stats {
+				t.Errorf("%s: signer %x status mismatch: have %+v, want %+v", name, signer, have, stats)
+			}
+		}
+	}
+	check("tracked", engine)
+	check("recomputed", New(config.Clique, db))
+
+	// Import a lighter side chain replacing blocks 5 and 6, which gets tracked
+	// while verified but must not leak into the status of the canonical chain
+	forks, _ := core.GenerateChain(&config, blocks[3], &fixedDiffEngine{engine}, db, 2, nil)
+	for j, block := range forks {
+		header := block.Header()
+		if j > 0 {
+			header.ParentHash = forks[j-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+		header.Difficulty = diffNoTurn
+
+		accounts.sign(header, names[[]int{2, 0}[j]])
+		forks[j] = block.WithSeal(header)
+	}
+	if _, err := chain.InsertChain(forks); err != nil {
+		t.Fatalf("failed to import side chain: %v", err)
+	}
+	if head := chain.CurrentBlock().Hash(); head != blocks[len(blocks)-1].Hash() {
+		t.Fatalf("side chain became canonical")
+	}
+	if !engine.activity.has(5, forks[0].Hash()) {
+		t.Fatalf("side chain block not tracked")
+	}
+	check("reorged", engine)
+}
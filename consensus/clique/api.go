@@ -102,11 +102,15 @@ func (api *API) Proposals() map[common.Address]bool {
 
 // Propose injects a new authorization proposal that the signer will attempt to
 // push through.
-func (api *API) Propose(address common.Address, auth bool) {
+func (api *API) Propose(address common.Address, auth bool) error {
+	if api.clique.config.SignerContract != nil {
+		return errVotingDisabled
+	}
 	api.clique.lock.Lock()
 	defer api.clique.lock.Unlock()
 
 	api.clique.proposals[address] = auth
+	return nil
 }
 
 // Discard drops a currently running proposal, stopping the signer from casting
@@ -117,3 +121,14 @@ func (api *API) Discard(address common.Address) {
 
 	delete(api.clique.proposals, address)
 }
+
+// Status returns the sealing activity of the signers over the recent blocks:
+// the blocks each signer sealed in-turn and out-of-turn, and the in-turn slots
+// each signer missed.
+func (api *API) Status() (*Status, error) {
+	header := api.chain.CurrentHeader()
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.clique.status(api.chain, header)
+}
@@ -206,6 +206,7 @@ type Clique struct {
 	signatures *lru.ARCCache // Signatures of recent blocks to speed up mining
 
 	proposals map[common.Address]bool // Current list of proposals we are pushing
+	activity  *signerActivity         // Sealing activity of the signers over recent blocks
 
 	signer common.Address // PaloAltoAi address of the signing key
 	signFn SignerFn       // Signer function to authorize hashes with
@@ -233,6 +234,7 @@ func New(config *params.CliqueConfig, db paadb.Database) *Clique {
 		recents:    recents,
 		signatures: signatures,
 		proposals:  make(map[common.Address]bool),
+		activity:   newSignerActivity(),
 	}
 }
 
@@ -294,6 +296,10 @@ func (c *Clique) verifyHeader(chain consensus.ChainReader, header *types.Header,
 	if checkpoint && !bytes.Equal(header.Nonce[:], nonceDropVote) {
 		return errInvalidCheckpointVote
 	}
+	// Contract governed signer sets don't permit header votes at all
+	if c.config.SignerContract != nil && (header.Coinbase != (common.Address{}) || !bytes.Equal(header.Nonce[:], nonceDropVote)) {
+		return errVotingDisabled
+	}
 	// Check that the extra-data contains both the vanity and signature
 	if len(header.Extra) < extraVanity {
 		return errMissingVanity
@@ -359,21 +365,35 @@ func (c *Clique) verifyCascadingFields(chain consensus.ChainReader, header *type
 	if err != nil {
 		return err
 	}
-	// If the block is a checkpoint block, verify the signer list
+	// If the block is a checkpoint block, verify the signer list. Contract governed
+	// lists need the parent state, see verifyContractCheckpoint for the caveats.
 	if number%c.config.Epoch == 0 {
-		signers := make([]byte, len(snap.Signers)*common.AddressLength)
-		for i, signer := range snap.signers() {
-			copy(signers[i*common.AddressLength:], signer[:])
-		}
-		extraSuffix := len(header.Extra) - extraSeal
-		if !bytes.Equal(header.Extra[extraVanity:extraSuffix], signers) {
-			return errMismatchingCheckpointSigners
+		if c.config.SignerContract != nil {
+			if err := c.verifyContractCheckpoint(chain, header, parent); err != nil {
+				return err
+			}
+		} else if err := verifyCheckpointSigners(header, snap.signers()); err != nil {
+			return err
 		}
 	}
 	// All basic checks passed, verify the seal and return
 	return c.verifySeal(chain, header, parents)
 }
 
+// verifyCheckpointSigners checks that the signer list embedded into a checkpoint
+// header matches the expected one.
+func verifyCheckpointSigners(header *types.Header, expect []common.Address) error {
+	signers := make([]byte, len(expect)*common.AddressLength)
+	for i, signer := range expect {
+		copy(signers[i*common.AddressLength:], signer[:])
+	}
+	extraSuffix := len(header.Extra) - extraSeal
+	if !bytes.Equal(header.Extra[extraVanity:extraSuffix], signers) {
+		return errMismatchingCheckpointSigners
+	}
+	return nil
+}
+
 // snapshot retrieves the authorization snapshot at a given point in time.
 func (c *Clique) snapshot(chain consensus.ChainReader, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
 	// Search for a snapshot in memory or on disk for checkpoints
@@ -392,6 +412,7 @@ func (c *Clique) snapshot(chain consensus.ChainReader, number uint64, hash commo
 			if s, err := loadSnapshot(c.config, c.signatures, c.db, hash); err == nil {
 				log.Trace("Loaded voting snapshot from disk", "number", number, "hash", hash)
 				snap = s
+				snap.activity = c.activity
 				break
 			}
 		}
@@ -401,11 +422,8 @@ func (c *Clique) snapshot(chain consensus.ChainReader, number uint64, hash commo
 			if checkpoint != nil {
 				hash := checkpoint.Hash()
 
-				signers := make([]common.Address, (len(checkpoint.Extra)-extraVanity-extraSeal)/common.AddressLength)
-				for i := 0; i < len(signers); i++ {
-					copy(signers[i][:], checkpoint.Extra[extraVanity+i*common.AddressLength:])
-				}
-				snap = newSnapshot(c.config, c.signatures, number, hash, signers)
+				snap = newSnapshot(c.config, c.signatures, number, hash, checkpointSigners(checkpoint))
+				snap.activity = c.activity
 				if err := snap.store(c.db); err != nil {
 					return nil, err
 				}
@@ -525,7 +543,7 @@ func (c *Clique) Prepare(chain consensus.ChainReader, header *types.Header) erro
 	if err != nil {
 		return err
 	}
-	if number%c.config.Epoch != 0 {
+	if number%c.config.Epoch != 0 && c.config.SignerContract == nil {
 		c.lock.RLock()
 
 		// Gather all the proposals that make sense voting on
@@ -555,8 +573,18 @@ func (c *Clique) Prepare(chain consensus.ChainReader, header *types.Header) erro
 	}
 	header.Extra = header.Extra[:extraVanity]
 
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
 	if number%c.config.Epoch == 0 {
-		for _, signer := range snap.signers() {
+		signers := snap.signers()
+		if c.config.SignerContract != nil {
+			if signers, err = c.governanceSigners(chain, parent); err != nil {
+				return err
+			}
+		}
+		for _, signer := range signers {
 			header.Extra = append(header.Extra, signer[:]...)
 		}
 	}
@@ -566,10 +594,6 @@ func (c *Clique) Prepare(chain consensus.ChainReader, header *types.Header) erro
 	header.MixDigest = common.Hash{}
 
 	// Ensure the timestamp has the correct delay
-	parent := chain.GetHeader(header.ParentHash, number-1)
-	if parent == nil {
-		return consensus.ErrUnknownAncestor
-	}
 	header.Time = new(big.Int).Add(parent.Time, new(big.Int).SetUint64(c.config.Period))
 	if header.Time.Int64() < time.Now().Unix() {
 		header.Time = big.NewInt(time.Now().Unix())
@@ -578,8 +602,26 @@ func (c *Clique) Prepare(chain consensus.ChainReader, header *types.Header) erro
 }
 
 // Finalize implements consensus.Engine, ensuring no uncles are set, nor block
-// rewards given, and returns the final block.
+// rewards given, and returns the final block. For contract governed signer sets
+// it also verifies the signer list announced by checkpoint blocks.
 func (c *Clique) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	// Ensure contract governed checkpoints announce the signers from the parent
+	// state (skipped for chains without state access, e.g. chain generators)
+	if number := header.Number.Uint64(); c.config.SignerContract != nil && number%c.config.Epoch == 0 {
+		if _, ok := chain.(stateReader); ok {
+			parent := chain.GetHeader(header.ParentHash, number-1)
+			if parent == nil {
+				return nil, consensus.ErrUnknownAncestor
+			}
+			signers, err := c.governanceSigners(chain, parent)
+			if err != nil {
+				return nil, err
+			}
+			if err := verifyCheckpointSigners(header, signers); err != nil {
+				return nil, err
+			}
+		}
+	}
 	// No block rewards in PoA, so the state remains as is and uncles are dropped
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 	header.UncleHash = types.CalcUncleHash(nil)
@@ -613,29 +655,15 @@ func (c *Clique) Seal(chain consensus.ChainReader, block *types.Block, results c
 		log.Info("Sealing paused, waiting for transactions")
 		return nil
 	}
-	// Don't hold the signer fields for the entire sealing procedure
-	c.lock.RLock()
-	signer, signFn := c.signer, c.signFn
-	c.lock.RUnlock()
-
 	// Bail out if we're unauthorized to sign a block
-	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	snap, err := c.sign(chain, header)
+	if err == errRecentlySigned {
+		log.Info("Signed recently, must wait for others")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	if _, authorized := snap.Signers[signer]; !authorized {
-		return errUnauthorizedSigner
-	}
-	// If we're amongst the recent signers, wait for the next block
-	for seen, recent := range snap.Recents {
-		if recent == signer {
-			// Signer is among recents, only wait if the current block doesn't shift it out
-			if limit := uint64(len(snap.Signers)/2 + 1); number < limit || seen > number-limit {
-				log.Info("Signed recently, must wait for others")
-				return nil
-			}
-		}
-	}
 	// Sweet, the protocol permits us to sign the block, wait for our time
 	delay := time.Unix(header.Time.Int64(), 0).Sub(time.Now()) // nolint: gosimple
 	if header.Difficulty.Cmp(diffNoTurn) == 0 {
@@ -645,12 +673,6 @@ func (c *Clique) Seal(chain consensus.ChainReader, block *types.Block, results c
 
 		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
 	}
-	// Sign all the things!
-	sighash, err := signFn(accounts.Account{Address: signer}, sigHash(header).Bytes())
-	if err != nil {
-		return err
-	}
-	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
 	// Wait until sealing is terminated or delay timeout.
 	log.Trace("Waiting for slot to sign and propagate", "delay", common.PrettyDuration(delay))
 	go func() {
@@ -670,6 +692,55 @@ func (c *Clique) Seal(chain consensus.ChainReader, block *types.Block, results c
 	return nil
 }
 
+// SealNow signs the block right away using the local signing credentials. Unlike
+// Seal it doesn't wait for the block's time slot and doesn't refuse empty blocks
+// on 0-period chains, so it's only suitable for developer chains mined on demand.
+func (c *Clique) SealNow(chain consensus.ChainReader, block *types.Block) (*types.Block, error) {
+	header := block.Header()
+	if header.Number.Uint64() == 0 {
+		return nil, errUnknownBlock
+	}
+	if _, err := c.sign(chain, header); err != nil {
+		return nil, err
+	}
+	return block.WithSeal(header), nil
+}
+
+// sign checks that the local signer is permitted to seal the header and inserts
+// its signature into the extra-data, returning the snapshot the signer was
+// authorized against.
+func (c *Clique) sign(chain consensus.ChainReader, header *types.Header) (*Snapshot, error) {
+	// Don't hold the signer fields for the entire sealing procedure
+	c.lock.RLock()
+	signer, signFn := c.signer, c.signFn
+	c.lock.RUnlock()
+
+	number := header.Number.Uint64()
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, authorized := snap.Signers[signer]; !authorized {
+		return nil, errUnauthorizedSigner
+	}
+	// If we're amongst the recent signers, wait for the next block
+	for seen, recent := range snap.Recents {
+		if recent == signer {
+			// Signer is among recents, only wait if the current block doesn't shift it out
+			if limit := uint64(len(snap.Signers)/2 + 1); number < limit || seen > number-limit {
+				return nil, errRecentlySigned
+			}
+		}
+	}
+	// Sign all the things!
+	sighash, err := signFn(accounts.Account{Address: signer}, sigHash(header).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
+	return snap, nil
+}
+
 // CalcDifficulty is the difficulty adjustment algorithm. It returns the difficulty
 // that a new block should have based on the previous blocks in the chain and the
 // current signer.
@@ -0,0 +1,143 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/trie"
+)
+
+// maxContractSigners is the maximum number of signers accepted from the signer
+// governance contract, capping the size of the checkpoint headers.
+const maxContractSigners = 1024
+
+var (
+	// errVotingDisabled is returned if a block casts a header vote on a chain
+	// where the signer set is managed by a governance contract.
+	errVotingDisabled = errors.New("header voting disabled by signer contract")
+
+	// errNoContractSigners is returned if the signer governance contract does not
+	// contain any authorized signers, which would halt the chain.
+	errNoContractSigners = errors.New("no signers in signer contract")
+
+	// errTooManyContractSigners is returned if the signer governance contract
+	// lists more signers than permitted in a checkpoint header.
+	errTooManyContractSigners = errors.New("too many signers in signer contract")
+
+	// errStateUnavailable is returned if the signers need to be read from the
+	// governance contract, but the chain doesn't provide access to the state or
+	// doesn't hold it (yet).
+	errStateUnavailable = errors.New("state unavailable to read signer contract")
+)
+
+// signersSlot is the storage slot of the dynamic signer array in the governance
+// contract (contracts/clique/contract/SignerRegistry.sol). The array length is
+// stored in the slot itself, the elements sequentially from keccak256(slot).
+var signersSlot = common.Hash{}
+
+// stateReader is implemented by chains that can open historical states, which
+// is required to read the signer list from the governance contract.
+type stateReader interface {
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// contractSigners reads the list of authorized signers from the storage of the
+// governance contract, returning them in ascending order.
+func contractSigners(statedb *state.StateDB, contract common.Address) ([]common.Address, error) {
+	length := statedb.GetState(contract, signersSlot).Big()
+	if length.Sign() == 0 {
+		return nil, errNoContractSigners
+	}
+	if length.Cmp(big.NewInt(maxContractSigners)) > 0 {
+		return nil, errTooManyContractSigners
+	}
+	var (
+		base    = new(big.Int).SetBytes(crypto.Keccak256(signersSlot[:]))
+		unique  = make(map[common.Address]struct{})
+		signers = make([]common.Address, 0, length.Uint64())
+	)
+	for i := uint64(0); i < length.Uint64(); i++ {
+		slot := common.BigToHash(new(big.Int).Add(base, new(big.Int).SetUint64(i)))
+		signer := common.BytesToAddress(statedb.GetState(contract, slot).Bytes())
+
+		if _, ok := unique[signer]; ok || signer == (common.Address{}) {
+			continue
+		}
+		unique[signer] = struct{}{}
+		signers = append(signers, signer)
+	}
+	sort.Sort(signersAscending(signers))
+	return signers, nil
+}
+
+// checkpointSigners extracts the list of signers embedded into the extra-data
+// section of a checkpoint header.
+func checkpointSigners(header *types.Header) []common.Address {
+	signers := make([]common.Address, (len(header.Extra)-extraVanity-extraSeal)/common.AddressLength)
+	for i := 0; i < len(signers); i++ {
+		copy(signers[i][:], header.Extra[extraVanity+i*common.AddressLength:])
+	}
+	return signers
+}
+
+// governanceSigners reads the authorized signers from the governance contract
+// as of the state of the given header.
+func (c *Clique) governanceSigners(chain consensus.ChainReader, header *types.Header) ([]common.Address, error) {
+	reader, ok := chain.(stateReader)
+	if !ok {
+		return nil, errStateUnavailable
+	}
+	statedb, err := reader.StateAt(header.Root)
+	if _, missing := err.(*trie.MissingNodeError); missing {
+		return nil, errStateUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	return contractSigners(statedb, *c.config.SignerContract)
+}
+
+// verifyContractCheckpoint checks the signer list announced by a checkpoint
+// header of a contract governed chain against the governance contract in the
+// parent state.
+//
+// The contract can only be read if the parent state is available. If it isn't,
+// e.g. for a batch of headers verified ahead of block processing, the check is
+// deferred to Finalize. Chains that never hold the state, i.e. light clients and
+// fast sync, can't verify the signer list at all and would have to trust every
+// checkpoint, which is why both sync modes are disabled for such networks.
+func (c *Clique) verifyContractCheckpoint(chain consensus.ChainReader, header, parent *types.Header) error {
+	if len(checkpointSigners(header)) == 0 {
+		return errNoContractSigners
+	}
+	signers, err := c.governanceSigners(chain, parent)
+	switch {
+	case err == errStateUnavailable:
+		return nil
+	case err != nil:
+		return err
+	}
+	return verifyCheckpointSigners(header, signers)
+}
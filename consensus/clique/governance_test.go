@@ -0,0 +1,269 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/vm"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+)
+
+// governanceStoreCode is a minimal stand-in for the signer governance contract,
+// storing the second 32 byte calldata word into the slot given by the first:
+//
+//	PUSH1 0x20 CALLDATALOAD PUSH1 0x00 CALLDATALOAD SSTORE STOP
+var governanceStoreCode = common.FromHex("0x6020356000355500")
+
+// fixedDiffEngine is a clique engine reporting a constant difficulty, needed as
+// the chain generator can't provide the headers to evaluate signer snapshots,
+// but the transactions it executes require a difficulty to be set.
+type fixedDiffEngine struct {
+	*Clique
+}
+
+func (e *fixedDiffEngine) CalcDifficulty(chain consensus.ChainReader, time uint64, parent *types.Header) *big.Int {
+	return new(big.Int).Set(diffInTurn)
+}
+
+// Tests that signer sets managed by a governance contract are adopted at epoch
+// checkpoints and that checkpoints and votes deviating from the contract are
+// rejected.
+func TestGovernanceSigners(t *testing.T) {
+	tests := []struct {
+		checkpoint []string // Signers announced in the checkpoint block
+		voted      string   // Account voted on in the second block
+		signers    []string // Signers of the blocks after the genesis
+		failure    error
+		results    []string
+	}{
+		{
+			// Contract extended with B, checkpoint announces it, B may sign afterwards
+			checkpoint: []string{"A", "B"},
+			signers:    []string{"A", "A", "A", "B"},
+			results:    []string{"A", "B"},
+		}, {
+			// Checkpoint ignoring the contract update is rejected
+			checkpoint: []string{"A"},
+			signers:    []string{"A", "A", "A", "A"},
+			failure:    errMismatchingCheckpointSigners,
+		}, {
+			// B attempting to sign before the checkpoint is rejected
+			checkpoint: []string{"A", "B"},
+			signers:    []string{"A", "B", "A", "B"},
+			failure:    errUnauthorizedSigner,
+		}, {
+			// Header votes are rejected in contract governed mode
+			checkpoint: []string{"A", "B"},
+			voted:      "C",
+			signers:    []string{"A", "A", "A", "B"},
+			failure:    errVotingDisabled,
+		},
+	}
+	for i, tt := range tests {
+		var (
+			accounts = newTesterAccountPool()
+			contract = common.HexToAddress("0x0000000000000000000000000000000000001000")
+			base     = new(big.Int).SetBytes(crypto.Keccak256(signersSlot[:]))
+		)
+		// Create the genesis block with A as signer both in the header and the contract
+		genesis := &core.Genesis{
+			ExtraData: make([]byte, extraVanity+common.AddressLength+extraSeal),
+			Alloc: core.GenesisAlloc{
+				accounts.address("A"): {Balance: big.NewInt(1000000000)},
+				contract: {
+					Balance: new(big.Int),
+					Code:    governanceStoreCode,
+					Storage: map[common.Hash]common.Hash{
+						signersSlot:            common.BigToHash(big.NewInt(1)),
+						common.BigToHash(base): accounts.address("A").Hash(),
+					},
+				},
+			},
+		}
+		copy(genesis.ExtraData[extraVanity:], accounts.address("A").Bytes())
+
+		db := paadb.NewMemDatabase()
+		genesis.Commit(db)
+
+		config := *params.TestChainConfig
+		config.Clique = &params.CliqueConfig{
+			Period:         1,
+			Epoch:          3,
+			SignerContract: &contract,
+		}
+		engine := New(config.Clique, db)
+		engine.fakeDiff = true
+
+		// Extend the contract's signer list with B in the first block
+		blocks, _ := core.GenerateChain(&config, genesis.ToBlock(db), &fixedDiffEngine{engine}, db, len(tt.signers), func(j int, gen *core.BlockGen) {
+			if j == 0 {
+				slots := []common.Hash{common.BigToHash(new(big.Int).Add(base, common.Big1)), signersSlot}
+				values := []common.Hash{accounts.address("B").Hash(), common.BigToHash(big.NewInt(2))}
+
+				for k := range slots {
+					tx := types.NewTransaction(gen.TxNonce(accounts.address("A")), contract, new(big.Int), 100000, new(big.Int), append(slots[k].Bytes(), values[k].Bytes()...))
+					tx, _ = types.SignTx(tx, types.HomesteadSigner{}, accounts.accounts["A"])
+					gen.AddTx(tx)
+				}
+			}
+			if j == 1 {
+				gen.SetCoinbase(accounts.address(tt.voted))
+			}
+		})
+		for j, block := range blocks {
+			header := block.Header()
+			if j > 0 {
+				header.ParentHash = blocks[j-1].Hash()
+			}
+			header.Extra = make([]byte, extraVanity+extraSeal)
+			if header.Number.Uint64()%config.Clique.Epoch == 0 {
+				header.Extra = make([]byte, extraVanity+len(tt.checkpoint)*common.AddressLength+extraSeal)
+				accounts.checkpoint(header, tt.checkpoint)
+			}
+			header.Difficulty = diffInTurn // Ignored, we just need a valid number
+
+			accounts.sign(header, tt.signers[j])
+			blocks[j] = block.WithSeal(header)
+		}
+		chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil)
+		if err != nil {
+			t.Errorf("test %d: failed to create test chain: %v", i, err)
+			continue
+		}
+		if _, err := chain.InsertChain(blocks); err != tt.failure {
+			t.Errorf("test %d: failure mismatch: have %v, want %v", i, err, tt.failure)
+		}
+		if tt.failure != nil {
+			continue
+		}
+		head := blocks[len(blocks)-1]
+
+		snap, err := engine.snapshot(chain, head.NumberU64(), head.Hash(), nil)
+		if err != nil {
+			t.Errorf("test %d: failed to retrieve signer snapshot: %v", i, err)
+			continue
+		}
+		want := newSnapshot(config.Clique, nil, 0, common.Hash{}, nil)
+		for _, signer := range tt.results {
+			want.Signers[accounts.address(signer)] = struct{}{}
+		}
+		if have := snap.signers(); !reflect.DeepEqual(have, want.signers()) {
+			t.Errorf("test %d: signers mismatch: have %x, want %x", i, have, want.signers())
+		}
+	}
+}
+
+// headerChain hides the state access of a chain, like light clients do.
+type headerChain struct {
+	consensus.ChainReader
+}
+
+// brokenStateChain is a chain failing to open any state.
+type brokenStateChain struct {
+	*core.BlockChain
+}
+
+func (c brokenStateChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return nil, errBrokenState
+}
+
+var errBrokenState = errors.New("broken state")
+
+// Tests that forged checkpoint signer lists are rejected during header
+// verification when the parent state is available, deferred to Finalize without
+// it (hence no fast or light sync on such networks), and that failures to read
+// the state aren't mistaken for its absence.
+func TestGovernanceCheckpointVerification(t *testing.T) {
+	var (
+		accounts = newTesterAccountPool()
+		contract = common.HexToAddress("0x0000000000000000000000000000000000001000")
+		base     = new(big.Int).SetBytes(crypto.Keccak256(signersSlot[:]))
+	)
+	genesis := &core.Genesis{
+		ExtraData: make([]byte, extraVanity+common.AddressLength+extraSeal),
+		Alloc: core.GenesisAlloc{
+			contract: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					signersSlot:            common.BigToHash(big.NewInt(1)),
+					common.BigToHash(base): accounts.address("A").Hash(),
+				},
+			},
+		},
+	}
+	copy(genesis.ExtraData[extraVanity:], accounts.address("A").Bytes())
+
+	db := paadb.NewMemDatabase()
+	genesis.Commit(db)
+
+	config := *params.TestChainConfig
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 3, SignerContract: &contract}
+	engine := New(config.Clique, db)
+	engine.fakeDiff = true
+
+	blocks, _ := core.GenerateChain(&config, genesis.ToBlock(db), &fixedDiffEngine{engine}, db, 3, nil)
+	for j, block := range blocks {
+		header := block.Header()
+		if j > 0 {
+			header.ParentHash = blocks[j-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+		if header.Number.Uint64()%config.Clique.Epoch == 0 {
+			// Announce B, who was never added to the contract
+			header.Extra = make([]byte, extraVanity+2*common.AddressLength+extraSeal)
+			accounts.checkpoint(header, []string{"A", "B"})
+		}
+		header.Difficulty = diffInTurn
+		accounts.sign(header, "A")
+		blocks[j] = block.WithSeal(header)
+	}
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks[:2]); err != nil {
+		t.Fatalf("failed to insert blocks before the checkpoint: %v", err)
+	}
+	forged := blocks[2].Header()
+	if err := engine.VerifyHeader(chain, forged, true); err != errMismatchingCheckpointSigners {
+		t.Errorf("forged checkpoint with state: have %v, want %v", err, errMismatchingCheckpointSigners)
+	}
+	if err := engine.VerifyHeader(headerChain{chain}, forged, true); err != nil {
+		t.Errorf("forged checkpoint without state: have %v, want <nil>", err)
+	}
+	if err := engine.VerifyHeader(brokenStateChain{chain}, forged, true); err != errBrokenState {
+		t.Errorf("forged checkpoint with broken state: have %v, want %v", err, errBrokenState)
+	}
+	statedb, err := chain.StateAt(blocks[1].Root())
+	if err != nil {
+		t.Fatalf("failed to open parent state: %v", err)
+	}
+	if _, err := engine.Finalize(chain, types.CopyHeader(forged), statedb, nil, nil, nil); err != errMismatchingCheckpointSigners {
+		t.Errorf("forged checkpoint finalization: have %v, want %v", err, errMismatchingCheckpointSigners)
+	}
+}
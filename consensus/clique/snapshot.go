@@ -48,6 +48,7 @@ type Tally struct {
 type Snapshot struct {
 	config   *params.CliqueConfig // Consensus engine parameters to fine tune behavior
 	sigcache *lru.ARCCache        // Cache of recent block signatures to speed up ecrecover
+	activity *signerActivity      // Sealing activity tracker to report applied blocks to
 
 	Number  uint64                      `json:"number"`  // Block number where the snapshot was created
 	Hash    common.Hash                 `json:"hash"`    // Block hash where the snapshot was created
@@ -113,6 +114,7 @@ func (s *Snapshot) copy() *Snapshot {
 	cpy := &Snapshot{
 		config:   s.config,
 		sigcache: s.sigcache,
+		activity: s.activity,
 		Number:   s.Number,
 		Hash:     s.Hash,
 		Signers:  make(map[common.Address]struct{}),
@@ -197,6 +199,7 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	// Iterate through the headers and create a new snapshot
 	snap := s.copy()
 
+	records := make([]*sealRecord, 0, len(headers))
 	for _, header := range headers {
 		// Remove any votes on checkpoint blocks
 		number := header.Number.Uint64()
@@ -222,7 +225,26 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 			}
 		}
 		snap.Recents[number] = signer
+		records = append(records, &sealRecord{number: number, hash: header.Hash(), signer: signer, inturn: snap.inturnSigner(number)})
 
+		// Contract governed signer sets ignore votes, adopting the signer list
+		// announced by the checkpoint headers instead
+		if s.config.SignerContract != nil {
+			if number%s.config.Epoch == 0 {
+				snap.Signers = make(map[common.Address]struct{})
+				for _, signer := range checkpointSigners(header) {
+					snap.Signers[signer] = struct{}{}
+				}
+				// Signer list may have shrunk, delete any leftover recent caches
+				limit := uint64(len(snap.Signers)/2 + 1)
+				for seen := range snap.Recents {
+					if seen+limit <= number {
+						delete(snap.Recents, seen)
+					}
+				}
+			}
+			continue
+		}
 		// Header authorized, discard any previous votes from the signer
 		for i, vote := range snap.Votes {
 			if vote.Signer == signer && vote.Address == header.Coinbase {
@@ -289,6 +311,9 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	snap.Number += uint64(len(headers))
 	snap.Hash = headers[len(headers)-1].Hash()
 
+	if snap.activity != nil {
+		snap.activity.record(records)
+	}
 	return snap, nil
 }
 
@@ -302,6 +327,15 @@ func (s *Snapshot) signers() []common.Address {
 	return sigs
 }
 
+// inturnSigner returns the signer whose turn it is at a given block height.
+func (s *Snapshot) inturnSigner(number uint64) common.Address {
+	signers := s.signers()
+	if len(signers) == 0 {
+		return common.Address{}
+	}
+	return signers[number%uint64(len(signers))]
+}
+
 // inturn returns if a signer at a given block height is in-turn or not.
 func (s *Snapshot) inturn(number uint64, signer common.Address) bool {
 	signers, offset := s.signers(), 0
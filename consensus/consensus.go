@@ -23,6 +23,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
 	"github.com/PaloAltoAi/go-PaloAltoAi/params"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
 )
@@ -114,3 +115,23 @@ type PoW interface {
 	// Hashrate returns the current mining hashrate of a PoW consensus engine.
 	Hashrate() float64
 }
+
+// BFT is a consensus engine that reaches finality through message exchange among
+// a set of validators, requiring a network protocol and a running event loop.
+type BFT interface {
+	Engine
+
+	// Protocols returns the p2p sub-protocols used to exchange consensus messages.
+	Protocols() []p2p.Protocol
+
+	// IsValidator reports whether the local node is a validator of the block
+	// after the head of the given chain.
+	IsValidator(chain ChainReader) bool
+
+	// Start launches the consensus event loop on top of the given chain. Blocks
+	// committed by other validators are imported through the insert callback.
+	Start(chain ChainReader, insert func(types.Blocks) (int, error)) error
+
+	// Stop terminates the consensus event loop.
+	Stop() error
+}
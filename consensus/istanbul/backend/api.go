@@ -0,0 +1,102 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+)
+
+// API is a user facing RPC API to inspect the validators and control the
+// validator voting of the Istanbul engine.
+type API struct {
+	chain    consensus.ChainReader
+	istanbul *Backend
+}
+
+// GetSnapshot retrieves the voting snapshot at a given block.
+func (api *API) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
+	header := api.header(number)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.istanbul.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+}
+
+// GetValidators retrieves the list of validators sealing the block after the
+// specified one.
+func (api *API) GetValidators(number *rpc.BlockNumber) ([]common.Address, error) {
+	snap, err := api.GetSnapshot(number)
+	if err != nil {
+		return nil, err
+	}
+	return snap.Validators, nil
+}
+
+// GetValidatorsAtHash retrieves the list of validators sealing the block after
+// the specified one.
+func (api *API) GetValidatorsAtHash(hash common.Hash) ([]common.Address, error) {
+	header := api.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.istanbul.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return snap.Validators, nil
+}
+
+// Candidates returns the current proposals the node tries to uphold and vote on.
+func (api *API) Candidates() map[common.Address]bool {
+	api.istanbul.lock.RLock()
+	defer api.istanbul.lock.RUnlock()
+
+	proposals := make(map[common.Address]bool)
+	for address, auth := range api.istanbul.proposals {
+		proposals[address] = auth
+	}
+	return proposals
+}
+
+// Propose injects a new authorization proposal that the validator will attempt
+// to push through.
+func (api *API) Propose(address common.Address, auth bool) {
+	api.istanbul.lock.Lock()
+	defer api.istanbul.lock.Unlock()
+
+	api.istanbul.proposals[address] = auth
+}
+
+// Discard drops a currently running proposal, stopping the validator from
+// casting further votes (either for or against).
+func (api *API) Discard(address common.Address) {
+	api.istanbul.lock.Lock()
+	defer api.istanbul.lock.Unlock()
+
+	delete(api.istanbul.proposals, address)
+}
+
+// header retrieves the requested header, or the current one if none requested.
+func (api *API) header(number *rpc.BlockNumber) *types.Header {
+	if number == nil || *number == rpc.LatestBlockNumber {
+		return api.chain.CurrentHeader()
+	}
+	return api.chain.GetHeaderByNumber(uint64(number.Int64()))
+}
@@ -0,0 +1,300 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package backend implements the Istanbul byzantine fault tolerant consensus
+// engine on top of the protocol state machine in package core.
+package backend
+
+import (
+	"crypto/ecdsa"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	istanbulCore "github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/event"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	checkpointInterval = 1024 // Number of blocks after which to save the vote snapshot to the database
+	inmemorySnapshots  = 128  // Number of recent vote snapshots to keep in memory
+	inmemoryPeers      = 40   // Number of peers to track the known messages of
+	inmemoryMessages   = 1024 // Number of recent consensus messages to keep in memory
+)
+
+// chainHeadSubscriber is implemented by chains able to announce new heads,
+// which the engine needs to start the consensus on the next block.
+type chainHeadSubscriber interface {
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Backend is the Istanbul consensus engine. It implements consensus.BFT for the
+// chain and istanbul.Backend for the protocol state machine.
+type Backend struct {
+	config     *params.IstanbulConfig
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+	db         paadb.Database
+	logger     log.Logger
+
+	core             istanbulCore.Engine
+	istanbulEventMux *event.TypeMux
+	recents          *lru.ARCCache // Snapshots for recent blocks to speed up reorgs
+
+	chain   consensus.ChainReader
+	insert  func(types.Blocks) (int, error)
+	headSub event.Subscription
+
+	coreStarted bool
+	coreMu      sync.RWMutex
+
+	commitCh          chan *types.Block // Delivers agreed blocks to the running Seal
+	proposedBlockHash common.Hash       // Proposal hash of the block being sealed
+	sealMu            sync.Mutex
+
+	peers          map[string]*peer // Connected peers speaking the consensus protocol
+	peersMu        sync.RWMutex
+	recentMessages *lru.ARCCache // Hashes of the recently seen consensus messages
+
+	proposals map[common.Address]bool // Current list of proposals we are pushing
+	lock      sync.RWMutex            // Protects the proposals
+}
+
+// New creates an Istanbul consensus engine sealing with the given key.
+func New(config *params.IstanbulConfig, privateKey *ecdsa.PrivateKey, db paadb.Database) *Backend {
+	// Set any missing consensus parameters to their defaults
+	conf := *config
+	if conf.Epoch == 0 {
+		conf.Epoch = epochLength
+	}
+	recents, _ := lru.NewARC(inmemorySnapshots)
+	messages, _ := lru.NewARC(inmemoryMessages)
+
+	sb := &Backend{
+		config:           &conf,
+		privateKey:       privateKey,
+		address:          crypto.PubkeyToAddress(privateKey.PublicKey),
+		db:               db,
+		istanbulEventMux: new(event.TypeMux),
+		recents:          recents,
+		commitCh:         make(chan *types.Block),
+		peers:            make(map[string]*peer),
+		recentMessages:   messages,
+		proposals:        make(map[common.Address]bool),
+	}
+	sb.logger = log.New("address", sb.address)
+	sb.core = istanbulCore.New(sb, sb.config)
+	return sb
+}
+
+// Start launches the consensus protocol on top of the given chain, inserting
+// the blocks agreed on but sealed by other validators through insert.
+func (sb *Backend) Start(chain consensus.ChainReader, insert func(types.Blocks) (int, error)) error {
+	sb.coreMu.Lock()
+	defer sb.coreMu.Unlock()
+
+	if sb.coreStarted {
+		return istanbul.ErrStartedEngine
+	}
+	sb.chain, sb.insert = chain, insert
+
+	if subscriber, ok := chain.(chainHeadSubscriber); ok {
+		heads := make(chan core.ChainHeadEvent, 10)
+		sb.headSub = subscriber.SubscribeChainHeadEvent(heads)
+		go sb.headLoop(heads, sb.headSub)
+	}
+	if err := sb.core.Start(); err != nil {
+		return err
+	}
+	sb.coreStarted = true
+	return nil
+}
+
+// Stop terminates the consensus protocol.
+func (sb *Backend) Stop() error {
+	sb.coreMu.Lock()
+	defer sb.coreMu.Unlock()
+
+	if !sb.coreStarted {
+		return istanbul.ErrStoppedEngine
+	}
+	if sb.headSub != nil {
+		sb.headSub.Unsubscribe()
+		sb.headSub = nil
+	}
+	if err := sb.core.Stop(); err != nil {
+		return err
+	}
+	sb.coreStarted = false
+	return nil
+}
+
+// headLoop notifies the protocol of every new chain head, so that it starts
+// agreeing on the next block.
+func (sb *Backend) headLoop(heads chan core.ChainHeadEvent, sub event.Subscription) {
+	for {
+		select {
+		case <-heads:
+			// The chain may announce heads from within a commit, don't block it
+			go sb.istanbulEventMux.Post(istanbul.FinalCommittedEvent{})
+		case <-sub.Err():
+			return
+		}
+	}
+}
+
+// IsValidator implements consensus.BFT, reporting whether the local node is a
+// validator of the block after the chain head.
+func (sb *Backend) IsValidator(chain consensus.ChainReader) bool {
+	head := chain.CurrentHeader()
+	snap, err := sb.snapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return false
+	}
+	return snap.ValSet().Contains(sb.address)
+}
+
+// Address implements istanbul.Backend, returning the validator address.
+func (sb *Backend) Address() common.Address {
+	return sb.address
+}
+
+// Validators implements istanbul.Backend, returning the validators sealing the
+// block after the given one.
+func (sb *Backend) Validators(number uint64, hash common.Hash) *istanbul.ValidatorSet {
+	snap, err := sb.snapshot(sb.chain, number, hash, nil)
+	if err != nil {
+		sb.logger.Warn("Failed to retrieve validators", "number", number, "hash", hash, "err", err)
+		return istanbul.NewValidatorSet(nil, istanbul.ProposerPolicy(sb.config.ProposerPolicy))
+	}
+	return snap.ValSet()
+}
+
+// EventMux implements istanbul.Backend, returning the mux feeding the protocol.
+func (sb *Backend) EventMux() *event.TypeMux {
+	return sb.istanbulEventMux
+}
+
+// Broadcast implements istanbul.Backend, delivering a message to the local
+// protocol and gossiping it to the connected peers.
+func (sb *Backend) Broadcast(valSet *istanbul.ValidatorSet, payload []byte) error {
+	hash := crypto.Keccak256Hash(payload)
+	sb.recentMessages.Add(hash, true)
+
+	// Deliver to the local protocol asynchronously, as it's the caller
+	go sb.istanbulEventMux.Post(istanbul.MessageEvent{Payload: payload})
+
+	sb.gossip(hash, payload, "")
+	return nil
+}
+
+// Gossip implements istanbul.Backend, relaying a message the local protocol
+// accepted to the peers that haven't seen it yet.
+func (sb *Backend) Gossip(valSet *istanbul.ValidatorSet, payload []byte) error {
+	sb.gossip(crypto.Keccak256Hash(payload), payload, "")
+	return nil
+}
+
+// Commit implements istanbul.Backend, attaching the committed seals to the
+// agreed block and handing it either to the running Seal or to the chain.
+func (sb *Backend) Commit(proposal *types.Block, seals [][]byte) error {
+	header := proposal.Header()
+
+	extra, err := istanbul.ExtractExtra(header)
+	if err != nil {
+		return err
+	}
+	extra.CommittedSeal = seals
+	if err := istanbul.WriteExtra(header, extra); err != nil {
+		return err
+	}
+	block := proposal.WithSeal(header)
+
+	sb.logger.Info("Committed", "number", block.Number(), "hash", block.Hash(), "proposer", sb.address)
+
+	// If the block is the one being sealed locally, let Seal deliver it
+	sb.sealMu.Lock()
+	proposed := sb.proposedBlockHash
+	sb.sealMu.Unlock()
+
+	if proposed == proposal.Hash() {
+		select {
+		case sb.commitCh <- block:
+			return nil
+		default:
+		}
+	}
+	if sb.insert == nil {
+		return nil
+	}
+	_, err = sb.insert(types.Blocks{block})
+	return err
+}
+
+// Verify implements istanbul.Backend, checking a proposed block.
+func (sb *Backend) Verify(proposal *types.Block) (time.Duration, error) {
+	header := proposal.Header()
+
+	if len(proposal.Uncles()) > 0 {
+		return 0, errInvalidUncleHash
+	}
+	if hash := types.DeriveSha(proposal.Transactions()); hash != header.TxHash {
+		return 0, errMismatchTxhashes
+	}
+	err := sb.verifyHeader(sb.chain, header, nil)
+
+	// Proposals carry no committed seals yet
+	if err == errEmptyCommittedSeals {
+		return 0, nil
+	}
+	if err == consensus.ErrFutureBlock {
+		return time.Unix(header.Time.Int64(), 0).Sub(time.Now()), err
+	}
+	return 0, err
+}
+
+// Sign implements istanbul.Backend, signing the keccak256 hash of data.
+func (sb *Backend) Sign(data []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), sb.privateKey)
+}
+
+// LastProposal implements istanbul.Backend, returning the head block and its
+// proposer.
+func (sb *Backend) LastProposal() (*types.Block, common.Address) {
+	header := sb.chain.CurrentHeader()
+
+	block := sb.chain.GetBlock(header.Hash(), header.Number.Uint64())
+	if block == nil {
+		block = types.NewBlockWithHeader(header)
+	}
+	var proposer common.Address
+	if header.Number.Sign() > 0 {
+		var err error
+		if proposer, err = istanbul.Ecrecover(header); err != nil {
+			sb.logger.Error("Failed to recover last proposer", "number", header.Number, "err", err)
+		}
+	}
+	return block, proposer
+}
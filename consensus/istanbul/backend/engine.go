@@ -0,0 +1,531 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+)
+
+// Istanbul protocol constants.
+var (
+	epochLength = uint64(30000) // Default number of blocks after which to checkpoint and reset the pending votes
+
+	nonceAuthVote = types.BlockNonce{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff} // Magic nonce number to vote on adding a new validator
+	nonceDropVote = types.BlockNonce{}                                               // Magic nonce number to vote on removing a validator
+
+	uncleHash   = types.CalcUncleHash(nil) // Always Keccak256(RLP([])) as uncles are meaningless outside of PoW.
+	defaultDiff = big.NewInt(1)            // Fixed difficulty of all blocks
+)
+
+// Various error messages to mark blocks invalid.
+var (
+	// errUnknownBlock is returned when the list of validators is requested for a
+	// block that is not part of the local blockchain.
+	errUnknownBlock = errors.New("unknown block")
+
+	// errInvalidVote is returned if a nonce value is something else than the two
+	// allowed constants of 0x00..0 or 0xff..f.
+	errInvalidVote = errors.New("vote nonce not 0x00..0 or 0xff..f")
+
+	// errInvalidCheckpointVote is returned if a checkpoint block casts a vote.
+	errInvalidCheckpointVote = errors.New("vote in checkpoint block")
+
+	// errInvalidMixDigest is returned if a block's mix digest isn't the Istanbul
+	// digest.
+	errInvalidMixDigest = errors.New("invalid istanbul mix digest")
+
+	// errInvalidUncleHash is returned if a block contains an non-empty uncle list.
+	errInvalidUncleHash = errors.New("non empty uncle hash")
+
+	// errInvalidDifficulty is returned if the difficulty of a block is not 1.
+	errInvalidDifficulty = errors.New("invalid difficulty")
+
+	// errInvalidTimestamp is returned if the timestamp of a block is lower than
+	// the previous block's timestamp + the minimum block period.
+	errInvalidTimestamp = errors.New("invalid timestamp")
+
+	// errInvalidVotingChain is returned if an authorization list is attempted to
+	// be modified via out-of-range or non-contiguous headers.
+	errInvalidVotingChain = errors.New("invalid voting chain")
+
+	// errInconsistentValidatorSet is returned if a block announces validators
+	// other than the ones computed from the parent.
+	errInconsistentValidatorSet = errors.New("inconsistent validator set")
+
+	// errEmptyCommittedSeals is returned if a block carries no committed seals.
+	errEmptyCommittedSeals = errors.New("zero committed seals")
+
+	// errInvalidCommittedSeals is returned if the committed seals of a block are
+	// not from distinct validators or don't reach a quorum.
+	errInvalidCommittedSeals = errors.New("invalid committed seals")
+
+	// errMismatchTxhashes is returned if a proposal's transactions don't match
+	// its header.
+	errMismatchTxhashes = errors.New("mismatch transactions hashes")
+)
+
+// Author implements consensus.Engine, returning the proposer of the block.
+func (sb *Backend) Author(header *types.Header) (common.Address, error) {
+	return istanbul.Ecrecover(header)
+}
+
+// VerifyHeader checks whether a header conforms to the consensus rules.
+func (sb *Backend) VerifyHeader(chain consensus.ChainReader, header *types.Header, seal bool) error {
+	return sb.verifyHeader(chain, header, nil)
+}
+
+// VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers. The
+// method returns a quit channel to abort the operations and a results channel to
+// retrieve the async verifications (the order is that of the input slice).
+func (sb *Backend) VerifyHeaders(chain consensus.ChainReader, headers []*types.Header, seals []bool) (chan<- struct{}, <-chan error) {
+	abort := make(chan struct{})
+	results := make(chan error, len(headers))
+
+	go func() {
+		for i, header := range headers {
+			err := sb.verifyHeader(chain, header, headers[:i])
+
+			select {
+			case <-abort:
+				return
+			case results <- err:
+			}
+		}
+	}()
+	return abort, results
+}
+
+// verifyHeader checks whether a header conforms to the consensus rules. The
+// caller may optionally pass in a batch of parents (ascending order) to avoid
+// looking those up from the database.
+func (sb *Backend) verifyHeader(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
+	if header.Number == nil {
+		return errUnknownBlock
+	}
+	// Don't waste time checking blocks from the future
+	if header.Time.Cmp(big.NewInt(time.Now().Unix())) > 0 {
+		return consensus.ErrFutureBlock
+	}
+	if _, err := istanbul.ExtractExtra(header); err != nil {
+		return err
+	}
+	// Checkpoint blocks can't cast votes, others may only vote with magic nonces
+	number := header.Number.Uint64()
+	if number%sb.config.Epoch == 0 && (header.Coinbase != (common.Address{}) || header.Nonce != nonceDropVote) {
+		return errInvalidCheckpointVote
+	}
+	if header.Nonce != nonceAuthVote && header.Nonce != nonceDropVote {
+		return errInvalidVote
+	}
+	if header.MixDigest != istanbul.Digest {
+		return errInvalidMixDigest
+	}
+	if header.UncleHash != uncleHash {
+		return errInvalidUncleHash
+	}
+	if header.Difficulty == nil || header.Difficulty.Cmp(defaultDiff) != 0 {
+		return errInvalidDifficulty
+	}
+	return sb.verifyCascadingFields(chain, header, parents)
+}
+
+// verifyCascadingFields verifies all the header fields that depend on a batch
+// of previous headers: the timestamp, the validator set, the proposer seal and
+// the committed seals.
+func (sb *Backend) verifyCascadingFields(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
+	// The genesis block is the always valid dead-end
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil
+	}
+	// Ensure that the block's timestamp isn't too close to it's parent
+	var parent *types.Header
+	if len(parents) > 0 {
+		parent = parents[len(parents)-1]
+	} else {
+		parent = chain.GetHeader(header.ParentHash, number-1)
+	}
+	if parent == nil || parent.Number.Uint64() != number-1 || parent.Hash() != header.ParentHash {
+		return consensus.ErrUnknownAncestor
+	}
+	if parent.Time.Uint64()+sb.config.BlockPeriod > header.Time.Uint64() {
+		return errInvalidTimestamp
+	}
+	// Ensure the block is sealed by the validators computed from the parent
+	snap, err := sb.snapshot(chain, number-1, header.ParentHash, parents)
+	if err != nil {
+		return err
+	}
+	extra, err := istanbul.ExtractExtra(header)
+	if err != nil {
+		return err
+	}
+	if !sameValidators(extra.Validators, snap.Validators) {
+		return errInconsistentValidatorSet
+	}
+	valSet := snap.ValSet()
+	if err := sb.verifySigner(header, valSet); err != nil {
+		return err
+	}
+	return sb.verifyCommittedSeals(header, valSet)
+}
+
+// sameValidators reports if two sorted validator lists are equal.
+func sameValidators(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// verifySigner checks that the proposer seal is from a validator.
+func (sb *Backend) verifySigner(header *types.Header, valSet *istanbul.ValidatorSet) error {
+	signer, err := istanbul.Ecrecover(header)
+	if err != nil {
+		return err
+	}
+	if !valSet.Contains(signer) {
+		return istanbul.ErrUnauthorizedAddress
+	}
+	return nil
+}
+
+// verifyCommittedSeals checks that a quorum of distinct validators committed
+// the block.
+func (sb *Backend) verifyCommittedSeals(header *types.Header, valSet *istanbul.ValidatorSet) error {
+	extra, err := istanbul.ExtractExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.CommittedSeal) == 0 {
+		return errEmptyCommittedSeals
+	}
+	data := istanbul.CommitSealData(istanbul.ProposalHash(header))
+
+	committers := make(map[common.Address]struct{})
+	for _, seal := range extra.CommittedSeal {
+		addr, err := istanbul.RecoverSigner(data, seal)
+		if err != nil {
+			return errInvalidCommittedSeals
+		}
+		if _, dup := committers[addr]; dup || !valSet.Contains(addr) {
+			return errInvalidCommittedSeals
+		}
+		committers[addr] = struct{}{}
+	}
+	if len(committers) < valSet.QuorumSize() {
+		return errInvalidCommittedSeals
+	}
+	return nil
+}
+
+// VerifyUncles implements consensus.Engine, always returning an error for any
+// uncles as this consensus mechanism doesn't permit uncles.
+func (sb *Backend) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
+	if len(block.Uncles()) > 0 {
+		return errInvalidUncleHash
+	}
+	return nil
+}
+
+// VerifySeal implements consensus.Engine, checking whether the proposer seal
+// of the header is from a validator.
+func (sb *Backend) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return errUnknownBlock
+	}
+	snap, err := sb.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
+	return sb.verifySigner(header, snap.ValSet())
+}
+
+// Prepare implements consensus.Engine, preparing all the consensus fields of the
+// header for running the transactions on top.
+func (sb *Backend) Prepare(chain consensus.ChainReader, header *types.Header) error {
+	// If the block isn't a checkpoint, cast a random vote (good enough for now)
+	header.Coinbase = common.Address{}
+	header.Nonce = nonceDropVote
+	header.MixDigest = istanbul.Digest
+
+	number := header.Number.Uint64()
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	snap, err := sb.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
+	if number%sb.config.Epoch != 0 {
+		sb.lock.RLock()
+
+		// Gather all the proposals that make sense voting on
+		addresses := make([]common.Address, 0, len(sb.proposals))
+		for address, authorize := range sb.proposals {
+			if snap.validVote(address, authorize) {
+				addresses = append(addresses, address)
+			}
+		}
+		// If there's pending proposals, cast a vote on them
+		if len(addresses) > 0 {
+			header.Coinbase = addresses[rand.Intn(len(addresses))]
+			if sb.proposals[header.Coinbase] {
+				header.Nonce = nonceAuthVote
+			}
+		}
+		sb.lock.RUnlock()
+	}
+	header.Difficulty = new(big.Int).Set(defaultDiff)
+
+	// Ensure the extra data has all it's components
+	if len(header.Extra) < istanbul.ExtraVanity {
+		header.Extra = append(header.Extra, bytes.Repeat([]byte{0x00}, istanbul.ExtraVanity-len(header.Extra))...)
+	}
+	header.Extra = header.Extra[:istanbul.ExtraVanity]
+
+	if err := istanbul.WriteExtra(header, &istanbul.Extra{
+		Validators:    snap.Validators,
+		Seal:          []byte{},
+		CommittedSeal: [][]byte{},
+	}); err != nil {
+		return err
+	}
+	// Ensure the timestamp has the correct delay
+	header.Time = new(big.Int).Add(parent.Time, new(big.Int).SetUint64(sb.config.BlockPeriod))
+	if header.Time.Int64() < time.Now().Unix() {
+		header.Time = big.NewInt(time.Now().Unix())
+	}
+	return nil
+}
+
+// Finalize implements consensus.Engine, ensuring no uncles are set, nor block
+// rewards given, and returns the final block.
+func (sb *Backend) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
+	header.UncleHash = uncleHash
+
+	return types.NewBlock(header, txs, nil, receipts), nil
+}
+
+// Seal implements consensus.Engine, signing the block as its proposer and
+// pushing it through the consensus protocol. The agreed block, carrying the
+// committed seals, is delivered into results.
+func (sb *Backend) Seal(chain consensus.ChainReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
+	header := block.Header()
+
+	// Sealing the genesis block is not supported
+	number := header.Number.Uint64()
+	if number == 0 {
+		return errUnknownBlock
+	}
+	// Bail out if we're not a validator of the block
+	snap, err := sb.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
+	if !snap.ValSet().Contains(sb.address) {
+		return istanbul.ErrUnauthorizedAddress
+	}
+	// Sign the proposal as its proposer
+	extra, err := istanbul.ExtractExtra(header)
+	if err != nil {
+		return err
+	}
+	if extra.Seal, err = sb.Sign(istanbul.SigHash(header).Bytes()); err != nil {
+		return err
+	}
+	if err := istanbul.WriteExtra(header, extra); err != nil {
+		return err
+	}
+	block = block.WithSeal(header)
+
+	delay := time.Unix(header.Time.Int64(), 0).Sub(time.Now()) // nolint: gosimple
+	go func() {
+		// Wait until the proposal becomes valid before asking for agreement
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+		sb.sealMu.Lock()
+		sb.proposedBlockHash = block.Hash()
+		sb.sealMu.Unlock()
+
+		defer func() {
+			sb.sealMu.Lock()
+			sb.proposedBlockHash = common.Hash{}
+			sb.sealMu.Unlock()
+		}()
+		go sb.istanbulEventMux.Post(istanbul.RequestEvent{Proposal: block})
+
+		for {
+			select {
+			case result := <-sb.commitCh:
+				// Only deliver the block we proposed, the others get inserted
+				if istanbul.ProposalHash(result.Header()) != block.Hash() {
+					continue
+				}
+				select {
+				case results <- result:
+				default:
+					sb.logger.Warn("Sealing result is not read by miner", "sealhash", sb.SealHash(header))
+				}
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// CalcDifficulty implements consensus.Engine, returning the fixed difficulty of
+// all Istanbul blocks.
+func (sb *Backend) CalcDifficulty(chain consensus.ChainReader, time uint64, parent *types.Header) *big.Int {
+	return new(big.Int).Set(defaultDiff)
+}
+
+// SealHash returns the hash of a block prior to it being sealed.
+func (sb *Backend) SealHash(header *types.Header) common.Hash {
+	return istanbul.SigHash(header)
+}
+
+// Close implements consensus.Engine. It's a noop for Istanbul as the protocol
+// is terminated through Stop.
+func (sb *Backend) Close() error {
+	return nil
+}
+
+// APIs implements consensus.Engine, returning the user facing RPC API to allow
+// controlling the validator voting.
+func (sb *Backend) APIs(chain consensus.ChainReader) []rpc.API {
+	return []rpc.API{{
+		Namespace: "istanbul",
+		Version:   "1.0",
+		Service:   &API{chain: chain, istanbul: sb},
+		Public:    true,
+	}}
+}
+
+// Protocols implements consensus.BFT, returning the p2p protocol carrying the
+// consensus messages between validators.
+func (sb *Backend) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{{
+		Name:    protocolName,
+		Version: protocolVersion,
+		Length:  protocolLength,
+		Run:     sb.runPeer,
+	}}
+}
+
+// snapshot retrieves the validator voting snapshot at a given point in time.
+func (sb *Backend) snapshot(chain consensus.ChainReader, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
+	// Search for a snapshot in memory or on disk for checkpoints
+	var (
+		headers []*types.Header
+		snap    *Snapshot
+		policy  = istanbul.ProposerPolicy(sb.config.ProposerPolicy)
+	)
+	for snap == nil {
+		// If an in-memory snapshot was found, use that
+		if s, ok := sb.recents.Get(hash); ok {
+			snap = s.(*Snapshot)
+			break
+		}
+		// If an on-disk checkpoint snapshot can be found, use that
+		if number%checkpointInterval == 0 {
+			if s, err := loadSnapshot(sb.config.Epoch, policy, sb.db, hash); err == nil {
+				sb.logger.Trace("Loaded voting snapshot from disk", "number", number, "hash", hash)
+				snap = s
+				break
+			}
+		}
+		// If we're at the genesis, snapshot the initial validators
+		if number == 0 {
+			genesis := chain.GetHeaderByNumber(0)
+			if genesis == nil {
+				return nil, errUnknownBlock
+			}
+			extra, err := istanbul.ExtractExtra(genesis)
+			if err != nil {
+				return nil, err
+			}
+			snap = newSnapshot(sb.config.Epoch, policy, 0, genesis.Hash(), extra.Validators)
+			if err := snap.store(sb.db); err != nil {
+				return nil, err
+			}
+			sb.logger.Trace("Stored genesis voting snapshot to disk")
+			break
+		}
+		// No snapshot for this header, gather the header and move backward
+		var header *types.Header
+		if len(parents) > 0 {
+			// If we have explicit parents, pick from there (enforced)
+			header = parents[len(parents)-1]
+			if header.Hash() != hash || header.Number.Uint64() != number {
+				return nil, consensus.ErrUnknownAncestor
+			}
+			parents = parents[:len(parents)-1]
+		} else {
+			// No explicit parents (or no more left), reach out to the database
+			header = chain.GetHeader(hash, number)
+			if header == nil {
+				return nil, consensus.ErrUnknownAncestor
+			}
+		}
+		headers = append(headers, header)
+		number, hash = number-1, header.ParentHash
+	}
+	// Previous snapshot found, apply any pending headers on top of it
+	for i := 0; i < len(headers)/2; i++ {
+		headers[i], headers[len(headers)-1-i] = headers[len(headers)-1-i], headers[i]
+	}
+	snap, err := snap.apply(headers)
+	if err != nil {
+		return nil, err
+	}
+	sb.recents.Add(snap.Hash, snap)
+
+	// If we've generated a new checkpoint snapshot, save to disk
+	if snap.Number%checkpointInterval == 0 && len(headers) > 0 {
+		if err = snap.store(sb.db); err != nil {
+			return nil, err
+		}
+		sb.logger.Trace("Stored voting snapshot to disk", "number", snap.Number, "hash", snap.Hash)
+	}
+	return snap, err
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/vm"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// newTestGenesis creates an Istanbul genesis block sealed by the given
+// validators.
+func newTestGenesis(config *params.IstanbulConfig, validators []common.Address) *core.Genesis {
+	chainConfig := *params.TestChainConfig
+	chainConfig.Paaash = nil
+	chainConfig.Istanbul = config
+
+	extra, err := rlp.EncodeToBytes(&istanbul.Extra{
+		Validators:    validators,
+		Seal:          []byte{},
+		CommittedSeal: [][]byte{},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &core.Genesis{
+		Config:     &chainConfig,
+		ExtraData:  append(make([]byte, istanbul.ExtraVanity), extra...),
+		GasLimit:   params.GenesisGasLimit,
+		Difficulty: big.NewInt(1),
+		Mixhash:    istanbul.Digest,
+		Alloc:      core.GenesisAlloc{},
+	}
+}
+
+// newTestChain creates a blockchain of the given genesis with an Istanbul
+// engine validating with the given key.
+func newTestChain(t *testing.T, genesis *core.Genesis, key *ecdsa.PrivateKey) (*core.BlockChain, *Backend) {
+	db := paadb.NewMemDatabase()
+	genesis.MustCommit(db)
+
+	engine := New(genesis.Config.Istanbul, key, db)
+	chain, err := core.NewBlockChain(db, nil, genesis.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	return chain, engine
+}
+
+// makeBlock assembles an empty block on top of the chain head, ready for sealing.
+func makeBlock(chain *core.BlockChain, engine *Backend) (*types.Block, error) {
+	parent := chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+	}
+	if err := engine.Prepare(chain, header); err != nil {
+		return nil, err
+	}
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+	return engine.Finalize(chain, header, statedb, nil, nil, nil)
+}
+
+// Tests that only nodes whose key is in the validator set report being validators.
+func TestIsValidator(t *testing.T) {
+	validator, _ := crypto.GenerateKey()
+	outsider, _ := crypto.GenerateKey()
+	genesis := newTestGenesis(&params.IstanbulConfig{Epoch: 30000}, []common.Address{crypto.PubkeyToAddress(validator.PublicKey)})
+
+	chain, engine := newTestChain(t, genesis, validator)
+	defer chain.Stop()
+	if !engine.IsValidator(chain) {
+		t.Error("validator not recognized")
+	}
+	chain, engine = newTestChain(t, genesis, outsider)
+	defer chain.Stop()
+	if engine.IsValidator(chain) {
+		t.Error("non-validator recognized as validator")
+	}
+}
+
+// Tests that a single validator agrees with itself on blocks, sealing them with
+// a committed seal the chain accepts.
+func TestSealSingleValidator(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	genesis := newTestGenesis(&params.IstanbulConfig{Epoch: 30000, RequestTimeout: 1000}, []common.Address{addr})
+	chain, engine := newTestChain(t, genesis, key)
+	defer chain.Stop()
+
+	if err := engine.Start(chain, chain.InsertChain); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer engine.Stop()
+
+	for i := 1; i <= 3; i++ {
+		block, err := makeBlock(chain, engine)
+		if err != nil {
+			t.Fatalf("block %d: failed to assemble: %v", i, err)
+		}
+		results := make(chan *types.Block)
+		if err := engine.Seal(chain, block, results, make(chan struct{})); err != nil {
+			t.Fatalf("block %d: failed to seal: %v", i, err)
+		}
+		var sealed *types.Block
+		select {
+		case sealed = <-results:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block %d: sealing timed out", i)
+		}
+		if engine.SealHash(sealed.Header()) != engine.SealHash(block.Header()) {
+			t.Fatalf("block %d: seal hash changed during sealing", i)
+		}
+		if author, err := engine.Author(sealed.Header()); err != nil || author != addr {
+			t.Fatalf("block %d: author mismatch: have %x, %v, want %x", i, author, err, addr)
+		}
+		if _, err := chain.InsertChain(types.Blocks{sealed}); err != nil {
+			t.Fatalf("block %d: failed to insert: %v", i, err)
+		}
+	}
+	if head := chain.CurrentBlock().NumberU64(); head != 3 {
+		t.Fatalf("head mismatch: have %d, want 3", head)
+	}
+}
+
+// Tests that blocks without a quorum of valid committed seals are rejected.
+func TestVerifyCommittedSeals(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 4)
+	addrs := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	genesis := newTestGenesis(&params.IstanbulConfig{Epoch: 30000}, addrs)
+	chain, engine := newTestChain(t, genesis, keys[0])
+	defer chain.Stop()
+
+	block, err := makeBlock(chain, engine)
+	if err != nil {
+		t.Fatalf("failed to assemble block: %v", err)
+	}
+	header := block.Header()
+	extra, _ := istanbul.ExtractExtra(header)
+	extra.Seal, _ = engine.Sign(istanbul.SigHash(header).Bytes())
+	istanbul.WriteExtra(header, extra)
+
+	// commit attaches the committed seals of the given validators to the header
+	commit := func(signers ...int) *types.Header {
+		data := istanbul.CommitSealData(istanbul.ProposalHash(header))
+
+		sealed := types.CopyHeader(header)
+		extra, _ := istanbul.ExtractExtra(sealed)
+		for _, i := range signers {
+			seal, _ := crypto.Sign(crypto.Keccak256(data), keys[i])
+			extra.CommittedSeal = append(extra.CommittedSeal, seal)
+		}
+		istanbul.WriteExtra(sealed, extra)
+		return sealed
+	}
+	outsider, _ := crypto.GenerateKey()
+	keys = append(keys, outsider)
+
+	tests := []struct {
+		signers []int
+		err     error
+	}{
+		{[]int{0, 1, 2}, nil},
+		{[]int{0, 1, 2, 3}, nil},
+		{nil, errEmptyCommittedSeals},
+		{[]int{0, 1}, errInvalidCommittedSeals},
+		{[]int{0, 1, 1}, errInvalidCommittedSeals},
+		{[]int{0, 1, 4}, errInvalidCommittedSeals},
+	}
+	for i, tt := range tests {
+		if err := engine.VerifyHeader(chain, commit(tt.signers...), true); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	// Validators collecting different seals must agree on the block hash
+	if have, want := commit(0, 1, 2).Hash(), commit(1, 2, 3).Hash(); have != want {
+		t.Errorf("hash depends on committed seals: %x != %x", have, want)
+	}
+	if have, want := commit(0, 1, 2).Hash(), header.Hash(); have != want {
+		t.Errorf("sealed hash mismatch: have %x, want %x", have, want)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	protocolName    = "istanbul"
+	protocolVersion = 1
+	protocolLength  = 1
+
+	istanbulMsg    = 0x00
+	maxMessageSize = 10 * 1024 * 1024 // Maximum cap on the size of a consensus message
+)
+
+var (
+	// errInvalidMsgCode is returned if a peer sends a message unknown to the
+	// consensus protocol.
+	errInvalidMsgCode = errors.New("invalid message code")
+
+	// errMsgTooLarge is returned if a peer sends an oversized message.
+	errMsgTooLarge = errors.New("message too long")
+)
+
+// peer is a connection speaking the consensus protocol.
+type peer struct {
+	id    string
+	rw    p2p.MsgReadWriter
+	known *lru.ARCCache // Hashes of the messages the peer is known to have
+}
+
+// send delivers a consensus message to the peer, marking it as known.
+func (p *peer) send(hash common.Hash, payload []byte) error {
+	p.known.Add(hash, true)
+	return p2p.Send(p.rw, istanbulMsg, payload)
+}
+
+// runPeer is the p2p protocol handler, relaying consensus messages between the
+// peer and the local protocol.
+func (sb *Backend) runPeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+	known, _ := lru.NewARC(inmemoryMessages)
+	remote := &peer{id: p.ID().String(), rw: rw, known: known}
+
+	sb.peersMu.Lock()
+	sb.peers[remote.id] = remote
+	sb.peersMu.Unlock()
+
+	defer func() {
+		sb.peersMu.Lock()
+		delete(sb.peers, remote.id)
+		sb.peersMu.Unlock()
+	}()
+	for {
+		if err := sb.handleMsg(remote); err != nil {
+			sb.logger.Debug("Consensus peer dropped", "peer", remote.id, "err", err)
+			return err
+		}
+	}
+}
+
+// handleMsg reads a consensus message from the peer, feeding it to the local
+// protocol the first time it's seen while the protocol is running. Messages are
+// only relayed to the other peers once the protocol authenticated them (see
+// Gossip), so that peers can't push arbitrary data through the validators.
+func (sb *Backend) handleMsg(p *peer) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+
+	if msg.Code != istanbulMsg {
+		return errInvalidMsgCode
+	}
+	if msg.Size > maxMessageSize {
+		return errMsgTooLarge
+	}
+	var payload []byte
+	if err := msg.Decode(&payload); err != nil {
+		return err
+	}
+	hash := crypto.Keccak256Hash(payload)
+	p.known.Add(hash, true)
+
+	if _, seen := sb.recentMessages.Get(hash); seen {
+		return nil
+	}
+	// Only remember the message once the protocol took it, so that anything
+	// arriving before it starts gets another chance when sent again
+	sb.coreMu.RLock()
+	started := sb.coreStarted
+	sb.coreMu.RUnlock()
+
+	if !started {
+		return nil
+	}
+	if err := sb.istanbulEventMux.Post(istanbul.MessageEvent{Payload: payload}); err != nil {
+		return nil
+	}
+	sb.recentMessages.Add(hash, true)
+	return nil
+}
+
+// gossip relays a consensus message to all peers not known to have it, other
+// than the given origin.
+func (sb *Backend) gossip(hash common.Hash, payload []byte, origin string) {
+	sb.peersMu.RLock()
+	defer sb.peersMu.RUnlock()
+
+	for id, p := range sb.peers {
+		if id == origin {
+			continue
+		}
+		if _, known := p.known.Get(hash); known {
+			continue
+		}
+		// Sends block on slow peers, don't hold up the caller
+		go func(p *peer) {
+			if err := p.send(hash, payload); err != nil {
+				sb.logger.Trace("Failed to relay consensus message", "peer", p.id, "err", err)
+			}
+		}(p)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// testMessage mirrors the signed envelope of the consensus messages.
+type testMessage struct {
+	Code          uint64
+	Msg           []byte
+	Address       common.Address
+	Signature     []byte
+	CommittedSeal []byte
+}
+
+// newRoundChange creates a round change request signed by the given key.
+func newRoundChange(key *ecdsa.PrivateKey, sequence, round int64) []byte {
+	subject, _ := rlp.EncodeToBytes(&istanbul.Subject{
+		View: &istanbul.View{Sequence: big.NewInt(sequence), Round: big.NewInt(round)},
+	})
+	msg := &testMessage{Code: 3, Msg: subject, Address: crypto.PubkeyToAddress(key.PublicKey), Signature: []byte{}, CommittedSeal: []byte{}}
+	data, _ := rlp.EncodeToBytes(msg)
+	msg.Signature, _ = crypto.Sign(crypto.Keccak256(data), key)
+
+	payload, _ := rlp.EncodeToBytes(msg)
+	return payload
+}
+
+// Tests that only consensus messages authenticated as coming from a validator
+// are relayed to the other peers.
+func TestHandleMsgRelay(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 4)
+	addrs := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	genesis := newTestGenesis(&params.IstanbulConfig{Epoch: 30000, RequestTimeout: 10000}, addrs)
+	chain, engine := newTestChain(t, genesis, keys[0])
+	defer chain.Stop()
+
+	if err := engine.Start(chain, chain.InsertChain); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer engine.Stop()
+
+	// Connect a peer sending messages and one receiving the relayed ones
+	var remotes []p2p.MsgReadWriter
+	for i := byte(1); i <= 2; i++ {
+		local, remote := p2p.MsgPipe()
+		defer local.Close()
+
+		go engine.runPeer(p2p.NewPeer(enode.ID{i}, "", nil), local)
+		remotes = append(remotes, remote)
+	}
+	sender, receiver := remotes[0], remotes[1]
+	waitFor(t, time.Second, func() error {
+		engine.peersMu.RLock()
+		defer engine.peersMu.RUnlock()
+
+		if len(engine.peers) != 2 {
+			return fmt.Errorf("have %d consensus peers, want 2", len(engine.peers))
+		}
+		return nil
+	})
+	outsider, _ := crypto.GenerateKey()
+	var (
+		junk   = []byte("junk")
+		forged = newRoundChange(outsider, 1, 1)
+		valid  = newRoundChange(keys[1], 1, 1)
+	)
+	for _, payload := range [][]byte{junk, forged, valid} {
+		if err := p2p.Send(sender, istanbulMsg, payload); err != nil {
+			t.Fatalf("failed to send message: %v", err)
+		}
+	}
+	// The first message relayed must be the valid one
+	relayed := make(chan []byte, 1)
+	go func() {
+		msg, err := receiver.ReadMsg()
+		if err != nil {
+			return
+		}
+		var payload []byte
+		msg.Decode(&payload)
+		relayed <- payload
+	}()
+	select {
+	case payload := <-relayed:
+		if string(payload) != string(valid) {
+			t.Fatalf("relayed unauthenticated message: %x", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("valid message not relayed")
+	}
+}
+
+// Tests that consensus messages arriving before the protocol runs aren't marked
+// as seen, so that they are handled once sent again after the start.
+func TestHandleMsgBeforeStart(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	genesis := newTestGenesis(&params.IstanbulConfig{Epoch: 30000, RequestTimeout: 10000}, []common.Address{crypto.PubkeyToAddress(key.PublicKey)})
+	chain, engine := newTestChain(t, genesis, key)
+	defer chain.Stop()
+
+	local, remote := p2p.MsgPipe()
+	defer local.Close()
+
+	known, _ := lru.NewARC(inmemoryMessages)
+	p := &peer{id: "remote", rw: local, known: known}
+
+	payload := newRoundChange(key, 1, 1)
+	hash := crypto.Keccak256Hash(payload)
+
+	deliver := func() {
+		go p2p.Send(remote, istanbulMsg, payload)
+		if err := engine.handleMsg(p); err != nil {
+			t.Fatalf("failed to handle message: %v", err)
+		}
+	}
+	deliver()
+	if _, seen := engine.recentMessages.Get(hash); seen {
+		t.Fatalf("message marked as seen before the protocol started")
+	}
+	if err := engine.Start(chain, chain.InsertChain); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer engine.Stop()
+
+	deliver()
+	if _, seen := engine.recentMessages.Get(hash); !seen {
+		t.Fatalf("message not marked as seen after the protocol took it")
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/vm"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/node"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/simulations"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/simulations/adapters"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+)
+
+// simValidator is a node.Service running a validator: a chain, the Istanbul
+// engine on top of it and a minimal sealing loop standing in for the miner.
+type simValidator struct {
+	chain  *core.BlockChain
+	engine *Backend
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newSimValidator(genesis *core.Genesis, key *ecdsa.PrivateKey) (*simValidator, error) {
+	db := paadb.NewMemDatabase()
+	genesis.MustCommit(db)
+
+	engine := New(genesis.Config.Istanbul, key, db)
+	chain, err := core.NewBlockChain(db, nil, genesis.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &simValidator{chain: chain, engine: engine, quit: make(chan struct{})}, nil
+}
+
+func (v *simValidator) Protocols() []p2p.Protocol { return v.engine.Protocols() }
+func (v *simValidator) APIs() []rpc.API           { return v.engine.APIs(v.chain) }
+func (v *simValidator) Start(*p2p.Server) error   { return nil }
+
+func (v *simValidator) Stop() error {
+	select {
+	case <-v.quit:
+		return nil
+	default:
+	}
+	close(v.quit)
+	v.wg.Wait()
+
+	v.engine.Stop()
+	v.chain.Stop()
+	return nil
+}
+
+// startSealing launches the consensus and keeps proposing empty blocks on top
+// of the chain head.
+func (v *simValidator) startSealing() error {
+	if err := v.engine.Start(v.chain, v.chain.InsertChain); err != nil {
+		return err
+	}
+	heads := make(chan core.ChainHeadEvent, 10)
+	sub := v.chain.SubscribeChainHeadEvent(heads)
+
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+		defer sub.Unsubscribe()
+
+		results := make(chan *types.Block)
+		stop := v.seal(results, nil)
+		for {
+			select {
+			case <-heads:
+				stop = v.seal(results, stop)
+			case block := <-results:
+				if _, err := v.chain.InsertChain(types.Blocks{block}); err != nil {
+					v.engine.logger.Error("Failed to insert sealed block", "err", err)
+				}
+			case <-v.quit:
+				close(stop)
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// seal aborts the previous sealing and starts a new one on the chain head.
+func (v *simValidator) seal(results chan *types.Block, prev chan struct{}) chan struct{} {
+	if prev != nil {
+		close(prev)
+	}
+	stop := make(chan struct{})
+
+	block, err := makeBlock(v.chain, v.engine)
+	if err == nil {
+		err = v.engine.Seal(v.chain, block, results, stop)
+	}
+	if err != nil {
+		v.engine.logger.Error("Failed to seal block", "err", err)
+	}
+	return stop
+}
+
+// peerCount returns the number of peers speaking the consensus protocol.
+func (v *simValidator) peerCount() int {
+	v.engine.peersMu.RLock()
+	defer v.engine.peersMu.RUnlock()
+
+	return len(v.engine.peers)
+}
+
+// Tests that a network of validators agrees on the same chain.
+func TestSimulation(t *testing.T) {
+	testSimulation(t, 4, 0, 5)
+}
+
+// Tests that the validators keep agreeing on blocks through round changes if a
+// tolerable number of them is offline.
+func TestSimulationFaultyValidator(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping round change simulation in short mode")
+	}
+	testSimulation(t, 4, 1, 4)
+}
+
+func testSimulation(t *testing.T, validators int, offline int, blocks uint64) {
+	// Generate the validator keys and the genesis sealed by all of them
+	configs := make([]*adapters.NodeConfig, validators)
+	addrs := make([]common.Address, validators)
+	for i := range configs {
+		configs[i] = adapters.RandomNodeConfig()
+		configs[i].Services = []string{"istanbul"}
+		addrs[i] = crypto.PubkeyToAddress(configs[i].PrivateKey.PublicKey)
+	}
+	genesis := newTestGenesis(&params.IstanbulConfig{Epoch: 30000, BlockPeriod: 1, RequestTimeout: 1000}, addrs)
+
+	var (
+		lock     sync.Mutex
+		services = make(map[enode.ID]*simValidator)
+	)
+	adapter := adapters.NewSimAdapter(adapters.Services{
+		"istanbul": func(ctx *adapters.ServiceContext) (node.Service, error) {
+			validator, err := newSimValidator(genesis, ctx.Config.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			lock.Lock()
+			services[ctx.Config.ID] = validator
+			lock.Unlock()
+			return validator, nil
+		},
+	})
+	network := simulations.NewNetwork(adapter, &simulations.NetworkConfig{DefaultService: "istanbul"})
+	defer network.Shutdown()
+
+	// Start the online validators and connect them all to each other
+	var ids []enode.ID
+	for _, config := range configs[offline:] {
+		node, err := network.NewNodeWithConfig(config)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		if err := network.Start(node.ID()); err != nil {
+			t.Fatalf("failed to start node: %v", err)
+		}
+		ids = append(ids, node.ID())
+	}
+	if err := network.ConnectNodesFull(ids); err != nil {
+		t.Fatalf("failed to connect nodes: %v", err)
+	}
+	waitFor(t, 5*time.Second, func() error {
+		for id, validator := range services {
+			if n := validator.peerCount(); n != len(ids)-1 {
+				return fmt.Errorf("node %s: have %d consensus peers, want %d", id.TerminalString(), n, len(ids)-1)
+			}
+		}
+		return nil
+	})
+	for _, validator := range services {
+		if err := validator.startSealing(); err != nil {
+			t.Fatalf("failed to start sealing: %v", err)
+		}
+	}
+	// Wait until all validators reached the target height, then compare chains
+	waitFor(t, time.Duration(blocks)*15*time.Second, func() error {
+		for id, validator := range services {
+			if head := validator.chain.CurrentBlock().NumberU64(); head < blocks {
+				return fmt.Errorf("node %s: head %d, want %d", id.TerminalString(), head, blocks)
+			}
+		}
+		return nil
+	})
+	var reference *simValidator
+	for _, validator := range services {
+		if reference == nil {
+			reference = validator
+			continue
+		}
+		for n := uint64(1); n <= blocks; n++ {
+			if have, want := validator.chain.GetBlockByNumber(n).Hash(), reference.chain.GetBlockByNumber(n).Hash(); have != want {
+				t.Fatalf("block %d: hash mismatch: have %x, want %x", n, have, want)
+			}
+		}
+	}
+	// Ensure every block was committed by a quorum of the validators
+	for n := uint64(1); n <= blocks; n++ {
+		header := reference.chain.GetHeaderByNumber(n)
+		if err := reference.engine.VerifyHeader(reference.chain, header, true); err != nil {
+			t.Fatalf("block %d: invalid header: %v", n, err)
+		}
+	}
+}
+
+// waitFor polls a condition until it holds or the timeout expires.
+func waitFor(t *testing.T, timeout time.Duration, cond func() error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := cond()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
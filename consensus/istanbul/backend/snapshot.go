@@ -0,0 +1,241 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"encoding/json"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
+)
+
+// Vote represents a single vote that a validator made to modify the validator
+// set.
+type Vote struct {
+	Validator common.Address `json:"validator"` // Validator that cast this vote
+	Block     uint64         `json:"block"`     // Block number the vote was cast in (expire old votes)
+	Address   common.Address `json:"address"`   // Account being voted on to change its authorization
+	Authorize bool           `json:"authorize"` // Whether to authorize or deauthorize the voted account
+}
+
+// Tally is a simple vote tally to keep the current score of votes.
+type Tally struct {
+	Authorize bool `json:"authorize"` // Whether the vote is about authorizing or kicking someone
+	Votes     int  `json:"votes"`     // Number of votes until now wanting to pass the proposal
+}
+
+// Snapshot is the state of the validator voting at a given point in time.
+type Snapshot struct {
+	epoch  uint64
+	policy istanbul.ProposerPolicy
+
+	Number     uint64                   `json:"number"`     // Block number where the snapshot was created
+	Hash       common.Hash              `json:"hash"`       // Block hash where the snapshot was created
+	Validators []common.Address         `json:"validators"` // Validators sealing the next block, sorted ascending
+	Votes      []*Vote                  `json:"votes"`      // List of votes cast in chronological order
+	Tally      map[common.Address]Tally `json:"tally"`      // Current vote tally to avoid recalculating
+}
+
+// newSnapshot creates a new snapshot with the specified startup parameters.
+func newSnapshot(epoch uint64, policy istanbul.ProposerPolicy, number uint64, hash common.Hash, validators []common.Address) *Snapshot {
+	return &Snapshot{
+		epoch:      epoch,
+		policy:     policy,
+		Number:     number,
+		Hash:       hash,
+		Validators: istanbul.NewValidatorSet(validators, policy).List(),
+		Tally:      make(map[common.Address]Tally),
+	}
+}
+
+// loadSnapshot loads an existing snapshot from the database.
+func loadSnapshot(epoch uint64, policy istanbul.ProposerPolicy, db paadb.Database, hash common.Hash) (*Snapshot, error) {
+	blob, err := db.Get(append([]byte("istanbul-"), hash[:]...))
+	if err != nil {
+		return nil, err
+	}
+	snap := new(Snapshot)
+	if err := json.Unmarshal(blob, snap); err != nil {
+		return nil, err
+	}
+	snap.epoch = epoch
+	snap.policy = policy
+
+	return snap, nil
+}
+
+// store inserts the snapshot into the database.
+func (s *Snapshot) store(db paadb.Database) error {
+	blob, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return db.Put(append([]byte("istanbul-"), s.Hash[:]...), blob)
+}
+
+// copy creates a deep copy of the snapshot, though not the individual votes.
+func (s *Snapshot) copy() *Snapshot {
+	cpy := &Snapshot{
+		epoch:      s.epoch,
+		policy:     s.policy,
+		Number:     s.Number,
+		Hash:       s.Hash,
+		Validators: make([]common.Address, len(s.Validators)),
+		Votes:      make([]*Vote, len(s.Votes)),
+		Tally:      make(map[common.Address]Tally),
+	}
+	copy(cpy.Validators, s.Validators)
+	copy(cpy.Votes, s.Votes)
+	for address, tally := range s.Tally {
+		cpy.Tally[address] = tally
+	}
+	return cpy
+}
+
+// ValSet returns the validator set sealing the block after the snapshot.
+func (s *Snapshot) ValSet() *istanbul.ValidatorSet {
+	return istanbul.NewValidatorSet(s.Validators, s.policy)
+}
+
+// validVote returns whether it makes sense to cast the specified vote in the
+// given snapshot context (e.g. don't try to add an already authorized validator).
+func (s *Snapshot) validVote(address common.Address, authorize bool) bool {
+	return s.ValSet().Contains(address) != authorize
+}
+
+// cast adds a new vote into the tally.
+func (s *Snapshot) cast(address common.Address, authorize bool) bool {
+	if !s.validVote(address, authorize) {
+		return false
+	}
+	if old, ok := s.Tally[address]; ok {
+		old.Votes++
+		s.Tally[address] = old
+	} else {
+		s.Tally[address] = Tally{Authorize: authorize, Votes: 1}
+	}
+	return true
+}
+
+// uncast removes a previously cast vote from the tally.
+func (s *Snapshot) uncast(address common.Address, authorize bool) bool {
+	tally, ok := s.Tally[address]
+	if !ok || tally.Authorize != authorize {
+		return false
+	}
+	if tally.Votes > 1 {
+		tally.Votes--
+		s.Tally[address] = tally
+	} else {
+		delete(s.Tally, address)
+	}
+	return true
+}
+
+// apply creates a new snapshot by applying the given headers to the original
+// one.
+func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
+	if len(headers) == 0 {
+		return s, nil
+	}
+	// Sanity check that the headers can be applied
+	for i := 0; i < len(headers)-1; i++ {
+		if headers[i+1].Number.Uint64() != headers[i].Number.Uint64()+1 {
+			return nil, errInvalidVotingChain
+		}
+	}
+	if headers[0].Number.Uint64() != s.Number+1 {
+		return nil, errInvalidVotingChain
+	}
+	snap := s.copy()
+
+	for _, header := range headers {
+		// Remove any votes on checkpoint blocks
+		number := header.Number.Uint64()
+		if number%s.epoch == 0 {
+			snap.Votes = nil
+			snap.Tally = make(map[common.Address]Tally)
+		}
+		// Resolve the authorization key and check against validators
+		validator, err := istanbul.Ecrecover(header)
+		if err != nil {
+			return nil, err
+		}
+		valSet := snap.ValSet()
+		if !valSet.Contains(validator) {
+			return nil, istanbul.ErrUnauthorizedAddress
+		}
+		// Discard any previous votes from the validator on the same account
+		for i, vote := range snap.Votes {
+			if vote.Validator == validator && vote.Address == header.Coinbase {
+				snap.uncast(vote.Address, vote.Authorize)
+				snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
+				break
+			}
+		}
+		// Tally up the new vote from the validator
+		var authorize bool
+		switch {
+		case header.Nonce == nonceAuthVote:
+			authorize = true
+		case header.Nonce == nonceDropVote:
+			authorize = false
+		default:
+			return nil, errInvalidVote
+		}
+		if snap.cast(header.Coinbase, authorize) {
+			snap.Votes = append(snap.Votes, &Vote{
+				Validator: validator,
+				Block:     number,
+				Address:   header.Coinbase,
+				Authorize: authorize,
+			})
+		}
+		// If the vote passed, update the list of validators
+		if tally := snap.Tally[header.Coinbase]; tally.Votes > valSet.Size()/2 {
+			if tally.Authorize {
+				valSet.AddValidator(header.Coinbase)
+			} else {
+				valSet.RemoveValidator(header.Coinbase)
+
+				// Discard any previous votes the deauthorized validator cast
+				for i := 0; i < len(snap.Votes); i++ {
+					if snap.Votes[i].Validator == header.Coinbase {
+						snap.uncast(snap.Votes[i].Address, snap.Votes[i].Authorize)
+						snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
+						i--
+					}
+				}
+			}
+			// Discard any previous votes around the just changed account
+			for i := 0; i < len(snap.Votes); i++ {
+				if snap.Votes[i].Address == header.Coinbase {
+					snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
+					i--
+				}
+			}
+			delete(snap.Tally, header.Coinbase)
+			snap.Validators = valSet.List()
+		}
+	}
+	snap.Number += uint64(len(headers))
+	snap.Hash = headers[len(headers)-1].Hash()
+
+	return snap, nil
+}
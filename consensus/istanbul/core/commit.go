@@ -0,0 +1,69 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// sendCommit votes to commit the proposal of the current round, attaching the
+// committed seal that ends up in the block header.
+func (c *core) sendCommit() {
+	subject := c.current.Subject()
+	if subject == nil {
+		return
+	}
+	payload, err := rlp.EncodeToBytes(subject)
+	if err != nil {
+		c.logger.Error("Failed to encode commit", "err", err)
+		return
+	}
+	seal, err := c.backend.Sign(istanbul.CommitSealData(subject.Digest))
+	if err != nil {
+		c.logger.Error("Failed to sign committed seal", "err", err)
+		return
+	}
+	c.broadcast(&message{Code: msgCommit, Msg: payload, CommittedSeal: seal})
+}
+
+// handleCommit collects a commit vote, committing the proposal once a quorum of
+// validators sealed it.
+func (c *core) handleCommit(msg *message) error {
+	var subject istanbul.Subject
+	if err := msg.decode(&subject); err != nil {
+		return errInvalidMessage
+	}
+	if err := c.checkMessage(msgCommit, subject.View); err != nil {
+		return err
+	}
+	if err := c.verifySubject(&subject); err != nil {
+		return err
+	}
+	signer, err := istanbul.RecoverSigner(istanbul.CommitSealData(subject.Digest), msg.CommittedSeal)
+	if err != nil || signer != msg.Address {
+		return errInvalidCommittedSeal
+	}
+	if err := c.current.commits.Add(msg); err != nil {
+		return err
+	}
+	if c.current.commits.Size() >= c.valSet.QuorumSize() && c.state < StateCommitted {
+		c.current.lock()
+		c.commit()
+	}
+	return nil
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package core implements the Istanbul byzantine fault tolerant consensus
+// protocol: a three phase (pre-prepare, prepare, commit) agreement on each block
+// among the validators, with round changes to replace a faulty proposer.
+package core
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/event"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// defaultRequestTimeout is the round timeout used if none is configured.
+const defaultRequestTimeout = 10000
+
+var (
+	// errInvalidMessage is returned if a message can't be decoded.
+	errInvalidMessage = errors.New("invalid message")
+
+	// errInvalidSigner is returned if a message isn't signed by its sender.
+	errInvalidSigner = errors.New("message not signed by the sender")
+
+	// errFutureMessage is returned if a message belongs to a view ahead of the
+	// current one.
+	errFutureMessage = errors.New("future message")
+
+	// errOldMessage is returned if a message belongs to a view behind the
+	// current one.
+	errOldMessage = errors.New("old message")
+
+	// errNotFromProposer is returned if a proposal isn't sent by the proposer of
+	// the round.
+	errNotFromProposer = errors.New("message does not come from proposer")
+
+	// errInconsistentSubject is returned if a vote doesn't match the subject of
+	// the current round.
+	errInconsistentSubject = errors.New("inconsistent subjects")
+
+	// errInvalidCommittedSeal is returned if a commit carries a bad seal.
+	errInvalidCommittedSeal = errors.New("invalid committed seal")
+)
+
+// timeoutEvent is posted when a round timed out.
+type timeoutEvent struct{}
+
+// backlogEvent is posted to replay a message postponed from an earlier view.
+type backlogEvent struct {
+	msg *message
+}
+
+// Engine is the consensus protocol state machine of a validator.
+type Engine interface {
+	Start() error
+	Stop() error
+}
+
+// core is the implementation of the protocol state machine. All the state is
+// only ever accessed from the event loop.
+type core struct {
+	config  *params.IstanbulConfig
+	address common.Address
+	backend istanbul.Backend
+	logger  log.Logger
+
+	events *event.TypeMuxSubscription
+	quit   chan struct{}
+	wg     sync.WaitGroup
+
+	state                 State
+	valSet                *istanbul.ValidatorSet
+	current               *roundState
+	roundChangeSet        *roundChangeSet
+	roundChangeTimer      *time.Timer
+	waitingForRoundChange bool
+
+	pendingRequest *types.Block                  // Latest block requested to be sealed
+	backlogs       map[common.Address][]*message // Messages of future views per sender
+}
+
+// New creates an Istanbul consensus state machine on top of a backend.
+func New(backend istanbul.Backend, config *params.IstanbulConfig) Engine {
+	return &core{
+		config:   config,
+		address:  backend.Address(),
+		backend:  backend,
+		logger:   log.New("address", backend.Address()),
+		state:    StateAcceptRequest,
+		backlogs: make(map[common.Address][]*message),
+	}
+}
+
+// Start launches the protocol, starting the first round on top of the head.
+func (c *core) Start() error {
+	c.quit = make(chan struct{})
+	c.events = c.backend.EventMux().Subscribe(
+		istanbul.RequestEvent{},
+		istanbul.MessageEvent{},
+		istanbul.FinalCommittedEvent{},
+		backlogEvent{},
+		timeoutEvent{},
+	)
+	c.startNewRound(common.Big0)
+
+	c.wg.Add(1)
+	go c.loop()
+	return nil
+}
+
+// Stop terminates the protocol.
+func (c *core) Stop() error {
+	c.events.Unsubscribe()
+	close(c.quit)
+	c.wg.Wait()
+
+	c.stopTimer()
+	return nil
+}
+
+// loop is the event loop of the protocol.
+func (c *core) loop() {
+	defer c.wg.Done()
+
+	for obj := range c.events.Chan() {
+		switch ev := obj.Data.(type) {
+		case istanbul.RequestEvent:
+			c.handleRequest(ev.Proposal)
+
+		case istanbul.MessageEvent:
+			if err := c.handleMsg(ev.Payload); err != nil {
+				c.logger.Trace("Failed to handle consensus message", "err", err)
+			}
+		case backlogEvent:
+			if err := c.handleCheckedMsg(ev.msg); err != nil {
+				c.logger.Trace("Failed to handle backlogged message", "err", err)
+			} else {
+				c.gossip(ev.msg, nil)
+			}
+		case timeoutEvent:
+			c.handleTimeout()
+
+		case istanbul.FinalCommittedEvent:
+			c.startNewRound(common.Big0)
+		}
+	}
+}
+
+// sendEvent posts an event to the loop from outside of it.
+func (c *core) sendEvent(ev interface{}) {
+	go func() {
+		select {
+		case <-c.quit:
+		default:
+			c.backend.EventMux().Post(ev)
+		}
+	}()
+}
+
+// broadcast signs a message and sends it to all validators.
+func (c *core) broadcast(msg *message) {
+	msg.Address = c.address
+
+	data, err := msg.payloadNoSig()
+	if err != nil {
+		c.logger.Error("Failed to encode consensus message", "err", err)
+		return
+	}
+	if msg.Signature, err = c.backend.Sign(data); err != nil {
+		c.logger.Error("Failed to sign consensus message", "err", err)
+		return
+	}
+	payload, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		c.logger.Error("Failed to encode consensus message", "err", err)
+		return
+	}
+	if err := c.backend.Broadcast(c.valSet, payload); err != nil {
+		c.logger.Error("Failed to broadcast consensus message", "err", err)
+	}
+}
+
+// currentView returns the view of the current round.
+func (c *core) currentView() *istanbul.View {
+	return c.current.View()
+}
+
+// isProposer reports if the local validator proposes in the current round.
+func (c *core) isProposer() bool {
+	return c.valSet != nil && c.valSet.IsProposer(c.address)
+}
+
+// commit hands the agreed proposal with its seals to the backend.
+func (c *core) commit() {
+	c.setState(StateCommitted)
+
+	proposal := c.current.Proposal()
+	if proposal == nil {
+		return
+	}
+	seals := make([][]byte, 0, c.current.commits.Size())
+	for _, msg := range c.current.commits.Values() {
+		seals = append(seals, msg.CommittedSeal)
+	}
+	if err := c.backend.Commit(proposal, seals); err != nil {
+		c.current.unlock()
+		c.sendNextRoundChange()
+	}
+}
+
+// startNewRound starts a new round, either as the first round of the sequence
+// after the current head or as a round change within the current sequence.
+func (c *core) startNewRound(round *big.Int) {
+	lastProposal, lastProposer := c.backend.LastProposal()
+
+	roundChange := false
+	switch {
+	case c.current == nil:
+		c.logger.Trace("Start the initial round")
+	case lastProposal.Number().Cmp(c.current.sequence) >= 0:
+		c.logger.Trace("Catch up to the latest proposal", "number", lastProposal.Number())
+	case lastProposal.Number().Cmp(new(big.Int).Sub(c.current.sequence, common.Big1)) == 0:
+		if round.Sign() == 0 || round.Cmp(c.current.round) < 0 {
+			// Same sequence and not a later round, nothing to do
+			return
+		}
+		roundChange = true
+	default:
+		c.logger.Warn("New sequence should be larger than the current one")
+		return
+	}
+	var view *istanbul.View
+	if roundChange {
+		view = &istanbul.View{
+			Sequence: new(big.Int).Set(c.current.sequence),
+			Round:    new(big.Int).Set(round),
+		}
+	} else {
+		view = &istanbul.View{
+			Sequence: new(big.Int).Add(lastProposal.Number(), common.Big1),
+			Round:    new(big.Int),
+		}
+		c.valSet = c.backend.Validators(lastProposal.NumberU64(), lastProposal.Hash())
+	}
+	c.roundChangeSet = newRoundChangeSet(c.valSet)
+	c.updateRoundState(view, roundChange)
+
+	c.valSet.CalcProposer(lastProposer, view.Round.Uint64())
+	c.waitingForRoundChange = false
+	c.setState(StateAcceptRequest)
+
+	// Propose the locked block if any, otherwise the pending request
+	if roundChange && c.current.isHashLocked() && c.current.preprepare != nil {
+		c.sendPreprepare(c.current.Proposal())
+	} else if c.pendingRequest != nil {
+		c.sendPreprepare(c.pendingRequest)
+	}
+	c.newRoundChangeTimer()
+
+	c.logger.Debug("New round", "sequence", view.Sequence, "round", view.Round, "proposer", c.valSet.Proposer(), "size", c.valSet.Size(), "isProposer", c.isProposer())
+}
+
+// catchUpRound moves to a higher round of the current sequence while waiting
+// for the other validators to agree on the round change.
+func (c *core) catchUpRound(view *istanbul.View) {
+	c.waitingForRoundChange = true
+
+	c.updateRoundState(view, true)
+	c.roundChangeSet.Clear(view.Round)
+	c.newRoundChangeTimer()
+}
+
+// updateRoundState replaces the round state, retaining the locked proposal on
+// round changes.
+func (c *core) updateRoundState(view *istanbul.View, roundChange bool) {
+	if roundChange && c.current != nil && c.current.isHashLocked() {
+		c.current = newRoundState(view, c.valSet, c.current.lockedHash, c.current.preprepare)
+		return
+	}
+	c.current = newRoundState(view, c.valSet, common.Hash{}, nil)
+}
+
+// setState transitions the round to a new phase, replaying any messages that
+// may have become processable.
+func (c *core) setState(state State) {
+	if c.state != state {
+		c.state = state
+	}
+	if state == StateAcceptRequest {
+		c.processPendingRequest()
+	}
+	c.processBacklog()
+}
+
+// processPendingRequest proposes the pending request if it's for the current
+// sequence.
+func (c *core) processPendingRequest() {
+	if c.pendingRequest != nil && c.pendingRequest.Number().Cmp(c.current.sequence) < 0 {
+		c.pendingRequest = nil
+	}
+}
+
+// stopTimer stops the round change timer.
+func (c *core) stopTimer() {
+	if c.roundChangeTimer != nil {
+		c.roundChangeTimer.Stop()
+	}
+}
+
+// newRoundChangeTimer restarts the round timeout, doubling the timeout with
+// every round to let the validators eventually synchronise.
+func (c *core) newRoundChangeTimer() {
+	c.stopTimer()
+
+	timeout := c.config.RequestTimeout
+	if timeout == 0 {
+		timeout = defaultRequestTimeout
+	}
+	wait := time.Duration(timeout) * time.Millisecond
+	if round := c.current.round.Uint64(); round > 0 {
+		wait += time.Duration(math.Pow(2, float64(round))) * time.Second
+	} else {
+		wait += time.Duration(c.config.BlockPeriod) * time.Second
+	}
+	c.roundChangeTimer = time.AfterFunc(wait, func() {
+		c.sendEvent(timeoutEvent{})
+	})
+}
+
+// checkMessage checks if a message of the given code and view can be handled
+// in the current state, returning errFutureMessage or errOldMessage otherwise.
+func (c *core) checkMessage(code uint64, view *istanbul.View) error {
+	if view == nil || view.Sequence == nil || view.Round == nil {
+		return errInvalidMessage
+	}
+	if code == msgRoundChange {
+		if view.Sequence.Cmp(c.current.sequence) > 0 {
+			return errFutureMessage
+		} else if view.Cmp(c.currentView()) < 0 {
+			return errOldMessage
+		}
+		return nil
+	}
+	if view.Cmp(c.currentView()) > 0 {
+		return errFutureMessage
+	}
+	if view.Cmp(c.currentView()) < 0 {
+		return errOldMessage
+	}
+	if c.waitingForRoundChange {
+		return errFutureMessage
+	}
+	// Only the proposal may be accepted before a proposal was made
+	if c.state == StateAcceptRequest && code > msgPreprepare {
+		return errFutureMessage
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/event"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// testBackend is an istanbul.Backend recording everything the protocol sends
+// and commits, on top of a fixed genesis head.
+type testBackend struct {
+	key     *ecdsa.PrivateKey
+	addrs   []common.Address
+	mux     *event.TypeMux
+	genesis *types.Block
+
+	sent      []*message   // Messages broadcast by the local validator
+	committed *types.Block // Last block handed over for committing
+	seals     [][]byte     // Committed seals of the last committed block
+}
+
+func (b *testBackend) Address() common.Address { return crypto.PubkeyToAddress(b.key.PublicKey) }
+
+func (b *testBackend) Validators(number uint64, hash common.Hash) *istanbul.ValidatorSet {
+	return istanbul.NewValidatorSet(b.addrs, istanbul.RoundRobin)
+}
+
+func (b *testBackend) EventMux() *event.TypeMux { return b.mux }
+
+func (b *testBackend) Broadcast(valSet *istanbul.ValidatorSet, payload []byte) error {
+	msg, err := decodeMessage(payload)
+	if err != nil {
+		return err
+	}
+	b.sent = append(b.sent, msg)
+	return nil
+}
+
+func (b *testBackend) Gossip(valSet *istanbul.ValidatorSet, payload []byte) error { return nil }
+
+func (b *testBackend) Commit(proposal *types.Block, seals [][]byte) error {
+	b.committed, b.seals = proposal, seals
+	return nil
+}
+
+func (b *testBackend) Verify(proposal *types.Block) (time.Duration, error) { return 0, nil }
+
+func (b *testBackend) Sign(data []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), b.key)
+}
+
+func (b *testBackend) LastProposal() (*types.Block, common.Address) {
+	return b.genesis, common.Address{}
+}
+
+// lastSent returns the code of the last message broadcast, or -1 if none.
+func (b *testBackend) lastSent() int {
+	if len(b.sent) == 0 {
+		return -1
+	}
+	return int(b.sent[len(b.sent)-1].Code)
+}
+
+// testValidators is a set of four validators running the protocol from the
+// point of view of one of them, which doesn't propose in the first two rounds.
+type testValidators struct {
+	keys    map[common.Address]*ecdsa.PrivateKey
+	others  []common.Address // Validators other than the local one, proposers first
+	core    *core
+	backend *testBackend
+}
+
+func newTestValidators(t *testing.T) *testValidators {
+	var (
+		keys  = make(map[common.Address]*ecdsa.PrivateKey)
+		addrs []common.Address
+	)
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr], addrs = key, append(addrs, addr)
+	}
+	// Pick the local validator among the ones not proposing in rounds 0 and 1
+	valSet := istanbul.NewValidatorSet(addrs, istanbul.RoundRobin)
+	var others []common.Address
+	for round := uint64(0); round < 2; round++ {
+		valSet.CalcProposer(common.Address{}, round)
+		others = append(others, valSet.Proposer())
+	}
+	var local common.Address
+	for _, addr := range valSet.List() {
+		if addr != others[0] && addr != others[1] {
+			if local == (common.Address{}) {
+				local = addr
+			} else {
+				others = append(others, addr)
+			}
+		}
+	}
+	backend := &testBackend{
+		key:     keys[local],
+		addrs:   addrs,
+		mux:     new(event.TypeMux),
+		genesis: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)}),
+	}
+	c := New(backend, &params.IstanbulConfig{RequestTimeout: 3600 * 1000}).(*core)
+	c.startNewRound(common.Big0)
+
+	return &testValidators{keys: keys, others: others, core: c, backend: backend}
+}
+
+// close stops the round timer of the protocol.
+func (tv *testValidators) close() {
+	tv.core.stopTimer()
+	tv.backend.mux.Stop()
+}
+
+// send delivers a message signed by the given validator to the protocol.
+func (tv *testValidators) send(t *testing.T, from common.Address, code uint64, val interface{}, seal []byte) error {
+	payload, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		t.Fatalf("failed to encode message: %v", err)
+	}
+	msg := &message{Code: code, Msg: payload, Address: from, Signature: []byte{}, CommittedSeal: seal}
+	if msg.CommittedSeal == nil {
+		msg.CommittedSeal = []byte{}
+	}
+	data, _ := msg.payloadNoSig()
+	msg.Signature, _ = crypto.Sign(crypto.Keccak256(data), tv.keys[from])
+
+	blob, _ := rlp.EncodeToBytes(msg)
+	return tv.core.handleMsg(blob)
+}
+
+// propose delivers a proposal of a block from the given proposer.
+func (tv *testValidators) propose(t *testing.T, from common.Address, round int64, block *types.Block) error {
+	view := &istanbul.View{Sequence: big.NewInt(1), Round: big.NewInt(round)}
+	return tv.send(t, from, msgPreprepare, &istanbul.Preprepare{View: view, Proposal: block}, nil)
+}
+
+// vote delivers a prepare or commit of a block from the given validator.
+func (tv *testValidators) vote(t *testing.T, from common.Address, code uint64, round int64, block *types.Block) error {
+	subject := &istanbul.Subject{
+		View:   &istanbul.View{Sequence: big.NewInt(1), Round: big.NewInt(round)},
+		Digest: block.Hash(),
+	}
+	var seal []byte
+	if code == msgCommit {
+		seal, _ = crypto.Sign(crypto.Keccak256(istanbul.CommitSealData(block.Hash())), tv.keys[from])
+	}
+	return tv.send(t, from, code, subject, seal)
+}
+
+// roundChange delivers a round change request from the given validator.
+func (tv *testValidators) roundChange(t *testing.T, from common.Address, round int64) error {
+	view := &istanbul.View{Sequence: big.NewInt(1), Round: big.NewInt(round)}
+	return tv.send(t, from, msgRoundChange, &istanbul.Subject{View: view}, nil)
+}
+
+// newTestBlock creates a block to agree on, distinguished by its extra-data.
+func newTestBlock(extra string) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Extra: []byte(extra)})
+}
+
+// Tests that a block is only prepared and committed once a quorum of distinct
+// validators voted for it, and that the committed seals are handed over.
+func TestQuorum(t *testing.T) {
+	tv := newTestValidators(t)
+	defer tv.close()
+
+	block := newTestBlock("block")
+	if err := tv.propose(t, tv.others[0], 0, block); err != nil {
+		t.Fatalf("failed to handle proposal: %v", err)
+	}
+	if tv.core.state != StatePreprepared || tv.backend.lastSent() != int(msgPrepare) {
+		t.Fatalf("proposal not accepted: state %v, last sent %d", tv.core.state, tv.backend.lastSent())
+	}
+	// Votes of a single validator count once, less than a quorum isn't enough
+	for i := 0; i < 2; i++ {
+		if err := tv.vote(t, tv.others[0], msgPrepare, 0, block); err != nil {
+			t.Fatalf("failed to handle prepare: %v", err)
+		}
+	}
+	if err := tv.vote(t, tv.others[1], msgPrepare, 0, block); err != nil {
+		t.Fatalf("failed to handle prepare: %v", err)
+	}
+	if tv.core.state != StatePreprepared {
+		t.Fatalf("prepared without quorum: state %v", tv.core.state)
+	}
+	if err := tv.vote(t, tv.others[2], msgPrepare, 0, block); err != nil {
+		t.Fatalf("failed to handle prepare: %v", err)
+	}
+	if tv.core.state != StatePrepared || tv.backend.lastSent() != int(msgCommit) {
+		t.Fatalf("not prepared with quorum: state %v, last sent %d", tv.core.state, tv.backend.lastSent())
+	}
+	if tv.core.current.lockedHash != block.Hash() {
+		t.Fatalf("prepared block not locked")
+	}
+	// Commits carrying a seal over anything else are rejected
+	other := newTestBlock("other")
+	subject := &istanbul.Subject{View: tv.core.currentView(), Digest: block.Hash()}
+	badSeal, _ := crypto.Sign(crypto.Keccak256(istanbul.CommitSealData(other.Hash())), tv.keys[tv.others[0]])
+	if err := tv.send(t, tv.others[0], msgCommit, subject, badSeal); err != errInvalidCommittedSeal {
+		t.Fatalf("bad committed seal: have %v, want %v", err, errInvalidCommittedSeal)
+	}
+	for i, addr := range tv.others {
+		if tv.backend.committed != nil {
+			t.Fatalf("committed after %d commits", i)
+		}
+		if err := tv.vote(t, addr, msgCommit, 0, block); err != nil {
+			t.Fatalf("failed to handle commit: %v", err)
+		}
+	}
+	if tv.backend.committed == nil || tv.backend.committed.Hash() != block.Hash() {
+		t.Fatalf("agreed block not committed")
+	}
+	if len(tv.backend.seals) != tv.core.valSet.QuorumSize() {
+		t.Fatalf("committed seal count mismatch: have %d, want %d", len(tv.backend.seals), tv.core.valSet.QuorumSize())
+	}
+}
+
+// Tests that a validator catches up with a round change requested by F+1 others
+// and only moves to a new round once a quorum requested it.
+func TestRoundChange(t *testing.T) {
+	tv := newTestValidators(t)
+	defer tv.close()
+
+	// A round change requested by too few validators is only recorded
+	if err := tv.roundChange(t, tv.others[0], 1); err != nil {
+		t.Fatalf("failed to handle round change: %v", err)
+	}
+	if round := tv.core.current.round.Uint64(); round != 0 {
+		t.Fatalf("round changed without quorum: round %d", round)
+	}
+	// Timing out requests the next round and waits for the others
+	tv.core.handleTimeout()
+	if !tv.core.waitingForRoundChange || tv.core.current.round.Uint64() != 1 || tv.backend.lastSent() != int(msgRoundChange) {
+		t.Fatalf("timeout didn't request a round change: waiting %v, round %d", tv.core.waitingForRoundChange, tv.core.current.round)
+	}
+	// F+1 validators asking for a later round pull the waiting validator along
+	for _, addr := range tv.others[:tv.core.valSet.F()+1] {
+		if err := tv.roundChange(t, addr, 2); err != nil {
+			t.Fatalf("failed to handle round change: %v", err)
+		}
+	}
+	if !tv.core.waitingForRoundChange || tv.core.current.round.Uint64() != 2 {
+		t.Fatalf("didn't catch up with round change: waiting %v, round %d", tv.core.waitingForRoundChange, tv.core.current.round)
+	}
+	// A quorum starts the round
+	if err := tv.roundChange(t, tv.others[2], 2); err != nil {
+		t.Fatalf("failed to handle round change: %v", err)
+	}
+	if tv.core.waitingForRoundChange || tv.core.current.round.Uint64() != 2 || tv.core.state != StateAcceptRequest {
+		t.Fatalf("round not started: waiting %v, round %d, state %v", tv.core.waitingForRoundChange, tv.core.current.round, tv.core.state)
+	}
+	// Round changes of older rounds are rejected
+	if err := tv.roundChange(t, tv.others[0], 1); err != errOldMessage {
+		t.Fatalf("old round change: have %v, want %v", err, errOldMessage)
+	}
+}
+
+// Tests that a validator locked on a block in an earlier round refuses to
+// prepare any other proposal, but commits the locked block straight away.
+func TestLockedProposal(t *testing.T) {
+	tv := newTestValidators(t)
+	defer tv.close()
+
+	// Prepare and lock a block in round 0 without committing it
+	block := newTestBlock("block")
+	if err := tv.propose(t, tv.others[0], 0, block); err != nil {
+		t.Fatalf("failed to handle proposal: %v", err)
+	}
+	for _, addr := range tv.others {
+		if err := tv.vote(t, addr, msgPrepare, 0, block); err != nil {
+			t.Fatalf("failed to handle prepare: %v", err)
+		}
+	}
+	if tv.core.current.lockedHash != block.Hash() {
+		t.Fatalf("prepared block not locked")
+	}
+	// Move to round 1, the lock must survive the round change
+	for _, addr := range tv.others {
+		if err := tv.roundChange(t, addr, 1); err != nil {
+			t.Fatalf("failed to handle round change: %v", err)
+		}
+	}
+	if round := tv.core.current.round.Uint64(); round != 1 {
+		t.Fatalf("round mismatch: have %d, want 1", round)
+	}
+	if tv.core.current.lockedHash != block.Hash() {
+		t.Fatalf("lock lost on round change")
+	}
+	// A different proposal is answered by a round change instead of a prepare
+	sent := len(tv.backend.sent)
+	if err := tv.propose(t, tv.others[1], 1, newTestBlock("other")); err != nil {
+		t.Fatalf("failed to handle proposal: %v", err)
+	}
+	if len(tv.backend.sent) == sent || tv.backend.lastSent() != int(msgRoundChange) {
+		t.Fatalf("conflicting proposal not refused: last sent %d", tv.backend.lastSent())
+	}
+	if tv.core.current.round.Uint64() != 2 || tv.core.current.lockedHash != block.Hash() {
+		t.Fatalf("lock not retained after refusal: round %d", tv.core.current.round)
+	}
+}
+
+// Tests that a validator locked on a block commits it directly when it's
+// proposed again in a later round.
+func TestLockedReproposal(t *testing.T) {
+	tv := newTestValidators(t)
+	defer tv.close()
+
+	block := newTestBlock("block")
+	if err := tv.propose(t, tv.others[0], 0, block); err != nil {
+		t.Fatalf("failed to handle proposal: %v", err)
+	}
+	for _, addr := range tv.others {
+		if err := tv.vote(t, addr, msgPrepare, 0, block); err != nil {
+			t.Fatalf("failed to handle prepare: %v", err)
+		}
+	}
+	for _, addr := range tv.others {
+		if err := tv.roundChange(t, addr, 1); err != nil {
+			t.Fatalf("failed to handle round change: %v", err)
+		}
+	}
+	if err := tv.propose(t, tv.others[1], 1, block); err != nil {
+		t.Fatalf("failed to handle proposal: %v", err)
+	}
+	if tv.core.state != StatePrepared || tv.backend.lastSent() != int(msgCommit) {
+		t.Fatalf("locked block not committed: state %v, last sent %d", tv.core.state, tv.backend.lastSent())
+	}
+}
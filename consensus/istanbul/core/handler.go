@@ -0,0 +1,170 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// maxBacklog is the maximum number of future messages retained per validator.
+const maxBacklog = 1024
+
+// handleRequest records a block to be sealed, proposing it right away if the
+// local validator is the proposer of the round.
+func (c *core) handleRequest(proposal *types.Block) {
+	if proposal.Number().Cmp(c.current.sequence) < 0 {
+		c.logger.Trace("Dropping stale sealing request", "number", proposal.Number(), "sequence", c.current.sequence)
+		return
+	}
+	c.pendingRequest = proposal
+
+	if c.state == StateAcceptRequest && !c.waitingForRoundChange {
+		c.sendPreprepare(proposal)
+	}
+}
+
+// handleMsg authenticates a consensus message and dispatches it, relaying it
+// to the other validators if it was accepted.
+func (c *core) handleMsg(payload []byte) error {
+	msg, err := decodeMessage(payload)
+	if err != nil {
+		return err
+	}
+	if !c.valSet.Contains(msg.Address) {
+		return istanbul.ErrUnauthorizedAddress
+	}
+	if err := c.handleCheckedMsg(msg); err != nil {
+		return err
+	}
+	c.gossip(msg, payload)
+	return nil
+}
+
+// gossip relays a message from another validator that passed all checks. The
+// local messages are already sent out when broadcast.
+func (c *core) gossip(msg *message, payload []byte) {
+	if msg.Address == c.address {
+		return
+	}
+	if payload == nil {
+		var err error
+		if payload, err = rlp.EncodeToBytes(msg); err != nil {
+			return
+		}
+	}
+	if err := c.backend.Gossip(c.valSet, payload); err != nil {
+		c.logger.Debug("Failed to gossip consensus message", "err", err)
+	}
+}
+
+// handleCheckedMsg dispatches an authenticated message, postponing it if it's
+// for a future view.
+func (c *core) handleCheckedMsg(msg *message) error {
+	var err error
+	switch msg.Code {
+	case msgPreprepare:
+		err = c.handlePreprepare(msg)
+	case msgPrepare:
+		err = c.handlePrepare(msg)
+	case msgCommit:
+		err = c.handleCommit(msg)
+	case msgRoundChange:
+		err = c.handleRoundChange(msg)
+	default:
+		return errInvalidMessage
+	}
+	if err == errFutureMessage {
+		c.storeBacklog(msg)
+	}
+	return err
+}
+
+// handleTimeout requests a round change after a round timed out, or catches up
+// with the round most validators already moved to.
+func (c *core) handleTimeout() {
+	if !c.waitingForRoundChange {
+		if max := c.roundChangeSet.MaxRound(c.valSet.F() + 1); max != nil && max.Cmp(c.current.round) > 0 {
+			c.sendRoundChange(max)
+			return
+		}
+	}
+	if lastProposal, _ := c.backend.LastProposal(); lastProposal != nil && lastProposal.Number().Cmp(c.current.sequence) >= 0 {
+		c.startNewRound(common.Big0)
+		return
+	}
+	c.sendNextRoundChange()
+}
+
+// storeBacklog postpones a message of a future view.
+func (c *core) storeBacklog(msg *message) {
+	if msg.Address == c.address {
+		return
+	}
+	backlog := c.backlogs[msg.Address]
+	if len(backlog) >= maxBacklog {
+		backlog = backlog[1:]
+	}
+	c.backlogs[msg.Address] = append(backlog, msg)
+}
+
+// processBacklog replays the postponed messages that became processable and
+// drops the ones that became stale.
+func (c *core) processBacklog() {
+	for addr, backlog := range c.backlogs {
+		if !c.valSet.Contains(addr) {
+			delete(c.backlogs, addr)
+			continue
+		}
+		var remaining []*message
+		for _, msg := range backlog {
+			view, err := msg.view()
+			if err == nil {
+				err = c.checkMessage(msg.Code, view)
+			}
+			switch err {
+			case nil:
+				c.sendEvent(backlogEvent{msg: msg})
+			case errFutureMessage:
+				remaining = append(remaining, msg)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(c.backlogs, addr)
+		} else {
+			c.backlogs[addr] = remaining
+		}
+	}
+}
+
+// view extracts the view a message belongs to.
+func (m *message) view() (*istanbul.View, error) {
+	if m.Code == msgPreprepare {
+		var preprepare istanbul.Preprepare
+		if err := m.decode(&preprepare); err != nil {
+			return nil, err
+		}
+		return preprepare.View, nil
+	}
+	var subject istanbul.Subject
+	if err := m.decode(&subject); err != nil {
+		return nil, err
+	}
+	return subject.View, nil
+}
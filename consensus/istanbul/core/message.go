@@ -0,0 +1,177 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// Consensus message codes.
+const (
+	msgPreprepare uint64 = iota
+	msgPrepare
+	msgCommit
+	msgRoundChange
+)
+
+// message is the signed envelope of all consensus messages.
+type message struct {
+	Code          uint64
+	Msg           []byte
+	Address       common.Address
+	Signature     []byte
+	CommittedSeal []byte
+}
+
+// payloadNoSig returns the encoding of the message covered by the signature.
+func (m *message) payloadNoSig() ([]byte, error) {
+	return rlp.EncodeToBytes(&message{
+		Code:          m.Code,
+		Msg:           m.Msg,
+		Address:       m.Address,
+		Signature:     []byte{},
+		CommittedSeal: m.CommittedSeal,
+	})
+}
+
+// decodeMessage parses a signed message envelope and authenticates its sender.
+func decodeMessage(payload []byte) (*message, error) {
+	msg := new(message)
+	if err := rlp.DecodeBytes(payload, msg); err != nil {
+		return nil, errInvalidMessage
+	}
+	data, err := msg.payloadNoSig()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := istanbul.RecoverSigner(data, msg.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if signer != msg.Address {
+		return nil, errInvalidSigner
+	}
+	return msg, nil
+}
+
+// decode parses the inner payload of the message.
+func (m *message) decode(val interface{}) error {
+	return rlp.DecodeBytes(m.Msg, val)
+}
+
+// messageSet collects the votes of distinct validators on the same view.
+type messageSet struct {
+	view     *istanbul.View
+	valSet   *istanbul.ValidatorSet
+	messages map[common.Address]*message
+	lock     sync.RWMutex
+}
+
+func newMessageSet(valSet *istanbul.ValidatorSet) *messageSet {
+	return &messageSet{
+		view:     &istanbul.View{Round: new(big.Int), Sequence: new(big.Int)},
+		valSet:   valSet,
+		messages: make(map[common.Address]*message),
+	}
+}
+
+// Add inserts a message from a validator into the set.
+func (ms *messageSet) Add(msg *message) error {
+	if !ms.valSet.Contains(msg.Address) {
+		return istanbul.ErrUnauthorizedAddress
+	}
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	ms.messages[msg.Address] = msg
+	return nil
+}
+
+// Values returns all the messages in the set.
+func (ms *messageSet) Values() []*message {
+	ms.lock.RLock()
+	defer ms.lock.RUnlock()
+
+	result := make([]*message, 0, len(ms.messages))
+	for _, msg := range ms.messages {
+		result = append(result, msg)
+	}
+	return result
+}
+
+// Size returns the number of distinct validators that voted.
+func (ms *messageSet) Size() int {
+	ms.lock.RLock()
+	defer ms.lock.RUnlock()
+
+	return len(ms.messages)
+}
+
+// roundChangeSet collects the round change requests per round.
+type roundChangeSet struct {
+	valSet *istanbul.ValidatorSet
+	rounds map[uint64]*messageSet
+}
+
+func newRoundChangeSet(valSet *istanbul.ValidatorSet) *roundChangeSet {
+	return &roundChangeSet{
+		valSet: valSet,
+		rounds: make(map[uint64]*messageSet),
+	}
+}
+
+// Add inserts a round change request, returning the number of requests for the
+// same round.
+func (rcs *roundChangeSet) Add(round *big.Int, msg *message) (int, error) {
+	r := round.Uint64()
+	if rcs.rounds[r] == nil {
+		rcs.rounds[r] = newMessageSet(rcs.valSet)
+	}
+	if err := rcs.rounds[r].Add(msg); err != nil {
+		return 0, err
+	}
+	return rcs.rounds[r].Size(), nil
+}
+
+// Clear drops all requests for rounds below the given one.
+func (rcs *roundChangeSet) Clear(round *big.Int) {
+	for r, set := range rcs.rounds {
+		if set.Size() == 0 || r < round.Uint64() {
+			delete(rcs.rounds, r)
+		}
+	}
+}
+
+// MaxRound returns the highest round requested by at least num validators, or
+// nil if there is none.
+func (rcs *roundChangeSet) MaxRound(num int) *big.Int {
+	var max *big.Int
+	for r, set := range rcs.rounds {
+		if set.Size() < num {
+			continue
+		}
+		if round := new(big.Int).SetUint64(r); max == nil || max.Cmp(round) < 0 {
+			max = round
+		}
+	}
+	return max
+}
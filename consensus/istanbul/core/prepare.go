@@ -0,0 +1,66 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// sendPrepare votes for the proposal of the current round.
+func (c *core) sendPrepare() {
+	payload, err := rlp.EncodeToBytes(c.current.Subject())
+	if err != nil {
+		c.logger.Error("Failed to encode prepare", "err", err)
+		return
+	}
+	c.broadcast(&message{Code: msgPrepare, Msg: payload})
+}
+
+// handlePrepare collects a prepare vote, moving to the commit phase once a
+// quorum of validators prepared the proposal.
+func (c *core) handlePrepare(msg *message) error {
+	var subject istanbul.Subject
+	if err := msg.decode(&subject); err != nil {
+		return errInvalidMessage
+	}
+	if err := c.checkMessage(msgPrepare, subject.View); err != nil {
+		return err
+	}
+	if err := c.verifySubject(&subject); err != nil {
+		return err
+	}
+	if err := c.current.prepares.Add(msg); err != nil {
+		return err
+	}
+	locked := c.current.isHashLocked() && subject.Digest == c.current.lockedHash
+	if (locked || c.current.prepareOrCommitSize() >= c.valSet.QuorumSize()) && c.state < StatePrepared {
+		c.current.lock()
+		c.setState(StatePrepared)
+		c.sendCommit()
+	}
+	return nil
+}
+
+// verifySubject checks that a vote is cast on the subject of the current round.
+func (c *core) verifySubject(subject *istanbul.Subject) error {
+	current := c.current.Subject()
+	if current == nil || subject.Digest != current.Digest || subject.View.Cmp(current.View) != 0 {
+		return errInconsistentSubject
+	}
+	return nil
+}
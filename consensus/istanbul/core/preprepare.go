@@ -0,0 +1,93 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// sendPreprepare proposes a block to the validators if the local validator is
+// the proposer of the current round.
+func (c *core) sendPreprepare(request *types.Block) {
+	if request.Number().Cmp(c.current.sequence) != 0 || !c.isProposer() {
+		return
+	}
+	payload, err := rlp.EncodeToBytes(&istanbul.Preprepare{
+		View:     c.currentView(),
+		Proposal: request,
+	})
+	if err != nil {
+		c.logger.Error("Failed to encode preprepare", "err", err)
+		return
+	}
+	c.broadcast(&message{Code: msgPreprepare, Msg: payload})
+}
+
+// handlePreprepare accepts a block proposed by the proposer of the round and
+// votes on it.
+func (c *core) handlePreprepare(msg *message) error {
+	var preprepare istanbul.Preprepare
+	if err := msg.decode(&preprepare); err != nil || preprepare.Proposal == nil {
+		return errInvalidMessage
+	}
+	if err := c.checkMessage(msgPreprepare, preprepare.View); err != nil {
+		return err
+	}
+	if !c.valSet.IsProposer(msg.Address) {
+		return errNotFromProposer
+	}
+	if duration, err := c.backend.Verify(preprepare.Proposal); err != nil {
+		// Retry proposals from the near future once they become valid
+		if err == consensus.ErrFutureBlock {
+			time.AfterFunc(duration, func() {
+				c.sendEvent(backlogEvent{msg: msg})
+			})
+			return err
+		}
+		c.logger.Warn("Failed to verify proposal", "number", preprepare.Proposal.Number(), "err", err)
+		c.sendNextRoundChange()
+		return err
+	}
+	if c.state != StateAcceptRequest {
+		return nil
+	}
+	if c.current.isHashLocked() {
+		// A locked validator may only vote for the locked proposal again
+		if preprepare.Proposal.Hash() != c.current.lockedHash {
+			c.sendNextRoundChange()
+			return nil
+		}
+		c.acceptPreprepare(&preprepare)
+		c.setState(StatePrepared)
+		c.sendCommit()
+		return nil
+	}
+	c.acceptPreprepare(&preprepare)
+	c.setState(StatePreprepared)
+	c.sendPrepare()
+	return nil
+}
+
+// acceptPreprepare records the proposal of the current round.
+func (c *core) acceptPreprepare(preprepare *istanbul.Preprepare) {
+	c.current.preprepare = preprepare
+}
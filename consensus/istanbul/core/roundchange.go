@@ -0,0 +1,76 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// sendNextRoundChange requests a change to the round after the current one.
+func (c *core) sendNextRoundChange() {
+	c.sendRoundChange(new(big.Int).Add(c.current.round, common.Big1))
+}
+
+// sendRoundChange moves to the given round and asks the other validators to
+// follow.
+func (c *core) sendRoundChange(round *big.Int) {
+	if c.current.round.Cmp(round) >= 0 {
+		return
+	}
+	view := &istanbul.View{
+		Round:    new(big.Int).Set(round),
+		Sequence: new(big.Int).Set(c.current.sequence),
+	}
+	c.catchUpRound(view)
+
+	payload, err := rlp.EncodeToBytes(&istanbul.Subject{View: view})
+	if err != nil {
+		c.logger.Error("Failed to encode round change", "err", err)
+		return
+	}
+	c.broadcast(&message{Code: msgRoundChange, Msg: payload})
+}
+
+// handleRoundChange collects a round change request. Once F+1 validators ask
+// for a higher round, the local validator joins them; once a quorum agrees,
+// the new round is started.
+func (c *core) handleRoundChange(msg *message) error {
+	var subject istanbul.Subject
+	if err := msg.decode(&subject); err != nil {
+		return errInvalidMessage
+	}
+	if err := c.checkMessage(msgRoundChange, subject.View); err != nil {
+		return err
+	}
+	round := subject.View.Round
+	num, err := c.roundChangeSet.Add(round, msg)
+	if err != nil {
+		return err
+	}
+	if c.waitingForRoundChange && num == c.valSet.F()+1 && c.current.round.Cmp(round) < 0 {
+		c.sendRoundChange(round)
+		return nil
+	}
+	if num == c.valSet.QuorumSize() && (c.waitingForRoundChange || c.current.round.Cmp(round) < 0) {
+		c.startNewRound(round)
+	}
+	return nil
+}
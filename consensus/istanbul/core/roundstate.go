@@ -0,0 +1,131 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+)
+
+// State is the phase of the consensus protocol within a round.
+type State uint64
+
+const (
+	StateAcceptRequest State = iota
+	StatePreprepared
+	StatePrepared
+	StateCommitted
+)
+
+// String implements fmt.Stringer.
+func (s State) String() string {
+	switch s {
+	case StateAcceptRequest:
+		return "Accept request"
+	case StatePreprepared:
+		return "Preprepared"
+	case StatePrepared:
+		return "Prepared"
+	case StateCommitted:
+		return "Committed"
+	default:
+		return "Unknown"
+	}
+}
+
+// roundState holds the votes collected within a single round, together with
+// the proposal the validator locked on, if any.
+type roundState struct {
+	round      *big.Int
+	sequence   *big.Int
+	preprepare *istanbul.Preprepare
+	prepares   *messageSet
+	commits    *messageSet
+	lockedHash common.Hash
+}
+
+func newRoundState(view *istanbul.View, valSet *istanbul.ValidatorSet, lockedHash common.Hash, preprepare *istanbul.Preprepare) *roundState {
+	return &roundState{
+		round:      view.Round,
+		sequence:   view.Sequence,
+		preprepare: preprepare,
+		prepares:   newMessageSet(valSet),
+		commits:    newMessageSet(valSet),
+		lockedHash: lockedHash,
+	}
+}
+
+// View returns the view of the round.
+func (s *roundState) View() *istanbul.View {
+	return &istanbul.View{
+		Round:    new(big.Int).Set(s.round),
+		Sequence: new(big.Int).Set(s.sequence),
+	}
+}
+
+// Subject returns the subject voted on in the round, or nil if nothing was
+// proposed yet.
+func (s *roundState) Subject() *istanbul.Subject {
+	if s.preprepare == nil {
+		return nil
+	}
+	return &istanbul.Subject{
+		View:   s.View(),
+		Digest: s.preprepare.Proposal.Hash(),
+	}
+}
+
+// Proposal returns the block proposed in the round, if any.
+func (s *roundState) Proposal() *types.Block {
+	if s.preprepare == nil {
+		return nil
+	}
+	return s.preprepare.Proposal
+}
+
+// lock locks the validator on the proposal of the round.
+func (s *roundState) lock() {
+	if s.preprepare != nil {
+		s.lockedHash = s.preprepare.Proposal.Hash()
+	}
+}
+
+// unlock releases the proposal lock.
+func (s *roundState) unlock() {
+	s.lockedHash = common.Hash{}
+}
+
+// isHashLocked reports if the validator is locked on a proposal.
+func (s *roundState) isHashLocked() bool {
+	return s.lockedHash != (common.Hash{})
+}
+
+// prepareOrCommitSize returns the number of distinct validators that sent a
+// prepare or a commit in the round, as a commit implies a prepare.
+func (s *roundState) prepareOrCommitSize() int {
+	voters := make(map[common.Address]struct{})
+	for _, msg := range s.prepares.Values() {
+		voters[msg.Address] = struct{}{}
+	}
+	for _, msg := range s.commits.Values() {
+		voters[msg.Address] = struct{}{}
+	}
+	return len(voters)
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+const (
+	ExtraVanity = types.IstanbulExtraVanity // Fixed number of extra-data prefix bytes reserved for validator vanity
+	ExtraSeal   = 65                        // Fixed number of bytes of a secp256k1 seal
+
+	// MsgCommit is the consensus message code of commit votes, mixed into the
+	// committed seals to separate them from any other signature.
+	MsgCommit = 2
+)
+
+// Digest is the fixed mix digest of Istanbul blocks. Headers carrying it are
+// hashed without their committed seals.
+var Digest = types.IstanbulDigest
+
+// Extra is the consensus data stored in the header extra-data after the vanity.
+type Extra = types.IstanbulExtra
+
+// ExtractExtra decodes the Istanbul consensus data from a header.
+func ExtractExtra(header *types.Header) (*Extra, error) {
+	return types.ExtractIstanbulExtra(header)
+}
+
+// WriteExtra encodes the Istanbul consensus data into a header, retaining the
+// vanity prefix of the existing extra-data.
+func WriteExtra(header *types.Header, extra *Extra) error {
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		return err
+	}
+	vanity := make([]byte, ExtraVanity)
+	copy(vanity, header.Extra)
+
+	header.Extra = append(vanity, payload...)
+	return nil
+}
+
+// FilteredHeader returns a copy of the header with the committed seals, and
+// optionally the proposer seal, removed from the extra-data.
+func FilteredHeader(header *types.Header, keepSeal bool) *types.Header {
+	if filtered := types.IstanbulFilteredHeader(header, keepSeal); filtered != nil {
+		return filtered
+	}
+	return types.CopyHeader(header)
+}
+
+// SigHash returns the hash signed by the proposer, i.e. the header hash without
+// any seals.
+func SigHash(header *types.Header) common.Hash {
+	return FilteredHeader(header, false).Hash()
+}
+
+// ProposalHash returns the digest the validators agree on, i.e. the header hash
+// including the proposer seal but without the committed seals.
+func ProposalHash(header *types.Header) common.Hash {
+	return FilteredHeader(header, true).Hash()
+}
+
+// CommitSealData returns the data signed by a committed seal of a proposal.
+func CommitSealData(hash common.Hash) []byte {
+	return append(hash.Bytes(), byte(MsgCommit))
+}
+
+// Ecrecover extracts the address of the proposer from a sealed header.
+func Ecrecover(header *types.Header) (common.Address, error) {
+	extra, err := ExtractExtra(header)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverSigner(SigHash(header).Bytes(), extra.Seal)
+}
+
+// RecoverSigner returns the address that signed the keccak256 hash of data.
+func RecoverSigner(data []byte, sig []byte) (common.Address, error) {
+	pubkey, err := crypto.SigToPub(crypto.Keccak256(data), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package istanbul contains the types shared between the Istanbul byzantine
+// fault tolerant consensus protocol and the engine driving it.
+package istanbul
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/event"
+)
+
+var (
+	// ErrUnauthorizedAddress is returned when a message or block is signed by an
+	// account that is not part of the validator set.
+	ErrUnauthorizedAddress = errors.New("unauthorized address")
+
+	// ErrStoppedEngine is returned if the engine is stopped.
+	ErrStoppedEngine = errors.New("stopped engine")
+
+	// ErrStartedEngine is returned if the engine is already started.
+	ErrStartedEngine = errors.New("started engine")
+)
+
+// Backend provides the application specific functions the consensus protocol
+// needs to agree on blocks.
+type Backend interface {
+	// Address returns the address of the local validator.
+	Address() common.Address
+
+	// Validators returns the validator set responsible for sealing the block
+	// following the given one.
+	Validators(number uint64, hash common.Hash) *ValidatorSet
+
+	// EventMux returns the event mux through which the protocol receives its
+	// requests and messages.
+	EventMux() *event.TypeMux
+
+	// Broadcast sends a message to all validators, including the local one.
+	Broadcast(valSet *ValidatorSet, payload []byte) error
+
+	// Gossip relays an authenticated message received from another validator to
+	// the peers not known to have it yet.
+	Gossip(valSet *ValidatorSet, payload []byte) error
+
+	// Commit delivers a block agreed on by the validators, together with the
+	// committed seals proving the agreement.
+	Commit(proposal *types.Block, seals [][]byte) error
+
+	// Verify checks a proposed block. If the block is from the future, the time
+	// to wait before it becomes valid is returned alongside the error.
+	Verify(proposal *types.Block) (time.Duration, error)
+
+	// Sign signs the keccak256 hash of the given data with the validator key.
+	Sign(data []byte) ([]byte, error)
+
+	// LastProposal returns the head block and its proposer.
+	LastProposal() (*types.Block, common.Address)
+}
+
+// View identifies a consensus round by the block number being agreed on and
+// the round within that sequence.
+type View struct {
+	Round    *big.Int
+	Sequence *big.Int
+}
+
+// String implements fmt.Stringer.
+func (v *View) String() string {
+	return fmt.Sprintf("{Round: %d, Sequence: %d}", v.Round.Uint64(), v.Sequence.Uint64())
+}
+
+// Cmp compares two views, ordering them by sequence first and round second.
+func (v *View) Cmp(y *View) int {
+	if c := v.Sequence.Cmp(y.Sequence); c != 0 {
+		return c
+	}
+	return v.Round.Cmp(y.Round)
+}
+
+// Preprepare is the proposal of a block by the proposer of a round.
+type Preprepare struct {
+	View     *View
+	Proposal *types.Block
+}
+
+// Subject is the payload of the prepare, commit and round change messages,
+// binding the vote to a view and a proposal digest.
+type Subject struct {
+	View   *View
+	Digest common.Hash
+}
+
+// String implements fmt.Stringer.
+func (s *Subject) String() string {
+	return fmt.Sprintf("{View: %v, Digest: %v}", s.View, s.Digest.String())
+}
+
+// RequestEvent is posted to propose a new block for sealing.
+type RequestEvent struct {
+	Proposal *types.Block
+}
+
+// MessageEvent is posted when a consensus message is received.
+type MessageEvent struct {
+	Payload []byte
+}
+
+// FinalCommittedEvent is posted when a new block was added to the local chain.
+type FinalCommittedEvent struct{}
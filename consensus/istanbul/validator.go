@@ -0,0 +1,195 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+)
+
+// ProposerPolicy selects how the proposer of a round is chosen.
+type ProposerPolicy uint64
+
+const (
+	RoundRobin ProposerPolicy = iota // Rotate the proposer after every block
+	Sticky                           // Keep the proposer until a round change
+)
+
+// ValidatorSet is the ordered set of validators sealing a block, tracking the
+// proposer of the current round.
+type ValidatorSet struct {
+	validators []common.Address // Validators in ascending order
+	policy     ProposerPolicy
+
+	proposer common.Address
+	lock     sync.RWMutex
+}
+
+// NewValidatorSet creates a validator set from the given addresses, with the
+// first validator as the initial proposer.
+func NewValidatorSet(addrs []common.Address, policy ProposerPolicy) *ValidatorSet {
+	validators := make([]common.Address, len(addrs))
+	copy(validators, addrs)
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i][:], validators[j][:]) < 0
+	})
+	set := &ValidatorSet{
+		validators: validators,
+		policy:     policy,
+	}
+	if len(validators) > 0 {
+		set.proposer = validators[0]
+	}
+	return set
+}
+
+// Size returns the number of validators.
+func (s *ValidatorSet) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.validators)
+}
+
+// List returns the validators in ascending order.
+func (s *ValidatorSet) List() []common.Address {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	list := make([]common.Address, len(s.validators))
+	copy(list, s.validators)
+	return list
+}
+
+// GetByAddress returns the index of a validator, or -1 if it's not in the set.
+func (s *ValidatorSet) GetByAddress(addr common.Address) int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for i, validator := range s.validators {
+		if validator == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains reports if an address is a member of the validator set.
+func (s *ValidatorSet) Contains(addr common.Address) bool {
+	return s.GetByAddress(addr) >= 0
+}
+
+// Proposer returns the proposer of the current round.
+func (s *ValidatorSet) Proposer() common.Address {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.proposer
+}
+
+// IsProposer reports if an address is the proposer of the current round.
+func (s *ValidatorSet) IsProposer(addr common.Address) bool {
+	return s.Proposer() == addr
+}
+
+// CalcProposer selects the proposer for the given round, based on the proposer
+// of the last block and the proposer policy.
+func (s *ValidatorSet) CalcProposer(lastProposer common.Address, round uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.validators) == 0 {
+		return
+	}
+	seed := round
+	if offset := s.index(lastProposer); offset >= 0 {
+		seed += uint64(offset)
+		if s.policy == RoundRobin {
+			seed++
+		}
+	}
+	s.proposer = s.validators[seed%uint64(len(s.validators))]
+}
+
+// index returns the position of an address in the set, or -1 if missing. The
+// caller must hold the lock.
+func (s *ValidatorSet) index(addr common.Address) int {
+	for i, validator := range s.validators {
+		if validator == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddValidator inserts a new validator into the set, returning false if it
+// was already present.
+func (s *ValidatorSet) AddValidator(addr common.Address) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.index(addr) >= 0 {
+		return false
+	}
+	s.validators = append(s.validators, addr)
+	sort.Slice(s.validators, func(i, j int) bool {
+		return bytes.Compare(s.validators[i][:], s.validators[j][:]) < 0
+	})
+	return true
+}
+
+// RemoveValidator drops a validator from the set, returning false if it wasn't
+// a member.
+func (s *ValidatorSet) RemoveValidator(addr common.Address) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	i := s.index(addr)
+	if i < 0 {
+		return false
+	}
+	s.validators = append(s.validators[:i], s.validators[i+1:]...)
+	return true
+}
+
+// Copy creates a deep copy of the validator set.
+func (s *ValidatorSet) Copy() *ValidatorSet {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	cpy := &ValidatorSet{
+		validators: make([]common.Address, len(s.validators)),
+		policy:     s.policy,
+		proposer:   s.proposer,
+	}
+	copy(cpy.validators, s.validators)
+	return cpy
+}
+
+// F returns the maximum number of faulty validators the set tolerates.
+func (s *ValidatorSet) F() int {
+	return (s.Size() - 1) / 3
+}
+
+// QuorumSize returns the number of matching votes needed to reach agreement,
+// i.e. ceil(2N/3).
+func (s *ValidatorSet) QuorumSize() int {
+	return (2*s.Size() + 2) / 3
+}
@@ -729,7 +729,11 @@ func TestConcurrentDiskCacheGeneration(t *testing.T) {
 
 		go func(idx int) {
 			defer pend.Done()
-			paaash := New(Config{cachedir, 0, 1, "", 0, 0, ModeNormal}, nil, false)
+			paaash, err := New(Config{cachedir, 0, 1, "", 0, 0, ModeNormal, ""}, nil, false)
+			if err != nil {
+				t.Errorf("proc %d: failed to create paaash: %v", idx, err)
+				return
+			}
 			defer paaash.Close()
 			if err := paaash.VerifySeal(nil, block.Header()); err != nil {
 				t.Errorf("proc %d: block verification failed: %v", idx, err)
@@ -37,6 +37,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/event"
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
@@ -50,7 +51,7 @@ var (
 	two256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
 
 	// sharedPaaash is a full instance that can be shared between multiple users.
-	sharedPaaash = New(Config{"", 3, 0, "", 1, 0, ModeNormal}, nil, false)
+	sharedPaaash = newPaaash(Config{"", 3, 0, "", 1, 0, ModeNormal, ""}, nil, false)
 
 	// algorithmRevision is the data structure version used for file naming.
 	algorithmRevision = 23
@@ -403,6 +404,10 @@ type Config struct {
 	DatasetsInMem  int
 	DatasetsOnDisk int
 	PowMode        Mode
+
+	// StratumAddr is the TCP listening address of the built-in stratum server
+	// for remote miners. The server is disabled if empty.
+	StratumAddr string `toml:",omitempty"`
 }
 
 // sealTask wraps a seal block with relative result channel for remote sealer thread.
@@ -455,6 +460,8 @@ type Paaash struct {
 	submitWorkCh chan *mineResult // Channel used for remote sealer to submit their mining result
 	fetchRateCh  chan chan uint64 // Channel used to gather submitted hash rate for local or remote sealer.
 	submitRateCh chan *hashrate   // Channel used for remote sealer to submit their mining hashrate
+	workFeed     event.Feed       // Feed of new work packages for push based remote miners
+	stratum      *stratumServer   // Stratum server for remote miners, nil if disabled
 
 	// The fields below are hooks for testing
 	shared    *Paaash       // Shared PoW verifier to avoid cache regeneration
@@ -468,8 +475,22 @@ type Paaash struct {
 
 // New creates a full sized paaash PoW scheme and starts a background thread for
 // remote mining, also optionally notifying a batch of remote services of new work
-// packages.
-func New(config Config, notify []string, noverify bool) *Paaash {
+// packages. It fails if the stratum server configured can't be started.
+func New(config Config, notify []string, noverify bool) (*Paaash, error) {
+	paaash := newPaaash(config, notify, noverify)
+
+	if config.StratumAddr != "" {
+		if err := paaash.startStratum(config.StratumAddr); err != nil {
+			paaash.Close()
+			return nil, fmt.Errorf("failed to start stratum server on %s: %v", config.StratumAddr, err)
+		}
+	}
+	return paaash, nil
+}
+
+// newPaaash creates a full sized paaash PoW scheme and starts its remote sealer,
+// but not the stratum server.
+func newPaaash(config Config, notify []string, noverify bool) *Paaash {
 	if config.CachesInMem <= 0 {
 		log.Warn("One paaash cache must always be in memory", "requested", config.CachesInMem)
 		config.CachesInMem = 1
@@ -576,6 +597,14 @@ func (paaash *Paaash) Close() error {
 		if paaash.exitCh == nil {
 			return
 		}
+		// Disconnect all stratum miners while the remote sealer still runs
+		paaash.lock.Lock()
+		stratum := paaash.stratum
+		paaash.lock.Unlock()
+
+		if stratum != nil {
+			stratum.stop()
+		}
 		errc := make(chan error)
 		paaash.exitCh <- errc
 		err = <-errc
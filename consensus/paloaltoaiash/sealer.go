@@ -314,6 +314,7 @@ func (paaash *Paaash) remote(notify []string, noverify bool) {
 
 			// Notify and requested URLs of the new work availability
 			notifyWork()
+			paaash.workFeed.Send(currentWork)
 
 		case work := <-paaash.fetchWorkCh:
 			// Return current mining work to remote miner.
@@ -0,0 +1,375 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package paaash
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
+)
+
+const (
+	// stratumMaxLineSize is the maximum size of a single request a stratum
+	// client may send before it's disconnected.
+	stratumMaxLineSize = 4096
+
+	// stratumWriteTimeout is the maximum time allowed for a single message to
+	// be written to a stratum client before it's considered dead.
+	stratumWriteTimeout = 5 * time.Second
+)
+
+var (
+	errStratumUnauthorized = errors.New("worker not logged in")
+	errStratumMethod       = errors.New("method not found")
+	errStratumParams       = errors.New("invalid params")
+
+	stratumAcceptedMeter = metrics.NewRegisteredMeter("paaash/stratum/shares/accepted", nil)
+	stratumRejectedMeter = metrics.NewRegisteredMeter("paaash/stratum/shares/rejected", nil)
+	stratumWorkersGauge  = metrics.NewRegisteredGauge("paaash/stratum/workers", nil)
+)
+
+// stratumRequest is a single line delimited request sent by a stratum client.
+// The worker field is an extension used by most mining software to identify
+// individual rigs behind a single login.
+type stratumRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Worker string            `json:"worker"`
+}
+
+// stratumError is the error object returned to a stratum client on failure.
+type stratumError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// stratumResponse is a reply to a stratum request, or a work notification if
+// the id is zero.
+type stratumResponse struct {
+	ID      json.RawMessage `json:"id"`
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result"`
+	Error   *stratumError   `json:"error,omitempty"`
+}
+
+// stratumServer is a TCP server speaking the line delimited JSON "stratum
+// proxy" dialect understood by most paaash mining software. Miners log in,
+// receive work packages pushed to them whenever the sealer has new work and
+// submit their solutions and hash rates over the same persistent connection.
+//
+// The server is a thin frontend over the remote sealer, every request being
+// served through the same channels as the paa_getWork RPC family.
+type stratumServer struct {
+	paaash   *Paaash
+	api      *API
+	listener net.Listener
+
+	sessions map[*stratumSession]struct{}
+	lock     sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// stratumSession is a single connected stratum client.
+type stratumSession struct {
+	conn   net.Conn
+	worker string
+	id     common.Hash // Hash rate identifier of the worker, derived from its name
+
+	work chan [4]string // Latest work package pending to be pushed to the miner
+	enc  *json.Encoder
+	lock sync.Mutex // Serializes writes to the connection
+}
+
+// startStratum opens a stratum listener on the given address and starts serving
+// remote miners from it.
+func (paaash *Paaash) startStratum(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &stratumServer{
+		paaash:   paaash,
+		api:      &API{paaash},
+		listener: listener,
+		sessions: make(map[*stratumSession]struct{}),
+		quit:     make(chan struct{}),
+	}
+	paaash.lock.Lock()
+	paaash.stratum = server
+	paaash.lock.Unlock()
+
+	server.wg.Add(2)
+	go server.acceptLoop()
+	go server.notifyLoop()
+
+	log.Info("Stratum server started", "addr", listener.Addr())
+	return nil
+}
+
+// StratumAddr returns the address the stratum server is listening on, or nil
+// if it's disabled.
+func (paaash *Paaash) StratumAddr() net.Addr {
+	paaash.lock.Lock()
+	defer paaash.lock.Unlock()
+
+	if paaash.stratum == nil {
+		return nil
+	}
+	return paaash.stratum.listener.Addr()
+}
+
+// stop terminates the listener and all connected sessions, waiting for every
+// goroutine of the server to exit.
+func (s *stratumServer) stop() {
+	close(s.quit)
+	s.listener.Close()
+
+	s.lock.Lock()
+	for session := range s.sessions {
+		session.conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+	log.Info("Stratum server stopped", "addr", s.listener.Addr())
+}
+
+// acceptLoop accepts inbound miner connections until the server is stopped.
+func (s *stratumServer) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				log.Debug("Temporary stratum accept error", "err", err)
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			log.Warn("Stratum listener failed", "err", err)
+			return
+		}
+		session := &stratumSession{
+			conn: conn,
+			work: make(chan [4]string, 1),
+			enc:  json.NewEncoder(conn),
+		}
+		s.lock.Lock()
+		select {
+		case <-s.quit:
+			s.lock.Unlock()
+			conn.Close()
+			return
+		default:
+		}
+		s.sessions[session] = struct{}{}
+		stratumWorkersGauge.Update(int64(len(s.sessions)))
+		s.lock.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(session)
+	}
+}
+
+// notifyLoop waits for new work packages from the remote sealer and queues them
+// up for delivery to every logged in session.
+func (s *stratumServer) notifyLoop() {
+	defer s.wg.Done()
+
+	workCh := make(chan [4]string, 1)
+	sub := s.paaash.workFeed.Subscribe(workCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case work := <-workCh:
+			s.lock.Lock()
+			for session := range s.sessions {
+				session.push(work)
+			}
+			s.lock.Unlock()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// serve reads and answers requests of a single session until the connection is
+// torn down.
+func (s *stratumServer) serve(session *stratumSession) {
+	defer s.wg.Done()
+
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		session.conn.Close()
+
+		s.lock.Lock()
+		delete(s.sessions, session)
+		stratumWorkersGauge.Update(int64(len(s.sessions)))
+		s.lock.Unlock()
+	}()
+	log.Debug("Stratum miner connected", "addr", session.conn.RemoteAddr())
+
+	// Push work packages to the miner concurrently with serving its requests
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			select {
+			case work := <-session.work:
+				if err := session.send(&stratumResponse{ID: json.RawMessage("0"), Version: "2.0", Result: work}); err != nil {
+					session.conn.Close()
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	scanner := bufio.NewScanner(session.conn)
+	scanner.Buffer(make([]byte, stratumMaxLineSize), stratumMaxLineSize)
+
+	for scanner.Scan() {
+		var req stratumRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			log.Debug("Malformed stratum request", "addr", session.conn.RemoteAddr(), "err", err)
+			return
+		}
+		result, err := s.handle(session, &req)
+
+		res := &stratumResponse{ID: req.ID, Version: "2.0", Result: result}
+		if err != nil {
+			res.Error = &stratumError{Code: -1, Message: err.Error()}
+		}
+		if err := session.send(res); err != nil {
+			return
+		}
+	}
+	log.Debug("Stratum miner disconnected", "addr", session.conn.RemoteAddr(), "worker", session.worker, "err", scanner.Err())
+}
+
+// handle executes a single stratum request, returning the result to reply with.
+func (s *stratumServer) handle(session *stratumSession, req *stratumRequest) (interface{}, error) {
+	if req.Method != "paa_submitLogin" && session.worker == "" {
+		return nil, errStratumUnauthorized
+	}
+	switch req.Method {
+	case "paa_submitLogin":
+		var login string
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &login) != nil || login == "" {
+			return false, errStratumParams
+		}
+		worker := login
+		if req.Worker != "" {
+			worker = login + "." + req.Worker
+		}
+		s.lock.Lock()
+		session.worker = worker
+		session.id = crypto.Keccak256Hash([]byte(worker))
+		s.lock.Unlock()
+		log.Info("Stratum miner logged in", "addr", session.conn.RemoteAddr(), "worker", session.worker)
+
+		// Hand the current work to the miner right away, if any is available
+		if work, err := s.api.GetWork(); err == nil {
+			session.push(work)
+		}
+		return true, nil
+
+	case "paa_getWork":
+		return s.api.GetWork()
+
+	case "paa_submitWork":
+		var (
+			nonce  types.BlockNonce
+			hash   common.Hash
+			digest common.Hash
+		)
+		if len(req.Params) != 3 || json.Unmarshal(req.Params[0], &nonce) != nil ||
+			json.Unmarshal(req.Params[1], &hash) != nil || json.Unmarshal(req.Params[2], &digest) != nil {
+			return false, errStratumParams
+		}
+		accepted := s.api.SubmitWork(nonce, hash, digest)
+		if accepted {
+			stratumAcceptedMeter.Mark(1)
+		} else {
+			stratumRejectedMeter.Mark(1)
+		}
+		log.Debug("Stratum share submitted", "worker", session.worker, "sealhash", hash, "accepted", accepted)
+		return accepted, nil
+
+	case "paa_submitHashrate":
+		var rate hexutil.Uint64
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &rate) != nil {
+			return false, errStratumParams
+		}
+		// The hash rate is tracked per worker regardless of any id sent along,
+		// so rigs reusing the same id don't overwrite each other's reports.
+		return s.api.SubmitHashRate(rate, session.id), nil
+
+	default:
+		return nil, errStratumMethod
+	}
+}
+
+// push queues a work package for delivery to a logged in miner, replacing any
+// older package not yet sent. The server lock must be held, unless called from
+// the session's own goroutine.
+func (session *stratumSession) push(work [4]string) {
+	if session.worker == "" {
+		return
+	}
+	for {
+		select {
+		case session.work <- work:
+			return
+		default:
+		}
+		select {
+		case <-session.work:
+		default:
+		}
+	}
+}
+
+// send writes a single message to the miner.
+func (session *stratumSession) send(msg *stratumResponse) error {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+
+	session.conn.SetWriteDeadline(time.Now().Add(stratumWriteTimeout))
+	return session.enc.Encode(msg)
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package paaash
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+)
+
+// fakeMiner is an in-process stratum client solving work packages with the
+// light verification cache of a test mode paaash.
+type fakeMiner struct {
+	t    *testing.T
+	conn net.Conn
+	in   *bufio.Scanner
+	id   int
+}
+
+func newFakeMiner(t *testing.T, addr net.Addr) *fakeMiner {
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatalf("failed to connect to stratum server: %v", err)
+	}
+	return &fakeMiner{t: t, conn: conn, in: bufio.NewScanner(conn)}
+}
+
+// call sends a request to the stratum server and waits for the reply, skipping
+// over any work notifications received in between.
+func (m *fakeMiner) call(method string, worker string, params ...interface{}) (json.RawMessage, *stratumError) {
+	m.id++
+	req, _ := json.Marshal(map[string]interface{}{"id": m.id, "method": method, "params": params, "worker": worker})
+	if _, err := m.conn.Write(append(req, '\n')); err != nil {
+		m.t.Fatalf("failed to send %s: %v", method, err)
+	}
+	for {
+		var res struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *stratumError   `json:"error"`
+		}
+		m.read(&res)
+		if res.ID == m.id {
+			return res.Result, res.Error
+		}
+	}
+}
+
+// notification waits for the next work package pushed by the server.
+func (m *fakeMiner) notification() [4]string {
+	var res struct {
+		ID     int       `json:"id"`
+		Result [4]string `json:"result"`
+	}
+	for {
+		m.read(&res)
+		if res.ID == 0 {
+			return res.Result
+		}
+	}
+}
+
+func (m *fakeMiner) read(v interface{}) {
+	m.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if !m.in.Scan() {
+		m.t.Fatalf("failed to read stratum message: %v", m.in.Err())
+	}
+	if err := json.Unmarshal(m.in.Bytes(), v); err != nil {
+		m.t.Fatalf("failed to decode stratum message %q: %v", m.in.Text(), err)
+	}
+}
+
+// Tests that a stratum miner receives new work, has its valid shares accepted
+// and invalid ones rejected, and gets its hash rate accounted for.
+func TestStratumMining(t *testing.T) {
+	paaash := NewTester(nil, false)
+	defer paaash.Close()
+	paaash.SetThreads(-1)
+
+	if err := paaash.startStratum("127.0.0.1:0"); err != nil {
+		t.Fatalf("failed to start stratum server: %v", err)
+	}
+	miner := newFakeMiner(t, paaash.StratumAddr())
+	defer miner.conn.Close()
+
+	// Requests prior to logging in must be refused
+	if _, err := miner.call("paa_getWork", ""); err == nil {
+		t.Fatalf("work retrieved without login")
+	}
+	res, err := miner.call("paa_submitLogin", "rig1", "0x0000000000000000000000000000000000000001", "x")
+	if err != nil || string(res) != "true" {
+		t.Fatalf("login failed: %s %v", res, err)
+	}
+	// Push a new work package and ensure it's delivered
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	results := make(chan *types.Block, 1)
+	paaash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+	work := miner.notification()
+	if want := paaash.SealHash(header).Hex(); work[0] != want {
+		t.Fatalf("work packet hash mismatch: have %s, want %s", work[0], want)
+	}
+	// Submit a bogus share, followed by a proper solution
+	res, err = miner.call("paa_submitWork", "rig1", types.BlockNonce{}, work[0], common.Hash{})
+	if err != nil || string(res) != "false" {
+		t.Fatalf("invalid share accepted: %s %v", res, err)
+	}
+	nonce, digest := solve(paaash, header)
+
+	res, err = miner.call("paa_submitWork", "rig1", nonce, work[0], digest)
+	if err != nil || string(res) != "true" {
+		t.Fatalf("valid share rejected: %s %v", res, err)
+	}
+	select {
+	case block := <-results:
+		if block.Nonce() != nonce.Uint64() || block.MixDigest() != digest {
+			t.Errorf("sealed block mismatch: have %x/%x, want %x/%x", block.Nonce(), block.MixDigest(), nonce, digest)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("sealed block not delivered")
+	}
+	// Report hash rates from two rigs and ensure both are accounted for
+	second := newFakeMiner(t, paaash.StratumAddr())
+	defer second.conn.Close()
+
+	if _, err := second.call("paa_submitLogin", "rig2", "0x0000000000000000000000000000000000000001", "x"); err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	miner.call("paa_submitHashrate", "rig1", hexutil.Uint64(100), common.Hash{})
+	second.call("paa_submitHashrate", "rig2", hexutil.Uint64(50), common.Hash{})
+
+	if rate := paaash.Hashrate(); rate != 150 {
+		t.Errorf("hashrate mismatch: have %v, want %v", rate, 150)
+	}
+}
+
+// solve brute forces a valid seal for the given header using the test cache.
+func solve(paaash *Paaash, header *types.Header) (types.BlockNonce, common.Hash) {
+	var (
+		cache  = paaash.cache(header.Number.Uint64())
+		hash   = paaash.SealHash(header).Bytes()
+		target = new(big.Int).Div(two256, header.Difficulty)
+	)
+	for nonce := uint64(0); ; nonce++ {
+		digest, result := hashimotoLight(32*1024, cache.cache, hash, nonce)
+		if new(big.Int).SetBytes(result).Cmp(target) <= 0 {
+			return types.EncodeNonce(nonce), common.BytesToHash(digest)
+		}
+	}
+}
+
+// Tests that creating the engine fails if the stratum server can't be started.
+func TestStratumListenFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to occupy port: %v", err)
+	}
+	defer listener.Close()
+
+	paaash, err := New(Config{PowMode: ModeTest, StratumAddr: listener.Addr().String()}, nil, false)
+	if err == nil {
+		paaash.Close()
+		t.Fatalf("engine created with stratum address in use")
+	}
+}
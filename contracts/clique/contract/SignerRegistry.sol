@@ -0,0 +1,104 @@
+pragma solidity ^0.4.24;
+
+/**
+ * SignerRegistry maintains the set of signers authorized to seal blocks on a
+ * clique network running with a governance contract (CliqueConfig.SignerContract).
+ *
+ * The consensus engine reads the signers array directly out of storage at every
+ * epoch checkpoint, so it must remain the first declared state variable (slot 0).
+ * Changes take effect at the first checkpoint following the passing vote.
+ */
+contract SignerRegistry {
+    address[] public signers;
+    mapping(address => bool) public authorized;
+
+    // Votes are keyed by (round, target, authorize); the round is bumped on every
+    // signer change, discarding all votes cast against the previous signer set.
+    uint public round;
+    mapping(bytes32 => mapping(address => bool)) voted;
+    mapping(bytes32 => uint) tally;
+
+    event Voted(address indexed voter, address indexed target, bool authorize);
+    event SignerAdded(address indexed signer);
+    event SignerRemoved(address indexed signer);
+
+    modifier onlySigner() {
+        require(authorized[msg.sender]);
+        _;
+    }
+
+    /**
+     * Constructor.
+     * @param initial The initial set of authorized signers.
+     */
+    constructor(address[] initial) public {
+        for (uint i = 0; i < initial.length; i++) {
+            add(initial[i]);
+        }
+    }
+
+    /**
+     * Returns the full list of currently authorized signers.
+     */
+    function getSigners() public view returns (address[]) {
+        return signers;
+    }
+
+    /**
+     * Returns the number of votes cast in the current round for a proposal.
+     * @param target The account being voted on.
+     * @param authorize True if the proposal is to add the account, false to remove it.
+     */
+    function votes(address target, bool authorize) public view returns (uint) {
+        return tally[keccak256(abi.encodePacked(round, target, authorize))];
+    }
+
+    /**
+     * Casts a vote to add or remove a signer. The proposal passes as soon as
+     * more than half of the current signers voted for it.
+     * @param target The account being voted on.
+     * @param authorize True to add the account, false to remove it.
+     */
+    function propose(address target, bool authorize) public onlySigner {
+        require(target != address(0));
+        require(authorized[target] != authorize);
+
+        bytes32 id = keccak256(abi.encodePacked(round, target, authorize));
+        require(!voted[id][msg.sender]);
+
+        voted[id][msg.sender] = true;
+        tally[id]++;
+        emit Voted(msg.sender, target, authorize);
+
+        if (tally[id] > signers.length / 2) {
+            if (authorize) {
+                add(target);
+            } else {
+                remove(target);
+            }
+            round++;
+        }
+    }
+
+    function add(address signer) internal {
+        require(signer != address(0) && !authorized[signer]);
+
+        authorized[signer] = true;
+        signers.push(signer);
+        emit SignerAdded(signer);
+    }
+
+    function remove(address signer) internal {
+        require(signers.length > 1);
+
+        authorized[signer] = false;
+        for (uint i = 0; i < signers.length; i++) {
+            if (signers[i] == signer) {
+                signers[i] = signers[signers.length - 1];
+                signers.length--;
+                break;
+            }
+        }
+        emit SignerRemoved(signer);
+    }
+}
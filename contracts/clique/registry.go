@@ -0,0 +1,87 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package clique wraps the signer governance contract of clique networks that
+// manage their signer set on-chain (CliqueConfig.SignerContract).
+package clique
+
+//go:generate abigen --abi contract/SignerRegistry.abi --pkg contract --type SignerRegistry --out contract/signerregistry.go
+
+import (
+	"math/big"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/accounts/abi/bind"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/contracts/clique/contract"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+)
+
+// Registry is a convenience wrapper around the signer governance contract.
+type Registry struct {
+	*contract.SignerRegistrySession
+	contractBackend bind.ContractBackend
+}
+
+// NewRegistry creates a struct exposing convenient high-level operations for
+// interacting with the signer governance contract.
+func NewRegistry(transactOpts *bind.TransactOpts, contractAddr common.Address, contractBackend bind.ContractBackend) (*Registry, error) {
+	registry, err := contract.NewSignerRegistry(contractAddr, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{
+		&contract.SignerRegistrySession{
+			Contract:     registry,
+			TransactOpts: *transactOpts,
+		},
+		contractBackend,
+	}, nil
+}
+
+// Authorize casts a vote to add a new signer to the authorized set.
+func (r *Registry) Authorize(signer common.Address) (*types.Transaction, error) {
+	return r.Propose(signer, true)
+}
+
+// Deauthorize casts a vote to remove a signer from the authorized set.
+func (r *Registry) Deauthorize(signer common.Address) (*types.Transaction, error) {
+	return r.Propose(signer, false)
+}
+
+// GenesisStorage assembles the storage layout of a signer governance contract
+// pre-populated with the given signers, suitable for embedding the contract
+// into the genesis allocation of a new network.
+func GenesisStorage(signers []common.Address) map[common.Hash]common.Hash {
+	var (
+		signersSlot    = common.Hash{}
+		authorizedSlot = common.BigToHash(common.Big1)
+		storage        = make(map[common.Hash]common.Hash)
+	)
+	// Dynamic array: length in the slot, elements starting at keccak256(slot)
+	storage[signersSlot] = common.BigToHash(big.NewInt(int64(len(signers))))
+
+	base := new(big.Int).SetBytes(crypto.Keccak256(signersSlot[:]))
+	for i, signer := range signers {
+		slot := common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i))))
+		storage[slot] = signer.Hash()
+
+		// Mapping: entry at keccak256(key . slot)
+		key := crypto.Keccak256Hash(signer.Hash().Bytes(), authorizedSlot[:])
+		storage[key] = common.BigToHash(common.Big1)
+	}
+	return storage
+}
@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -604,11 +605,17 @@ func (bc *BlockChain) HasBlockAndState(hash common.Hash, number uint64) bool {
 // GetBlock retrieves a block from the database by hash and number,
 // caching it if found.
 func (bc *BlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.getBlock(bc.db, hash, number)
+}
+
+// getBlock retrieves a block from the cache or the given database by hash and
+// number, caching it if found.
+func (bc *BlockChain) getBlock(db paadb.Database, hash common.Hash, number uint64) *types.Block {
 	// Short circuit if the block's already in the cache, retrieve otherwise
 	if block, ok := bc.blockCache.Get(hash); ok {
 		return block.(*types.Block)
 	}
-	block := rawdb.ReadBlock(bc.db, hash, number)
+	block := rawdb.ReadBlock(db, hash, number)
 	if block == nil {
 		return nil
 	}
@@ -619,33 +626,68 @@ func (bc *BlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
 
 // GetBlockByHash retrieves a block from the database by hash, caching it if found.
 func (bc *BlockChain) GetBlockByHash(hash common.Hash) *types.Block {
-	number := bc.hc.GetBlockNumber(hash)
+	return bc.getBlockByHash(bc.db, hash)
+}
+
+// GetBlockByHashContext is like GetBlockByHash, tracing the database reads as
+// part of the request carried by ctx.
+func (bc *BlockChain) GetBlockByHashContext(ctx context.Context, hash common.Hash) *types.Block {
+	return bc.getBlockByHash(paadb.WithContext(ctx, bc.db), hash)
+}
+
+// getBlockByHash retrieves a block from the cache or the given database by hash.
+func (bc *BlockChain) getBlockByHash(db paadb.Database, hash common.Hash) *types.Block {
+	number := bc.hc.getBlockNumber(db, hash)
 	if number == nil {
 		return nil
 	}
-	return bc.GetBlock(hash, *number)
+	return bc.getBlock(db, hash, *number)
 }
 
 // GetBlockByNumber retrieves a block from the database by number, caching it
 // (associated with its hash) if found.
 func (bc *BlockChain) GetBlockByNumber(number uint64) *types.Block {
-	hash := rawdb.ReadCanonicalHash(bc.db, number)
+	return bc.getBlockByNumber(bc.db, number)
+}
+
+// GetBlockByNumberContext is like GetBlockByNumber, tracing the database reads
+// as part of the request carried by ctx.
+func (bc *BlockChain) GetBlockByNumberContext(ctx context.Context, number uint64) *types.Block {
+	return bc.getBlockByNumber(paadb.WithContext(ctx, bc.db), number)
+}
+
+// getBlockByNumber retrieves a block from the cache or the given database by
+// number.
+func (bc *BlockChain) getBlockByNumber(db paadb.Database, number uint64) *types.Block {
+	hash := rawdb.ReadCanonicalHash(db, number)
 	if hash == (common.Hash{}) {
 		return nil
 	}
-	return bc.GetBlock(hash, number)
+	return bc.getBlock(db, hash, number)
 }
 
 // GetReceiptsByHash retrieves the receipts for all transactions in a given block.
 func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
+	return bc.getReceiptsByHash(bc.db, hash)
+}
+
+// GetReceiptsByHashContext is like GetReceiptsByHash, tracing the database reads
+// as part of the request carried by ctx.
+func (bc *BlockChain) GetReceiptsByHashContext(ctx context.Context, hash common.Hash) types.Receipts {
+	return bc.getReceiptsByHash(paadb.WithContext(ctx, bc.db), hash)
+}
+
+// getReceiptsByHash retrieves the receipts of a block from the cache or the given
+// database.
+func (bc *BlockChain) getReceiptsByHash(db paadb.Database, hash common.Hash) types.Receipts {
 	if receipts, ok := bc.receiptsCache.Get(hash); ok {
 		return receipts.(types.Receipts)
 	}
-	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	number := rawdb.ReadHeaderNumber(db, hash)
 	if number == nil {
 		return nil
 	}
-	receipts := rawdb.ReadReceipts(bc.db, hash, *number)
+	receipts := rawdb.ReadReceipts(db, hash, *number)
 	bc.receiptsCache.Add(hash, receipts)
 	return receipts
 }
@@ -1663,6 +1705,12 @@ func (bc *BlockChain) GetHeaderByHash(hash common.Hash) *types.Header {
 	return bc.hc.GetHeaderByHash(hash)
 }
 
+// GetHeaderByHashContext is like GetHeaderByHash, tracing the database reads as
+// part of the request carried by ctx.
+func (bc *BlockChain) GetHeaderByHashContext(ctx context.Context, hash common.Hash) *types.Header {
+	return bc.hc.getHeaderByHash(paadb.WithContext(ctx, bc.db), hash)
+}
+
 // HasHeader checks if a block header is present in the database or not, caching
 // it if present.
 func (bc *BlockChain) HasHeader(hash common.Hash, number uint64) bool {
@@ -1693,6 +1741,12 @@ func (bc *BlockChain) GetHeaderByNumber(number uint64) *types.Header {
 	return bc.hc.GetHeaderByNumber(number)
 }
 
+// GetHeaderByNumberContext is like GetHeaderByNumber, tracing the database reads
+// as part of the request carried by ctx.
+func (bc *BlockChain) GetHeaderByNumberContext(ctx context.Context, number uint64) *types.Header {
+	return bc.hc.getHeaderByNumber(paadb.WithContext(ctx, bc.db), number)
+}
+
 // Config retrieves the blockchain's chain configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -34,6 +35,9 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
 	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
 	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
+	opentracing "github.com/opentracing/opentracing-go"
+	jaeger "github.com/uber/jaeger-client-go"
 )
 
 // So we can deterministically seed different blockchains
@@ -1538,3 +1542,64 @@ func TestLowDiffLongChain(t *testing.T) {
 		header = chain.GetHeader(header.ParentHash, number-1)
 	}
 }
+
+// Tests that block, header and receipt reads with a context are traced as part of
+// the request carried by it, but only if they hit the database.
+func TestTracedReads(t *testing.T) {
+	db, chain, err := newCanonical(paaash.NewFaker(), 4, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	chain.Stop()
+
+	// Reopen the chain so that nothing is cached
+	chain, _ = NewBlockChain(db, nil, params.AllPaaashProtocolChanges, paaash.NewFaker(), vm.Config{}, nil)
+	defer chain.Stop()
+
+	reporter := jaeger.NewInMemoryReporter()
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), reporter)
+	defer closer.Close()
+
+	opentracing.SetGlobalTracer(tracer)
+	tracing.Enabled = true
+	defer func() {
+		opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+		tracing.Enabled = false
+	}()
+
+	// Count the database reads traced as children of a request
+	traced := func(read func(ctx context.Context)) int {
+		reporter.Reset()
+		ctx, request := tracing.StartSpan(context.Background(), "request")
+		read(ctx)
+		request.Finish()
+
+		id := request.Context().(jaeger.SpanContext).SpanID()
+		reads := 0
+		for _, span := range reporter.GetSpans() {
+			if span.(*jaeger.Span).Context().(jaeger.SpanContext).ParentID() == id {
+				reads++
+			}
+		}
+		return reads
+	}
+	var block *types.Block
+	tests := []struct {
+		name string
+		read func(ctx context.Context)
+	}{
+		{"header", func(ctx context.Context) { chain.GetHeaderByNumberContext(ctx, 1) }},
+		{"block", func(ctx context.Context) { block = chain.GetBlockByNumberContext(ctx, 2) }},
+		{"block by hash", func(ctx context.Context) { chain.GetBlockByHashContext(ctx, chain.GetHeaderByNumber(3).Hash()) }},
+		{"receipts", func(ctx context.Context) { chain.GetReceiptsByHashContext(ctx, block.Hash()) }},
+	}
+	for _, tt := range tests {
+		if reads := traced(tt.read); reads == 0 {
+			t.Errorf("%s: no database reads traced", tt.name)
+		}
+	}
+	// Cached items are served without touching the database
+	if reads := traced(func(ctx context.Context) { chain.GetReceiptsByHashContext(ctx, block.Hash()) }); reads != 0 {
+		t.Errorf("cached receipts: %d database reads traced", reads)
+	}
+}
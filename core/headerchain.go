@@ -111,11 +111,17 @@ func NewHeaderChain(chainDb paadb.Database, config *params.ChainConfig, engine c
 // GetBlockNumber retrieves the block number belonging to the given hash
 // from the cache or database
 func (hc *HeaderChain) GetBlockNumber(hash common.Hash) *uint64 {
+	return hc.getBlockNumber(hc.chainDb, hash)
+}
+
+// getBlockNumber retrieves the block number belonging to the given hash from the
+// cache or the given database.
+func (hc *HeaderChain) getBlockNumber(db paadb.Database, hash common.Hash) *uint64 {
 	if cached, ok := hc.numberCache.Get(hash); ok {
 		number := cached.(uint64)
 		return &number
 	}
-	number := rawdb.ReadHeaderNumber(hc.chainDb, hash)
+	number := rawdb.ReadHeaderNumber(db, hash)
 	if number != nil {
 		hc.numberCache.Add(hash, *number)
 	}
@@ -394,11 +400,17 @@ func (hc *HeaderChain) WriteTd(hash common.Hash, number uint64, td *big.Int) err
 // GetHeader retrieves a block header from the database by hash and number,
 // caching it if found.
 func (hc *HeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return hc.getHeader(hc.chainDb, hash, number)
+}
+
+// getHeader retrieves a block header from the cache or the given database by hash
+// and number, caching it if found.
+func (hc *HeaderChain) getHeader(db paadb.Database, hash common.Hash, number uint64) *types.Header {
 	// Short circuit if the header's already in the cache, retrieve otherwise
 	if header, ok := hc.headerCache.Get(hash); ok {
 		return header.(*types.Header)
 	}
-	header := rawdb.ReadHeader(hc.chainDb, hash, number)
+	header := rawdb.ReadHeader(db, hash, number)
 	if header == nil {
 		return nil
 	}
@@ -410,11 +422,17 @@ func (hc *HeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header
 // GetHeaderByHash retrieves a block header from the database by hash, caching it if
 // found.
 func (hc *HeaderChain) GetHeaderByHash(hash common.Hash) *types.Header {
-	number := hc.GetBlockNumber(hash)
+	return hc.getHeaderByHash(hc.chainDb, hash)
+}
+
+// getHeaderByHash retrieves a block header from the cache or the given database
+// by hash, caching it if found.
+func (hc *HeaderChain) getHeaderByHash(db paadb.Database, hash common.Hash) *types.Header {
+	number := hc.getBlockNumber(db, hash)
 	if number == nil {
 		return nil
 	}
-	return hc.GetHeader(hash, *number)
+	return hc.getHeader(db, hash, *number)
 }
 
 // HasHeader checks if a block header is present in the database or not.
@@ -428,11 +446,17 @@ func (hc *HeaderChain) HasHeader(hash common.Hash, number uint64) bool {
 // GetHeaderByNumber retrieves a block header from the database by number,
 // caching it (associated with its hash) if found.
 func (hc *HeaderChain) GetHeaderByNumber(number uint64) *types.Header {
-	hash := rawdb.ReadCanonicalHash(hc.chainDb, number)
+	return hc.getHeaderByNumber(hc.chainDb, number)
+}
+
+// getHeaderByNumber retrieves a block header from the cache or the given database
+// by number, caching it if found.
+func (hc *HeaderChain) getHeaderByNumber(db paadb.Database, number uint64) *types.Header {
+	hash := rawdb.ReadCanonicalHash(db, number)
 	if hash == (common.Hash{}) {
 		return nil
 	}
-	return hc.GetHeader(hash, number)
+	return hc.getHeader(db, hash, number)
 }
 
 // CurrentHeader retrieves the current head header of the canonical chain. The
@@ -178,7 +178,9 @@ func (self *stateObject) GetCommittedState(db Database, key common.Hash) common.
 		return value
 	}
 	// Otherwise load the value from the database
+	done := self.db.startSpan("state/storage")
 	enc, err := self.getTrie(db).TryGet(key[:])
+	done()
 	if err != nil {
 		self.setError(err)
 		return common.Hash{}
@@ -327,7 +329,9 @@ func (self *stateObject) Code(db Database) []byte {
 	if bytes.Equal(self.CodeHash(), emptyCodeHash) {
 		return nil
 	}
+	done := self.db.startSpan("state/code")
 	code, err := db.ContractCode(self.addrHash, common.BytesToHash(self.CodeHash()))
+	done()
 	if err != nil {
 		self.setError(fmt.Errorf("can't load code hash %x: %v", self.CodeHash(), err))
 	}
@@ -18,6 +18,7 @@
 package state
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -28,6 +29,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
 	"github.com/PaloAltoAi/go-PaloAltoAi/trie"
 )
 
@@ -86,6 +88,10 @@ type StateDB struct {
 	journal        *journal
 	validRevisions []revision
 	nextRevisionId int
+
+	// Context of the request the state is accessed for, used to trace reads
+	// hitting the database. Nil if the state is not used to serve a request.
+	ctx context.Context
 }
 
 // Create a new state from a given trie.
@@ -116,6 +122,23 @@ func (self *StateDB) Error() error {
 	return self.dbErr
 }
 
+// SetContext sets the context of the request the state is accessed for. Reads
+// of accounts, storage and code missing the caches are traced as children of
+// the span carried by the context.
+func (self *StateDB) SetContext(ctx context.Context) {
+	self.ctx = ctx
+}
+
+// startSpan starts tracing a database read, if tracing is enabled and the state
+// is accessed for a request.
+func (self *StateDB) startSpan(name string) func() {
+	if !tracing.Enabled || self.ctx == nil {
+		return func() {}
+	}
+	_, span := tracing.StartSpan(self.ctx, name)
+	return span.Finish
+}
+
 // Reset clears out all ephemeral state objects from the state db, but keeps
 // the underlying state trie to avoid reloading data for the next operations.
 func (self *StateDB) Reset(root common.Hash) error {
@@ -413,7 +436,9 @@ func (self *StateDB) getStateObject(addr common.Address) (stateObject *stateObje
 	}
 
 	// Load the object from the database.
+	done := self.startSpan("state/account")
 	enc, err := self.trie.TryGet(addr[:])
+	done()
 	if len(enc) == 0 {
 		self.setError(err)
 		return nil
@@ -504,6 +529,7 @@ func (self *StateDB) Copy() *StateDB {
 		logSize:           self.logSize,
 		preimages:         make(map[common.Hash][]byte),
 		journal:           newJournal(),
+		ctx:               self.ctx,
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range self.journal.dirties {
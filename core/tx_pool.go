@@ -384,6 +384,10 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 
 		if depth := uint64(math.Abs(float64(oldNum) - float64(newNum))); depth > 64 {
 			log.Debug("Skipping deep transaction reorg", "depth", depth)
+		} else if pool.chain.GetBlock(oldHead.Hash(), oldNum) == nil {
+			// The old head was rewound (e.g. via SetHead) and its body deleted,
+			// there's nothing left to reinject
+			log.Debug("Skipping transaction reorg of rewound chain", "number", oldNum, "hash", oldHead.Hash())
 		} else {
 			// Reorg seems shallow enough to pull in all transactions into memory
 			var discarded, included types.Transactions
@@ -97,8 +97,14 @@ type headerMarshaling struct {
 }
 
 // Hash returns the block hash of the header, which is simply the keccak256 hash of its
-// RLP encoding.
+// RLP encoding. Istanbul headers are hashed without their committed seals, as
+// every validator may collect a different set of them for the same block.
 func (h *Header) Hash() common.Hash {
+	if h.MixDigest == IstanbulDigest {
+		if filtered := IstanbulFilteredHeader(h, true); filtered != nil {
+			return rlpHash(filtered)
+		}
+	}
 	return rlpHash(h)
 }
 
@@ -0,0 +1,80 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+var (
+	// IstanbulDigest is the fixed mix digest of Istanbul blocks, the hex encoding
+	// of "Istanbul practical byzantine fault tolerance".
+	IstanbulDigest = common.HexToHash("0x63746963616c2062797a616e74696e65206661756c7420746f6c6572616e6365")
+
+	// ErrInvalidIstanbulHeaderExtra is returned if a header's extra-data can't be
+	// decoded as Istanbul consensus data.
+	ErrInvalidIstanbulHeaderExtra = errors.New("invalid istanbul header extra-data")
+)
+
+// IstanbulExtraVanity is the fixed number of extra-data prefix bytes reserved
+// for validator vanity.
+const IstanbulExtraVanity = 32
+
+// IstanbulExtra is the consensus data stored in the extra-data of Istanbul
+// headers after the vanity.
+type IstanbulExtra struct {
+	Validators    []common.Address // Validators responsible for sealing the block
+	Seal          []byte           // Signature of the proposer
+	CommittedSeal [][]byte         // Commit signatures of the agreeing validators
+}
+
+// ExtractIstanbulExtra decodes the Istanbul consensus data from a header.
+func ExtractIstanbulExtra(h *Header) (*IstanbulExtra, error) {
+	if len(h.Extra) < IstanbulExtraVanity {
+		return nil, ErrInvalidIstanbulHeaderExtra
+	}
+	extra := new(IstanbulExtra)
+	if err := rlp.DecodeBytes(h.Extra[IstanbulExtraVanity:], extra); err != nil {
+		return nil, ErrInvalidIstanbulHeaderExtra
+	}
+	return extra, nil
+}
+
+// IstanbulFilteredHeader returns a copy of the header with the committed seals,
+// and optionally the proposer seal, removed from the extra-data. It returns nil
+// if the header doesn't carry Istanbul consensus data.
+func IstanbulFilteredHeader(h *Header, keepSeal bool) *Header {
+	extra, err := ExtractIstanbulExtra(h)
+	if err != nil {
+		return nil
+	}
+	if !keepSeal {
+		extra.Seal = []byte{}
+	}
+	extra.CommittedSeal = [][]byte{}
+
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		return nil
+	}
+	cpy := CopyHeader(h)
+	cpy.Extra = append(cpy.Extra[:IstanbulExtraVanity:IstanbulExtraVanity], payload...)
+	return cpy
+}
@@ -0,0 +1,969 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package graphql provides a GraphQL interface to PaloAltoAi node data.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	PaloAltoAi "github.com/PaloAltoAi/go-PaloAltoAi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/rawdb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/internal/paaapi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paa/filters"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+)
+
+var (
+	errBlockNotFound   = errors.New("block not found")
+	errStateNotFound   = errors.New("state not found")
+	errFilterUnsupport = errors.New("log filtering not supported by backend")
+)
+
+// maxBlocksRange is the maximum number of blocks a blocks query may span if the
+// backend doesn't configure a limit of its own for log queries.
+const maxBlocksRange = 1024
+
+// Long is a 64 bit integer. GraphQL limits its own Int type to 32 bits, which
+// isn't enough for block numbers, nonces and gas amounts.
+type Long int64
+
+// ImplementsGraphQLType returns true if Long implements the specified GraphQL type.
+func (l Long) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+// UnmarshalGraphQL unmarshals the provided GraphQL query data.
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		// Accept both decimal and 0x-prefixed hexadecimal strings
+		value, err := strconv.ParseInt(input, 0, 64)
+		if err != nil {
+			return err
+		}
+		*l = Long(value)
+	case int32:
+		*l = Long(input)
+	case int64:
+		*l = Long(input)
+	case float64:
+		*l = Long(input)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return nil
+}
+
+// BlockNumberArgs is the optional block number argument of the account fields.
+type BlockNumberArgs struct {
+	Block *Long
+}
+
+// Number returns the requested block number, defaulting to the latest block.
+func (a BlockNumberArgs) Number() rpc.BlockNumber {
+	if a.Block != nil {
+		return rpc.BlockNumber(*a.Block)
+	}
+	return rpc.LatestBlockNumber
+}
+
+// Account represents a PaloAltoAi account at a particular block.
+type Account struct {
+	backend     paaapi.Backend
+	address     common.Address
+	blockNumber rpc.BlockNumber
+}
+
+// getState fetches the state database the account is resolved against.
+func (a *Account) getState(ctx context.Context) (*state.StateDB, error) {
+	statedb, _, err := a.backend.StateAndHeaderByNumber(ctx, a.blockNumber)
+	if statedb == nil && err == nil {
+		err = errStateNotFound
+	}
+	return statedb, err
+}
+
+func (a *Account) Address(ctx context.Context) (common.Address, error) {
+	return a.address, nil
+}
+
+func (a *Account) Balance(ctx context.Context) (hexutil.Big, error) {
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*statedb.GetBalance(a.address)), nil
+}
+
+func (a *Account) TransactionCount(ctx context.Context) (Long, error) {
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(statedb.GetNonce(a.address)), nil
+}
+
+func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return hexutil.Bytes(statedb.GetCode(a.address)), nil
+}
+
+func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash }) (common.Hash, error) {
+	statedb, err := a.getState(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return statedb.GetState(a.address, args.Slot), nil
+}
+
+// Log represents an individual log message. All arguments are mandatory.
+type Log struct {
+	backend     paaapi.Backend
+	transaction *Transaction
+	log         *types.Log
+}
+
+func (l *Log) Transaction(ctx context.Context) *Transaction {
+	return l.transaction
+}
+
+func (l *Log) Account(ctx context.Context, args BlockNumberArgs) *Account {
+	return &Account{
+		backend:     l.backend,
+		address:     l.log.Address,
+		blockNumber: args.Number(),
+	}
+}
+
+func (l *Log) Index(ctx context.Context) int32 {
+	return int32(l.log.Index)
+}
+
+func (l *Log) Topics(ctx context.Context) []common.Hash {
+	return l.log.Topics
+}
+
+func (l *Log) Data(ctx context.Context) hexutil.Bytes {
+	return hexutil.Bytes(l.log.Data)
+}
+
+// Transaction represents a PaloAltoAi transaction. The backend and hash are
+// mandatory, all other fields are lazily resolved as needed.
+type Transaction struct {
+	backend paaapi.Backend
+	hash    common.Hash
+	tx      *types.Transaction
+	block   *Block
+	index   uint64
+
+	lock sync.Mutex // Fields may be resolved concurrently
+}
+
+// resolve returns the internal transaction object, fetching it if needed. Mined
+// transactions are looked up in the chain, others in the transaction pool.
+func (t *Transaction) resolve(ctx context.Context) (*types.Transaction, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.tx == nil {
+		tx, blockHash, _, index := rawdb.ReadTransaction(t.backend.ChainDb(), t.hash)
+		if tx != nil {
+			t.tx = tx
+			t.block = &Block{backend: t.backend, hash: blockHash}
+			t.index = index
+		} else {
+			t.tx = t.backend.GetPoolTransaction(t.hash)
+		}
+	}
+	return t.tx, nil
+}
+
+func (t *Transaction) Hash(ctx context.Context) common.Hash {
+	return t.hash
+}
+
+func (t *Transaction) InputData(ctx context.Context) (hexutil.Bytes, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Bytes{}, err
+	}
+	return hexutil.Bytes(tx.Data()), nil
+}
+
+func (t *Transaction) Gas(ctx context.Context) (Long, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return 0, err
+	}
+	return Long(tx.Gas()), nil
+}
+
+func (t *Transaction) GasPrice(ctx context.Context) (hexutil.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*tx.GasPrice()), nil
+}
+
+func (t *Transaction) Value(ctx context.Context) (hexutil.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*tx.Value()), nil
+}
+
+func (t *Transaction) Nonce(ctx context.Context) (Long, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return 0, err
+	}
+	return Long(tx.Nonce()), nil
+}
+
+func (t *Transaction) To(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	to := tx.To()
+	if to == nil {
+		return nil, nil
+	}
+	return &Account{
+		backend:     t.backend,
+		address:     *to,
+		blockNumber: args.Number(),
+	}, nil
+}
+
+func (t *Transaction) From(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		backend:     t.backend,
+		address:     from,
+		blockNumber: args.Number(),
+	}, nil
+}
+
+func (t *Transaction) Block(ctx context.Context) (*Block, error) {
+	if _, err := t.resolve(ctx); err != nil {
+		return nil, err
+	}
+	return t.block, nil
+}
+
+func (t *Transaction) Index(ctx context.Context) (*int32, error) {
+	if _, err := t.resolve(ctx); err != nil {
+		return nil, err
+	}
+	if t.block == nil {
+		return nil, nil
+	}
+	index := int32(t.index)
+	return &index, nil
+}
+
+// getReceipt returns the receipt of the transaction, or nil if it's not mined.
+func (t *Transaction) getReceipt(ctx context.Context) (*types.Receipt, error) {
+	if _, err := t.resolve(ctx); err != nil {
+		return nil, err
+	}
+	if t.block == nil {
+		return nil, nil
+	}
+	receipts, err := t.block.resolveReceipts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.index >= uint64(len(receipts)) {
+		return nil, nil
+	}
+	return receipts[t.index], nil
+}
+
+func (t *Transaction) Status(ctx context.Context) (*Long, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	status := Long(receipt.Status)
+	return &status, nil
+}
+
+func (t *Transaction) GasUsed(ctx context.Context) (*Long, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	used := Long(receipt.GasUsed)
+	return &used, nil
+}
+
+func (t *Transaction) CumulativeGasUsed(ctx context.Context) (*Long, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	used := Long(receipt.CumulativeGasUsed)
+	return &used, nil
+}
+
+func (t *Transaction) CreatedContract(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil || receipt.ContractAddress == (common.Address{}) {
+		return nil, err
+	}
+	return &Account{
+		backend:     t.backend,
+		address:     receipt.ContractAddress,
+		blockNumber: args.Number(),
+	}, nil
+}
+
+func (t *Transaction) Logs(ctx context.Context) (*[]*Log, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	ret := make([]*Log, 0, len(receipt.Logs))
+	for _, log := range receipt.Logs {
+		ret = append(ret, &Log{
+			backend:     t.backend,
+			transaction: t,
+			log:         log,
+		})
+	}
+	return &ret, nil
+}
+
+// Block represents a PaloAltoAi block. The backend and either the number or
+// the hash are mandatory, all other fields are lazily resolved as needed.
+type Block struct {
+	backend  paaapi.Backend
+	num      *rpc.BlockNumber
+	hash     common.Hash
+	header   *types.Header
+	block    *types.Block
+	receipts []*types.Receipt
+
+	lock sync.Mutex // Fields may be resolved concurrently
+}
+
+// resolve returns the internal block object, fetching it if needed.
+func (b *Block) resolve(ctx context.Context) (*types.Block, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.resolveLocked(ctx)
+}
+
+func (b *Block) resolveLocked(ctx context.Context) (*types.Block, error) {
+	if b.block != nil {
+		return b.block, nil
+	}
+	var err error
+	if b.num != nil {
+		b.block, err = b.backend.BlockByNumber(ctx, *b.num)
+	} else {
+		b.block, err = b.backend.GetBlock(ctx, b.hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b.block == nil {
+		return nil, errBlockNotFound
+	}
+	b.header, b.hash = b.block.Header(), b.block.Hash()
+	return b.block, nil
+}
+
+// resolveHeader returns the internal header object, fetching it if needed. The
+// header is retrieved on its own if the block was requested by number, sparing
+// the body lookup for header-only queries.
+func (b *Block) resolveHeader(ctx context.Context) (*types.Header, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.header != nil {
+		return b.header, nil
+	}
+	if b.num == nil {
+		if _, err := b.resolveLocked(ctx); err != nil {
+			return nil, err
+		}
+		return b.header, nil
+	}
+	header, err := b.backend.HeaderByNumber(ctx, *b.num)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errBlockNotFound
+	}
+	// Pin the block to the resolved hash, so later lookups stay consistent
+	b.header, b.hash, b.num = header, header.Hash(), nil
+	return b.header, nil
+}
+
+// resolveReceipts returns the list of receipts of the block, fetching them if
+// needed.
+func (b *Block) resolveReceipts(ctx context.Context) ([]*types.Receipt, error) {
+	hash, err := b.Hash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.receipts == nil {
+		receipts, err := b.backend.GetReceipts(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		b.receipts = receipts
+	}
+	return b.receipts, nil
+}
+
+func (b *Block) Number(ctx context.Context) (Long, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(header.Number.Uint64()), nil
+}
+
+func (b *Block) Hash(ctx context.Context) (common.Hash, error) {
+	// Blocks not requested by number already know their hash
+	b.lock.Lock()
+	if b.num == nil {
+		defer b.lock.Unlock()
+		return b.hash, nil
+	}
+	b.lock.Unlock()
+
+	if _, err := b.resolveHeader(ctx); err != nil {
+		return common.Hash{}, err
+	}
+	return b.hash, nil
+}
+
+func (b *Block) GasLimit(ctx context.Context) (Long, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(header.GasLimit), nil
+}
+
+func (b *Block) GasUsed(ctx context.Context) (Long, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(header.GasUsed), nil
+}
+
+func (b *Block) Parent(ctx context.Context) (*Block, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header.Number.Sign() == 0 {
+		return nil, err
+	}
+	return &Block{backend: b.backend, hash: header.ParentHash}, nil
+}
+
+func (b *Block) Difficulty(ctx context.Context) (hexutil.Big, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*header.Difficulty), nil
+}
+
+func (b *Block) Timestamp(ctx context.Context) (hexutil.Big, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*header.Time), nil
+}
+
+func (b *Block) Nonce(ctx context.Context) (hexutil.Bytes, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return hexutil.Bytes(header.Nonce[:]), nil
+}
+
+func (b *Block) MixHash(ctx context.Context) (common.Hash, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.MixDigest, nil
+}
+
+func (b *Block) TransactionsRoot(ctx context.Context) (common.Hash, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.TxHash, nil
+}
+
+func (b *Block) StateRoot(ctx context.Context) (common.Hash, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Root, nil
+}
+
+func (b *Block) ReceiptsRoot(ctx context.Context) (common.Hash, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.ReceiptHash, nil
+}
+
+func (b *Block) OmmerHash(ctx context.Context) (common.Hash, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.UncleHash, nil
+}
+
+func (b *Block) OmmerCount(ctx context.Context) (*int32, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	count := int32(len(block.Uncles()))
+	return &count, nil
+}
+
+func (b *Block) Ommers(ctx context.Context) (*[]*Block, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Block, 0, len(block.Uncles()))
+	for _, uncle := range block.Uncles() {
+		ret = append(ret, &Block{
+			backend: b.backend,
+			hash:    uncle.Hash(),
+			header:  uncle,
+		})
+	}
+	return &ret, nil
+}
+
+func (b *Block) OmmerAt(ctx context.Context, args struct{ Index int32 }) (*Block, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	uncles := block.Uncles()
+	if args.Index < 0 || int(args.Index) >= len(uncles) {
+		return nil, nil
+	}
+	uncle := uncles[args.Index]
+	return &Block{
+		backend: b.backend,
+		hash:    uncle.Hash(),
+		header:  uncle,
+	}, nil
+}
+
+func (b *Block) ExtraData(ctx context.Context) (hexutil.Bytes, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return hexutil.Bytes(header.Extra), nil
+}
+
+func (b *Block) LogsBloom(ctx context.Context) (hexutil.Bytes, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return hexutil.Bytes(header.Bloom.Bytes()), nil
+}
+
+func (b *Block) TotalDifficulty(ctx context.Context) (hexutil.Big, error) {
+	hash, err := b.Hash(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	td := b.backend.GetTd(hash)
+	if td == nil {
+		return hexutil.Big{}, fmt.Errorf("total difficulty not found for %x", hash)
+	}
+	return hexutil.Big(*td), nil
+}
+
+func (b *Block) Miner(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		backend:     b.backend,
+		address:     header.Coinbase,
+		blockNumber: args.Number(),
+	}, nil
+}
+
+func (b *Block) TransactionCount(ctx context.Context) (*int32, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	count := int32(len(block.Transactions()))
+	return &count, nil
+}
+
+func (b *Block) Transactions(ctx context.Context) (*[]*Transaction, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Transaction, 0, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		ret = append(ret, &Transaction{
+			backend: b.backend,
+			hash:    tx.Hash(),
+			tx:      tx,
+			block:   b,
+			index:   uint64(i),
+		})
+	}
+	return &ret, nil
+}
+
+func (b *Block) TransactionAt(ctx context.Context, args struct{ Index int32 }) (*Transaction, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txs := block.Transactions()
+	if args.Index < 0 || int(args.Index) >= len(txs) {
+		return nil, nil
+	}
+	tx := txs[args.Index]
+	return &Transaction{
+		backend: b.backend,
+		hash:    tx.Hash(),
+		tx:      tx,
+		block:   b,
+		index:   uint64(args.Index),
+	}, nil
+}
+
+// BlockFilterCriteria encapsulates criteria passed to a `logs` accessor inside
+// a block.
+type BlockFilterCriteria struct {
+	Addresses *[]common.Address // restricts matches to events created by specific contracts
+	Topics    *[][]common.Hash  // restricts matches to particular event topics
+}
+
+func (b *Block) Logs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) ([]*Log, error) {
+	backend, ok := b.backend.(filters.Backend)
+	if !ok {
+		return nil, errFilterUnsupport
+	}
+	hash, err := b.Hash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		addresses []common.Address
+		topics    [][]common.Hash
+	)
+	if args.Filter.Addresses != nil {
+		addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		topics = *args.Filter.Topics
+	}
+	filter := filters.NewBlockFilter(backend, hash, addresses, topics)
+	return runFilter(ctx, b.backend, filter)
+}
+
+func (b *Block) Account(ctx context.Context, args struct{ Address common.Address }) (*Account, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		backend:     b.backend,
+		address:     args.Address,
+		blockNumber: rpc.BlockNumber(header.Number.Int64()),
+	}, nil
+}
+
+// runFilter runs a log filter and wraps the results into resolvers.
+func runFilter(ctx context.Context, backend paaapi.Backend, filter *filters.Filter) ([]*Log, error) {
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Log, 0, len(logs))
+	for _, log := range logs {
+		ret = append(ret, &Log{
+			backend:     backend,
+			transaction: &Transaction{backend: backend, hash: log.TxHash},
+			log:         log,
+		})
+	}
+	return ret, nil
+}
+
+// Pending represents the current pending state.
+type Pending struct {
+	backend paaapi.Backend
+}
+
+func (p *Pending) TransactionCount(ctx context.Context) (int32, error) {
+	txs, err := p.backend.GetPoolTransactions()
+	return int32(len(txs)), err
+}
+
+func (p *Pending) Transactions(ctx context.Context) (*[]*Transaction, error) {
+	txs, err := p.backend.GetPoolTransactions()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Transaction, 0, len(txs))
+	for _, tx := range txs {
+		ret = append(ret, &Transaction{
+			backend: p.backend,
+			hash:    tx.Hash(),
+			tx:      tx,
+		})
+	}
+	return &ret, nil
+}
+
+func (p *Pending) Account(ctx context.Context, args struct{ Address common.Address }) *Account {
+	return &Account{
+		backend:     p.backend,
+		address:     args.Address,
+		blockNumber: rpc.PendingBlockNumber,
+	}
+}
+
+// SyncState represents the synchronisation status returned from the `syncing`
+// accessor.
+type SyncState struct {
+	progress PaloAltoAi.SyncProgress
+}
+
+func (s *SyncState) StartingBlock() Long {
+	return Long(s.progress.StartingBlock)
+}
+
+func (s *SyncState) CurrentBlock() Long {
+	return Long(s.progress.CurrentBlock)
+}
+
+func (s *SyncState) HighestBlock() Long {
+	return Long(s.progress.HighestBlock)
+}
+
+func (s *SyncState) PulledStates() *Long {
+	ret := Long(s.progress.PulledStates)
+	return &ret
+}
+
+func (s *SyncState) KnownStates() *Long {
+	ret := Long(s.progress.KnownStates)
+	return &ret
+}
+
+// Resolver is the top level object in the GraphQL hierarchy.
+type Resolver struct {
+	backend paaapi.Backend
+}
+
+func (r *Resolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *common.Hash
+}) (*Block, error) {
+	block := &Block{backend: r.backend}
+	switch {
+	case args.Number != nil:
+		num := rpc.BlockNumber(*args.Number)
+		block.num = &num
+	case args.Hash != nil:
+		block.hash = *args.Hash
+	default:
+		num := rpc.LatestBlockNumber
+		block.num = &num
+	}
+	// Only return the block if it actually exists
+	switch _, err := block.resolveHeader(ctx); err {
+	case nil:
+		return block, nil
+	case errBlockNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+func (r *Resolver) Blocks(ctx context.Context, args struct {
+	From Long
+	To   *Long
+}) ([]*Block, error) {
+	from := rpc.BlockNumber(args.From)
+
+	var to rpc.BlockNumber
+	if args.To != nil {
+		to = rpc.BlockNumber(*args.To)
+	} else {
+		to = rpc.BlockNumber(r.backend.CurrentBlock().Number().Int64())
+	}
+	// Cap the range like log queries, falling back to a default if unlimited
+	limit := uint64(maxBlocksRange)
+	if backend, ok := r.backend.(filters.Backend); ok && backend.RPCLogsBlockRange() > 0 {
+		limit = backend.RPCLogsBlockRange()
+	}
+	if to >= from && uint64(to-from) >= limit {
+		return nil, fmt.Errorf("query spans more than %d blocks", limit)
+	}
+	ret := make([]*Block, 0)
+	for i := from; i <= to; i++ {
+		num := i
+		block := &Block{backend: r.backend, num: &num}
+
+		switch _, err := block.resolveHeader(ctx); err {
+		case nil:
+			ret = append(ret, block)
+		case errBlockNotFound:
+			return ret, nil
+		default:
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (r *Resolver) Pending(ctx context.Context) *Pending {
+	return &Pending{r.backend}
+}
+
+func (r *Resolver) Transaction(ctx context.Context, args struct{ Hash common.Hash }) (*Transaction, error) {
+	tx := &Transaction{backend: r.backend, hash: args.Hash}
+
+	// Only return the transaction if it actually exists
+	if t, err := tx.resolve(ctx); err != nil || t == nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r *Resolver) SendRawTransaction(ctx context.Context, args struct{ Data hexutil.Bytes }) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(args.Data, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return paaapi.SubmitTransaction(ctx, r.backend, tx)
+}
+
+// FilterCriteria encapsulates the arguments to `logs` on the root resolver object.
+type FilterCriteria struct {
+	FromBlock *Long             // beginning of the queried range, nil means latest block
+	ToBlock   *Long             // end of the range, nil means latest block
+	Addresses *[]common.Address // restricts matches to events created by specific contracts
+	Topics    *[][]common.Hash  // restricts matches to particular event topics
+}
+
+func (r *Resolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria }) ([]*Log, error) {
+	backend, ok := r.backend.(filters.Backend)
+	if !ok {
+		return nil, errFilterUnsupport
+	}
+	var (
+		begin     = rpc.LatestBlockNumber.Int64()
+		end       = rpc.LatestBlockNumber.Int64()
+		addresses []common.Address
+		topics    [][]common.Hash
+	)
+	if args.Filter.FromBlock != nil {
+		begin = int64(*args.Filter.FromBlock)
+	}
+	if args.Filter.ToBlock != nil {
+		end = int64(*args.Filter.ToBlock)
+	}
+	if args.Filter.Addresses != nil {
+		addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		topics = *args.Filter.Topics
+	}
+	filter := filters.NewRangeFilter(backend, begin, end, addresses, topics)
+	return runFilter(ctx, r.backend, filter)
+}
+
+func (r *Resolver) GasPrice(ctx context.Context) (hexutil.Big, error) {
+	price, err := r.backend.SuggestPrice(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*price), nil
+}
+
+func (r *Resolver) ProtocolVersion(ctx context.Context) int32 {
+	return int32(r.backend.ProtocolVersion())
+}
+
+// Syncing returns the synchronisation status, or nil if the node isn't syncing.
+func (r *Resolver) Syncing() *SyncState {
+	progress := r.backend.Downloader().Progress()
+	if progress.CurrentBlock >= progress.HighestBlock {
+		return nil
+	}
+	return &SyncState{progress}
+}
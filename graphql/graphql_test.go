@@ -0,0 +1,299 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/paaash"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/node"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paa"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+	testFunds   = big.NewInt(1000000000000000000)
+	testRecv    = common.HexToAddress("0x1234")
+
+	// testInitCode emits an empty log during contract creation
+	testInitCode = []byte{0x60, 0x00, 0x60, 0x00, 0xa0, 0x00} // PUSH1 0, PUSH1 0, LOG0, STOP
+)
+
+// newTestNode starts a node serving GraphQL, backed by a full node with two
+// blocks: a value transfer followed by a log emitting contract creation.
+func newTestNode(t *testing.T) (*node.Node, string) {
+	// Find a free port for the HTTP server to listen on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find free port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	stack, err := node.New(&node.Config{
+		HTTPHost:         "127.0.0.1",
+		HTTPPort:         port,
+		HTTPVirtualHosts: []string{"localhost", "127.0.0.1"},
+		P2P:              p2p.Config{NoDiscovery: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	config := paa.DefaultConfig
+	config.Genesis = &core.Genesis{
+		Config: params.AllPaaashProtocolChanges,
+		Alloc:  core.GenesisAlloc{testAddress: {Balance: testFunds}},
+	}
+	config.Paaash.PowMode = paaash.ModeFake
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return paa.New(ctx, &config)
+	}); err != nil {
+		t.Fatalf("failed to register paa service: %v", err)
+	}
+	registerTestService(t, stack)
+
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	var paaServ *paa.PaloAltoAi
+	if err := stack.Service(&paaServ); err != nil {
+		t.Fatalf("failed to retrieve paa service: %v", err)
+	}
+	chain := paaServ.BlockChain()
+	signer := types.HomesteadSigner{}
+	blocks, _ := core.GenerateChain(chain.Config(), chain.Genesis(), paaash.NewFaker(), paaServ.ChainDb(), 2, func(i int, b *core.BlockGen) {
+		var tx *types.Transaction
+		switch i {
+		case 0:
+			tx = types.NewTransaction(b.TxNonce(testAddress), testRecv, big.NewInt(1000), params.TxGas, nil, nil)
+		case 1:
+			tx = types.NewContractCreation(b.TxNonce(testAddress), new(big.Int), 100000, nil, testInitCode)
+		}
+		tx, _ = types.SignTx(tx, signer, testKey)
+		b.AddTx(tx)
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return stack, fmt.Sprintf("http://127.0.0.1:%d%s", port, Path)
+}
+
+// registerTestService registers the GraphQL service on the node without any
+// CORS domains, allowing only local virtual hosts.
+func registerTestService(t *testing.T, stack *node.Node) {
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		var paaServ *paa.PaloAltoAi
+		if err := ctx.Service(&paaServ); err != nil {
+			return nil, err
+		}
+		return New(paaServ.APIBackend, nil, []string{"localhost", "127.0.0.1"})
+	}); err != nil {
+		t.Fatalf("failed to register graphql service: %v", err)
+	}
+}
+
+// query runs a GraphQL query against the endpoint, decoding the result into v.
+func query(t *testing.T, url string, q string, v interface{}) {
+	body, _ := json.Marshal(map[string]string{"query": q})
+	res, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+	defer res.Body.Close()
+
+	var reply struct {
+		Data   json.RawMessage
+		Errors []struct{ Message string }
+	}
+	if err := json.NewDecoder(res.Body).Decode(&reply); err != nil {
+		t.Fatalf("failed to decode reply: %v", err)
+	}
+	if len(reply.Errors) > 0 {
+		t.Fatalf("query %q failed: %v", q, reply.Errors)
+	}
+	if err := json.Unmarshal(reply.Data, v); err != nil {
+		t.Fatalf("failed to decode data %s: %v", reply.Data, err)
+	}
+}
+
+// Tests that blocks, transactions, receipts and accounts can be queried.
+func TestQueryBlocks(t *testing.T) {
+	stack, url := newTestNode(t)
+	defer stack.Stop()
+
+	var res struct {
+		Block struct {
+			Number           int64
+			TransactionCount int
+			Transactions     []struct {
+				Hash   common.Hash
+				From   struct{ Address common.Address }
+				To     struct{ Balance string }
+				Value  string
+				Status int64
+			}
+		}
+		Blocks []struct{ Number int64 }
+	}
+	query(t, url, `{
+		block(number: 1) {
+			number
+			transactionCount
+			transactions { hash from { address } to(block: 1) { balance } value status }
+		}
+		blocks(from: 0) { number }
+	}`, &res)
+
+	if res.Block.Number != 1 || res.Block.TransactionCount != 1 || len(res.Block.Transactions) != 1 {
+		t.Fatalf("block mismatch: %+v", res.Block)
+	}
+	tx := res.Block.Transactions[0]
+	if tx.From.Address != testAddress || tx.To.Balance != "0x3e8" || tx.Value != "0x3e8" || tx.Status != 1 {
+		t.Errorf("transaction mismatch: %+v", tx)
+	}
+	if want := []struct{ Number int64 }{{0}, {1}, {2}}; !reflect.DeepEqual(res.Blocks, want) {
+		t.Errorf("block range mismatch: have %v, want %v", res.Blocks, want)
+	}
+	// Look the transfer up by hash and ensure it resolves its block
+	var txres struct {
+		Transaction struct {
+			Index   int
+			GasUsed int64
+			Block   struct{ Number int64 }
+		}
+		Missing *struct{ Hash common.Hash }
+	}
+	query(t, url, fmt.Sprintf(`{
+		transaction(hash: "%s") { index gasUsed block { number } }
+		missing: transaction(hash: "%s") { hash }
+	}`, tx.Hash.Hex(), common.Hash{}.Hex()), &txres)
+
+	if txres.Transaction.Block.Number != 1 || txres.Transaction.Index != 0 || txres.Transaction.GasUsed != int64(params.TxGas) {
+		t.Errorf("transaction lookup mismatch: %+v", txres.Transaction)
+	}
+	if txres.Missing != nil {
+		t.Errorf("unknown transaction found: %+v", txres.Missing)
+	}
+}
+
+// Tests that block range queries spanning too many blocks are rejected.
+func TestQueryBlocksRange(t *testing.T) {
+	stack, url := newTestNode(t)
+	defer stack.Stop()
+
+	body, _ := json.Marshal(map[string]string{"query": fmt.Sprintf("{ blocks(from: 0, to: %d) { number } }", maxBlocksRange)})
+	res, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+	defer res.Body.Close()
+
+	var reply struct {
+		Errors []struct{ Message string }
+	}
+	if err := json.NewDecoder(res.Body).Decode(&reply); err != nil {
+		t.Fatalf("failed to decode reply: %v", err)
+	}
+	if len(reply.Errors) != 1 || !strings.Contains(reply.Errors[0].Message, "query spans more than") {
+		t.Errorf("oversized range not rejected: %+v", reply.Errors)
+	}
+}
+
+// Tests that oversized request bodies are rejected.
+func TestRequestSizeLimit(t *testing.T) {
+	stack, url := newTestNode(t)
+	defer stack.Stop()
+
+	body, _ := json.Marshal(map[string]string{"query": "{ gasPrice }", "padding": strings.Repeat("x", 1024*1024)})
+	res, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		t.Errorf("oversized request accepted")
+	}
+}
+
+// Tests that logs can be filtered both within a block and across a range.
+func TestQueryLogs(t *testing.T) {
+	stack, url := newTestNode(t)
+	defer stack.Stop()
+
+	contract := crypto.CreateAddress(testAddress, 1)
+
+	var res struct {
+		Logs []struct {
+			Account     struct{ Address common.Address }
+			Transaction struct {
+				CreatedContract struct{ Address common.Address }
+			}
+		}
+		Block struct {
+			Logs []struct{ Index int }
+		}
+	}
+	query(t, url, fmt.Sprintf(`{
+		logs(filter: {fromBlock: 0, addresses: ["%s"]}) { account { address } transaction { createdContract { address } } }
+		block(number: 1) { logs(filter: {}) { index } }
+	}`, hexutil.Encode(contract.Bytes())), &res)
+
+	if len(res.Logs) != 1 {
+		t.Fatalf("log count mismatch: have %d, want 1", len(res.Logs))
+	}
+	if log := res.Logs[0]; log.Account.Address != contract || log.Transaction.CreatedContract.Address != contract {
+		t.Errorf("log mismatch: %+v", log)
+	}
+	if len(res.Block.Logs) != 0 {
+		t.Errorf("unexpected logs in transfer block: %+v", res.Block.Logs)
+	}
+}
+
+// Tests that the endpoint enforces its own virtual host restrictions.
+func TestVirtualHosts(t *testing.T) {
+	stack, url := newTestNode(t)
+	defer stack.Stop()
+
+	req, _ := http.NewRequest("POST", url, bytes.NewReader([]byte(`{"query":"{ gasPrice }"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Host = "example.com"
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusForbidden {
+		t.Errorf("status mismatch: have %d, want %d", res.StatusCode, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"net/http"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/internal/paaapi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// Path is the location the GraphQL endpoint is served at on the node's HTTP
+// server.
+const Path = "/graphql"
+
+// Service encapsulates a GraphQL endpoint mounted onto the HTTP-RPC server of
+// the node, enforcing its own CORS and virtual host restrictions.
+type Service struct {
+	handler http.Handler // Handler serving GraphQL queries, wrapped in the access restrictions
+}
+
+// New constructs a new GraphQL service answering queries from the given backend.
+func New(backend paaapi.Backend, cors, vhosts []string) (*Service, error) {
+	parsed, err := graphqlgo.ParseSchema(schema, &Resolver{backend})
+	if err != nil {
+		return nil, err
+	}
+	handler := rpc.NewHTTPHandlerStack(&relay.Handler{Schema: parsed}, cors, vhosts)
+	return &Service{handler: handler}, nil
+}
+
+// Protocols implements node.Service, returning the P2P network protocols used
+// by the GraphQL service (nil as it doesn't use the devp2p overlay network).
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs implements node.Service, returning the RPC API endpoints provided by the
+// GraphQL service (nil as it provides none).
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Start implements node.Service, as the handler is served by the node's HTTP
+// server there's nothing to start.
+func (s *Service) Start(server *p2p.Server) error { return nil }
+
+// Stop implements node.Service, as the handler is served by the node's HTTP
+// server there's nothing to stop.
+func (s *Service) Stop() error { return nil }
+
+// HTTPHandlers implements node.HTTPService, mounting the GraphQL endpoint on
+// the node's HTTP-RPC server.
+func (s *Service) HTTPHandlers() map[string]http.Handler {
+	return map[string]http.Handler{Path: s.handler}
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package health implements liveness and readiness HTTP endpoints, meant to be
+// polled by orchestrators and load balancers.
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/rawdb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/internal/paaapi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+)
+
+const (
+	// HealthPath is the location the liveness check is served at on the node's
+	// HTTP server. It fails if the node is unable to serve any requests.
+	HealthPath = "/health"
+
+	// ReadyPath is the location the readiness check is served at on the node's
+	// HTTP server. It fails if the node is not in sync with the network.
+	ReadyPath = "/ready"
+)
+
+// Config are the thresholds a node needs to meet to be considered ready.
+type Config struct {
+	MinPeers    int           // Minimum number of connected peers
+	MaxBlockAge time.Duration // Maximum age of the head block (0 = unchecked)
+	MaxSyncLag  uint64        // Maximum number of blocks the head may trail the highest known one
+}
+
+// DefaultConfig contains the default readiness thresholds.
+var DefaultConfig = Config{
+	MinPeers:    1,
+	MaxBlockAge: 10 * time.Minute,
+	MaxSyncLag:  64,
+}
+
+// Status is the outcome of a health check, returned as the body of both the
+// liveness and readiness endpoints.
+type Status struct {
+	Healthy bool `json:"healthy"` // Whether the node is able to serve requests
+	Ready   bool `json:"ready"`   // Whether the node is in sync with the network
+
+	Peers        int    `json:"peers"`
+	Syncing      bool   `json:"syncing"`
+	CurrentBlock uint64 `json:"currentBlock"`
+	HighestBlock uint64 `json:"highestBlock"`
+	HeadAge      uint64 `json:"headAge"` // Age of the head block in seconds
+
+	Errors []string `json:"errors,omitempty"` // Reasons for failing the checks
+}
+
+// Service serves the health checks of a node on its HTTP server.
+type Service struct {
+	backend paaapi.Backend
+	config  Config
+
+	server *p2p.Server // Peer-to-peer server to count the peers of
+	lock   sync.RWMutex
+}
+
+// New constructs a new health service checking the given backend against the
+// configured thresholds.
+func New(backend paaapi.Backend, config Config) *Service {
+	return &Service{
+		backend: backend,
+		config:  config,
+	}
+}
+
+// Protocols implements node.Service, returning the P2P network protocols used
+// by the health service (nil as it doesn't use the devp2p overlay network).
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs implements node.Service, returning the RPC API endpoints provided by the
+// health service (nil as it provides none).
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Start implements node.Service, retaining the P2P server to count peers on.
+func (s *Service) Start(server *p2p.Server) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.server = server
+	return nil
+}
+
+// Stop implements node.Service, releasing the P2P server.
+func (s *Service) Stop() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.server = nil
+	return nil
+}
+
+// OpenHTTPHandlers implements node.OpenHTTPService, mounting the liveness and
+// readiness endpoints on the node's HTTP server. They are exempt from the RPC
+// authentication and rate limits, as probes usually can't authenticate.
+func (s *Service) OpenHTTPHandlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		HealthPath: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := s.Check()
+			s.respond(w, status, status.Healthy)
+		}),
+		ReadyPath: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := s.Check()
+			s.respond(w, status, status.Ready)
+		}),
+	}
+}
+
+// Check runs all the health checks, reporting the state of the node.
+func (s *Service) Check() *Status {
+	status := &Status{Healthy: true}
+
+	// The node is unhealthy if its database is unusable. Every initialized database
+	// holds the head block, so failing to read it means a broken one.
+	if err := s.checkDatabase(); err != nil {
+		status.Healthy = false
+		status.Errors = append(status.Errors, fmt.Sprintf("database unavailable: %v", err))
+	}
+	// The node is not ready if it's not connected to or not in sync with the network
+	s.lock.RLock()
+	if s.server != nil {
+		status.Peers = s.server.PeerCount()
+	}
+	s.lock.RUnlock()
+
+	if status.Peers < s.config.MinPeers {
+		status.Errors = append(status.Errors, fmt.Sprintf("not enough peers: %d < %d", status.Peers, s.config.MinPeers))
+	}
+	progress := s.backend.Downloader().Progress()
+	head := s.backend.CurrentBlock()
+
+	status.CurrentBlock = head.NumberU64()
+	status.HighestBlock = progress.HighestBlock
+	if status.HighestBlock > status.CurrentBlock {
+		status.Syncing = true
+		if lag := status.HighestBlock - status.CurrentBlock; lag > s.config.MaxSyncLag {
+			status.Errors = append(status.Errors, fmt.Sprintf("syncing: %d blocks behind", lag))
+		}
+	}
+	if now, stamp := uint64(time.Now().Unix()), head.Time().Uint64(); now > stamp {
+		status.HeadAge = now - stamp
+	}
+	if age := time.Duration(status.HeadAge) * time.Second; s.config.MaxBlockAge > 0 && age > s.config.MaxBlockAge {
+		status.Errors = append(status.Errors, fmt.Sprintf("head block too old: %v", age))
+	}
+	status.Ready = status.Healthy && len(status.Errors) == 0
+	return status
+}
+
+// checkDatabase reads the head block's header through its hash and number, the
+// lookups every chain access relies on.
+func (s *Service) checkDatabase() error {
+	db := s.backend.ChainDb()
+
+	hash := rawdb.ReadHeadBlockHash(db)
+	if hash == (common.Hash{}) {
+		return errors.New("head block unknown")
+	}
+	number := rawdb.ReadHeaderNumber(db, hash)
+	if number == nil {
+		return fmt.Errorf("head block %x number unknown", hash)
+	}
+	if rawdb.ReadHeader(db, hash, *number) == nil {
+		return fmt.Errorf("head block %d [%x] header missing", *number, hash)
+	}
+	return nil
+}
+
+// respond writes a health status as a JSON reply, the HTTP status code reporting
+// whether the check succeeded.
+func (s *Service) respond(w http.ResponseWriter, status *Status, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Debug("Failed to write health status", "err", err)
+	}
+}
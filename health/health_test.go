@@ -0,0 +1,171 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/paaash"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/rawdb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/node"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paa"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+)
+
+// newTestNode starts a node serving the health checks with the given thresholds,
+// backed by a full node with only the genesis block and no peers. If a JWT secret
+// file is given, RPC authentication is enabled.
+func newTestNode(t *testing.T, config Config, jwtSecret string) (*node.Node, string) {
+	// Find a free port for the HTTP server to listen on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find free port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	stack, err := node.New(&node.Config{
+		HTTPHost:  "127.0.0.1",
+		HTTPPort:  port,
+		JWTSecret: jwtSecret,
+		P2P:       p2p.Config{NoDiscovery: true, MaxPeers: 1},
+	})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	paaConfig := paa.DefaultConfig
+	paaConfig.Genesis = &core.Genesis{Config: params.AllPaaashProtocolChanges}
+	paaConfig.Paaash.PowMode = paaash.ModeFake
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return paa.New(ctx, &paaConfig)
+	}); err != nil {
+		t.Fatalf("failed to register paa service: %v", err)
+	}
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		var paaServ *paa.PaloAltoAi
+		if err := ctx.Service(&paaServ); err != nil {
+			return nil, err
+		}
+		return New(paaServ.APIBackend, config), nil
+	}); err != nil {
+		t.Fatalf("failed to register health service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	return stack, fmt.Sprintf("http://127.0.0.1:%d", port)
+}
+
+// check requests a health endpoint, returning the HTTP status code and the
+// reported status.
+func check(t *testing.T, url string) (int, *Status) {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("failed to query %s: %v", url, err)
+	}
+	defer res.Body.Close()
+
+	status := new(Status)
+	if err := json.NewDecoder(res.Body).Decode(status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	return res.StatusCode, status
+}
+
+// Tests that a node without peers and with a stale head is reported healthy but
+// not ready.
+func TestNotReady(t *testing.T) {
+	stack, url := newTestNode(t, DefaultConfig, "")
+	defer stack.Stop()
+
+	code, status := check(t, url+HealthPath)
+	if code != http.StatusOK || !status.Healthy {
+		t.Errorf("health mismatch: code %d, status %+v", code, status)
+	}
+	code, status = check(t, url+ReadyPath)
+	if code != http.StatusServiceUnavailable || status.Ready {
+		t.Errorf("readiness mismatch: code %d, status %+v", code, status)
+	}
+	// Both the missing peers and the genesis timestamp must be reported
+	if len(status.Errors) != 2 {
+		t.Errorf("error count mismatch: have %v, want 2", status.Errors)
+	}
+	if status.Peers != 0 || status.CurrentBlock != 0 || status.Syncing {
+		t.Errorf("status mismatch: %+v", status)
+	}
+}
+
+// Tests that a node meeting all thresholds is reported ready.
+func TestReady(t *testing.T) {
+	stack, url := newTestNode(t, Config{MinPeers: 0, MaxSyncLag: DefaultConfig.MaxSyncLag}, "")
+	defer stack.Stop()
+
+	code, status := check(t, url+ReadyPath)
+	if code != http.StatusOK || !status.Ready || !status.Healthy {
+		t.Errorf("readiness mismatch: code %d, status %+v", code, status)
+	}
+	if len(status.Errors) != 0 {
+		t.Errorf("unexpected errors: %v", status.Errors)
+	}
+}
+
+// Tests that the checks are served without authentication even if the RPC API
+// requires it.
+func TestUnauthenticated(t *testing.T) {
+	secret, err := ioutil.TempFile("", "jwtsecret")
+	if err != nil {
+		t.Fatalf("failed to create JWT secret: %v", err)
+	}
+	defer os.Remove(secret.Name())
+	secret.WriteString(fmt.Sprintf("%x", make([]byte, 32)))
+	secret.Close()
+
+	stack, url := newTestNode(t, Config{MaxSyncLag: DefaultConfig.MaxSyncLag}, secret.Name())
+	defer stack.Stop()
+
+	for _, path := range []string{HealthPath, ReadyPath} {
+		if code, _ := check(t, url+path); code != http.StatusOK {
+			t.Errorf("%s: status code mismatch: have %d, want %d", path, code, http.StatusOK)
+		}
+	}
+}
+
+// Tests that a node whose database lost the head block is reported unhealthy.
+func TestBrokenDatabase(t *testing.T) {
+	stack, url := newTestNode(t, Config{MaxSyncLag: DefaultConfig.MaxSyncLag}, "")
+	defer stack.Stop()
+
+	var paaServ *paa.PaloAltoAi
+	if err := stack.Service(&paaServ); err != nil {
+		t.Fatalf("failed to retrieve paa service: %v", err)
+	}
+	head := paaServ.BlockChain().CurrentBlock()
+	rawdb.DeleteHeader(paaServ.ChainDb(), head.Hash(), head.NumberU64())
+
+	code, status := check(t, url+HealthPath)
+	if code != http.StatusServiceUnavailable || status.Healthy || status.Ready {
+		t.Errorf("health mismatch: code %d, status %+v", code, status)
+	}
+}
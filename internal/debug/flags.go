@@ -27,6 +27,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
 	"github.com/PaloAltoAi/go-PaloAltoAi/metrics/exp"
+	"github.com/PaloAltoAi/go-PaloAltoAi/metrics/prometheus"
 	"github.com/fjl/memsize/memsizeui"
 	colorable "github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
@@ -157,6 +158,10 @@ func StartPProf(address string) {
 	// Hook go-metrics into expvar on any /debug/metrics request, load all vars
 	// from the registry into expvar, and execute regular expvar handler.
 	exp.Exp(metrics.DefaultRegistry)
+
+	// Serve the same registry in the Prometheus text format for scrapers.
+	http.Handle("/debug/metrics/prometheus", prometheus.Handler(metrics.DefaultRegistry))
+
 	http.Handle("/memsize/", http.StripPrefix("/memsize", &Memsize))
 	log.Info("Starting pprof server", "addr", fmt.Sprintf("http://%s/debug/pprof", address))
 	go func() {
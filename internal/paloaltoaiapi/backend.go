@@ -20,6 +20,7 @@ package paaapi
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/accounts"
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
@@ -69,6 +70,10 @@ type Backend interface {
 
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
+
+	// Resource limits of API calls
+	RPCGasCap() uint64            // Maximum gas of EVM calls, 0 if uncapped
+	RPCEVMTimeout() time.Duration // Maximum execution time of EVM calls, 0 if unlimited
 }
 
 func GetAPIs(apiBackend Backend) []rpc.API {
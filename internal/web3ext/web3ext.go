@@ -24,6 +24,7 @@ var Modules = map[string]string{
 	"clique":     Clique_JS,
 	"paaash":     Paaash_JS,
 	"debug":      Debug_JS,
+	"dev":        Dev_JS,
 	"paa":        Paa_JS,
 	"miner":      Miner_JS,
 	"net":        Net_JS,
@@ -101,6 +102,11 @@ web3._extend({
 			call: 'clique_discard',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'status',
+			call: 'clique_status',
+			params: 0
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -163,6 +169,21 @@ web3._extend({
 			call: 'admin_removeTrustedPeer',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'banPeer',
+			call: 'admin_banPeer',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'unbanPeer',
+			call: 'admin_unbanPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'listBans',
+			call: 'admin_listBans'
+		}),
 		new web3._extend.Method({
 			name: 'exportChain',
 			call: 'admin_exportChain',
@@ -209,6 +230,10 @@ web3._extend({
 			name: 'peers',
 			getter: 'admin_peers'
 		}),
+		new web3._extend.Property({
+			name: 'peerStats',
+			getter: 'admin_peerStats'
+		}),
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
@@ -448,6 +473,58 @@ web3._extend({
 });
 `
 
+const Dev_JS = `
+web3._extend({
+	property: 'dev',
+	methods: [
+		new web3._extend.Method({
+			name: 'mine',
+			call: 'dev_mine',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'setNextBlockTimestamp',
+			call: 'dev_setNextBlockTimestamp',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'increaseTime',
+			call: 'dev_increaseTime',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'snapshot',
+			call: 'dev_snapshot'
+		}),
+		new web3._extend.Method({
+			name: 'revert',
+			call: 'dev_revert',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setBalance',
+			call: 'dev_setBalance',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'setCode',
+			call: 'dev_setCode',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'setStorageAt',
+			call: 'dev_setStorageAt',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+	],
+	properties: []
+});
+`
+
 const Paa_JS = `
 web3._extend({
 	property: 'paa',
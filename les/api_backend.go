@@ -19,6 +19,7 @@ package les
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/accounts"
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
@@ -36,6 +37,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/light"
 	"github.com/PaloAltoAi/go-PaloAltoAi/params"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
 )
 
 type LesApiBackend struct {
@@ -57,6 +59,9 @@ func (b *LesApiBackend) SetHead(number uint64) {
 }
 
 func (b *LesApiBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	ctx, span := tracing.StartSpan(ctx, "paaapi/headerByNumber")
+	defer span.Finish()
+
 	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
 		return b.paa.blockchain.CurrentHeader(), nil
 	}
@@ -68,6 +73,9 @@ func (b *LesApiBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*ty
 }
 
 func (b *LesApiBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	ctx, span := tracing.StartSpan(ctx, "paaapi/blockByNumber")
+	defer span.Finish()
+
 	header, err := b.HeaderByNumber(ctx, blockNr)
 	if header == nil || err != nil {
 		return nil, err
@@ -80,22 +88,33 @@ func (b *LesApiBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.
 	if header == nil || err != nil {
 		return nil, nil, err
 	}
-	return light.NewState(ctx, header, b.paa.odr), header, nil
+	state := light.NewState(ctx, header, b.paa.odr)
+	state.SetContext(ctx)
+	return state, header, nil
 }
 
 func (b *LesApiBackend) GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error) {
+	ctx, span := tracing.StartSpan(ctx, "paaapi/getBlock")
+	defer span.Finish()
+
 	return b.paa.blockchain.GetBlockByHash(ctx, blockHash)
 }
 
 func (b *LesApiBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	if number := rawdb.ReadHeaderNumber(b.paa.chainDb, hash); number != nil {
+	ctx, span := tracing.StartSpan(ctx, "paaapi/getReceipts")
+	defer span.Finish()
+
+	if number := rawdb.ReadHeaderNumber(paadb.WithContext(ctx, b.paa.chainDb), hash); number != nil {
 		return light.GetBlockReceipts(ctx, b.paa.odr, hash, *number)
 	}
 	return nil, nil
 }
 
 func (b *LesApiBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
-	if number := rawdb.ReadHeaderNumber(b.paa.chainDb, hash); number != nil {
+	ctx, span := tracing.StartSpan(ctx, "paaapi/getLogs")
+	defer span.Finish()
+
+	if number := rawdb.ReadHeaderNumber(paadb.WithContext(ctx, b.paa.chainDb), hash); number != nil {
 		return light.GetBlockLogs(ctx, b.paa.odr, hash, *number)
 	}
 	return nil, nil
@@ -187,6 +206,22 @@ func (b *LesApiBackend) AccountManager() *accounts.Manager {
 	return b.paa.accountManager
 }
 
+func (b *LesApiBackend) RPCGasCap() uint64 {
+	return b.paa.config.RPCGasCap
+}
+
+func (b *LesApiBackend) RPCEVMTimeout() time.Duration {
+	return b.paa.config.RPCEVMTimeout
+}
+
+func (b *LesApiBackend) RPCLogsLimit() int {
+	return b.paa.config.RPCLogsLimit
+}
+
+func (b *LesApiBackend) RPCLogsBlockRange() uint64 {
+	return b.paa.config.RPCLogsBlockRange
+}
+
 func (b *LesApiBackend) BloomStatus() (uint64, uint64) {
 	if b.paa.bloomIndexer == nil {
 		return 0, 0
@@ -18,6 +18,7 @@
 package les
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -88,6 +89,14 @@ func New(ctx *node.ServiceContext, config *paa.Config) (*LightPaloAltoAi, error)
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	// Contract governed clique checkpoints can't be verified without the state
+	if chainConfig.Clique != nil && chainConfig.Clique.SignerContract != nil {
+		return nil, errors.New("light client unsupported on networks with a clique signer contract")
+	}
+	engine, err := paa.CreateConsensusEngine(ctx, chainConfig, &config.Paaash, nil, false, chainDb)
+	if err != nil {
+		return nil, err
+	}
 	peers := newPeerSet()
 	quitSync := make(chan struct{})
 
@@ -102,7 +111,7 @@ func New(ctx *node.ServiceContext, config *paa.Config) (*LightPaloAltoAi, error)
 		peers:          peers,
 		reqDist:        newRequestDistributor(peers, quitSync),
 		accountManager: ctx.AccountManager,
-		engine:         paa.CreateConsensusEngine(ctx, chainConfig, &config.Paaash, nil, false, chainDb),
+		engine:         engine,
 		shutdownChan:   make(chan bool),
 		networkId:      config.NetworkId,
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
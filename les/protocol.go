@@ -49,6 +49,7 @@ var (
 var ProtocolLengths = map[uint]uint64{lpv1: 15, lpv2: 22}
 
 const (
+	ProtocolName       = "les"
 	NetworkId          = 1
 	ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 )
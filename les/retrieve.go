@@ -81,6 +81,7 @@ type sentReq struct {
 // valid channel and no more responses are accepted.
 type sentReqToPeer struct {
 	delivered bool
+	sent      mclock.AbsTime // Time the request was sent, to measure the response time
 	valid     chan bool
 }
 
@@ -151,7 +152,7 @@ func (rm *retrieveManager) sendReq(reqID uint64, req *distReq, val validatorFunc
 	req.request = func(p distPeer) func() {
 		// before actually sending the request, put an entry into the sentTo map
 		r.lock.Lock()
-		r.sentTo[p] = sentReqToPeer{false, make(chan bool, 1)}
+		r.sentTo[p] = sentReqToPeer{sent: mclock.Now(), valid: make(chan bool, 1)}
 		r.lock.Unlock()
 		return request(p)
 	}
@@ -163,8 +164,9 @@ func (rm *retrieveManager) sendReq(reqID uint64, req *distReq, val validatorFunc
 	return r
 }
 
-// deliver is called by the LES protocol manager to deliver reply messages to waiting requests
-func (rm *retrieveManager) deliver(peer distPeer, msg *Msg) error {
+// deliver is called by the LES protocol manager to deliver reply messages to waiting
+// requests. It returns the response time of valid replies.
+func (rm *retrieveManager) deliver(peer distPeer, msg *Msg) (time.Duration, error) {
 	rm.lock.RLock()
 	req, ok := rm.sentReqs[msg.ReqID]
 	rm.lock.RUnlock()
@@ -172,7 +174,7 @@ func (rm *retrieveManager) deliver(peer distPeer, msg *Msg) error {
 	if ok {
 		return req.deliver(peer, msg)
 	}
-	return errResp(ErrUnexpectedResponse, "reqID = %v", msg.ReqID)
+	return 0, errResp(ErrUnexpectedResponse, "reqID = %v", msg.ReqID)
 }
 
 // reqStateFn represents a state of the retrieve loop state machine
@@ -370,22 +372,23 @@ func (r *sentReq) tryRequest() {
 	}
 }
 
-// deliver a reply belonging to this request
-func (r *sentReq) deliver(peer distPeer, msg *Msg) error {
+// deliver a reply belonging to this request, returning its response time
+func (r *sentReq) deliver(peer distPeer, msg *Msg) (time.Duration, error) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
 	s, ok := r.sentTo[peer]
 	if !ok || s.delivered {
-		return errResp(ErrUnexpectedResponse, "reqID = %v", msg.ReqID)
+		return 0, errResp(ErrUnexpectedResponse, "reqID = %v", msg.ReqID)
 	}
 	valid := r.validate(peer, msg) == nil
-	r.sentTo[peer] = sentReqToPeer{true, s.valid}
+	s.delivered = true
+	r.sentTo[peer] = s
 	s.valid <- valid
 	if !valid {
-		return errResp(ErrInvalidResponse, "reqID = %v", msg.ReqID)
+		return 0, errResp(ErrInvalidResponse, "reqID = %v", msg.ReqID)
 	}
-	return nil
+	return time.Duration(mclock.Now() - s.sent), nil
 }
 
 // stop stops the retrieval process and sets an error code that will be returned
@@ -0,0 +1,139 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
+)
+
+const (
+	typeGaugeTpl           = "# TYPE %s gauge\n"
+	typeCounterTpl         = "# TYPE %s counter\n"
+	typeSummaryTpl         = "# TYPE %s summary\n"
+	keyValueTpl            = "%s %v\n\n"
+	keyQuantileTagValueTpl = "%s{quantile=\"%s\"} %v\n"
+)
+
+// quantiles are the percentiles exported for histograms and timers.
+var quantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999, 0.9999}
+
+// collector is a collection of byte buffers that aggregate Prometheus reports
+// for different metric types.
+type collector struct {
+	buff *bytes.Buffer
+}
+
+// newCollector creates a new Prometheus metric aggregator.
+func newCollector() *collector {
+	return &collector{
+		buff: &bytes.Buffer{},
+	}
+}
+
+func (c *collector) addCounter(name string, m metrics.Counter) {
+	c.writeGaugeCounter(name, m.Count())
+}
+
+func (c *collector) addGauge(name string, m metrics.Gauge) {
+	c.writeGaugeCounter(name, m.Value())
+}
+
+func (c *collector) addGaugeFloat64(name string, m metrics.GaugeFloat64) {
+	c.writeGaugeCounter(name, m.Value())
+}
+
+func (c *collector) addMeter(name string, m metrics.Meter) {
+	c.writeCounter(name, m.Count())
+	c.writeGaugeCounter(name+"_rate1", m.Rate1())
+}
+
+func (c *collector) addHistogram(name string, m metrics.Histogram) {
+	ps := m.Percentiles(quantiles)
+	c.writeSummaryCounter(name, m.Count(), m.Sum())
+	for i := range quantiles {
+		c.writeSummaryPercentile(name, strconv.FormatFloat(quantiles[i], 'f', -1, 64), ps[i])
+	}
+	c.buff.WriteRune('\n')
+}
+
+func (c *collector) addTimer(name string, m metrics.Timer) {
+	ps := m.Percentiles(quantiles)
+	c.writeSummaryCounter(name, m.Count(), m.Sum())
+	for i := range quantiles {
+		c.writeSummaryPercentile(name, strconv.FormatFloat(quantiles[i], 'f', -1, 64), ps[i])
+	}
+	c.buff.WriteRune('\n')
+}
+
+func (c *collector) addResettingTimer(name string, m metrics.ResettingTimer) {
+	values := m.Values()
+	if len(values) <= 0 {
+		return
+	}
+	var sum int64
+	for _, v := range values {
+		sum += v
+	}
+	ps := m.Percentiles([]float64{50, 95, 99})
+	c.writeSummaryCounter(name, int64(len(values)), sum)
+	c.writeSummaryPercentile(name, "0.50", ps[0])
+	c.writeSummaryPercentile(name, "0.95", ps[1])
+	c.writeSummaryPercentile(name, "0.99", ps[2])
+	c.buff.WriteRune('\n')
+}
+
+func (c *collector) writeGaugeCounter(name string, value interface{}) {
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(typeGaugeTpl, name))
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+}
+
+func (c *collector) writeCounter(name string, value interface{}) {
+	name = mutateKey(name) + "_total"
+	c.buff.WriteString(fmt.Sprintf(typeCounterTpl, name))
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+}
+
+func (c *collector) writeSummaryCounter(name string, count, sum int64) {
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(typeSummaryTpl, name))
+	c.buff.WriteString(fmt.Sprintf("%s_count %d\n", name, count))
+	c.buff.WriteString(fmt.Sprintf("%s_sum %d\n", name, sum))
+}
+
+func (c *collector) writeSummaryPercentile(name, p string, value interface{}) {
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(keyQuantileTagValueTpl, name, p, value))
+}
+
+// mutateKey converts a registry metric name into a valid Prometheus one,
+// replacing all unsupported characters with underscores.
+func mutateKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
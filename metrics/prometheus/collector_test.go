@@ -0,0 +1,138 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
+)
+
+func TestMain(m *testing.M) {
+	metrics.Enabled = true
+	os.Exit(m.Run())
+}
+
+func TestCollector(t *testing.T) {
+	c := newCollector()
+
+	counter := metrics.NewCounter()
+	counter.Inc(12345)
+	c.addCounter("test/counter", counter)
+
+	gauge := metrics.NewGauge()
+	gauge.Update(23456)
+	c.addGauge("test/gauge", gauge)
+
+	gaugeFloat64 := metrics.NewGaugeFloat64()
+	gaugeFloat64.Update(34567.89)
+	c.addGaugeFloat64("test/gauge_float64", gaugeFloat64)
+
+	histogram := metrics.NewHistogram(&metrics.NilSample{})
+	c.addHistogram("test/histogram", histogram)
+
+	meter := metrics.NewMeter()
+	defer meter.Stop()
+	meter.Mark(9999999)
+	c.addMeter("test/meter", meter)
+
+	timer := metrics.NewTimer()
+	defer timer.Stop()
+	timer.Update(20 * time.Millisecond)
+	timer.Update(21 * time.Millisecond)
+	timer.Update(22 * time.Millisecond)
+	timer.Update(120 * time.Millisecond)
+	timer.Update(23 * time.Millisecond)
+	timer.Update(24 * time.Millisecond)
+	c.addTimer("test/timer", timer)
+
+	resettingTimer := metrics.NewResettingTimer()
+	resettingTimer.Update(10 * time.Millisecond)
+	resettingTimer.Update(11 * time.Millisecond)
+	resettingTimer.Update(12 * time.Millisecond)
+	resettingTimer.Update(120 * time.Millisecond)
+	resettingTimer.Update(13 * time.Millisecond)
+	resettingTimer.Update(14 * time.Millisecond)
+	c.addResettingTimer("test/resetting_timer", resettingTimer.Snapshot())
+
+	emptyResettingTimer := metrics.NewResettingTimer().Snapshot()
+	c.addResettingTimer("test/empty_resetting_timer", emptyResettingTimer)
+
+	const expectedOutput = `# TYPE test_counter gauge
+test_counter 12345
+
+# TYPE test_gauge gauge
+test_gauge 23456
+
+# TYPE test_gauge_float64 gauge
+test_gauge_float64 34567.89
+
+# TYPE test_histogram summary
+test_histogram_count 0
+test_histogram_sum 0
+test_histogram{quantile="0.5"} 0
+test_histogram{quantile="0.75"} 0
+test_histogram{quantile="0.95"} 0
+test_histogram{quantile="0.99"} 0
+test_histogram{quantile="0.999"} 0
+test_histogram{quantile="0.9999"} 0
+
+# TYPE test_meter_total counter
+test_meter_total 9999999
+
+`
+	if have := c.buff.String(); !strings.HasPrefix(have, expectedOutput) {
+		t.Fatalf("prefix mismatch:\nhave:\n%s\nwant prefix:\n%s", have, expectedOutput)
+	}
+	// Timer values depend on the exact sample, only check their presence
+	for _, want := range []string{
+		"# TYPE test_meter_rate1 gauge\n",
+		"# TYPE test_timer summary\ntest_timer_count 6\ntest_timer_sum 230000000\n",
+		"test_timer{quantile=\"0.5\"} 2.25e+07\n",
+		"# TYPE test_resetting_timer summary\ntest_resetting_timer_count 6\ntest_resetting_timer_sum 180000000\n",
+		"test_resetting_timer{quantile=\"0.50\"} 12000000\n",
+		"test_resetting_timer{quantile=\"0.99\"} 120000000\n",
+	} {
+		if !strings.Contains(c.buff.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, c.buff.String())
+		}
+	}
+	if strings.Contains(c.buff.String(), "empty_resetting_timer") {
+		t.Errorf("empty resetting timer exported")
+	}
+}
+
+// Tests that the HTTP handler walks the registry in name order.
+func TestHandler(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("b/gauge", registry).Update(2)
+	metrics.NewRegisteredCounter("a/counter", registry).Inc(1)
+
+	rec := httptest.NewRecorder()
+	Handler(registry).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/metrics/prometheus", nil))
+
+	body, _ := ioutil.ReadAll(rec.Body)
+	want := "# TYPE a_counter gauge\na_counter 1\n\n# TYPE b_gauge gauge\nb_gauge 2\n\n"
+	if string(body) != want {
+		t.Errorf("output mismatch:\nhave:\n%s\nwant:\n%s", body, want)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+)
+
+var (
+	// errDevUnsupported is returned if developer mode is requested with a
+	// consensus engine unable to seal blocks on demand.
+	errDevUnsupported = errors.New("consensus engine doesn't support on-demand sealing")
+
+	// errDevDisabled is returned if a developer mode operation is requested
+	// while the mode is not enabled.
+	errDevDisabled = errors.New("developer mode not enabled")
+
+	// errWorkerClosed is returned if a request is made to an already closed worker.
+	errWorkerClosed = errors.New("worker closed")
+)
+
+// devSealer is implemented by consensus engines which are able to seal blocks
+// instantly, which is a prerequisite of developer mode.
+type devSealer interface {
+	SealNow(chain consensus.ChainReader, block *types.Block) (*types.Block, error)
+}
+
+// devReq is a request to mine a single block on demand.
+type devReq struct {
+	apply func(*state.StateDB) // Optional state modification to apply before the transactions
+
+	block *types.Block
+	err   error
+	done  chan struct{}
+}
+
+// devClock tracks the block timestamp overrides of the developer mode.
+type devClock struct {
+	lock   sync.Mutex
+	offset int64 // Seconds added to the wall clock for new blocks
+	next   int64 // Timestamp forced onto the next block, zero if unset
+}
+
+// timestamp returns the timestamp to use for the next block mined on top of a
+// parent with the given timestamp, consuming any one-shot override.
+func (c *devClock) timestamp(parent int64) int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now().Unix()
+	if c.next != 0 {
+		// Subsequent blocks continue from the forced timestamp
+		c.offset, c.next = c.next-now, 0
+	}
+	timestamp := now + c.offset
+	if timestamp < parent {
+		timestamp = parent
+	}
+	return timestamp
+}
+
+// isDevMode returns an indicator whpaaer blocks are only sealed on demand.
+func (w *worker) isDevMode() bool {
+	return atomic.LoadInt32(&w.devMode) == 1
+}
+
+// commitDevBlock assembles a block on top of the current head from all pending
+// transactions, seals it instantly and writes it into the chain. The optional
+// apply callback may modify the state before any transaction is executed.
+func (w *worker) commitDevBlock(apply func(*state.StateDB)) (*types.Block, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	sealer, ok := w.engine.(devSealer)
+	if !ok {
+		return nil, errDevUnsupported
+	}
+	parent := w.chain.CurrentBlock()
+	timestamp := w.clock.timestamp(parent.Time().Int64())
+
+	num := parent.Number()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     num.Add(num, common.Big1),
+		GasLimit:   core.CalcGasLimit(parent, w.gasFloor, w.gasCeil),
+		Extra:      w.extra,
+		Coinbase:   w.coinbase,
+	}
+	if err := w.engine.Prepare(w.chain, header); err != nil {
+		return nil, err
+	}
+	// Engines may round the timestamp to the wall clock, override it afterwards
+	header.Time = big.NewInt(timestamp)
+
+	if err := w.makeCurrent(parent, header); err != nil {
+		return nil, err
+	}
+	if apply != nil {
+		apply(w.current.state)
+
+		// Flush the modifications, the journal isn't carried over by state copies
+		w.current.state.Finalise(w.config.IsEIP158(header.Number))
+	}
+	pending, err := w.paa.TxPool().Pending()
+	if err != nil {
+		return nil, err
+	}
+	w.fillTransactions(pending, nil)
+
+	// Seal and write a copy, the current environment is still used for the
+	// pending state until the next head arrives
+	receipts := make([]*types.Receipt, len(w.current.receipts))
+	for i, receipt := range w.current.receipts {
+		receipts[i] = new(types.Receipt)
+		*receipts[i] = *receipt
+	}
+	s := w.current.state.Copy()
+	block, err := w.engine.Finalize(w.chain, w.current.header, s, w.current.txs, nil, receipts)
+	if err != nil {
+		return nil, err
+	}
+	if block, err = sealer.SealNow(w.chain, block); err != nil {
+		return nil, err
+	}
+	if err := w.writeBlock(block, receipts, s); err != nil {
+		return nil, err
+	}
+	log.Info("Mined developer block", "number", block.Number(), "hash", block.Hash(), "txs", len(block.Transactions()), "time", timestamp)
+	return block, nil
+}
+
+// EnableDevMode switches the miner into developer mode, where blocks are not
+// sealed continuously anymore, but only on demand via MineBlock or when new
+// transactions arrive while mining is running.
+func (self *Miner) EnableDevMode() error {
+	if _, ok := self.engine.(devSealer); !ok {
+		return errDevUnsupported
+	}
+	atomic.StoreInt32(&self.worker.devMode, 1)
+	return nil
+}
+
+// MineBlock mines a single block on top of the current head in developer mode,
+// containing all executable pending transactions. The optional apply callback
+// may modify the state of the block before any transaction is executed.
+func (self *Miner) MineBlock(apply func(*state.StateDB)) (*types.Block, error) {
+	if !self.worker.isDevMode() {
+		return nil, errDevDisabled
+	}
+	req := &devReq{apply: apply, done: make(chan struct{})}
+	select {
+	case self.worker.devCh <- req:
+	case <-self.worker.exitCh:
+		return nil, errWorkerClosed
+	}
+	<-req.done
+	return req.block, req.err
+}
+
+// SetNextBlockTimestamp forces the timestamp of the next block mined in developer
+// mode. Subsequent blocks continue counting from there.
+func (self *Miner) SetNextBlockTimestamp(timestamp uint64) error {
+	if !self.worker.isDevMode() {
+		return errDevDisabled
+	}
+	if head := self.worker.chain.CurrentBlock(); head.Time().Uint64() > timestamp {
+		return fmt.Errorf("timestamp %d is before the current head's %d", timestamp, head.Time())
+	}
+	clock := &self.worker.clock
+
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	clock.next = int64(timestamp)
+	return nil
+}
+
+// TimeOffset returns the number of seconds developer mode blocks are shifted
+// from the wall clock.
+func (self *Miner) TimeOffset() int64 {
+	clock := &self.worker.clock
+
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	return clock.offset
+}
+
+// SetTimeOffset sets the number of seconds developer mode blocks are shifted
+// from the wall clock, dropping any pending next block timestamp.
+func (self *Miner) SetTimeOffset(offset int64) {
+	clock := &self.worker.clock
+
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	clock.offset, clock.next = offset, 0
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/accounts"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/clique"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+)
+
+// newTestDevMiner creates a developer mode miner on top of a 0-period clique
+// chain signed by the test bank.
+func newTestDevMiner(t *testing.T) (*Miner, *testWorkerBackend) {
+	config := *params.AllCliqueProtocolChanges
+	config.Clique = &params.CliqueConfig{Period: 0, Epoch: 30000}
+
+	engine := clique.New(config.Clique, paadb.NewMemDatabase())
+	engine.Authorize(testBankAddress, func(account accounts.Account, hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, testBankKey)
+	})
+	w, b := newTestWorker(t, &config, engine, 0)
+	miner := &Miner{worker: w, engine: engine, exitCh: make(chan struct{})}
+	if err := miner.EnableDevMode(); err != nil {
+		t.Fatalf("failed to enable developer mode: %v", err)
+	}
+	return miner, b
+}
+
+// Tests that developer mode mines blocks on demand, including empty ones and
+// ones carrying state modifications.
+func TestDevMineBlocks(t *testing.T) {
+	miner, b := newTestDevMiner(t)
+	defer miner.Close()
+
+	// The first block picks up the pending transaction
+	block, err := miner.MineBlock(nil)
+	if err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+	if block.NumberU64() != 1 || len(block.Transactions()) != len(pendingTxs) {
+		t.Fatalf("block mismatch: have #%d with %d txs, want #1 with %d txs", block.NumberU64(), len(block.Transactions()), len(pendingTxs))
+	}
+	// Subsequent blocks are mined even without transactions
+	block, err = miner.MineBlock(nil)
+	if err != nil {
+		t.Fatalf("failed to mine empty block: %v", err)
+	}
+	if head := b.chain.CurrentBlock(); head.Hash() != block.Hash() || head.NumberU64() != 2 {
+		t.Fatalf("head mismatch: have #%d %x, want #2 %x", head.NumberU64(), head.Hash(), block.Hash())
+	}
+	// State modifications are persisted into the mined block
+	var (
+		balance = big.NewInt(12345)
+		code    = []byte{0x60, 0x00}
+		slot    = common.HexToHash("0x01")
+		value   = common.HexToHash("0xff")
+	)
+	if _, err := miner.MineBlock(func(statedb *state.StateDB) {
+		statedb.SetBalance(testUserAddress, balance)
+		statedb.SetCode(testUserAddress, code)
+		statedb.SetState(testUserAddress, slot, value)
+	}); err != nil {
+		t.Fatalf("failed to mine modifying block: %v", err)
+	}
+	statedb, err := b.chain.State()
+	if err != nil {
+		t.Fatalf("failed to retrieve head state: %v", err)
+	}
+	if have := statedb.GetBalance(testUserAddress); have.Cmp(balance) != 0 {
+		t.Errorf("balance mismatch: have %v, want %v", have, balance)
+	}
+	if have := statedb.GetCode(testUserAddress); string(have) != string(code) {
+		t.Errorf("code mismatch: have %x, want %x", have, code)
+	}
+	if have := statedb.GetState(testUserAddress, slot); have != value {
+		t.Errorf("storage mismatch: have %x, want %x", have, value)
+	}
+}
+
+// Tests that developer mode blocks honour the requested timestamp overrides.
+func TestDevTimestamps(t *testing.T) {
+	miner, _ := newTestDevMiner(t)
+	defer miner.Close()
+
+	// A forced timestamp is used for the next block and counted from afterwards
+	next := uint64(time.Now().Add(24 * time.Hour).Unix())
+	if err := miner.SetNextBlockTimestamp(next); err != nil {
+		t.Fatalf("failed to set next timestamp: %v", err)
+	}
+	block, err := miner.MineBlock(nil)
+	if err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+	if block.Time().Uint64() != next {
+		t.Fatalf("timestamp mismatch: have %v, want %v", block.Time(), next)
+	}
+	block, err = miner.MineBlock(nil)
+	if err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+	if have := block.Time().Uint64(); have < next || have > next+5 {
+		t.Fatalf("follow-up timestamp mismatch: have %v, want ~%v", have, next)
+	}
+	// Timestamps before the head are rejected
+	if err := miner.SetNextBlockTimestamp(next - 1); err == nil {
+		t.Fatalf("timestamp before head accepted")
+	}
+	// Increasing the time offset shifts subsequent blocks
+	miner.SetTimeOffset(miner.TimeOffset() + 3600)
+
+	block, err = miner.MineBlock(nil)
+	if err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+	if have := block.Time().Uint64(); have < next+3600 || have > next+3600+5 {
+		t.Fatalf("shifted timestamp mismatch: have %v, want ~%v", have, next+3600)
+	}
+}
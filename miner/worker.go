@@ -147,6 +147,7 @@ type worker struct {
 	exitCh             chan struct{}
 	resubmitIntervalCh chan time.Duration
 	resubmitAdjustCh   chan *intervalAdjust
+	devCh              chan *devReq
 
 	current      *environment                 // An environment for current running cycle.
 	localUncles  map[common.Hash]*types.Block // A set of side blocks generated locally as the possible uncle blocks.
@@ -167,6 +168,9 @@ type worker struct {
 	// atomic status counters
 	running int32 // The indicator whpaaer the consensus engine is running or not.
 	newTxs  int32 // New arrival transaction count since last sealing work submitting.
+	devMode int32 // The indicator whpaaer blocks are only sealed on demand (developer mode).
+
+	clock devClock // Block timestamp overrides of the developer mode
 
 	// External functions
 	isLocalBlock func(block *types.Block) bool // Function used to determine whpaaer the specified block is mined by local miner.
@@ -202,6 +206,7 @@ func newWorker(config *params.ChainConfig, engine consensus.Engine, paa Backend,
 		startCh:            make(chan struct{}, 1),
 		resubmitIntervalCh: make(chan time.Duration),
 		resubmitAdjustCh:   make(chan *intervalAdjust, resubmitAdjustChanSize),
+		devCh:              make(chan *devReq),
 	}
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = paa.TxPool().SubscribeNewTxsEvent(worker.txsCh)
@@ -353,8 +358,9 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 
 		case <-timer.C:
 			// If mining is running resubmit a new work cycle periodically to pull in
-			// higher priced transactions. Disable this overhead for pending blocks.
-			if w.isRunning() && (w.config.Clique == nil || w.config.Clique.Period > 0) {
+			// higher priced transactions. Disable this overhead for pending blocks and
+			// for Istanbul, where resubmitting would restart the agreement.
+			if w.isRunning() && (w.config.Clique == nil || w.config.Clique.Period > 0) && w.config.Istanbul == nil {
 				// Short circuit if no new transaction arrives.
 				if atomic.LoadInt32(&w.newTxs) == 0 {
 					timer.Reset(recommit)
@@ -409,6 +415,10 @@ func (w *worker) mainLoop() {
 		case req := <-w.newWorkCh:
 			w.commitNewWork(req.interrupt, req.noempty, req.timestamp)
 
+		case req := <-w.devCh:
+			req.block, req.err = w.commitDevBlock(req.apply)
+			close(req.done)
+
 		case ev := <-w.chainSideCh:
 			// Short circuit for duplicate side blocks
 			if _, exist := w.localUncles[ev.Block.Hash()]; exist {
@@ -469,7 +479,13 @@ func (w *worker) mainLoop() {
 				w.updateSnapshot()
 			} else {
 				// If we're mining, but nothing is being processed, wake on new transactions
-				if w.config.Clique != nil && w.config.Clique.Period == 0 {
+				if w.isDevMode() {
+					if w.isRunning() {
+						if _, err := w.commitDevBlock(nil); err != nil {
+							log.Warn("Failed to mine developer block", "err", err)
+						}
+					}
+				} else if w.config.Clique != nil && w.config.Clique.Period == 0 {
 					w.commitNewWork(nil, false, time.Now().Unix())
 				}
 			}
@@ -561,51 +577,59 @@ func (w *worker) resultLoop() {
 				continue
 			}
 			// Different block could share same sealhash, deep copy here to prevent write-write conflict.
-			var (
-				receipts = make([]*types.Receipt, len(task.receipts))
-				logs     []*types.Log
-			)
+			receipts := make([]*types.Receipt, len(task.receipts))
 			for i, receipt := range task.receipts {
 				receipts[i] = new(types.Receipt)
 				*receipts[i] = *receipt
-				// Update the block hash in all logs since it is now available and not when the
-				// receipt/log of individual transactions were created.
-				for _, log := range receipt.Logs {
-					log.BlockHash = hash
-				}
-				logs = append(logs, receipt.Logs...)
 			}
-			// Commit block and state to database.
-			stat, err := w.chain.WriteBlockWithState(block, receipts, task.state)
-			if err != nil {
+			if err := w.writeBlock(block, receipts, task.state); err != nil {
 				log.Error("Failed writing block to chain", "err", err)
 				continue
 			}
 			log.Info("Successfully sealed new block", "number", block.Number(), "sealhash", sealhash, "hash", hash,
 				"elapsed", common.PrettyDuration(time.Since(task.createdAt)))
 
-			// Broadcast the block and announce chain insertion event
-			w.mux.Post(core.NewMinedBlockEvent{Block: block})
-
-			var events []interface{}
-			switch stat {
-			case core.CanonStatTy:
-				events = append(events, core.ChainEvent{Block: block, Hash: block.Hash(), Logs: logs})
-				events = append(events, core.ChainHeadEvent{Block: block})
-			case core.SideStatTy:
-				events = append(events, core.ChainSideEvent{Block: block})
-			}
-			w.chain.PostChainEvents(events, logs)
-
-			// Insert the block into the set of pending ones to resultLoop for confirmations
-			w.unconfirmed.Insert(block.NumberU64(), block.Hash())
-
 		case <-w.exitCh:
 			return
 		}
 	}
 }
 
+// writeBlock commits a sealed block and its state to the database, broadcasts it
+// and announces the chain insertion events.
+func (w *worker) writeBlock(block *types.Block, receipts []*types.Receipt, state *state.StateDB) error {
+	// Update the block hash in all logs since it is now available and not when the
+	// receipt/log of individual transactions were created.
+	var logs []*types.Log
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			log.BlockHash = block.Hash()
+		}
+		logs = append(logs, receipt.Logs...)
+	}
+	// Commit block and state to database.
+	stat, err := w.chain.WriteBlockWithState(block, receipts, state)
+	if err != nil {
+		return err
+	}
+	// Broadcast the block and announce chain insertion event
+	w.mux.Post(core.NewMinedBlockEvent{Block: block})
+
+	var events []interface{}
+	switch stat {
+	case core.CanonStatTy:
+		events = append(events, core.ChainEvent{Block: block, Hash: block.Hash(), Logs: logs})
+		events = append(events, core.ChainHeadEvent{Block: block})
+	case core.SideStatTy:
+		events = append(events, core.ChainSideEvent{Block: block})
+	}
+	w.chain.PostChainEvents(events, logs)
+
+	// Insert the block into the set of pending ones to resultLoop for confirmations
+	w.unconfirmed.Insert(block.NumberU64(), block.Hash())
+	return nil
+}
+
 // makeCurrent creates a new environment for the current cycle.
 func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 	state, err := w.chain.StateAt(parent.Root())
@@ -826,8 +850,9 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	if parent.Time().Cmp(new(big.Int).SetInt64(timestamp)) >= 0 {
 		timestamp = parent.Time().Int64() + 1
 	}
-	// this will ensure we're not going off too far in the future
-	if now := time.Now().Unix(); timestamp > now+1 {
+	// this will ensure we're not going off too far in the future. Developer mode
+	// only seals on demand, so time traveled chains don't need to wait.
+	if now := time.Now().Unix(); timestamp > now+1 && !w.isDevMode() {
 		wait := time.Duration(timestamp-now) * time.Second
 		log.Info("Mining too far in the future", "wait", common.PrettyDuration(wait))
 		time.Sleep(wait)
@@ -919,6 +944,15 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		w.updateSnapshot()
 		return
 	}
+	if w.fillTransactions(pending, interrupt) {
+		return
+	}
+	w.commit(uncles, w.fullTaskHook, true, tstart)
+}
+
+// fillTransactions commits the given pending transactions into the current block,
+// prioritizing local ones. It returns true if the filling was interrupted.
+func (w *worker) fillTransactions(pending map[common.Address]types.Transactions, interrupt *int32) bool {
 	// Split the pending transactions into locals and remotes
 	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
 	for _, account := range w.paa.TxPool().Locals() {
@@ -930,16 +964,16 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	if len(localTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
 		if w.commitTransactions(txs, w.coinbase, interrupt) {
-			return
+			return true
 		}
 	}
 	if len(remoteTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
 		if w.commitTransactions(txs, w.coinbase, interrupt) {
-			return
+			return true
 		}
 	}
-	w.commit(uncles, w.fullTaskHook, true, tstart)
+	return false
 }
 
 // commit runs any post-transaction state modifications, assembles the final block
@@ -956,7 +990,7 @@ func (w *worker) commit(uncles []*types.Header, interval func(), update bool, st
 	if err != nil {
 		return err
 	}
-	if w.isRunning() {
+	if w.isRunning() && !w.isDevMode() {
 		if interval != nil {
 			interval()
 		}
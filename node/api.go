@@ -19,6 +19,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -106,6 +107,57 @@ func (api *PrivateAdminAPI) RemoveTrustedPeer(url string) (bool, error) {
 	return true, nil
 }
 
+// maxBanSeconds is the longest ban that fits in a time.Duration.
+const maxBanSeconds = uint64(math.MaxInt64 / int64(time.Second))
+
+// BanPeer disconnects a remote node and refuses any connection to or from it
+// for the given number of seconds, or p2p.DefaultBanDuration if omitted. The ban
+// persists across restarts. It returns the expiry time of the ban.
+func (api *PrivateAdminAPI) BanPeer(url string, seconds *uint64) (time.Time, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return time.Time{}, ErrNodeStopped
+	}
+	node, err := enode.ParseV4(url)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid enode: %v", err)
+	}
+	duration := p2p.DefaultBanDuration
+	if seconds != nil {
+		if *seconds == 0 || *seconds > maxBanSeconds {
+			return time.Time{}, fmt.Errorf("invalid ban duration: %d seconds, must be between 1 and %d", *seconds, maxBanSeconds)
+		}
+		duration = time.Duration(*seconds) * time.Second
+	}
+	return server.BanPeer(node.ID(), duration)
+}
+
+// UnbanPeer lifts the ban of a remote node, reporting whether it was banned.
+func (api *PrivateAdminAPI) UnbanPeer(url string) (bool, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	node, err := enode.ParseV4(url)
+	if err != nil {
+		return false, fmt.Errorf("invalid enode: %v", err)
+	}
+	return server.UnbanPeer(node.ID())
+}
+
+// ListBans retrieves the currently banned nodes along with the expiry times of
+// their bans.
+func (api *PrivateAdminAPI) ListBans() ([]p2p.BanInfo, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.Bans(), nil
+}
+
 // PeerEvents creates an RPC subscription which receives peer events from the
 // node's p2p.Server
 func (api *PrivateAdminAPI) PeerEvents(ctx context.Context) (*rpc.Subscription, error) {
@@ -188,7 +240,7 @@ func (api *PrivateAdminAPI) StartRPC(host *string, port *int, cors *string, apis
 		}
 	}
 
-	if err := api.node.startHTTP(fmt.Sprintf("%s:%d", *host, *port), api.node.rpcAPIs, modules, allowedOrigins, allowedVHosts, api.node.config.HTTPTimeouts); err != nil {
+	if err := api.node.startHTTP(fmt.Sprintf("%s:%d", *host, *port), api.node.rpcAPIs, modules, allowedOrigins, allowedVHosts, api.node.config.HTTPTimeouts, false); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -282,6 +334,16 @@ func (api *PublicAdminAPI) Peers() ([]*p2p.PeerInfo, error) {
 	return server.PeersInfo(), nil
 }
 
+// PeerStats retrieves the message and latency statistics of all connected peers,
+// broken down by protocol and message code, keyed by node identifier.
+func (api *PublicAdminAPI) PeerStats() (map[string]*p2p.PeerStats, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.PeersStats(), nil
+}
+
 // NodeInfo retrieves all the information we know about the host node at the
 // protocol granularity.
 func (api *PublicAdminAPI) NodeInfo() (*p2p.NodeInfo, error) {
@@ -18,6 +18,7 @@ package node
 
 import (
 	"crypto/ecdsa"
+	"crypto/rand"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -30,6 +31,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/accounts/keystore"
 	"github.com/PaloAltoAi/go-PaloAltoAi/accounts/usbwallet"
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
 	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
@@ -125,6 +127,11 @@ type Config struct {
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
 
+	// HTTPPathPrefix is the path the HTTP RPC API is served at (e.g. "/rpc"),
+	// leaving the rest of the endpoint to the HTTP handlers of services, such as
+	// GraphQL. An empty prefix serves the API at the root path.
+	HTTPPathPrefix string `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string `toml:",omitempty"`
@@ -151,6 +158,32 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// WSPathPrefix is the path the websocket RPC API is served at. If the websocket
+	// endpoint is configured on the same host and port as the HTTP one, they share
+	// a single listener, websocket upgrades at the HTTP path prefix being detected
+	// per request. An empty prefix serves the API at the root path.
+	WSPathPrefix string `toml:",omitempty"`
+
+	// JWTSecret is the path to a file containing the hex encoded 32 byte secret
+	// used to authenticate clients of the HTTP and websocket RPC interfaces. If
+	// set, requests need to carry an HS256 signed bearer token, which may limit
+	// the namespaces and methods accessible to the client. A new secret is
+	// generated if the file doesn't exist.
+	JWTSecret string `toml:",omitempty"`
+
+	// RPCRateLimit enables per-client request accounting and rate limiting on the
+	// HTTP and websocket RPC interfaces. Clients are throttled per remote address,
+	// or per access token if authentication is enabled.
+	RPCRateLimit *rpc.RateLimitConfig `toml:",omitempty"`
+
+	// RPCBatchLimit is the maximum number of requests a batch sent to the HTTP and
+	// websocket RPC interfaces may contain. Zero means no limit.
+	RPCBatchLimit int `toml:",omitempty"`
+
+	// RPCResponseSizeLimit is the maximum size in bytes of a (batch) response sent
+	// by the HTTP and websocket RPC interfaces. Zero means no limit.
+	RPCResponseSizeLimit int `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 
@@ -342,6 +375,45 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 	return key
 }
 
+// loadJWTSecret retrieves the shared secret used to authenticate RPC clients,
+// generating and storing a new one if the configured file doesn't exist yet.
+// Nil is returned if authentication is disabled.
+func (c *Config) loadJWTSecret() ([]byte, error) {
+	if c.JWTSecret == "" {
+		return nil, nil
+	}
+	path := c.ResolvePath(c.JWTSecret)
+	if path == "" {
+		return nil, fmt.Errorf("relative JWT secret path %q without data directory", c.JWTSecret)
+	}
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		secret := common.FromHex(strings.TrimSpace(string(data)))
+		if len(secret) != 32 {
+			return nil, fmt.Errorf("invalid JWT secret in %s: want 32 hex encoded bytes, have %d", path, len(secret))
+		}
+		return secret, nil
+
+	case os.IsNotExist(err):
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(path, []byte(hexutil.Encode(secret)), 0600); err != nil {
+			return nil, err
+		}
+		log.Info("Generated JWT secret", "path", path)
+		return secret, nil
+
+	default:
+		return nil, err
+	}
+}
+
 // StaticNodes returns a list of node enode URLs configured as static nodes.
 func (c *Config) StaticNodes() []*enode.Node {
 	return c.parsePersistentNodes(&c.staticNodesWarning, c.ResolvePath(datadirStaticNodes))
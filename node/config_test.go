@@ -148,3 +148,43 @@ func TestNodeKeyPersistency(t *testing.T) {
 		t.Fatalf("ephemeral node key persisted to disk")
 	}
 }
+
+// Tests that JWT secrets are generated if missing, persisted and reloaded, and
+// that malformed ones are rejected.
+func TestJWTSecretPersistency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "node-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary data directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Authentication is disabled without a configured secret
+	config := &Config{Name: "unit-test", DataDir: dir}
+	if secret, err := config.loadJWTSecret(); secret != nil || err != nil {
+		t.Fatalf("secret loaded without configuration: %x, %v", secret, err)
+	}
+	// Configure a missing secret and ensure it's generated
+	config.JWTSecret = "jwtsecret"
+	secret1, err := config.loadJWTSecret()
+	if err != nil {
+		t.Fatalf("failed to generate secret: %v", err)
+	}
+	if len(secret1) != 32 {
+		t.Fatalf("generated secret length mismatch: have %d, want 32", len(secret1))
+	}
+	// Reload the secret and ensure it's the persisted one
+	secret2, err := config.loadJWTSecret()
+	if err != nil {
+		t.Fatalf("failed to load persisted secret: %v", err)
+	}
+	if !bytes.Equal(secret1, secret2) {
+		t.Fatalf("persisted secret mismatch: have %x, want %x", secret2, secret1)
+	}
+	// Corrupt the secret and ensure it's rejected
+	if err := ioutil.WriteFile(filepath.Join(dir, "unit-test", "jwtsecret"), []byte("0x1234"), 0600); err != nil {
+		t.Fatalf("failed to corrupt secret: %v", err)
+	}
+	if _, err := config.loadJWTSecret(); err == nil {
+		t.Fatalf("malformed secret accepted")
+	}
+}
@@ -43,10 +43,15 @@ var DefaultConfig = Config{
 	HTTPTimeouts:     rpc.DefaultHTTPTimeouts,
 	WSPort:           DefaultWSPort,
 	WSModules:        []string{"net", "web3"},
+
+	RPCBatchLimit:        1000,
+	RPCResponseSizeLimit: 25 * 1024 * 1024,
+
 	P2P: p2p.Config{
-		ListenAddr: ":30303",
-		MaxPeers:   25,
-		NAT:        nat.Any(),
+		ListenAddr:        ":30303",
+		MaxPeers:          25,
+		MaxPeersPerSubnet: 4,
+		NAT:               nat.Any(),
 	},
 }
 
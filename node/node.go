@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -58,14 +59,20 @@ type Node struct {
 	ipcListener net.Listener // IPC RPC listener socket to serve API requests
 	ipcHandler  *rpc.Server  // IPC RPC request handler to process the API requests
 
-	httpEndpoint  string       // HTTP endpoint (interface + port) to listen at (empty = HTTP disabled)
-	httpWhitelist []string     // HTTP RPC modules to allow through this endpoint
-	httpListener  net.Listener // HTTP RPC listener socket to server API requests
-	httpHandler   *rpc.Server  // HTTP RPC request handler to process the API requests
+	httpEndpoint  string                  // HTTP endpoint (interface + port) to listen at (empty = HTTP disabled)
+	httpWhitelist []string                // HTTP RPC modules to allow through this endpoint
+	httpListener  net.Listener            // HTTP RPC listener socket to server API requests
+	httpHandler   *rpc.Server             // HTTP RPC request handler to process the API requests
+	httpServices  map[string]http.Handler // Extra HTTP handlers mounted by services next to the RPC API
+	httpOpen      map[string]bool         // Paths of the extra HTTP handlers exempt from the RPC access restrictions
+
+	jwtSecret []byte // Shared secret authenticating HTTP and websocket RPC clients (nil = no authentication)
 
 	wsEndpoint string       // Websocket endpoint (interface + port) to listen at (empty = websocket disabled)
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
+	wsShared   bool         // Whether websocket RPC is served on the HTTP listener (no own listener)
+	wsSwitch   *wsSwitch    // Gate of the websocket upgrades on the shared HTTP listener
 
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
@@ -97,6 +104,13 @@ func New(conf *Config) (*Node, error) {
 	if strings.HasSuffix(conf.Name, ".ipc") {
 		return nil, errors.New(`Config.Name cannot end in ".ipc"`)
 	}
+	// Ensure the RPC path prefixes are absolute
+	if conf.HTTPPathPrefix != "" && !strings.HasPrefix(conf.HTTPPathPrefix, "/") {
+		return nil, errors.New(`Config.HTTPPathPrefix must start with '/'`)
+	}
+	if conf.WSPathPrefix != "" && !strings.HasPrefix(conf.WSPathPrefix, "/") {
+		return nil, errors.New(`Config.WSPathPrefix must start with '/'`)
+	}
 	// Ensure that the AccountManager method works before the node has started.
 	// We rely on this in cmd/gpaa.
 	am, ephemeralKeystore, err := makeAccountManager(conf)
@@ -255,6 +269,41 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
 	}
+	// Gather all the extra HTTP handlers to mount, rejecting conflicting paths
+	var (
+		handlers = make(map[string]http.Handler)
+		open     = make(map[string]bool)
+	)
+	mount := func(mounts map[string]http.Handler, unrestricted bool) error {
+		for path, handler := range mounts {
+			if _, exists := handlers[path]; exists {
+				return fmt.Errorf("duplicate HTTP handler for path %q", path)
+			}
+			handlers[path], open[path] = handler, unrestricted
+		}
+		return nil
+	}
+	for _, service := range services {
+		if service, ok := service.(HTTPService); ok {
+			if err := mount(service.HTTPHandlers(), false); err != nil {
+				return err
+			}
+		}
+		if service, ok := service.(OpenHTTPService); ok {
+			if err := mount(service.OpenHTTPHandlers(), true); err != nil {
+				return err
+			}
+		}
+	}
+	n.httpServices, n.httpOpen = handlers, open
+
+	// Load the secret authenticating remote clients, if enabled
+	secret, err := n.config.loadJWTSecret()
+	if err != nil {
+		return err
+	}
+	n.jwtSecret = secret
+
 	// Start the various API endpoints, terminating all in case of errors
 	if err := n.startInProc(apis); err != nil {
 		return err
@@ -263,16 +312,22 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopInProc()
 		return err
 	}
-	if err := n.startHTTP(n.httpEndpoint, apis, n.config.HTTPModules, n.config.HTTPCors, n.config.HTTPVirtualHosts, n.config.HTTPTimeouts); err != nil {
+	// Serve websocket RPC on the HTTP listener if both are configured on the same
+	// endpoint (random ports are never shared)
+	shared := n.httpEndpoint != "" && n.httpEndpoint == n.wsEndpoint && n.config.HTTPPort != 0
+
+	if err := n.startHTTP(n.httpEndpoint, apis, n.config.HTTPModules, n.config.HTTPCors, n.config.HTTPVirtualHosts, n.config.HTTPTimeouts, shared); err != nil {
 		n.stopIPC()
 		n.stopInProc()
 		return err
 	}
-	if err := n.startWS(n.wsEndpoint, apis, n.config.WSModules, n.config.WSOrigins, n.config.WSExposeAll); err != nil {
-		n.stopHTTP()
-		n.stopIPC()
-		n.stopInProc()
-		return err
+	if !shared {
+		if err := n.startWS(n.wsEndpoint, apis, n.config.WSModules, n.config.WSOrigins, n.config.WSExposeAll); err != nil {
+			n.stopHTTP()
+			n.stopIPC()
+			n.stopInProc()
+			return err
+		}
 	}
 	// All API endpoints started successfully
 	n.rpcAPIs = apis
@@ -330,26 +385,134 @@ func (n *Node) stopIPC() {
 	}
 }
 
-// startHTTP initializes and starts the HTTP RPC endpoint.
-func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts) error {
+// endpointConfig returns the access restrictions and limits of the HTTP and
+// websocket RPC endpoints.
+func (n *Node) endpointConfig() rpc.EndpointConfig {
+	return rpc.EndpointConfig{
+		JWTSecret:         n.jwtSecret,
+		RateLimit:         n.config.RPCRateLimit,
+		BatchLimit:        n.config.RPCBatchLimit,
+		ResponseSizeLimit: n.config.RPCResponseSizeLimit,
+	}
+}
+
+// startHTTP initializes and starts the HTTP RPC endpoint, along with the HTTP
+// handlers of the services. If shared is set, the websocket RPC endpoint is
+// served on the same listener too.
+func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts, shared bool) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, timeouts)
+	rpcHandler, handler, err := rpc.NewHTTPEndpoint(apis, modules, cors, vhosts, n.endpointConfig())
 	if err != nil {
 		return err
 	}
-	n.log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","))
+	// Mount the RPC handlers next to the ones of the services, which share the
+	// authentication and limits of the RPC API unless opted out
+	mounts := make(map[string]http.Handler)
+	for path, h := range n.httpServices {
+		if n.httpOpen[path] {
+			mounts[path] = h
+			continue
+		}
+		mounts[path] = rpc.NewServiceHandler(path, n.jwtSecret, n.config.RPCRateLimit, h)
+	}
+	var (
+		wsHandler *rpc.Server
+		wsGate    *wsSwitch
+	)
+	if shared {
+		var ws http.Handler
+		if ws, wsHandler, err = rpc.NewWSEndpoint(apis, n.config.WSModules, n.config.WSOrigins, n.config.WSExposeAll, n.endpointConfig()); err != nil {
+			handler.Stop()
+			return err
+		}
+		wsGate = &wsSwitch{handler: ws}
+		if strings.TrimSuffix(n.config.WSPathPrefix, "/") == strings.TrimSuffix(n.config.HTTPPathPrefix, "/") {
+			rpcHandler = rpc.NewUpgradeHandler(rpcHandler, wsGate)
+		} else {
+			err = mountRPC(mounts, n.config.WSPathPrefix, wsGate)
+		}
+	}
+	if err == nil {
+		err = mountRPC(mounts, n.config.HTTPPathPrefix, rpcHandler)
+	}
+	var listener net.Listener
+	if err == nil {
+		mux := http.NewServeMux()
+		for path, h := range mounts {
+			mux.Handle(path, h)
+		}
+		listener, err = rpc.StartHTTPServer(endpoint, timeouts, mux)
+	}
+	if err != nil {
+		handler.Stop()
+		if wsHandler != nil {
+			wsHandler.Stop()
+		}
+		return err
+	}
+	n.log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s%s", endpoint, n.config.HTTPPathPrefix), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","), "auth", n.jwtSecret != nil, "ratelimit", n.config.RPCRateLimit != nil)
+	if shared {
+		n.log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s%s", endpoint, n.config.WSPathPrefix), "shared", true)
+	}
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
 	n.httpListener = listener
 	n.httpHandler = handler
+	if shared {
+		n.wsHandler, n.wsShared, n.wsSwitch = wsHandler, true, wsGate
+	}
+	return nil
+}
+
+// wsSwitch serves websocket RPC on the shared HTTP listener, allowing it to be
+// turned off without closing the listener.
+type wsSwitch struct {
+	handler http.Handler // Websocket handler to serve upgrades with (nil = disabled)
+	lock    sync.RWMutex
+}
 
+// ServeHTTP implements http.Handler, rejecting upgrades if websocket RPC is off.
+func (s *wsSwitch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.RLock()
+	handler := s.handler
+	s.lock.RUnlock()
+
+	if handler == nil {
+		http.Error(w, "websocket RPC disabled", http.StatusNotFound)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// disable stops serving websocket upgrades.
+func (s *wsSwitch) disable() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.handler = nil
+}
+
+// mountRPC adds an RPC handler to the handlers served by an endpoint at the given
+// path prefix, rejecting conflicts with the handlers of services.
+func mountRPC(mounts map[string]http.Handler, prefix string, handler http.Handler) error {
+	paths := []string{"/"}
+	if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" {
+		paths = []string{prefix, prefix + "/"}
+	}
+	for _, path := range paths {
+		if _, exists := mounts[path]; exists {
+			return fmt.Errorf("HTTP handler conflicts with RPC path %q", path)
+		}
+		mounts[path] = handler
+	}
 	return nil
 }
 
-// stopHTTP terminates the HTTP RPC endpoint.
+// stopHTTP terminates the HTTP RPC endpoint, along with the websocket RPC endpoint
+// if it shares the listener.
 func (n *Node) stopHTTP() {
 	if n.httpListener != nil {
 		n.httpListener.Close()
@@ -361,6 +524,20 @@ func (n *Node) stopHTTP() {
 		n.httpHandler.Stop()
 		n.httpHandler = nil
 	}
+	if n.wsShared {
+		n.stopSharedWS()
+	}
+	n.wsSwitch = nil
+}
+
+// stopSharedWS terminates the websocket RPC endpoint served on the HTTP listener,
+// rejecting further upgrades while leaving the HTTP endpoint running.
+func (n *Node) stopSharedWS() {
+	n.wsSwitch.disable()
+	n.wsHandler.Stop()
+	n.wsHandler, n.wsShared = nil, false
+
+	n.log.Info("WebSocket endpoint closed", "url", fmt.Sprintf("ws://%s", n.httpEndpoint), "shared", true)
 }
 
 // startWS initializes and starts the websocket RPC endpoint.
@@ -369,11 +546,27 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	wsHandler, handler, err := rpc.NewWSEndpoint(apis, modules, wsOrigins, exposeAll, n.endpointConfig())
 	if err != nil {
 		return err
 	}
-	n.log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()))
+	mounts := make(map[string]http.Handler)
+	if err := mountRPC(mounts, n.config.WSPathPrefix, wsHandler); err != nil {
+		handler.Stop()
+		return err
+	}
+	mux := http.NewServeMux()
+	for path, h := range mounts {
+		mux.Handle(path, h)
+	}
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		handler.Stop()
+		return err
+	}
+	go (&http.Server{Handler: mux}).Serve(listener)
+
+	n.log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s%s", listener.Addr(), n.config.WSPathPrefix), "auth", n.jwtSecret != nil, "ratelimit", n.config.RPCRateLimit != nil)
 	// All listeners booted successfully
 	n.wsEndpoint = endpoint
 	n.wsListener = listener
@@ -382,8 +575,13 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	return nil
 }
 
-// stopWS terminates the websocket RPC endpoint.
+// stopWS terminates the websocket RPC endpoint. If it shares the HTTP listener,
+// only the upgrades are turned off and the HTTP endpoint keeps running.
 func (n *Node) stopWS() {
+	if n.wsShared {
+		n.stopSharedWS()
+		return
+	}
 	if n.wsListener != nil {
 		n.wsListener.Close()
 		n.wsListener = nil
@@ -412,6 +610,9 @@ func (n *Node) Stop() error {
 	n.stopHTTP()
 	n.stopIPC()
 	n.rpcAPIs = nil
+	n.httpServices = nil
+	n.httpOpen = nil
+	n.jwtSecret = nil
 	failure := &StopError{
 		Services: make(map[reflect.Type]error),
 	}
@@ -566,6 +767,9 @@ func (n *Node) WSEndpoint() string {
 	if n.wsListener != nil {
 		return n.wsListener.Addr().String()
 	}
+	if n.wsShared && n.httpListener != nil {
+		return n.httpListener.Addr().String()
+	}
 	return n.wsEndpoint
 }
 
@@ -17,8 +17,12 @@
 package node
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"net"
+	"net/http"
 	"os"
 	"reflect"
 	"testing"
@@ -573,3 +577,142 @@ func TestAPIGather(t *testing.T) {
 		}
 	}
 }
+
+// Tests that HTTP and websocket RPC can share a single listener under a path
+// prefix, next to the HTTP handlers of services.
+func TestSharedRPCEndpoint(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find free port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	config := testNodeConfig()
+	config.HTTPHost, config.HTTPPort, config.HTTPPathPrefix = "127.0.0.1", port, "/rpc"
+	config.WSHost, config.WSPort, config.WSPathPrefix = "127.0.0.1", port, "/rpc/"
+	config.WSOrigins = []string{"*"}
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	custom := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("custom")) })
+	if err := stack.Register(func(*ServiceContext) (Service, error) {
+		return &HTTPHandlerService{handlers: map[string]http.Handler{"/custom": custom}}, nil
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	// Ensure both RPC transports are served at the prefix
+	endpoint := fmt.Sprintf("127.0.0.1:%d", port)
+	httpClient, err := rpc.DialHTTP("http://" + endpoint + "/rpc")
+	if err != nil {
+		t.Fatalf("failed to dial HTTP: %v", err)
+	}
+	defer httpClient.Close()
+
+	wsClient, err := rpc.DialWebsocket(context.Background(), "ws://"+endpoint+"/rpc", "")
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	defer wsClient.Close()
+
+	for name, client := range map[string]*rpc.Client{"http": httpClient, "ws": wsClient} {
+		var modules map[string]string
+		if err := client.Call(&modules, "rpc_modules"); err != nil {
+			t.Errorf("%s: call failed: %v", name, err)
+		}
+	}
+	// Ensure the service handler is served, and nothing at the root path
+	tests := map[string]int{"/custom": http.StatusOK, "/": http.StatusNotFound}
+	for path, status := range tests {
+		res, err := http.Get("http://" + endpoint + path)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", path, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != status {
+			t.Errorf("%s: status mismatch: have %d, want %d", path, res.StatusCode, status)
+		}
+	}
+	// Ensure stopping the websocket endpoint only rejects upgrades, leaving the
+	// shared listener serving HTTP
+	if _, err := NewPrivateAdminAPI(stack).StopWS(); err != nil {
+		t.Fatalf("failed to stop websocket endpoint: %v", err)
+	}
+	if _, err := rpc.DialWebsocket(context.Background(), "ws://"+endpoint+"/rpc", ""); err == nil {
+		t.Errorf("websocket still accepted after stopping")
+	}
+	var modules map[string]string
+	if err := httpClient.Call(&modules, "rpc_modules"); err != nil {
+		t.Errorf("HTTP call failed after stopping the websocket endpoint: %v", err)
+	}
+	res, err := http.Get("http://" + endpoint + "/custom")
+	if err != nil {
+		t.Fatalf("service request failed after stopping the websocket endpoint: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("service status mismatch after stopping the websocket endpoint: have %d, want %d", res.StatusCode, http.StatusOK)
+	}
+	if _, err := NewPrivateAdminAPI(stack).StopRPC(); err != nil {
+		t.Errorf("failed to stop HTTP endpoint: %v", err)
+	}
+}
+
+// Tests that HTTP handlers of services are subject to RPC authentication unless
+// opted out.
+func TestOpenHTTPService(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find free port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	secret, err := ioutil.TempFile("", "jwtsecret")
+	if err != nil {
+		t.Fatalf("failed to create JWT secret: %v", err)
+	}
+	defer os.Remove(secret.Name())
+	secret.WriteString(fmt.Sprintf("%x", make([]byte, 32)))
+	secret.Close()
+
+	config := testNodeConfig()
+	config.HTTPHost, config.HTTPPort, config.JWTSecret = "127.0.0.1", port, secret.Name()
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("handler")) })
+	if err := stack.Register(func(*ServiceContext) (Service, error) {
+		return &OpenHTTPHandlerService{
+			HTTPHandlerService: HTTPHandlerService{handlers: map[string]http.Handler{"/restricted": handler}},
+			open:               map[string]http.Handler{"/open": handler},
+		}, nil
+	}); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	tests := map[string]int{"/open": http.StatusOK, "/restricted": http.StatusUnauthorized}
+	for path, status := range tests {
+		res, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, path))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", path, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != status {
+			t.Errorf("%s: status mismatch: have %d, want %d", path, res.StatusCode, status)
+		}
+	}
+}
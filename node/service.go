@@ -17,6 +17,8 @@
 package node
 
 import (
+	"crypto/ecdsa"
+	"net/http"
 	"reflect"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/accounts"
@@ -57,6 +59,12 @@ func (ctx *ServiceContext) ResolvePath(path string) string {
 	return ctx.config.ResolvePath(path)
 }
 
+// NodeKey retrieves the private key of the node, used by services that need to
+// sign on behalf of the node (e.g. byzantine fault tolerant validators).
+func (ctx *ServiceContext) NodeKey() *ecdsa.PrivateKey {
+	return ctx.config.NodeKey()
+}
+
 // Service retrieves a currently running service registered of a specific type.
 func (ctx *ServiceContext) Service(service interface{}) error {
 	element := reflect.ValueOf(service).Elem()
@@ -96,3 +104,24 @@ type Service interface {
 	// are all terminated.
 	Stop() error
 }
+
+// HTTPService is an optional interface of a Service serving plain HTTP handlers
+// on the node's HTTP-RPC endpoint, next to the RPC API. Each handler is mounted
+// on its own path and is responsible for enforcing its own CORS and virtual host
+// restrictions. Authentication, rate limits and the request size cap of the RPC
+// API apply to the handlers too, the path being the namespace they're permitted
+// and limited under.
+type HTTPService interface {
+	// HTTPHandlers retrieves the handlers to mount, keyed by path.
+	HTTPHandlers() map[string]http.Handler
+}
+
+// OpenHTTPService is an optional interface of a Service serving plain HTTP
+// handlers on the node's HTTP-RPC endpoint, exempt from the authentication, rate
+// limits and request size cap of the RPC API. It's meant for endpoints polled by
+// clients unable to authenticate, like the probes of orchestrators, and the
+// handlers must not expose anything sensitive or expensive.
+type OpenHTTPService interface {
+	// OpenHTTPHandlers retrieves the unrestricted handlers to mount, keyed by path.
+	OpenHTTPHandlers() map[string]http.Handler
+}
@@ -20,6 +20,7 @@
 package node
 
 import (
+	"net/http"
 	"reflect"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
@@ -131,3 +132,20 @@ func (api *OneMethodAPI) TheOneMethod() {
 		api.fun()
 	}
 }
+
+// HTTPHandlerService is a service mounting extra HTTP handlers on the node.
+type HTTPHandlerService struct {
+	NoopService
+	handlers map[string]http.Handler
+}
+
+func (s *HTTPHandlerService) HTTPHandlers() map[string]http.Handler { return s.handlers }
+
+// OpenHTTPHandlerService is a service mounting extra HTTP handlers on the node,
+// both restricted and exempt from the RPC access restrictions.
+type OpenHTTPHandlerService struct {
+	HTTPHandlerService
+	open map[string]http.Handler
+}
+
+func (s *OpenHTTPHandlerService) OpenHTTPHandlers() map[string]http.Handler { return s.open }
@@ -0,0 +1,206 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/netutil"
+)
+
+const (
+	// DefaultBanDuration is the ban duration used when none is given.
+	DefaultBanDuration = 24 * time.Hour
+
+	// peerSubnetBits and peerSubnetBits6 are the prefix lengths of the IPv4 and
+	// IPv6 subnets that MaxPeersPerSubnet applies to.
+	peerSubnetBits  = 24
+	peerSubnetBits6 = 48
+)
+
+var errInvalidBanDuration = errors.New("invalid ban duration")
+
+// BanInfo represents a ban list entry.
+type BanInfo struct {
+	ID      enode.ID  `json:"id"`
+	Expires time.Time `json:"expires"`
+}
+
+// banList tracks banned nodes. Bans are persisted in the node database and are
+// cached in memory because they are checked for every connection.
+type banList struct {
+	db   *enode.DB
+	bans map[enode.ID]time.Time
+	lock sync.Mutex
+}
+
+// newBanList creates a ban list, loading the unexpired bans from the database.
+// Expired bans failing to be deleted are retried on the next load.
+func newBanList(db *enode.DB) *banList {
+	b := &banList{db: db, bans: make(map[enode.ID]time.Time)}
+	now := time.Now()
+	for id, expiry := range db.Bans() {
+		if now.Before(expiry) {
+			b.bans[id] = expiry
+		} else {
+			db.DeleteBan(id)
+		}
+	}
+	return b
+}
+
+// add bans a node for the given duration, returning the expiry time of the ban.
+// The ban is not applied if it can't be persisted.
+func (b *banList) add(id enode.ID, d time.Duration) (time.Time, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	expiry := time.Now().Add(d)
+	if err := b.db.UpdateBan(id, expiry); err != nil {
+		return time.Time{}, err
+	}
+	b.bans[id] = expiry
+	return expiry, nil
+}
+
+// remove lifts the ban of a node, reporting whether it was banned. The ban stays
+// in place if it can't be deleted from the database.
+func (b *banList) remove(id enode.ID) (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.db.DeleteBan(id); err != nil {
+		return false, err
+	}
+	_, ok := b.bans[id]
+	delete(b.bans, id)
+	return ok, nil
+}
+
+// banned reports whether the given node is banned at the given time. Expired
+// bans are dropped on access, or retried on the next access if they can't be
+// deleted from the database.
+func (b *banList) banned(id enode.ID, now time.Time) (bool, error) {
+	if b == nil {
+		return false, nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	expiry, ok := b.bans[id]
+	if ok && !now.Before(expiry) {
+		if err := b.db.DeleteBan(id); err != nil {
+			return false, err
+		}
+		delete(b.bans, id)
+		return false, nil
+	}
+	return ok, nil
+}
+
+// list returns the active bans, ordered by expiry time.
+func (b *banList) list() []BanInfo {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := time.Now()
+	infos := make([]BanInfo, 0, len(b.bans))
+	for id, expiry := range b.bans {
+		if now.Before(expiry) {
+			infos = append(infos, BanInfo{ID: id, Expires: expiry})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Expires.Before(infos[j].Expires)
+	})
+	return infos
+}
+
+// subnetFull reports whether the peer set already holds limit peers from the
+// subnet containing ip, a /24 for IPv4 and a /48 for IPv6 addresses. Trusted and
+// static peers don't count against the limit, and LAN addresses are exempt so
+// that private networks remain usable.
+func subnetFull(peers map[enode.ID]*Peer, ip net.IP, limit int) bool {
+	if limit <= 0 || ip == nil || netutil.IsLAN(ip) {
+		return false
+	}
+	var (
+		set4 = netutil.DistinctNetSet{Subnet: peerSubnetBits, Limit: uint(limit)}
+		set6 = netutil.DistinctNetSet{Subnet: peerSubnetBits6, Limit: uint(limit)}
+	)
+	subnet := func(ip net.IP) *netutil.DistinctNetSet {
+		if ip.To4() != nil {
+			return &set4
+		}
+		return &set6
+	}
+	for _, p := range peers {
+		if p.rw.is(trustedConn | staticDialedConn) {
+			continue
+		}
+		if pip := p.Node().IP(); pip != nil {
+			subnet(pip).Add(pip)
+		}
+	}
+	return !subnet(ip).Add(ip)
+}
+
+// BanPeer bans the given node for the given duration, disconnecting it if it is
+// currently connected. Banned nodes are neither dialed nor accepted.
+func (srv *Server) BanPeer(id enode.ID, d time.Duration) (time.Time, error) {
+	if srv.bans == nil {
+		return time.Time{}, errServerStopped
+	}
+	if d <= 0 {
+		return time.Time{}, errInvalidBanDuration
+	}
+	expiry, err := srv.bans.add(id, d)
+	if err != nil {
+		return time.Time{}, err
+	}
+	select {
+	case srv.peerOp <- func(peers map[enode.ID]*Peer) {
+		if p := peers[id]; p != nil {
+			p.Disconnect(DiscUselessPeer)
+		}
+	}:
+		<-srv.peerOpDone
+	case <-srv.quit:
+	}
+	return expiry, nil
+}
+
+// UnbanPeer lifts the ban of the given node, reporting whether it was banned.
+func (srv *Server) UnbanPeer(id enode.ID) (bool, error) {
+	if srv.bans == nil {
+		return false, errServerStopped
+	}
+	return srv.bans.remove(id)
+}
+
+// Bans returns the currently active bans.
+func (srv *Server) Bans() []BanInfo {
+	if srv.bans == nil {
+		return nil
+	}
+	return srv.bans.list()
+}
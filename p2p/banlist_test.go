@@ -0,0 +1,71 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+// Tests that bans survive reloading the ban list from the database, and that
+// expired bans are dropped.
+func TestBanListPersistence(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	bans := newBanList(db)
+	bans.add(uintID(1), time.Hour)
+	bans.add(uintID(2), -time.Second)
+
+	now := time.Now()
+	if banned, _ := bans.banned(uintID(1), now); !banned {
+		t.Error("node 1 not banned")
+	}
+	if banned, _ := bans.banned(uintID(2), now); banned {
+		t.Error("expired ban of node 2 still active")
+	}
+	// Checking after expiry drops the ban of node 1.
+	if banned, _ := bans.banned(uintID(1), now.Add(2*time.Hour)); banned {
+		t.Error("ban of node 1 active after expiry")
+	}
+	bans.add(uintID(3), time.Hour)
+	bans.add(uintID(4), -time.Second)
+
+	reloaded := newBanList(db)
+	if list := reloaded.list(); len(list) != 1 || list[0].ID != uintID(3) {
+		t.Errorf("wrong bans after reload: %v", list)
+	}
+	if len(db.Bans()) != 1 {
+		t.Errorf("expired bans not deleted from database: %v", db.Bans())
+	}
+}
+
+// Tests that bans which can't be persisted are reported and not applied.
+func TestBanListDatabaseFailure(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	bans := newBanList(db)
+	db.Close()
+
+	if _, err := bans.add(uintID(1), time.Hour); err == nil {
+		t.Error("no error for ban failing to persist")
+	}
+	if banned, _ := bans.banned(uintID(1), time.Now()); banned {
+		t.Error("node banned although the ban failed to persist")
+	}
+}
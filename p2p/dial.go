@@ -68,10 +68,14 @@ func (t TCPDialer) Dial(dest *enode.Node) (net.Conn, error) {
 // it get's a chance to compute new tasks on every iteration
 // of the main loop in Server.run.
 type dialstate struct {
-	maxDynDials int
-	ntab        discoverTable
-	netrestrict *netutil.Netlist
-	self        enode.ID
+	maxDynDials  int
+	ntab         discoverTable
+	netrestrict  *netutil.Netlist
+	netBlacklist *netutil.Netlist
+	maxPerSubnet int // limit of dynamic dials per subnet, zero means no limit
+	self         enode.ID
+	rep          *reputation // orders dynamic dial candidates if set
+	bans         *banList    // banned nodes are never dialed if set
 
 	lookupRunning bool
 	dialing       map[enode.ID]connFlag
@@ -170,6 +174,10 @@ func (s *dialstate) newTasks(nRunning int, peers map[enode.ID]*Peer, now time.Ti
 			log.Trace("Skipping dial candidate", "id", n.ID(), "addr", &net.TCPAddr{IP: n.IP(), Port: n.TCP()}, "err", err)
 			return false
 		}
+		if flag&dynDialedConn != 0 && subnetFull(peers, n.IP(), s.maxPerSubnet) {
+			log.Trace("Skipping dial candidate", "id", n.ID(), "addr", &net.TCPAddr{IP: n.IP(), Port: n.TCP()}, "err", errSubnetFull)
+			return false
+		}
 		s.dialing[n.ID()] = flag
 		newtasks = append(newtasks, &dialTask{flags: flag, dest: n})
 		return true
@@ -195,7 +203,7 @@ func (s *dialstate) newTasks(nRunning int, peers map[enode.ID]*Peer, now time.Ti
 	for id, t := range s.static {
 		err := s.checkDial(t.dest, peers)
 		switch err {
-		case errNotWhitelisted, errSelf:
+		case errNotWhitelisted, errBlacklisted, errSelf:
 			log.Warn("Removing static dial candidate", "id", t.dest.ID, "addr", &net.TCPAddr{IP: t.dest.IP(), Port: t.dest.TCP()}, "err", err)
 			delete(s.static, t.dest.ID())
 		case nil:
@@ -216,10 +224,11 @@ func (s *dialstate) newTasks(nRunning int, peers map[enode.ID]*Peer, now time.Ti
 		}
 	}
 	// Use random nodes from the table for half of the necessary
-	// dynamic dials.
+	// dynamic dials, preferring the ones with the best reputation.
 	randomCandidates := needDynDials / 2
 	if randomCandidates > 0 {
 		n := s.ntab.ReadRandomNodes(s.randomNodes)
+		s.rep.sortByScore(s.randomNodes[:n])
 		for i := 0; i < randomCandidates && i < n; i++ {
 			if addDial(dynDialedConn, s.randomNodes[i]) {
 				needDynDials--
@@ -227,7 +236,8 @@ func (s *dialstate) newTasks(nRunning int, peers map[enode.ID]*Peer, now time.Ti
 		}
 	}
 	// Create dynamic dials from random lookup results, removing tried
-	// items from the result buffer.
+	// items from the result buffer. Nodes with a better reputation go first.
+	s.rep.sortByScore(s.lookupBuf)
 	i := 0
 	for ; i < len(s.lookupBuf) && needDynDials > 0; i++ {
 		if addDial(dynDialedConn, s.lookupBuf[i]) {
@@ -258,10 +268,14 @@ var (
 	errAlreadyConnected = errors.New("already connected")
 	errRecentlyDialed   = errors.New("recently dialed")
 	errNotWhitelisted   = errors.New("not contained in netrestrict whitelist")
+	errBlacklisted      = errors.New("contained in netblacklist")
+	errBanned           = errors.New("node is banned")
+	errSubnetFull       = errors.New("too many peers in subnet")
 )
 
 func (s *dialstate) checkDial(n *enode.Node, peers map[enode.ID]*Peer) error {
 	_, dialing := s.dialing[n.ID()]
+	banned, banErr := s.bans.banned(n.ID(), time.Now())
 	switch {
 	case dialing:
 		return errAlreadyDialing
@@ -271,6 +285,12 @@ func (s *dialstate) checkDial(n *enode.Node, peers map[enode.ID]*Peer) error {
 		return errSelf
 	case s.netrestrict != nil && !s.netrestrict.Contains(n.IP()):
 		return errNotWhitelisted
+	case s.netBlacklist != nil && s.netBlacklist.Contains(n.IP()):
+		return errBlacklisted
+	case banErr != nil:
+		return banErr
+	case banned:
+		return errBanned
 	case s.hist.contains(n.ID()):
 		return errRecentlyDialed
 	}
@@ -442,6 +442,78 @@ func TestDialStateNetRestrict(t *testing.T) {
 	})
 }
 
+// This test checks that discovery results with a better reputation are dialed first.
+func TestDialStateReputation(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+	rep := newReputation(db)
+	rep.adjust(uintID(1), scoreTimeout)
+	rep.adjust(uintID(3), 10)
+	rep.adjust(uintID(4), 20)
+
+	state := newDialState(enode.ID{}, nil, nil, fakeTable{}, 2, nil)
+	state.rep = rep
+	runDialTest(t, dialtest{
+		init: state,
+		rounds: []round{
+			{
+				new: []task{&discoverTask{}},
+			},
+			{
+				done: []task{
+					&discoverTask{results: []*enode.Node{
+						newNode(uintID(1), nil),
+						newNode(uintID(2), nil),
+						newNode(uintID(3), nil),
+						newNode(uintID(4), nil),
+					}},
+				},
+				new: []task{
+					&dialTask{flags: dynDialedConn, dest: newNode(uintID(4), nil)},
+					&dialTask{flags: dynDialedConn, dest: newNode(uintID(3), nil)},
+				},
+			},
+		},
+	})
+}
+
+// This test checks that banned and blacklisted nodes are not dialed, and that
+// dynamic dials respect the subnet limit.
+func TestDialStateBans(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+	bans := newBanList(db)
+	bans.add(uintID(1), time.Hour)
+
+	blacklist := new(netutil.Netlist)
+	blacklist.Add("88.99.2.0/24")
+
+	table := fakeTable{
+		newNode(uintID(1), net.ParseIP("88.99.1.1")), // banned
+		newNode(uintID(2), net.ParseIP("88.99.2.1")), // blacklisted
+		newNode(uintID(3), net.ParseIP("88.99.3.1")), // subnet is full
+		newNode(uintID(4), net.ParseIP("88.99.4.1")),
+	}
+	state := newDialState(enode.ID{}, nil, nil, table, 10, nil)
+	state.bans = bans
+	state.netBlacklist = blacklist
+	state.maxPerSubnet = 1
+	runDialTest(t, dialtest{
+		init: state,
+		rounds: []round{
+			{
+				peers: []*Peer{
+					{rw: &conn{flags: dynDialedConn, node: newNode(uintID(5), net.ParseIP("88.99.3.2"))}},
+				},
+				new: []task{
+					&dialTask{flags: dynDialedConn, dest: table[3]},
+					&discoverTask{},
+				},
+			},
+		},
+	})
+}
+
 // This test checks that static dials are launched.
 func TestDialStateStaticDial(t *testing.T) {
 	wantStatic := []*enode.Node{
@@ -88,8 +88,8 @@ type Table struct {
 // sockets and without generating a private key.
 type transport interface {
 	self() *enode.Node
-	ping(enode.ID, *net.UDPAddr) error
-	findnode(toid enode.ID, addr *net.UDPAddr, target encPubkey) ([]*node, error)
+	ping(*enode.Node) error
+	findnode(n *enode.Node, target encPubkey) ([]*node, error)
 	close()
 }
 
@@ -237,6 +237,40 @@ func (tab *Table) Resolve(n *enode.Node) *enode.Node {
 	return nil
 }
 
+// getNode returns the node with the given ID or nil if it isn't in the table.
+func (tab *Table) getNode(id enode.ID) *enode.Node {
+	tab.mutex.Lock()
+	defer tab.mutex.Unlock()
+
+	b := tab.bucket(id)
+	for _, e := range b.entries {
+		if e.ID() == id {
+			return unwrapNode(e)
+		}
+	}
+	return nil
+}
+
+// appendLiveNodes adds nodes at the given log distance from the local node to
+// result. Only nodes which passed at least one liveness check are added.
+func (tab *Table) appendLiveNodes(dist uint, result []*enode.Node) []*enode.Node {
+	if dist > uint(hashBits) {
+		return result
+	}
+	self := tab.self().ID()
+
+	tab.mutex.Lock()
+	defer tab.mutex.Unlock()
+	for _, b := range &tab.buckets {
+		for _, n := range b.entries {
+			if n.livenessChecks > 0 && uint(enode.LogDist(self, n.ID())) == dist {
+				result = append(result, unwrapNode(n))
+			}
+		}
+	}
+	return result
+}
+
 // LookupRandom finds random nodes in the network.
 func (tab *Table) LookupRandom() []*enode.Node {
 	var target encPubkey
@@ -308,7 +342,7 @@ func (tab *Table) lookup(targetKey encPubkey, refreshIfEmpty bool) []*node {
 
 func (tab *Table) findnode(n *node, targetKey encPubkey, reply chan<- []*node) {
 	fails := tab.db.FindFails(n.ID(), n.IP())
-	r, err := tab.net.findnode(n.ID(), n.addr(), targetKey)
+	r, err := tab.net.findnode(unwrapNode(n), targetKey)
 	if err == errClosed {
 		// Avoid recording failures on shutdown.
 		reply <- nil
@@ -459,7 +493,7 @@ func (tab *Table) doRevalidate(done chan<- struct{}) {
 	}
 
 	// Ping the selected node and wait for a pong.
-	err := tab.net.ping(last.ID(), last.addr())
+	err := tab.net.ping(unwrapNode(last))
 
 	tab.mutex.Lock()
 	defer tab.mutex.Unlock()
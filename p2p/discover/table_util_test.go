@@ -113,16 +113,16 @@ func (t *pingRecorder) self() *enode.Node {
 	return nullNode
 }
 
-func (t *pingRecorder) findnode(toid enode.ID, toaddr *net.UDPAddr, target encPubkey) ([]*node, error) {
+func (t *pingRecorder) findnode(n *enode.Node, target encPubkey) ([]*node, error) {
 	return nil, nil
 }
 
-func (t *pingRecorder) ping(toid enode.ID, toaddr *net.UDPAddr) error {
+func (t *pingRecorder) ping(n *enode.Node) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	t.pinged[toid] = true
-	if t.dead[toid] {
+	t.pinged[n.ID()] = true
+	if t.dead[n.ID()] {
 		return errTimeout
 	} else {
 		return nil
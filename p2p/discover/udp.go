@@ -297,8 +297,8 @@ func (t *udp) ourEndpoint() rpcEndpoint {
 }
 
 // ping sends a ping message to the given node and waits for a reply.
-func (t *udp) ping(toid enode.ID, toaddr *net.UDPAddr) error {
-	return <-t.sendPing(toid, toaddr, nil)
+func (t *udp) ping(n *enode.Node) error {
+	return <-t.sendPing(n.ID(), &net.UDPAddr{IP: n.IP(), Port: n.UDP()}, nil)
 }
 
 // sendPing sends a ping message to the given node and invokes the callback
@@ -333,11 +333,13 @@ func (t *udp) sendPing(toid enode.ID, toaddr *net.UDPAddr, callback func()) <-ch
 
 // findnode sends a findnode request to the given node and waits until
 // the node has sent up to k neighbors.
-func (t *udp) findnode(toid enode.ID, toaddr *net.UDPAddr, target encPubkey) ([]*node, error) {
+func (t *udp) findnode(n *enode.Node, target encPubkey) ([]*node, error) {
+	toid, toaddr := n.ID(), &net.UDPAddr{IP: n.IP(), Port: n.UDP()}
+
 	// If we haven't seen a ping from the destination node for a while, it won't remember
 	// our endpoint proof and reject findnode. Solicit a ping first.
 	if time.Since(t.db.LastPingReceived(toid, toaddr.IP)) > bondExpiration {
-		t.ping(toid, toaddr)
+		<-t.sendPing(toid, toaddr, nil)
 		// Wait for them to ping back and process our pong.
 		time.Sleep(respTimeout)
 	}
@@ -578,8 +580,11 @@ func (t *udp) readLoop(unhandled chan<- ReadPacket) {
 			return
 		}
 		if t.handlePacket(from, buf[:nbytes]) != nil && unhandled != nil {
+			// The read buffer is reused, hand a copy to the next protocol.
+			data := make([]byte, nbytes)
+			copy(data, buf)
 			select {
-			case unhandled <- ReadPacket{buf[:nbytes], from}:
+			case unhandled <- ReadPacket{data, from}:
 			default:
 			}
 		}
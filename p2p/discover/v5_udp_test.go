@@ -0,0 +1,199 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+// This test checks that a node answers PING, FINDNODE and TALKREQ after
+// completing the handshake.
+func TestUDPv5_calls(t *testing.T) {
+	t.Parallel()
+	a := startLocalhostV5(t, Config{})
+	defer a.Close()
+	b := startLocalhostV5(t, Config{})
+	defer b.Close()
+
+	if err := a.Ping(b.Self()); err != nil {
+		t.Fatal("ping failed:", err)
+	}
+
+	// Distance zero returns the record of the node itself.
+	n, err := a.RequestENR(b.Self())
+	if err != nil {
+		t.Fatal("RequestENR failed:", err)
+	}
+	if n.ID() != b.Self().ID() || n.Seq() != b.Self().Seq() {
+		t.Fatalf("wrong record returned: %v", n)
+	}
+
+	b.RegisterTalkHandler("test", func(id enode.ID, addr *net.UDPAddr, msg []byte) []byte {
+		if id != a.Self().ID() {
+			t.Errorf("wrong sender ID %v", id)
+		}
+		return append([]byte("reply to "), msg...)
+	})
+	resp, err := a.TalkRequest(b.Self(), "test", []byte("hello"))
+	if err != nil {
+		t.Fatal("talk request failed:", err)
+	}
+	if !bytes.Equal(resp, []byte("reply to hello")) {
+		t.Fatalf("wrong talk response %q", resp)
+	}
+	resp, err = a.TalkRequest(b.Self(), "unknown", []byte("hello"))
+	if err != nil {
+		t.Fatal("talk request for unknown protocol failed:", err)
+	}
+	if len(resp) != 0 {
+		t.Fatalf("non-empty response %q for unknown protocol", resp)
+	}
+
+	// After the handshake, b knows a and returns it in FINDNODE.
+	waitForTableNode(t, b, a.Self().ID())
+	dist := uint(enode.LogDist(b.Self().ID(), a.Self().ID()))
+	nodes, err := a.Findnode(b.Self(), []uint{dist})
+	if err != nil {
+		t.Fatal("findnode failed:", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID() != a.Self().ID() {
+		t.Fatalf("wrong findnode result: %v", nodes)
+	}
+}
+
+// This test checks that calls to unreachable nodes time out.
+func TestUDPv5_pingTimeout(t *testing.T) {
+	t.Parallel()
+	a := startLocalhostV5(t, Config{})
+	defer a.Close()
+	b := startLocalhostV5(t, Config{})
+	dead := b.Self()
+	b.Close()
+
+	if err := a.Ping(dead); err != errTimeout {
+		t.Fatalf("wrong error %v, want %v", err, errTimeout)
+	}
+}
+
+// This test checks that packets of other protocols are passed on.
+func TestUDPv5_unhandled(t *testing.T) {
+	t.Parallel()
+	unhandled := make(chan ReadPacket, 1)
+	a := startLocalhostV5(t, Config{Unhandled: unhandled})
+	defer a.Close()
+
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: a.Self().IP(), Port: a.Self().UDP()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	packet := bytes.Repeat([]byte{0xff}, 100)
+	conn.Write(packet)
+
+	select {
+	case p := <-unhandled:
+		if !bytes.Equal(p.Data, packet) {
+			t.Fatalf("wrong unhandled packet data %x", p.Data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("packet not forwarded")
+	}
+}
+
+func TestLookupDistances(t *testing.T) {
+	var (
+		base  = enode.ID{}
+		tests = []struct {
+			dist int
+			want []uint
+		}{
+			{256, []uint{256, 255, 254}},
+			{255, []uint{255, 256, 254}},
+			{1, []uint{1, 2, 3}},
+		}
+	)
+	for _, test := range tests {
+		target := idAtDistance(base, test.dist)
+		if got := lookupDistances(target, base); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("dist %d: got %v, want %v", test.dist, got, test.want)
+		}
+	}
+}
+
+func TestPackNodes(t *testing.T) {
+	var nodes []*enode.Node
+	for i := 0; i < 7; i++ {
+		nodes = append(nodes, unwrapNode(nodeAtDistance(enode.ID{}, 256, net.IP{10, 0, 0, byte(i)})))
+	}
+	packets := packNodes([]byte{1}, nodes)
+	if len(packets) != 3 {
+		t.Fatalf("wrong number of packets %d", len(packets))
+	}
+	for i, p := range packets {
+		if p.Total != 3 {
+			t.Errorf("packet %d: wrong total %d", i, p.Total)
+		}
+	}
+	if len(packets[2].Nodes) != 1 {
+		t.Errorf("wrong number of records in last packet: %d", len(packets[2].Nodes))
+	}
+	if empty := packNodes([]byte{1}, nil); len(empty) != 1 || empty[0].Total != 1 {
+		t.Errorf("wrong response for empty result: %+v", empty)
+	}
+}
+
+func startLocalhostV5(t *testing.T, cfg Config) *UDPv5 {
+	cfg.PrivateKey = newkey()
+	db, _ := enode.OpenDB("")
+	ln := enode.NewLocalNode(db, cfg.PrivateKey)
+
+	socket, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	realaddr := socket.LocalAddr().(*net.UDPAddr)
+	ln.SetStaticIP(realaddr.IP)
+	ln.SetFallbackUDP(realaddr.Port)
+	udp, err := ListenV5(socket, ln, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return udp
+}
+
+func waitForTableNode(t *testing.T, udp *UDPv5, id enode.ID) {
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if n := udp.tab.getNode(id); n != nil {
+			// Mark the node live so it is served in FINDNODE responses.
+			udp.tab.mutex.Lock()
+			for _, e := range udp.tab.bucket(id).entries {
+				if e.ID() == id {
+					e.livenessChecks++
+				}
+			}
+			udp.tab.mutex.Unlock()
+			return
+		}
+	}
+	t.Fatalf("node %v not added to table", id)
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package v5wire
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+var (
+	testKeyA, _ = crypto.GenerateKey()
+	testKeyB, _ = crypto.GenerateKey()
+)
+
+func TestDeriveKeys(t *testing.T) {
+	var (
+		n1        = enode.ID{1}
+		n2        = enode.ID{2}
+		challenge = []byte("challenge data")
+	)
+	s1 := deriveKeys(sha256.New, testKeyA, &testKeyB.PublicKey, n1, n2, challenge)
+	s2 := deriveKeys(sha256.New, testKeyB, &testKeyA.PublicKey, n1, n2, challenge)
+	if !bytes.Equal(s1.writeKey, s2.writeKey) || !bytes.Equal(s1.readKey, s2.readKey) {
+		t.Fatal("derived keys don't match")
+	}
+	if bytes.Equal(s1.writeKey, s1.readKey) {
+		t.Fatal("read and write keys are equal")
+	}
+}
+
+func TestIDSignature(t *testing.T) {
+	var (
+		challenge = []byte("challenge data")
+		ephkey    = EncodePubkey(&testKeyB.PublicKey)
+		destID    = enode.ID{3}
+		db, _     = enode.OpenDB("")
+		node      = enode.NewLocalNode(db, testKeyA).Node()
+	)
+	defer db.Close()
+	sig, err := makeIDSignature(sha256.New(), testKeyA, challenge, ephkey, destID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyIDSignature(sha256.New(), sig, node, challenge, ephkey, destID); err != nil {
+		t.Fatal("can't verify signature:", err)
+	}
+	if err := verifyIDSignature(sha256.New(), sig, node, challenge, ephkey, enode.ID{4}); err != errInvalidNonceSig {
+		t.Fatal("wrong error for bad destination:", err)
+	}
+}
+
+// This test checks the full handshake: random packet, WHOAREYOU, handshake
+// message and ordinary messages in both directions.
+func TestHandshake(t *testing.T) {
+	net := newHandshakeTest()
+	defer net.close()
+
+	// A -> B   RANDOM PACKET
+	packet, _ := net.nodeA.encode(t, net.nodeB, &Findnode{})
+	resp := net.nodeB.expectDecode(t, UnknownPacket, packet)
+
+	// A <- B   WHOAREYOU
+	challenge := &Whoareyou{
+		Nonce:     resp.(*Unknown).Nonce,
+		IDNonce:   [16]byte{1, 2, 3},
+		RecordSeq: 0,
+	}
+	whoareyou, _ := net.nodeB.encode(t, net.nodeA, challenge)
+	recvChallenge := net.nodeA.expectDecode(t, WhoareyouPacket, whoareyou).(*Whoareyou)
+
+	// A -> B   FINDNODE (handshake packet)
+	recvChallenge.Node = net.nodeB.n()
+	findnode, _ := net.nodeA.encodeWithChallenge(t, net.nodeB, recvChallenge, &Findnode{Distances: []uint{256}})
+	p := net.nodeB.expectDecode(t, FindnodeMsg, findnode)
+	if !reflect.DeepEqual(p.(*Findnode).Distances, []uint{256}) {
+		t.Fatalf("wrong distances in findnode: %v", p.(*Findnode).Distances)
+	}
+
+	// A <- B   NODES
+	nodes, _ := net.nodeB.encode(t, net.nodeA, &Nodes{Total: 1})
+	net.nodeA.expectDecode(t, NodesMsg, nodes)
+
+	// A -> B   PING (ordinary message with established session)
+	ping, _ := net.nodeA.encode(t, net.nodeB, &Ping{ReqID: []byte("reqid"), ENRSeq: 2})
+	p = net.nodeB.expectDecode(t, PingMsg, ping)
+	if !bytes.Equal(p.(*Ping).ReqID, []byte("reqid")) {
+		t.Fatalf("wrong request ID %x", p.(*Ping).ReqID)
+	}
+}
+
+// This test checks that handshake packets without a prior challenge are rejected.
+func TestHandshakeUnexpected(t *testing.T) {
+	net := newHandshakeTest()
+	defer net.close()
+
+	challenge := &Whoareyou{
+		ChallengeData: []byte("fake challenge"),
+		IDNonce:       [16]byte{1},
+		Node:          net.nodeB.n(),
+	}
+	findnode, _ := net.nodeA.encodeWithChallenge(t, net.nodeB, challenge, &Findnode{})
+	net.nodeB.expectDecodeErr(t, errUnexpectedHandshake, findnode)
+}
+
+// This test checks that packets of other protocols are recognized as such.
+func TestDecodeForeignPacket(t *testing.T) {
+	net := newHandshakeTest()
+	defer net.close()
+
+	packet := make([]byte, 100)
+	_, _, _, err := net.nodeB.c.Decode(packet, "")
+	if err != ErrInvalidHeader {
+		t.Fatalf("wrong error %v, want %v", err, ErrInvalidHeader)
+	}
+	if _, _, _, err := net.nodeB.c.Decode(make([]byte, 10), ""); err != errTooShort {
+		t.Fatalf("wrong error %v, want %v", err, errTooShort)
+	}
+}
+
+type handshakeTest struct {
+	nodeA, nodeB handshakeTestNode
+	clock        mclock.Simulated
+}
+
+type handshakeTestNode struct {
+	ln *enode.LocalNode
+	c  *Codec
+}
+
+func newHandshakeTest() *handshakeTest {
+	t := new(handshakeTest)
+	t.nodeA.init(testKeyA, net.IP{127, 0, 0, 1}, &t.clock)
+	t.nodeB.init(testKeyB, net.IP{127, 0, 0, 1}, &t.clock)
+	return t
+}
+
+func (t *handshakeTest) close() {
+	t.nodeA.ln.Database().Close()
+	t.nodeB.ln.Database().Close()
+}
+
+func (n *handshakeTestNode) init(key *ecdsa.PrivateKey, ip net.IP, clock mclock.Clock) {
+	db, _ := enode.OpenDB("")
+	n.ln = enode.NewLocalNode(db, key)
+	n.ln.SetStaticIP(ip)
+	n.c = NewCodec(n.ln, key, clock)
+}
+
+func (n *handshakeTestNode) encode(t testing.TB, to handshakeTestNode, p Packet) ([]byte, Nonce) {
+	t.Helper()
+	return n.encodeWithChallenge(t, to, nil, p)
+}
+
+func (n *handshakeTestNode) encodeWithChallenge(t testing.TB, to handshakeTestNode, c *Whoareyou, p Packet) ([]byte, Nonce) {
+	t.Helper()
+	enc, nonce, err := n.c.Encode(to.id(), to.addr(), p, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Copy the packet because the codec reuses its buffers.
+	return append([]byte(nil), enc...), nonce
+}
+
+func (n *handshakeTestNode) expectDecode(t *testing.T, ptype byte, p []byte) Packet {
+	t.Helper()
+	dec, err := n.decode(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.Kind() != ptype {
+		t.Fatalf("expected packet type %d, got %d", ptype, dec.Kind())
+	}
+	return dec
+}
+
+func (n *handshakeTestNode) expectDecodeErr(t *testing.T, wantErr error, p []byte) {
+	t.Helper()
+	if _, err := n.decode(p); err != wantErr {
+		t.Fatalf("wrong error %v, want %v", err, wantErr)
+	}
+}
+
+func (n *handshakeTestNode) decode(input []byte) (Packet, error) {
+	_, _, p, err := n.c.Decode(input, "127.0.0.1")
+	return p, err
+}
+
+func (n *handshakeTestNode) n() *enode.Node {
+	return n.ln.Node()
+}
+
+func (n *handshakeTestNode) addr() string {
+	return n.ln.Node().IP().String()
+}
+
+func (n *handshakeTestNode) id() enode.ID {
+	return n.ln.ID()
+}
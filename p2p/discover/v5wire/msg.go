@@ -0,0 +1,178 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package v5wire
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// Packet is implemented by all message types.
+type Packet interface {
+	Name() string        // Name returns a string corresponding to the message type.
+	Kind() byte          // Kind returns the message type.
+	RequestID() []byte   // Returns the request ID.
+	SetRequestID([]byte) // Sets the request ID.
+}
+
+// Message types.
+const (
+	PingMsg byte = iota + 1
+	PongMsg
+	FindnodeMsg
+	NodesMsg
+	TalkRequestMsg
+	TalkResponseMsg
+
+	UnknownPacket   = byte(255) // any non-decryptable packet
+	WhoareyouPacket = byte(254) // the WHOAREYOU packet
+)
+
+// Protocol messages.
+type (
+	// Unknown represents any packet that can't be decrypted.
+	Unknown struct {
+		Nonce Nonce
+	}
+
+	// Whoareyou contains the handshake challenge.
+	Whoareyou struct {
+		ChallengeData []byte   // Encoded challenge
+		Nonce         Nonce    // Nonce of request packet
+		IDNonce       [16]byte // Identity proof data
+		RecordSeq     uint64   // ENR sequence number of recipient
+
+		// Node is the locally known node record of recipient.
+		// This must be set by the caller of Encode.
+		Node *enode.Node
+
+		sent mclock.AbsTime // for handshake GC.
+	}
+
+	// Ping is sent during liveness checks.
+	Ping struct {
+		ReqID  []byte
+		ENRSeq uint64
+	}
+
+	// Pong is the reply to Ping.
+	Pong struct {
+		ReqID  []byte
+		ENRSeq uint64
+		ToIP   net.IP // These fields should mirror the UDP envelope address of the ping
+		ToPort uint16 // packet, which provides a way to discover the external address (after NAT).
+	}
+
+	// Findnode is a query for nodes at the given log distances.
+	Findnode struct {
+		ReqID     []byte
+		Distances []uint
+	}
+
+	// Nodes is the reply to Findnode.
+	Nodes struct {
+		ReqID []byte
+		Total uint8 // Number of Nodes messages making up the response
+		Nodes []*enr.Record
+	}
+
+	// TalkRequest is an application-level request.
+	TalkRequest struct {
+		ReqID    []byte
+		Protocol string
+		Message  []byte
+	}
+
+	// TalkResponse is the reply to TalkRequest.
+	TalkResponse struct {
+		ReqID   []byte
+		Message []byte
+	}
+)
+
+// DecodeMessage decodes the message body of a packet.
+func DecodeMessage(ptype byte, body []byte) (Packet, error) {
+	var dec Packet
+	switch ptype {
+	case PingMsg:
+		dec = new(Ping)
+	case PongMsg:
+		dec = new(Pong)
+	case FindnodeMsg:
+		dec = new(Findnode)
+	case NodesMsg:
+		dec = new(Nodes)
+	case TalkRequestMsg:
+		dec = new(TalkRequest)
+	case TalkResponseMsg:
+		dec = new(TalkResponse)
+	default:
+		return nil, fmt.Errorf("unknown packet type %d", ptype)
+	}
+	if err := rlp.DecodeBytes(body, dec); err != nil {
+		return nil, err
+	}
+	if len(dec.RequestID()) > 8 {
+		return nil, ErrInvalidReqID
+	}
+	return dec, nil
+}
+
+func (*Unknown) Name() string            { return "UNKNOWN/v5" }
+func (*Unknown) Kind() byte              { return UnknownPacket }
+func (*Unknown) RequestID() []byte       { return nil }
+func (*Unknown) SetRequestID(rid []byte) {}
+
+func (*Whoareyou) Name() string            { return "WHOAREYOU/v5" }
+func (*Whoareyou) Kind() byte              { return WhoareyouPacket }
+func (*Whoareyou) RequestID() []byte       { return nil }
+func (*Whoareyou) SetRequestID(rid []byte) {}
+
+func (*Ping) Name() string              { return "PING/v5" }
+func (*Ping) Kind() byte                { return PingMsg }
+func (p *Ping) RequestID() []byte       { return p.ReqID }
+func (p *Ping) SetRequestID(rid []byte) { p.ReqID = rid }
+
+func (*Pong) Name() string              { return "PONG/v5" }
+func (*Pong) Kind() byte                { return PongMsg }
+func (p *Pong) RequestID() []byte       { return p.ReqID }
+func (p *Pong) SetRequestID(rid []byte) { p.ReqID = rid }
+
+func (*Findnode) Name() string              { return "FINDNODE/v5" }
+func (*Findnode) Kind() byte                { return FindnodeMsg }
+func (p *Findnode) RequestID() []byte       { return p.ReqID }
+func (p *Findnode) SetRequestID(rid []byte) { p.ReqID = rid }
+
+func (*Nodes) Name() string              { return "NODES/v5" }
+func (*Nodes) Kind() byte                { return NodesMsg }
+func (p *Nodes) RequestID() []byte       { return p.ReqID }
+func (p *Nodes) SetRequestID(rid []byte) { p.ReqID = rid }
+
+func (*TalkRequest) Name() string              { return "TALKREQ/v5" }
+func (*TalkRequest) Kind() byte                { return TalkRequestMsg }
+func (p *TalkRequest) RequestID() []byte       { return p.ReqID }
+func (p *TalkRequest) SetRequestID(rid []byte) { p.ReqID = rid }
+
+func (*TalkResponse) Name() string              { return "TALKRESP/v5" }
+func (*TalkResponse) Kind() byte                { return TalkResponseMsg }
+func (p *TalkResponse) RequestID() []byte       { return p.ReqID }
+func (p *TalkResponse) SetRequestID(rid []byte) { p.ReqID = rid }
@@ -0,0 +1,430 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	mrand "math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// Client discovers nodes by querying DNS servers.
+type Client struct {
+	cfg     Config
+	clock   mclock.Clock
+	entries *lru.Cache
+}
+
+// Config holds configuration options for the client.
+type Config struct {
+	Timeout         time.Duration      // timeout used for DNS lookups (default 5s)
+	RecheckInterval time.Duration      // time between tree root update checks (default 30min)
+	CacheLimit      int                // maximum number of cached records (default 1000)
+	ValidSchemes    enr.IdentityScheme // acceptable ENR identity schemes (default enode.ValidSchemes)
+	Resolver        Resolver           // the DNS resolver to use (defaults to system DNS)
+	Logger          log.Logger         // destination of client log messages (defaults to root logger)
+}
+
+// Resolver is a DNS resolver that can query TXT records.
+type Resolver interface {
+	LookupTXT(ctx context.Context, domain string) ([]string, error)
+}
+
+func (cfg Config) withDefaults() Config {
+	const (
+		defaultTimeout = 5 * time.Second
+		defaultRecheck = 30 * time.Minute
+		defaultCache   = 1000
+	)
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.RecheckInterval == 0 {
+		cfg.RecheckInterval = defaultRecheck
+	}
+	if cfg.CacheLimit == 0 {
+		cfg.CacheLimit = defaultCache
+	}
+	if cfg.ValidSchemes == nil {
+		cfg.ValidSchemes = enode.ValidSchemes
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = new(net.Resolver)
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Root()
+	}
+	return cfg
+}
+
+// NewClient creates a client.
+func NewClient(cfg Config) *Client {
+	cfg = cfg.withDefaults()
+	cache, err := lru.New(cfg.CacheLimit)
+	if err != nil {
+		panic(err)
+	}
+	return &Client{cfg: cfg, clock: mclock.System{}, entries: cache}
+}
+
+// SyncTree downloads the entire node tree at the given URL.
+func (c *Client) SyncTree(url string) (*Tree, error) {
+	le, err := parseLink(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrtree URL: %v", err)
+	}
+	ct := &clientTree{c: c, loc: le}
+	root, err := c.resolveRoot(le)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tree{root: &root, entries: make(map[string]entry)}
+	if err := ct.syncSubtree(root.eroot, t.entries, false); err != nil {
+		return nil, err
+	}
+	if err := ct.syncSubtree(root.lroot, t.entries, true); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// resolveRoot retrieves a root entry via DNS.
+func (c *Client) resolveRoot(loc *linkEntry) (rootEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, loc.domain)
+	c.cfg.Logger.Trace("Updating DNS discovery root", "tree", loc.domain, "err", err)
+	if err != nil {
+		return rootEntry{}, err
+	}
+	for _, txt := range txts {
+		if strings.HasPrefix(txt, rootPrefix) {
+			return parseAndVerifyRoot(txt, loc)
+		}
+	}
+	return rootEntry{}, nameError{loc.domain, errNoRoot}
+}
+
+func parseAndVerifyRoot(txt string, loc *linkEntry) (rootEntry, error) {
+	e, err := parseRoot(txt)
+	if err != nil {
+		return e, err
+	}
+	if !e.verifySignature(loc.pubkey) {
+		return e, entryError{typ: "root", err: errInvalidSig}
+	}
+	return e, nil
+}
+
+// resolveEntry retrieves an entry from the cache or fetches it from the network
+// if it isn't cached.
+func (c *Client) resolveEntry(domain, hash string) (entry, error) {
+	if e, ok := c.entries.Get(hash); ok {
+		return e.(entry), nil
+	}
+	e, err := c.doResolveEntry(domain, hash)
+	if err != nil {
+		return nil, err
+	}
+	c.entries.Add(hash, e)
+	return e, nil
+}
+
+// doResolveEntry fetches an entry via DNS.
+func (c *Client) doResolveEntry(domain, hash string) (entry, error) {
+	wantHash, err := b32format.DecodeString(hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base32 hash")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+
+	name := hash + "." + domain
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, name)
+	c.cfg.Logger.Trace("DNS discovery lookup", "name", name, "err", err)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		e, err := parseEntry(txt, c.cfg.ValidSchemes)
+		if err == errUnknownEntry {
+			continue
+		}
+		if !bytes.HasPrefix(crypto.Keccak256([]byte(txt)), wantHash) {
+			err = nameError{name, errHashMismatch}
+		} else if err != nil {
+			err = nameError{name, err}
+		}
+		return e, err
+	}
+	return nil, nameError{name, errNoEntry}
+}
+
+// clientTree is a tree which is kept up to date by a Source.
+type clientTree struct {
+	c             *Client
+	loc           *linkEntry
+	root          *rootEntry
+	lastRootCheck mclock.AbsTime // last root check time
+	nodes         []*enode.Node  // nodes of the current tree version
+	links         []*linkEntry   // links of the current tree version
+}
+
+// update checks the root of the tree and downloads the tree if it has changed.
+// The root check is skipped if the last check was less than RecheckInterval ago.
+func (ct *clientTree) update() error {
+	now := ct.c.clock.Now()
+	if ct.root != nil && now-ct.lastRootCheck < mclock.AbsTime(ct.c.cfg.RecheckInterval) {
+		return nil
+	}
+	ct.lastRootCheck = now
+
+	root, err := ct.c.resolveRoot(ct.loc)
+	if err != nil {
+		return err
+	}
+	if ct.root != nil {
+		if root.seq < ct.root.seq {
+			return fmt.Errorf("tree %s: root sequence number went backwards (%d < %d)", ct.loc.domain, root.seq, ct.root.seq)
+		}
+		if root.eroot == ct.root.eroot && root.lroot == ct.root.lroot {
+			ct.root = &root
+			return nil
+		}
+	}
+
+	entries := make(map[string]entry)
+	if err := ct.syncSubtree(root.eroot, entries, false); err != nil {
+		return err
+	}
+	if err := ct.syncSubtree(root.lroot, entries, true); err != nil {
+		return err
+	}
+	ct.root = &root
+	ct.nodes, ct.links = nil, nil
+	for _, e := range entries {
+		switch e := e.(type) {
+		case *enrEntry:
+			ct.nodes = append(ct.nodes, e.node)
+		case *linkEntry:
+			ct.links = append(ct.links, e)
+		}
+	}
+	sortByID(ct.nodes)
+	ct.c.cfg.Logger.Debug("Synced DNS discovery tree", "tree", ct.loc.domain, "seq", root.seq, "nodes", len(ct.nodes), "links", len(ct.links))
+	return nil
+}
+
+// syncSubtree downloads the subtree at the given hash into dest. Subtrees already
+// contained in dest are not visited again.
+func (ct *clientTree) syncSubtree(hash string, dest map[string]entry, link bool) error {
+	if _, ok := dest[hash]; ok {
+		return nil
+	}
+	e, err := ct.c.resolveEntry(ct.loc.domain, hash)
+	if err != nil {
+		return err
+	}
+	dest[hash] = e
+	switch e := e.(type) {
+	case *branchEntry:
+		for _, h := range e.children {
+			if err := ct.syncSubtree(h, dest, link); err != nil {
+				return err
+			}
+		}
+	case *enrEntry:
+		if link {
+			return errENRInLinkTree
+		}
+	case *linkEntry:
+		if !link {
+			return errLinkInENRTree
+		}
+	}
+	return nil
+}
+
+// Source is a node source backed by one or more DNS trees. It can be used as a
+// discovery table by the p2p server.
+type Source struct {
+	c      *Client
+	syncMu sync.Mutex // serializes tree updates
+
+	mu    sync.Mutex
+	roots []string               // links of the trees the source was created with
+	trees map[string]*clientTree // all known trees, keyed by link
+	nodes []*enode.Node          // nodes of all synced trees
+	rand  *mrand.Rand
+}
+
+// lookupLimit is the maximum number of nodes returned by LookupRandom.
+const lookupLimit = 16
+
+// NewSource creates a node source which discovers nodes from the trees at the
+// given enrtree:// URLs and all trees linked from them.
+func (c *Client) NewSource(urls ...string) (*Source, error) {
+	s := &Source{
+		c:     c,
+		trees: make(map[string]*clientTree),
+		rand:  mrand.New(mrand.NewSource(time.Now().UnixNano())),
+	}
+	for _, url := range urls {
+		le, err := parseLink(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enrtree URL: %v", err)
+		}
+		s.roots = append(s.roots, le.str)
+		s.trees[le.str] = &clientTree{c: c, loc: le}
+	}
+	return s, nil
+}
+
+// Close implements the discovery table interface. It does nothing because
+// the source has no background activity.
+func (s *Source) Close() {}
+
+// Resolve returns the most recent record of the given node known to the
+// source, or nil if the node is not contained in any synced tree.
+func (s *Source) Resolve(n *enode.Node) *enode.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rn := range s.nodes {
+		if rn.ID() == n.ID() && rn.Seq() >= n.Seq() {
+			return rn
+		}
+	}
+	return nil
+}
+
+// LookupRandom updates all trees which are due for a root check, then
+// returns a random selection of the nodes they contain.
+func (s *Source) LookupRandom() []*enode.Node {
+	s.sync()
+	buf := make([]*enode.Node, lookupLimit)
+	return buf[:s.ReadRandomNodes(buf)]
+}
+
+// ReadRandomNodes fills buf with random nodes from the trees synced so far.
+// It never performs DNS queries.
+func (s *Source) ReadRandomNodes(buf []*enode.Node) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, i := range s.rand.Perm(len(s.nodes)) {
+		if n == len(buf) {
+			break
+		}
+		buf[n] = s.nodes[i]
+		n++
+	}
+	return n
+}
+
+// sync updates all trees, adding trees which are linked from synced trees and
+// dropping trees which are no longer linked. DNS queries are made without
+// holding s.mu so that ReadRandomNodes and Resolve don't block.
+func (s *Source) sync() {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	done := make(map[string]bool)
+	for {
+		s.mu.Lock()
+		var todo []*clientTree
+		for key, ct := range s.trees {
+			if !done[key] {
+				todo = append(todo, ct)
+				done[key] = true
+			}
+		}
+		s.mu.Unlock()
+		if len(todo) == 0 {
+			break
+		}
+
+		for _, ct := range todo {
+			if err := ct.update(); err != nil {
+				s.c.cfg.Logger.Debug("DNS discovery tree update failed", "tree", ct.loc.domain, "err", err)
+			}
+		}
+
+		s.mu.Lock()
+		for _, ct := range todo {
+			for _, le := range ct.links {
+				if _, ok := s.trees[le.str]; !ok {
+					s.trees[le.str] = &clientTree{c: s.c, loc: le}
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneTrees()
+
+	seen := make(map[enode.ID]bool)
+	s.nodes = s.nodes[:0]
+	for _, ct := range s.trees {
+		for _, n := range ct.nodes {
+			if !seen[n.ID()] {
+				seen[n.ID()] = true
+				s.nodes = append(s.nodes, n)
+			}
+		}
+	}
+}
+
+// pruneTrees drops the trees which aren't reachable from the root trees through
+// links anymore. The caller must hold s.mu.
+func (s *Source) pruneTrees() {
+	linked := make(map[string]bool)
+	queue := append([]string{}, s.roots...)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		ct, ok := s.trees[key]
+		if !ok || linked[key] {
+			continue
+		}
+		linked[key] = true
+		for _, le := range ct.links {
+			queue = append(queue, le.str)
+		}
+	}
+	for key := range s.trees {
+		if !linked[key] {
+			delete(s.trees, key)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/math"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
+)
+
+const (
+	signingKeySeed = 0x111111
+	nodesSeed1     = 0x2945237
+	nodesSeed2     = 0x4567299
+)
+
+func TestClientSyncTree(t *testing.T) {
+	nodes := testNodes(nodesSeed1, 5)
+	tree, url := makeTestTree("n", nodes, []string{linkTo("x", signingKeySeed+1)})
+	r := mapResolver(tree.ToTXT("n"))
+	c := NewClient(Config{Resolver: r})
+
+	stree, err := c.SyncTree(url)
+	if err != nil {
+		t.Fatal("sync error:", err)
+	}
+	if !reflect.DeepEqual(sortByID(stree.Nodes()), sortByID(nodes)) {
+		t.Errorf("wrong nodes in synced tree")
+	}
+	if !reflect.DeepEqual(stree.Links(), tree.Links()) {
+		t.Errorf("wrong links in synced tree: %v", stree.Links())
+	}
+	if !reflect.DeepEqual(stree.ToTXT("n"), tree.ToTXT("n")) {
+		t.Errorf("synced tree has different TXT records")
+	}
+}
+
+// This test checks that the root signature is verified against the key in the URL.
+func TestClientSyncTreeBadSignature(t *testing.T) {
+	tree, _ := makeTestTree("n", testNodes(nodesSeed1, 1), nil)
+	r := mapResolver(tree.ToTXT("n"))
+	c := NewClient(Config{Resolver: r})
+
+	url := newLinkEntry("n", &testKey(signingKeySeed+1).PublicKey).String()
+	_, err := c.SyncTree(url)
+	if err != (entryError{"root", errInvalidSig}) {
+		t.Fatalf("wrong error %v", err)
+	}
+}
+
+// This test checks that entries whose content doesn't match their name are rejected.
+func TestClientSyncTreeHashMismatch(t *testing.T) {
+	tree, url := makeTestTree("n", testNodes(nodesSeed1, 3), nil)
+	r := mapResolver(tree.ToTXT("n"))
+	bad := tree.Nodes()[0]
+	badName := subdomain(&enrEntry{bad}) + ".n"
+	r[badName] = (&enrEntry{testNodes(nodesSeed2, 1)[0]}).String()
+	c := NewClient(Config{Resolver: r})
+
+	_, err := c.SyncTree(url)
+	if err != (nameError{badName, errHashMismatch}) {
+		t.Fatalf("wrong error %v", err)
+	}
+}
+
+// This test checks that the source follows links to other trees and
+// serves the nodes of all of them.
+func TestSourceLinks(t *testing.T) {
+	var (
+		nodes1        = testNodes(nodesSeed1, 4)
+		nodes2        = testNodes(nodesSeed2, 3)
+		tree2, url2   = makeTestTreeWithKey("m", nodes2, nil, signingKeySeed+1)
+		tree1, url1   = makeTestTree("n", nodes1, []string{url2})
+		r             = mapResolver(tree1.ToTXT("n"))
+		all           = append(append([]*enode.Node{}, nodes1...), nodes2...)
+		c             = NewClient(Config{Resolver: r})
+		buf           = make([]*enode.Node, 10)
+		src, srcError = c.NewSource(url1)
+	)
+	r.add(tree2.ToTXT("m"))
+	if srcError != nil {
+		t.Fatal(srcError)
+	}
+	if n := src.ReadRandomNodes(buf); n != 0 {
+		t.Fatalf("ReadRandomNodes returned %d nodes before sync", n)
+	}
+	result := src.LookupRandom()
+	if !reflect.DeepEqual(sortByID(result), sortByID(all)) {
+		t.Fatalf("wrong LookupRandom result: %v", result)
+	}
+	if n := src.ReadRandomNodes(buf); n != len(all) {
+		t.Fatalf("ReadRandomNodes returned %d nodes, want %d", n, len(all))
+	}
+	if rn := src.Resolve(nodes2[0]); rn == nil || rn.ID() != nodes2[0].ID() {
+		t.Fatalf("Resolve returned %v", rn)
+	}
+}
+
+// This test checks that the source drops trees which are no longer linked.
+func TestSourceLinkRemoved(t *testing.T) {
+	var (
+		clock         = new(mclock.Simulated)
+		nodes1        = testNodes(nodesSeed1, 4)
+		nodes2        = testNodes(nodesSeed2, 3)
+		tree2, url2   = makeTestTreeWithKey("m", nodes2, nil, signingKeySeed+1)
+		tree1, url1   = makeTestTree("n", nodes1, []string{url2})
+		unlinked, _   = makeTestTree("n", nodes1, nil)
+		r             = mapResolver(tree1.ToTXT("n"))
+		c             = NewClient(Config{Resolver: r, RecheckInterval: time.Minute})
+		all           = append(append([]*enode.Node{}, nodes1...), nodes2...)
+		src, srcError = c.NewSource(url1)
+	)
+	c.clock = clock
+	r.add(tree2.ToTXT("m"))
+	if srcError != nil {
+		t.Fatal(srcError)
+	}
+	if result := src.LookupRandom(); !reflect.DeepEqual(sortByID(result), sortByID(all)) {
+		t.Fatalf("wrong nodes before link removal: %v", result)
+	}
+
+	// Publish the tree without the link. The linked tree is dropped.
+	unlinked.root.seq = 2
+	unlinked.Sign(testKey(signingKeySeed), "n")
+	r.add(unlinked.ToTXT("n"))
+	clock.Run(time.Minute)
+	if result := src.LookupRandom(); !reflect.DeepEqual(sortByID(result), sortByID(nodes1)) {
+		t.Fatalf("wrong nodes after link removal: %v", result)
+	}
+	if len(src.trees) != 1 {
+		t.Fatalf("unlinked tree not dropped: %d trees", len(src.trees))
+	}
+}
+
+// This test checks that the source picks up root updates after RecheckInterval.
+func TestSourceRootUpdate(t *testing.T) {
+	var (
+		clock    = new(mclock.Simulated)
+		nodes    = testNodes(nodesSeed1, 8)
+		tree1, _ = makeTestTree("n", nodes[:4], nil)
+		tree2, _ = makeTestTree("n", nodes[4:], nil)
+		r        = mapResolver(tree1.ToTXT("n"))
+		c        = NewClient(Config{Resolver: r, RecheckInterval: time.Minute})
+	)
+	c.clock = clock
+	src, err := c.NewSource(newLinkEntry("n", &testKey(signingKeySeed).PublicKey).String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := src.LookupRandom(); !reflect.DeepEqual(sortByID(result), sortByID(nodes[:4])) {
+		t.Fatalf("wrong nodes before update: %v", result)
+	}
+
+	// Publish the new tree. It's not picked up before the recheck interval.
+	tree2.root.seq = 2
+	tree2.Sign(testKey(signingKeySeed), "n")
+	r.add(tree2.ToTXT("n"))
+	if result := src.LookupRandom(); !reflect.DeepEqual(sortByID(result), sortByID(nodes[:4])) {
+		t.Fatalf("tree updated too early: %v", result)
+	}
+	clock.Run(time.Minute)
+	if result := src.LookupRandom(); !reflect.DeepEqual(sortByID(result), sortByID(nodes[4:])) {
+		t.Fatalf("wrong nodes after update: %v", result)
+	}
+}
+
+func makeTestTree(domain string, nodes []*enode.Node, links []string) (*Tree, string) {
+	return makeTestTreeWithKey(domain, nodes, links, signingKeySeed)
+}
+
+func makeTestTreeWithKey(domain string, nodes []*enode.Node, links []string, keySeed int64) (*Tree, string) {
+	tree, err := MakeTree(1, nodes, links)
+	if err != nil {
+		panic(err)
+	}
+	url, err := tree.Sign(testKey(keySeed), domain)
+	if err != nil {
+		panic(err)
+	}
+	return tree, url
+}
+
+func linkTo(domain string, keySeed int64) string {
+	return newLinkEntry(domain, &testKey(keySeed).PublicKey).String()
+}
+
+// testKey creates a deterministic private key for testing.
+func testKey(seed int64) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(crypto.Keccak256(math.PaddedBigBytes(big.NewInt(seed), 32)))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func testNodes(seed int64, n int) []*enode.Node {
+	nodes := make([]*enode.Node, n)
+	for i := range nodes {
+		var r enr.Record
+		r.SetSeq(uint64(i))
+		r.Set(enr.IP(net.IP{10, 0, byte(i >> 8), byte(i)}))
+		r.Set(enr.TCP(30303))
+		if err := enode.SignV4(&r, testKey(seed+int64(i))); err != nil {
+			panic(err)
+		}
+		node, err := enode.New(enode.ValidSchemes, &r)
+		if err != nil {
+			panic(err)
+		}
+		nodes[i] = node
+	}
+	return nodes
+}
+
+// mapResolver is a Resolver which serves TXT records from a map.
+type mapResolver map[string]string
+
+func (mr mapResolver) add(m map[string]string) {
+	for k, v := range m {
+		mr[k] = v
+	}
+}
+
+func (mr mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if record, ok := mr[name]; ok {
+		return []string{record}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/davecgh/go-spew/spew"
+)
+
+func TestParseRoot(t *testing.T) {
+	tests := []struct {
+		input string
+		e     rootEntry
+		err   error
+	}{
+		{
+			input: "enrtree-root:v1 e=TO4Q75OQ2N7DX4EOOR7X66A6OM seq=3 sig=N-YY6UB9xD0hFx1Gmnt7v0RfSxch5tKyry2SRDoLx7B4GfPXagwLxQqyf7gAMvApFn_ORwZQekMWa_pXrcGCtw",
+			err:   entryError{"root", errSyntax},
+		},
+		{
+			input: "enrtree-root:v1 e=TO4Q75OQ2N7DX4EOOR7X66A6OM l=TO4Q75OQ2N7DX4EOOR7X66A6OM seq=3 sig=N-YY6UB9xD0hFx1Gmnt7v0RfSxch5tKyry2SRDoLx7B4GfPXagwLxQqyf7gAMvApFn_ORwZQekMWa_pXrcGCtw",
+			err:   entryError{"root", errInvalidSig},
+		},
+	}
+	for i, test := range tests {
+		e, err := parseRoot(test.input)
+		if !reflect.DeepEqual(e, test.e) {
+			t.Errorf("test %d: wrong entry %s, want %s", i, spew.Sdump(e), spew.Sdump(test.e))
+		}
+		if err != test.err {
+			t.Errorf("test %d: wrong error %q, want %q", i, err, test.err)
+		}
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	testlink := newLinkEntry("nodes.example.org", &testKey(signingKeySeed).PublicKey)
+	tests := []struct {
+		input string
+		e     entry
+		err   error
+	}{
+		// Subtrees:
+		{
+			input: "enrtree-branch:1,2",
+			err:   entryError{"branch", errInvalidChild},
+		},
+		{
+			input: "enrtree-branch:AAAAAAAAAAAAAAAA",
+			err:   entryError{"branch", errInvalidChild},
+		},
+		{
+			input: "enrtree-branch:",
+			e:     &branchEntry{},
+		},
+		{
+			input: "enrtree-branch:AAAAAAAAAAAAAAAAAAAAAAAAAA",
+			e:     &branchEntry{[]string{"AAAAAAAAAAAAAAAAAAAAAAAAAA"}},
+		},
+		{
+			input: "enrtree-branch:AAAAAAAAAAAAAAAAAAAAAAAAAA,BBBBBBBBBBBBBBBBBBBBBBBBBB",
+			e:     &branchEntry{[]string{"AAAAAAAAAAAAAAAAAAAAAAAAAA", "BBBBBBBBBBBBBBBBBBBBBBBBBB"}},
+		},
+		// Links
+		{
+			input: testlink.String(),
+			e:     testlink,
+		},
+		{
+			input: "enrtree://nodes.example.org",
+			err:   entryError{"link", errNoPubkey},
+		},
+		{
+			input: "enrtree://AP62DT7WOTEQZGQZOU474PP3KMEGVTTE7A7NPRXKX3DUD57@nodes.example.org",
+			err:   entryError{"link", errBadPubkey},
+		},
+		{
+			input: "enrtree://AP62DT7WONEQZGQZOU474PP3KMEGVTTE7A7NPRXKX3DUD57TQHGIA@nodes.example.org",
+			err:   entryError{"link", errBadPubkey},
+		},
+		// ENRs
+		{
+			input: "enr:-ooo",
+			err:   entryError{"enr", errInvalidENR},
+		},
+		// Invalid
+		{input: "", err: errUnknownEntry},
+		{input: "foo", err: errUnknownEntry},
+		{input: "enrtree", err: errUnknownEntry},
+		{input: "enrtree-x=", err: errUnknownEntry},
+	}
+	for i, test := range tests {
+		e, err := parseEntry(test.input, enode.ValidSchemes)
+		if !reflect.DeepEqual(e, test.e) {
+			t.Errorf("test %d: wrong entry %s, want %s", i, spew.Sdump(e), spew.Sdump(test.e))
+		}
+		if err != test.err {
+			t.Errorf("test %d: wrong error %q, want %q", i, err, test.err)
+		}
+	}
+}
+
+func TestMakeTree(t *testing.T) {
+	nodes := testNodes(nodesSeed2, 50)
+	tree, err := MakeTree(2, nodes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txt := tree.ToTXT("")
+	if len(txt) < len(nodes)+1 {
+		t.Fatal("too few TXT records in output")
+	}
+	if !reflect.DeepEqual(tree.Nodes(), sortByID(nodes)) {
+		t.Fatal("wrong nodes in tree")
+	}
+}
+
+func TestTreeSignature(t *testing.T) {
+	var (
+		key   = testKey(signingKeySeed)
+		other = testKey(signingKeySeed + 1)
+	)
+	tree, err := MakeTree(1, testNodes(nodesSeed1, 3), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(key, "n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain, pubkey, err := ParseURL(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain != "n" || !reflect.DeepEqual(pubkey, &key.PublicKey) {
+		t.Fatalf("wrong URL components %q %v", domain, pubkey)
+	}
+
+	// The signature can be transferred to an identical tree.
+	tree2, _ := MakeTree(1, testNodes(nodesSeed1, 3), nil)
+	if err := tree2.SetSignature(&key.PublicKey, tree.Signature()); err != nil {
+		t.Fatal("SetSignature failed for valid signature:", err)
+	}
+	if err := tree2.SetSignature(&other.PublicKey, tree.Signature()); err != errInvalidSig {
+		t.Fatalf("SetSignature with wrong key returned %v, want %v", err, errInvalidSig)
+	}
+	if !reflect.DeepEqual(tree.ToTXT("n"), tree2.ToTXT("n")) {
+		t.Fatal("TXT records differ after SetSignature")
+	}
+}
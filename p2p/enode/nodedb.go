@@ -40,6 +40,8 @@ const (
 	dbVersionKey   = "version" // Version of the database to flush if changes
 	dbNodePrefix   = "n:"      // Identifier to prefix node entries with
 	dbLocalPrefix  = "local:"
+	dbBanPrefix    = "ban:"   // Identifier to prefix ban entries with, the full key is "ban:<ID>"
+	dbScorePrefix  = "score:" // Identifier to prefix score entries with, the full key is "score:<ID>"
 	dbDiscoverRoot = "v4"
 
 	// These fields are stored per ID and IP, the full key is "n:<ID>:v4:<IP>:findfail".
@@ -378,6 +380,76 @@ func (db *DB) UpdateFindFails(id ID, ip net.IP, fails int) error {
 	return db.storeInt64(nodeItemKey(id, ip, dbNodeFindFails), int64(fails))
 }
 
+// scoreKey returns the database key of a node's reputation score. Like bans,
+// scores outlive the node entries, which expire when the node is not seen for
+// a while.
+func scoreKey(id ID) []byte {
+	return append([]byte(dbScorePrefix), id[:]...)
+}
+
+// Score retrieves the reputation score of a node.
+func (db *DB) Score(id ID) int64 {
+	return db.fetchInt64(scoreKey(id))
+}
+
+// UpdateScore stores the reputation score of a node.
+func (db *DB) UpdateScore(id ID, score int64) error {
+	return db.storeInt64(scoreKey(id), score)
+}
+
+// UpdateScores stores the reputation scores of several nodes in one batch.
+func (db *DB) UpdateScores(scores map[ID]int64) error {
+	batch := new(leveldb.Batch)
+	blob := make([]byte, binary.MaxVarintLen64)
+	for id, score := range scores {
+		batch.Put(scoreKey(id), blob[:binary.PutVarint(blob, score)])
+	}
+	return db.lvl.Write(batch, nil)
+}
+
+// banKey returns the database key of a node's ban entry. Bans are kept outside of
+// the node entries so they aren't dropped along with stale node data.
+func banKey(id ID) []byte {
+	return append([]byte(dbBanPrefix), id[:]...)
+}
+
+// BanExpiry retrieves the time at which the ban of a node expires. The zero time
+// is returned for nodes that were never banned.
+func (db *DB) BanExpiry(id ID) time.Time {
+	if expiry := db.fetchInt64(banKey(id)); expiry != 0 {
+		return time.Unix(expiry, 0)
+	}
+	return time.Time{}
+}
+
+// UpdateBan stores the time at which the ban of a node expires.
+func (db *DB) UpdateBan(id ID, expiry time.Time) error {
+	return db.storeInt64(banKey(id), expiry.Unix())
+}
+
+// DeleteBan removes the ban entry of a node.
+func (db *DB) DeleteBan(id ID) error {
+	return db.lvl.Delete(banKey(id), nil)
+}
+
+// Bans retrieves all ban entries along with their expiry times.
+func (db *DB) Bans() map[ID]time.Time {
+	bans := make(map[ID]time.Time)
+	it := db.lvl.NewIterator(util.BytesPrefix([]byte(dbBanPrefix)), nil)
+	defer it.Release()
+	for it.Next() {
+		var id ID
+		if len(it.Key()) != len(dbBanPrefix)+len(id) {
+			continue
+		}
+		copy(id[:], it.Key()[len(dbBanPrefix):])
+		if expiry, n := binary.Varint(it.Value()); n > 0 {
+			bans[id] = time.Unix(expiry, 0)
+		}
+	}
+	return bans
+}
+
 // LocalSeq retrieves the local record sequence counter.
 func (db *DB) localSeq(id ID) uint64 {
 	return db.fetchUint64(nodeItemKey(id, zeroIP, dbLocalSeq))
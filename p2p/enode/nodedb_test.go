@@ -154,6 +154,41 @@ func TestDBFetchStore(t *testing.T) {
 	if stored := db.FindFails(node.ID(), node.IP()); stored != num {
 		t.Errorf("find-node fails: value mismatch: have %v, want %v", stored, num)
 	}
+	// Check fetch/store operations on a node reputation score
+	if stored := db.Score(node.ID()); stored != 0 {
+		t.Errorf("score: non-existing object: %v", stored)
+	}
+	if err := db.UpdateScore(node.ID(), -42); err != nil {
+		t.Errorf("score: failed to update: %v", err)
+	}
+	if stored := db.Score(node.ID()); stored != -42 {
+		t.Errorf("score: value mismatch: have %v, want %v", stored, -42)
+	}
+	if err := db.UpdateScores(map[ID]int64{node.ID(): 7}); err != nil {
+		t.Errorf("score: failed to batch update: %v", err)
+	}
+	if stored := db.Score(node.ID()); stored != 7 {
+		t.Errorf("score: batch value mismatch: have %v, want %v", stored, 7)
+	}
+	// Check fetch/store/delete operations on a node ban entry
+	if stored := db.BanExpiry(node.ID()); !stored.IsZero() {
+		t.Errorf("ban: non-existing object: %v", stored)
+	}
+	if err := db.UpdateBan(node.ID(), inst); err != nil {
+		t.Errorf("ban: failed to update: %v", err)
+	}
+	if stored := db.BanExpiry(node.ID()); stored.Unix() != inst.Unix() {
+		t.Errorf("ban: value mismatch: have %v, want %v", stored, inst)
+	}
+	if bans := db.Bans(); len(bans) != 1 || bans[node.ID()].Unix() != inst.Unix() {
+		t.Errorf("ban: list mismatch: have %v", bans)
+	}
+	if err := db.DeleteBan(node.ID()); err != nil {
+		t.Errorf("ban: failed to delete: %v", err)
+	}
+	if stored := db.BanExpiry(node.ID()); !stored.IsZero() {
+		t.Errorf("ban: deleted object: %v", stored)
+	}
 	// Check fetch/store operations on an actual node object
 	if stored := db.Node(node.ID()); stored != nil {
 		t.Errorf("node: non-existing object: %v", stored)
@@ -426,7 +461,7 @@ func TestDBExpiration(t *testing.T) {
 	db, _ := OpenDB("")
 	defer db.Close()
 
-	// Add all the test nodes and set their last pong time.
+	// Add all the test nodes and set their last pong time and score.
 	for i, seed := range nodeDBExpirationNodes {
 		if seed.storeNode {
 			if err := db.UpdateNode(seed.node); err != nil {
@@ -436,6 +471,9 @@ func TestDBExpiration(t *testing.T) {
 		if err := db.UpdateLastPongReceived(seed.node.ID(), seed.node.IP(), seed.pong); err != nil {
 			t.Fatalf("node %d: failed to update bondTime: %v", i, err)
 		}
+		if err := db.UpdateScore(seed.node.ID(), 42); err != nil {
+			t.Fatalf("node %d: failed to update score: %v", i, err)
+		}
 	}
 
 	db.expireNodes()
@@ -460,5 +498,9 @@ func TestDBExpiration(t *testing.T) {
 				t.Errorf("pong time %d (%s) should be %v after expiration, but is %v", i, seed.node.ID().TerminalString(), seed.pong, pong)
 			}
 		}
+		// Scores are kept regardless of expiration.
+		if score := db.Score(seed.node.ID()); score != 42 {
+			t.Errorf("score %d (%s) should be 42 after expiration, but is %d", i, seed.node.ID().TerminalString(), score)
+		}
 	}
 }
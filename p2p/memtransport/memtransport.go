@@ -0,0 +1,148 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package memtransport implements an in-memory stream transport for p2p servers
+// running in the same process.
+//
+// Listeners are registered under the ID of the node accepting connections and
+// dialing looks up the listener by the ID of the destination node. Set the
+// listener as p2p.Config.Listener and the transport as p2p.Config.Dialer, or
+// register it in a p2p.TransportDialer under Network.
+package memtransport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+// Network is the network name of the transport, as advertised in node records.
+const Network = "mem"
+
+// dialTimeout is how long dialing waits for the listener to accept, matching the
+// default dial timeout of p2p servers.
+var dialTimeout = 15 * time.Second
+
+var (
+	errClosed       = errors.New("listener closed")
+	errNotListening = errors.New("node is not listening")
+	errDialTimeout  = errors.New("dial timeout")
+)
+
+// Addr is the address of an in-memory endpoint.
+type Addr enode.ID
+
+// Network implements net.Addr.
+func (a Addr) Network() string { return Network }
+
+// String implements net.Addr.
+func (a Addr) String() string { return enode.ID(a).String() }
+
+// Transport is a set of in-memory listeners.
+type Transport struct {
+	lock      sync.Mutex
+	listeners map[enode.ID]*listener
+}
+
+// New creates an empty transport.
+func New() *Transport {
+	return &Transport{listeners: make(map[enode.ID]*listener)}
+}
+
+// Listen opens a listener accepting connections for the node with the given ID.
+func (t *Transport) Listen(id enode.ID) (net.Listener, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.listeners[id]; ok {
+		return nil, fmt.Errorf("already listening for %v", id)
+	}
+	l := &listener{
+		t:      t,
+		id:     id,
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+	t.listeners[id] = l
+	return l, nil
+}
+
+// Dial connects to the listener of the given node, failing if the connection is
+// not accepted within the dial timeout. It implements p2p.NodeDialer.
+func (t *Transport) Dial(dest *enode.Node) (net.Conn, error) {
+	t.lock.Lock()
+	l := t.listeners[dest.ID()]
+	t.lock.Unlock()
+
+	if l == nil {
+		return nil, errNotListening
+	}
+	local, remote := net.Pipe()
+	timeout := time.NewTimer(dialTimeout)
+	defer timeout.Stop()
+
+	select {
+	case l.conns <- remote:
+		return local, nil
+	case <-l.closed:
+		local.Close()
+		remote.Close()
+		return nil, errNotListening
+	case <-timeout.C:
+		local.Close()
+		remote.Close()
+		return nil, errDialTimeout
+	}
+}
+
+// listener implements net.Listener for the transport.
+type listener struct {
+	t      *Transport
+	id     enode.ID
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+// Accept waits for the next connection.
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, errClosed
+	}
+}
+
+// Close stops the listener, unregistering it from the transport.
+func (l *listener) Close() error {
+	l.once.Do(func() {
+		l.t.lock.Lock()
+		delete(l.t.listeners, l.id)
+		l.t.lock.Unlock()
+		close(l.closed)
+	})
+	return nil
+}
+
+// Addr returns the address of the listener.
+func (l *listener) Addr() net.Addr {
+	return Addr(l.id)
+}
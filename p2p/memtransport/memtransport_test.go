@@ -0,0 +1,99 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package memtransport
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+func TestDialAccept(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	node := enode.NewV4(&key.PublicKey, nil, 0, 0)
+
+	tr := New()
+	if _, err := tr.Dial(node); err != errNotListening {
+		t.Fatalf("wrong error dialing without listener: %v", err)
+	}
+	l, err := tr.Listen(node.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Listen(node.ID()); err == nil {
+		t.Fatal("listening twice for the same node succeeded")
+	}
+	if l.Addr().Network() != Network || l.Addr().String() != node.ID().String() {
+		t.Errorf("wrong listener address: %v/%v", l.Addr().Network(), l.Addr())
+	}
+
+	// Data written on the dialed connection arrives on the accepted one.
+	accepted := make(chan []byte)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			t.Error("accept error:", err)
+			close(accepted)
+			return
+		}
+		defer c.Close()
+		buf := make([]byte, 5)
+		io.ReadFull(c, buf)
+		accepted <- buf
+	}()
+	c, err := tr.Dial(node)
+	if err != nil {
+		t.Fatal("dial error:", err)
+	}
+	defer c.Close()
+	c.Write([]byte("hello"))
+	if buf := <-accepted; !bytes.Equal(buf, []byte("hello")) {
+		t.Errorf("wrong data received: %q", buf)
+	}
+
+	// Closing unregisters the listener.
+	l.Close()
+	if _, err := l.Accept(); err != errClosed {
+		t.Errorf("wrong error accepting on closed listener: %v", err)
+	}
+	if _, err := tr.Dial(node); err != errNotListening {
+		t.Errorf("wrong error dialing closed listener: %v", err)
+	}
+}
+
+func TestDialTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { dialTimeout = timeout }(dialTimeout)
+	dialTimeout = 50 * time.Millisecond
+
+	key, _ := crypto.GenerateKey()
+	node := enode.NewV4(&key.PublicKey, nil, 0, 0)
+
+	// Dialing a listener that never accepts fails after the timeout.
+	tr := New()
+	l, err := tr.Listen(node.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if _, err := tr.Dial(node); err != errDialTimeout {
+		t.Errorf("wrong error dialing unresponsive listener: %v", err)
+	}
+}
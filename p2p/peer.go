@@ -113,14 +113,22 @@ type Peer struct {
 
 	// events receives message send / receive events if set
 	events *event.Feed
+
+	// rep tracks the reputation score of the peer if set
+	rep *reputation
+
+	// stats counts the messages exchanged with the peer
+	stats *peerStats
 }
 
-// NewPeer returns a peer for testing purposes.
+// NewPeer returns a peer for testing purposes. Its reputation is only kept in
+// memory.
 func NewPeer(id enode.ID, name string, caps []Cap) *Peer {
 	pipe, _ := net.Pipe()
 	node := enode.SignNull(new(enr.Record), id)
 	conn := &conn{fd: pipe, transport: nil, node: node, caps: caps, name: name}
 	peer := newPeer(conn, nil)
+	peer.rep = newReputation(nil)
 	close(peer.closed) // ensures Disconnect doesn't block
 	return peer
 }
@@ -186,6 +194,7 @@ func newPeer(conn *conn, protocols []Protocol) *Peer {
 		protoErr: make(chan error, len(protomap)+1), // protocols + pingLoop
 		closed:   make(chan struct{}),
 		log:      log.New("id", conn.node.ID(), "conn", conn.flags),
+		stats:    newPeerStats(),
 	}
 	return p
 }
@@ -352,6 +361,7 @@ func (p *Peer) startProtocols(writeStart <-chan struct{}, writeErr chan<- error)
 		proto.closed = p.closed
 		proto.wstart = writeStart
 		proto.werr = writeErr
+		proto.stats = p.stats
 		var rw MsgReadWriter = proto
 		if p.events != nil {
 			rw = newMsgEventer(rw, p.events, p.ID(), proto.Name)
@@ -390,16 +400,21 @@ type protoRW struct {
 	werr   chan<- error    // for write results
 	offset uint64
 	w      MsgWriter
+	stats  *peerStats
 }
 
 func (rw *protoRW) WriteMsg(msg Msg) (err error) {
 	if msg.Code >= rw.Length {
 		return newPeerError(errInvalidMsgCode, "not handled")
 	}
+	code, size := msg.Code, msg.Size
 	msg.Code += rw.offset
 	select {
 	case <-rw.wstart:
 		err = rw.w.WriteMsg(msg)
+		if err == nil {
+			rw.stats.recordMsg(rw.Name, code, size, false)
+		}
 		// Report write status back to Peer.run. It will initiate
 		// shutdown if the error is non-nil and unblock the next write
 		// otherwise. The calling protocol code should exit for errors
@@ -415,6 +430,7 @@ func (rw *protoRW) ReadMsg() (Msg, error) {
 	select {
 	case msg := <-rw.in:
 		msg.Code -= rw.offset
+		rw.stats.recordMsg(rw.Name, msg.Code, msg.Size, true)
 		return msg, nil
 	case <-rw.closed:
 		return Msg{}, io.EOF
@@ -437,6 +453,7 @@ type PeerInfo struct {
 		Static        bool   `json:"static"`
 	} `json:"network"`
 	Protocols map[string]interface{} `json:"protocols"` // Sub-protocol specific metadata fields
+	Stats     *PeerStats             `json:"stats"`     // Per-protocol message and latency statistics
 }
 
 // Info gathers and returns a collection of metadata known about a peer.
@@ -453,6 +470,7 @@ func (p *Peer) Info() *PeerInfo {
 		Name:      p.Name(),
 		Caps:      caps,
 		Protocols: make(map[string]interface{}),
+		Stats:     p.Stats(),
 	}
 	info.Network.LocalAddress = p.LocalAddr().String()
 	info.Network.RemoteAddress = p.RemoteAddr().String()
@@ -98,6 +98,78 @@ func TestPeerProtoReadMsg(t *testing.T) {
 	}
 }
 
+func TestPeerStats(t *testing.T) {
+	proto := Protocol{
+		Name:   "a",
+		Length: 5,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			if err := ExpectMsg(rw, 2, []uint{1}); err != nil {
+				t.Error(err)
+			}
+			if err := SendItems(rw, 1, "foo", "bar"); err != nil {
+				t.Error(err)
+			}
+			peer.RecordResponse("a", 2, 10*time.Millisecond)
+			peer.RecordResponse("a", 2, 30*time.Millisecond)
+			peer.RecordResponse("b", 0, 5*time.Millisecond)
+			return nil
+		},
+	}
+	closer, rw, peer, errc := testPeer([]Protocol{proto})
+	defer closer()
+
+	Send(rw, baseProtocolLength+2, []uint{1})
+	if err := ExpectMsg(rw, baseProtocolLength+1, []string{"foo", "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-errc:
+	case <-time.After(2 * time.Second):
+		t.Fatal("protocol did not return")
+	}
+
+	want := &PeerStats{
+		Protocols: map[string]*ProtocolStats{
+			"a": {
+				MsgStats: MsgStats{MessagesIn: 1, MessagesOut: 1, BytesIn: 2, BytesOut: 9},
+				Codes: map[uint64]*MsgStats{
+					1: {MessagesOut: 1, BytesOut: 9},
+					2: {MessagesIn: 1, BytesIn: 2},
+				},
+				Latency: map[uint64]*LatencyStats{
+					2: {
+						Responses: 2,
+						Min:       10 * time.Millisecond,
+						Max:       30 * time.Millisecond,
+						Average:   20 * time.Millisecond,
+						Last:      30 * time.Millisecond,
+						sum:       40 * time.Millisecond,
+					},
+				},
+			},
+			"b": {
+				Codes: map[uint64]*MsgStats{},
+				Latency: map[uint64]*LatencyStats{
+					0: {
+						Responses: 1,
+						Min:       5 * time.Millisecond,
+						Max:       5 * time.Millisecond,
+						Average:   5 * time.Millisecond,
+						Last:      5 * time.Millisecond,
+						sum:       5 * time.Millisecond,
+					},
+				},
+			},
+		},
+	}
+	if stats := peer.Stats(); !reflect.DeepEqual(stats, want) {
+		t.Errorf("stats mismatch:\ngot  %+v\nwant %+v", stats, want)
+	}
+	if info := peer.Info(); !reflect.DeepEqual(info.Stats, want) {
+		t.Errorf("peer info stats mismatch:\ngot  %+v\nwant %+v", info.Stats, want)
+	}
+}
+
 func TestPeerProtoEncodeMsg(t *testing.T) {
 	proto := Protocol{
 		Name:   "a",
@@ -0,0 +1,193 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"sync"
+	"time"
+)
+
+// MsgStats counts the messages exchanged with a peer. Byte counts are those of
+// the message payloads, before compression.
+type MsgStats struct {
+	MessagesIn  uint64 `json:"messagesIn"`
+	MessagesOut uint64 `json:"messagesOut"`
+	BytesIn     uint64 `json:"bytesIn"`
+	BytesOut    uint64 `json:"bytesOut"`
+}
+
+// add counts a single message.
+func (s *MsgStats) add(size uint32, ingress bool) {
+	if ingress {
+		s.MessagesIn++
+		s.BytesIn += uint64(size)
+	} else {
+		s.MessagesOut++
+		s.BytesOut += uint64(size)
+	}
+}
+
+// ProtocolStats counts the messages of a sub-protocol, both in total and broken
+// down by message code. Latency holds the response times of the requests the
+// sub-protocol reported through Peer.RecordResponse, keyed by the code of the
+// reply. The paa and les protocols report every reply matched to a request,
+// whatever the protocol version. Protocols that don't report responses have no
+// latency statistics.
+type ProtocolStats struct {
+	MsgStats
+	Codes   map[uint64]*MsgStats     `json:"codes"`
+	Latency map[uint64]*LatencyStats `json:"latency,omitempty"`
+}
+
+// newProtocolStats creates empty statistics for a sub-protocol.
+func newProtocolStats() *ProtocolStats {
+	return &ProtocolStats{Codes: make(map[uint64]*MsgStats)}
+}
+
+// LatencyStats summarises the time a peer took to answer requests of one kind.
+// Durations are encoded as nanoseconds in JSON.
+type LatencyStats struct {
+	Responses uint64        `json:"responses"`
+	Min       time.Duration `json:"min"`
+	Max       time.Duration `json:"max"`
+	Average   time.Duration `json:"average"`
+	Last      time.Duration `json:"last"`
+
+	sum time.Duration // Total of all response times, for the average
+}
+
+// add includes a response time in the summary.
+func (l *LatencyStats) add(d time.Duration) {
+	if l.Responses == 0 || d < l.Min {
+		l.Min = d
+	}
+	if d > l.Max {
+		l.Max = d
+	}
+	l.Responses++
+	l.Last = d
+	l.sum += d
+	l.Average = l.sum / time.Duration(l.Responses)
+}
+
+// PeerStats is a snapshot of the traffic statistics of a peer.
+type PeerStats struct {
+	Protocols map[string]*ProtocolStats `json:"protocols"`
+}
+
+// peerStats collects the traffic statistics of a peer.
+type peerStats struct {
+	lock      sync.Mutex
+	protocols map[string]*ProtocolStats
+}
+
+func newPeerStats() *peerStats {
+	return &peerStats{protocols: make(map[string]*ProtocolStats)}
+}
+
+// recordMsg counts a message of the given protocol. The code is relative to the
+// protocol's offset. All methods of peerStats are no-ops on a nil receiver.
+func (s *peerStats) recordMsg(proto string, code uint64, size uint32, ingress bool) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ps := s.protocol(proto)
+	cs := ps.Codes[code]
+	if cs == nil {
+		cs = new(MsgStats)
+		ps.Codes[code] = cs
+	}
+	ps.add(size, ingress)
+	cs.add(size, ingress)
+}
+
+// recordLatency adds a request/response round trip time to the latency summary
+// of the given protocol and reply code.
+func (s *peerStats) recordLatency(proto string, code uint64, d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ps := s.protocol(proto)
+	if ps.Latency == nil {
+		ps.Latency = make(map[uint64]*LatencyStats)
+	}
+	l := ps.Latency[code]
+	if l == nil {
+		l = new(LatencyStats)
+		ps.Latency[code] = l
+	}
+	l.add(d)
+}
+
+// protocol returns the statistics of a protocol, creating them if necessary.
+// The lock must be held.
+func (s *peerStats) protocol(proto string) *ProtocolStats {
+	ps := s.protocols[proto]
+	if ps == nil {
+		ps = newProtocolStats()
+		s.protocols[proto] = ps
+	}
+	return ps
+}
+
+// snapshot returns a copy of the current statistics.
+func (s *peerStats) snapshot() *PeerStats {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := &PeerStats{Protocols: make(map[string]*ProtocolStats, len(s.protocols))}
+	for name, ps := range s.protocols {
+		cpy := &ProtocolStats{MsgStats: ps.MsgStats, Codes: make(map[uint64]*MsgStats, len(ps.Codes))}
+		for code, cs := range ps.Codes {
+			c := *cs
+			cpy.Codes[code] = &c
+		}
+		if ps.Latency != nil {
+			cpy.Latency = make(map[uint64]*LatencyStats, len(ps.Latency))
+			for code, ls := range ps.Latency {
+				l := *ls
+				cpy.Latency[code] = &l
+			}
+		}
+		stats.Protocols[name] = cpy
+	}
+	return stats
+}
+
+// Stats returns the traffic statistics of the peer.
+func (p *Peer) Stats() *PeerStats {
+	return p.stats.snapshot()
+}
+
+// PeersStats returns the traffic statistics of all connected peers, keyed by
+// node identifier.
+func (srv *Server) PeersStats() map[string]*PeerStats {
+	stats := make(map[string]*PeerStats)
+	for _, p := range srv.Peers() {
+		stats[p.ID().String()] = p.Stats()
+	}
+	return stats
+}
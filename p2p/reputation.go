@@ -0,0 +1,206 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+const (
+	// Score adjustments for the events tracked by the reputation system.
+	scoreUsefulResponse    = 1   // Credited for every useful response
+	scoreFastResponse      = 1   // Extra credit for responses faster than fastResponseTime
+	scoreTimeout           = -5  // Charged for requests timing out
+	scoreUseless           = -10 // Charged for peers dropped as useless
+	scoreProtocolViolation = -50 // Charged for invalid messages and protocol breaches
+
+	// Scores are kept within these bounds, so no amount of past behaviour can
+	// make a node unforgivable or untouchable.
+	minScore = -1000
+	maxScore = 1000
+
+	// fastResponseTime is the latency below which responses earn extra credit.
+	fastResponseTime = time.Second
+
+	// scoreEvictMargin is the minimum difference between the score of a new
+	// connection and the worst inbound peer for the latter to be evicted when
+	// the server is full. It prevents churn between nodes of similar standing.
+	scoreEvictMargin = 10
+
+	// scoreFlushInterval is how often changed scores are written to the database.
+	scoreFlushInterval = time.Minute
+)
+
+// reputation tracks the scores of remote nodes, persisting them in the node
+// database so they survive restarts. Scores change on every response, so they
+// are kept in memory and written out in batches by flush. Without a database
+// the scores only live in memory.
+type reputation struct {
+	db     *enode.DB
+	lock   sync.Mutex            // Serialises read-modify-write cycles on the scores
+	scores map[enode.ID]int64    // Scores loaded or changed since the last flush
+	dirty  map[enode.ID]struct{} // Scores changed since the last flush
+}
+
+func newReputation(db *enode.DB) *reputation {
+	return &reputation{
+		db:     db,
+		scores: make(map[enode.ID]int64),
+		dirty:  make(map[enode.ID]struct{}),
+	}
+}
+
+// score returns the current score of a node. Unknown nodes have a zero score.
+func (r *reputation) score(id enode.ID) int64 {
+	if r == nil {
+		return 0
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.load(id)
+}
+
+// load returns the score of a node, reading it from the database if it isn't
+// cached yet. The lock must be held.
+func (r *reputation) load(id enode.ID) int64 {
+	score, ok := r.scores[id]
+	if !ok && r.db != nil {
+		score = r.db.Score(id)
+		r.scores[id] = score
+	}
+	return score
+}
+
+// adjust changes the score of a node by delta, returning the new score.
+func (r *reputation) adjust(id enode.ID, delta int64) int64 {
+	if r == nil {
+		return 0
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	score := r.load(id) + delta
+	if score < minScore {
+		score = minScore
+	}
+	if score > maxScore {
+		score = maxScore
+	}
+	r.scores[id] = score
+	r.dirty[id] = struct{}{}
+	return score
+}
+
+// flush writes the changed scores to the database and drops the cache, so it
+// only ever holds the nodes seen since the last flush.
+func (r *reputation) flush() error {
+	if r == nil || r.db == nil {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	changed := make(map[enode.ID]int64, len(r.dirty))
+	for id := range r.dirty {
+		changed[id] = r.scores[id]
+	}
+	if err := r.db.UpdateScores(changed); err != nil {
+		return err
+	}
+	r.scores = make(map[enode.ID]int64)
+	r.dirty = make(map[enode.ID]struct{})
+	return nil
+}
+
+// sortByScore orders nodes by descending score. Nodes with equal score keep
+// their relative order.
+func (r *reputation) sortByScore(nodes []*enode.Node) {
+	if r == nil || len(nodes) < 2 {
+		return
+	}
+	scores := make(map[enode.ID]int64, len(nodes))
+	for _, n := range nodes {
+		scores[n.ID()] = r.score(n.ID())
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return scores[nodes[i].ID()] > scores[nodes[j].ID()]
+	})
+}
+
+// disconnectPenalty returns the score adjustment for a peer that was dropped
+// with the given error. Only disconnects initiated locally for a reason that
+// reflects on the remote node are charged; network failures and remote
+// requested disconnects are not. Subprotocol errors can't be told apart from
+// network failures either, so subprotocols charge the misbehaviour they detect
+// themselves through RecordTimeout and RecordViolation.
+func disconnectPenalty(err error, requested bool) int64 {
+	if requested {
+		return 0
+	}
+	switch err := err.(type) {
+	case DiscReason:
+		switch err {
+		case DiscUselessPeer:
+			return scoreUseless
+		case DiscProtocolError:
+			return scoreProtocolViolation
+		case DiscReadTimeout:
+			return scoreTimeout
+		}
+	case *peerError:
+		switch err.code {
+		case errInvalidMsgCode, errInvalidMsg:
+			return scoreProtocolViolation
+		}
+	}
+	return 0
+}
+
+// RecordResponse credits the peer's reputation with a useful response that
+// arrived after the given latency. The latency is added to the peer's statistics
+// of the given protocol, under the code of the reply message.
+func (p *Peer) RecordResponse(proto string, code uint64, latency time.Duration) {
+	p.stats.recordLatency(proto, code, latency)
+
+	delta := int64(scoreUsefulResponse)
+	if latency < fastResponseTime {
+		delta += scoreFastResponse
+	}
+	p.rep.adjust(p.ID(), delta)
+}
+
+// RecordTimeout charges the peer's reputation for failing to answer a request
+// in time.
+func (p *Peer) RecordTimeout() {
+	p.rep.adjust(p.ID(), scoreTimeout)
+}
+
+// RecordViolation charges the peer's reputation for a protocol violation, such
+// as sending invalid data.
+func (p *Peer) RecordViolation() {
+	p.rep.adjust(p.ID(), scoreProtocolViolation)
+}
+
+// Score returns the reputation score of the peer.
+func (p *Peer) Score() int64 {
+	return p.rep.score(p.ID())
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"testing"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+// Tests that score changes are only written to the database when flushed, and
+// that flushed scores survive reloading.
+func TestReputationFlush(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	rep := newReputation(db)
+	rep.adjust(uintID(1), scoreTimeout)
+	rep.adjust(uintID(2), minScore-1)
+
+	if score := rep.score(uintID(1)); score != scoreTimeout {
+		t.Errorf("wrong cached score of node 1: have %d, want %d", score, scoreTimeout)
+	}
+	if score := db.Score(uintID(1)); score != 0 {
+		t.Errorf("score of node 1 stored before flush: %d", score)
+	}
+	if err := rep.flush(); err != nil {
+		t.Fatal("flush failed:", err)
+	}
+	reloaded := newReputation(db)
+	if score := reloaded.score(uintID(1)); score != scoreTimeout {
+		t.Errorf("wrong score of node 1 after reload: have %d, want %d", score, scoreTimeout)
+	}
+	if score := reloaded.score(uintID(2)); score != minScore {
+		t.Errorf("wrong score of node 2 after reload: have %d, want %d", score, minScore)
+	}
+	// Flushing again must not clobber scores changed through other instances.
+	reloaded.adjust(uintID(1), 1)
+	reloaded.flush()
+	if err := rep.flush(); err != nil {
+		t.Fatal("flush failed:", err)
+	}
+	if score := db.Score(uintID(1)); score != scoreTimeout+1 {
+		t.Errorf("wrong stored score of node 1: have %d, want %d", score, scoreTimeout+1)
+	}
+}
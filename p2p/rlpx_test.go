@@ -265,6 +265,98 @@ func TestProtocolHandshakeErrors(t *testing.T) {
 	}
 }
 
+// This test checks that Snappy compression is only enabled if both sides support
+// it, and that messages go through between peers of different versions.
+func TestProtocolHandshakeSnappy(t *testing.T) {
+	tests := []struct {
+		version0, version1 uint64
+		snappy             bool
+	}{
+		{version0: 4, version1: 4, snappy: false},
+		{version0: 4, version1: 5, snappy: false},
+		{version0: 5, version1: 4, snappy: false},
+		{version0: 5, version1: 5, snappy: true},
+		{version0: 5, version1: 6, snappy: true},
+	}
+	for _, test := range tests {
+		if err := testProtocolHandshakeSnappy(test.version0, test.version1, test.snappy); err != nil {
+			t.Errorf("v%d <-> v%d: %v", test.version0, test.version1, err)
+		}
+	}
+}
+
+func testProtocolHandshakeSnappy(version0, version1 uint64, snappy bool) error {
+	var (
+		prv0, _  = crypto.GenerateKey()
+		prv1, _  = crypto.GenerateKey()
+		hs0      = &protoHandshake{Version: version0, ID: crypto.FromECDSAPub(&prv0.PublicKey)[1:]}
+		hs1      = &protoHandshake{Version: version1, ID: crypto.FromECDSAPub(&prv1.PublicKey)[1:]}
+		fd0, fd1 = net.Pipe()
+		c0, c1   = newRLPX(fd0).(*rlpx), newRLPX(fd1).(*rlpx)
+		output   = make(chan error, 2)
+		payload  = bytes.Repeat([]byte{0x42}, 4096)
+	)
+	defer fd0.Close()
+	defer fd1.Close()
+
+	// Both sides send a compressible message after the handshake and expect the
+	// message of the other side.
+	run := func(c *rlpx, prv *ecdsa.PrivateKey, dest *ecdsa.PublicKey, hs *protoHandshake) error {
+		if _, err := c.doEncHandshake(prv, dest); err != nil {
+			return err
+		}
+		if _, err := c.doProtoHandshake(hs); err != nil {
+			return err
+		}
+		if c.rw.snappy != snappy {
+			return fmt.Errorf("snappy mismatch: got %t, want %t", c.rw.snappy, snappy)
+		}
+		werr := make(chan error, 1)
+		go func() { werr <- Send(c, baseProtocolLength, payload) }()
+		if err := ExpectMsg(c, baseProtocolLength, payload); err != nil {
+			return err
+		}
+		return <-werr
+	}
+	go func() { output <- run(c0, prv0, &prv1.PublicKey, hs0) }()
+	go func() { output <- run(c1, prv1, nil, hs1) }()
+
+	for i := 0; i < 2; i++ {
+		if err := <-output; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// This test checks that compressed messages expanding beyond the maximum message
+// size are rejected without being decompressed.
+func TestRLPXFrameSnappyTooLarge(t *testing.T) {
+	buf := new(bytes.Buffer)
+	hash := fakeHash([]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+	rw := newRLPXFrameRW(buf, secrets{
+		AES:        crypto.Keccak256(),
+		MAC:        crypto.Keccak256(),
+		IngressMAC: hash,
+		EgressMAC:  hash,
+	})
+
+	// A Snappy block starts with the varint encoded decompressed length.
+	bomb := []byte{0x80, 0x80, 0x80, 0x08, 0x00} // 1<<24
+	if err := rw.WriteMsg(Msg{Code: 8, Size: uint32(len(bomb)), Payload: bytes.NewReader(bomb)}); err != nil {
+		t.Fatalf("WriteMsg error: %v", err)
+	}
+	rw.snappy = true
+	if _, err := rw.ReadMsg(); err != errPlainMessageTooLarge {
+		t.Fatalf("wrong error for oversized message: got %v, want %v", err, errPlainMessageTooLarge)
+	}
+
+	// Writing an oversized message is rejected too.
+	if err := rw.WriteMsg(Msg{Code: 8, Size: maxUint24 + 1, Payload: bytes.NewReader(nil)}); err != errPlainMessageTooLarge {
+		t.Fatalf("wrong error for writing oversized message: got %v, want %v", err, errPlainMessageTooLarge)
+	}
+}
+
 func TestRLPXFrameFake(t *testing.T) {
 	buf := new(bytes.Buffer)
 	hash := fakeHash([]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
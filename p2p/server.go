@@ -35,6 +35,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/discover"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/discv5"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/dnsdisc"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/nat"
@@ -87,6 +88,11 @@ type Config struct {
 	// protocol should be started or not.
 	DiscoveryV5 bool `toml:",omitempty"`
 
+	// DiscoveryV51 specifies whether the ENR-based discovery v5.1 protocol
+	// should be started. It uses BootstrapNodes and can run alongside the
+	// v4 protocol on the same UDP port.
+	DiscoveryV51 bool `toml:",omitempty"`
+
 	// Name sets the node name of this server.
 	// Use common.MakeName to create a name that follows existing conventions.
 	Name string `toml:"-"`
@@ -95,6 +101,10 @@ type Config struct {
 	// with the rest of the network.
 	BootstrapNodes []*enode.Node
 
+	// DNSDiscovery is a list of enrtree:// URLs of DNS node lists (EIP-1459).
+	// Nodes found in these lists are used as dial candidates.
+	DNSDiscovery []string `toml:",omitempty"`
+
 	// BootstrapNodesV5 are used to establish connectivity
 	// with the rest of the network using the V5 discovery
 	// protocol.
@@ -113,6 +123,16 @@ type Config struct {
 	// IP networks contained in the list are considered.
 	NetRestrict *netutil.Netlist `toml:",omitempty"`
 
+	// Connectivity can be denied to certain IP networks. Hosts which match one
+	// of the IP networks contained in the blacklist are neither dialed nor accepted.
+	NetBlacklist *netutil.Netlist `toml:",omitempty"`
+
+	// MaxPeersPerSubnet is the maximum number of peers that may be connected from
+	// a single /24 subnet, guarding against eclipse attacks from a small address
+	// range. Trusted and static peers as well as LAN hosts are exempt. Zero means
+	// no limit.
+	MaxPeersPerSubnet int `toml:",omitempty"`
+
 	// NodeDatabase is the path to the database containing the previously seen
 	// live nodes in the network.
 	NodeDatabase string `toml:",omitempty"`
@@ -130,13 +150,21 @@ type Config struct {
 	// the server is started.
 	ListenAddr string
 
+	// If Listener is set to a non-nil value, the server accepts inbound
+	// connections on it instead of opening a TCP listener on ListenAddr. The
+	// network of the listener's address is advertised in the node record.
+	Listener net.Listener `toml:"-"`
+
 	// If set to a non-nil value, the given NAT port mapper
 	// is used to make the listening port available to the
 	// Internet.
 	NAT nat.Interface `toml:",omitempty"`
 
 	// If Dialer is set to a non-nil value, the given Dialer
-	// is used to dial outbound peer connections.
+	// is used to dial outbound peer connections. The default
+	// dialer only connects to nodes advertising the "tcp"
+	// transport. Use a TransportDialer to reach nodes on other
+	// transports.
 	Dialer NodeDialer `toml:"-"`
 
 	// If NoDial is true, the server will not dial any peers.
@@ -164,6 +192,8 @@ type Server struct {
 	running bool
 
 	nodedb       *enode.DB
+	rep          *reputation
+	bans         *banList
 	localnode    *enode.LocalNode
 	ntab         discoverTable
 	listener     net.Listener
@@ -344,6 +374,11 @@ func (srv *Server) RemoveTrustedPeer(node *enode.Node) {
 	}
 }
 
+// LocalNode returns the local node record.
+func (srv *Server) LocalNode() *enode.LocalNode {
+	return srv.localnode
+}
+
 // SubscribePeers subscribes the given channel to peer events
 func (srv *Server) SubscribeEvents(ch chan *PeerEvent) event.Subscription {
 	return srv.peerFeed.Subscribe(ch)
@@ -405,6 +440,59 @@ func (s *sharedUDPConn) Close() error {
 	return nil
 }
 
+// discoverTables combines the node tables of discovery protocols running side
+// by side. The tables are closed in order, which must match the order in which
+// packets are passed along on the shared UDP socket.
+type discoverTables []discoverTable
+
+func (ts discoverTables) Close() {
+	for _, t := range ts {
+		t.Close()
+	}
+}
+
+func (ts discoverTables) Resolve(n *enode.Node) *enode.Node {
+	for _, t := range ts {
+		if rn := t.Resolve(n); rn != nil {
+			return rn
+		}
+	}
+	return nil
+}
+
+func (ts discoverTables) LookupRandom() []*enode.Node {
+	var (
+		result []*enode.Node
+		seen   = make(map[enode.ID]bool)
+	)
+	for _, t := range ts {
+		for _, n := range t.LookupRandom() {
+			if !seen[n.ID()] {
+				seen[n.ID()] = true
+				result = append(result, n)
+			}
+		}
+	}
+	return result
+}
+
+func (ts discoverTables) ReadRandomNodes(buf []*enode.Node) int {
+	n := 0
+	for i, t := range ts {
+		// Give each table an equal share of the remaining space.
+		share := (len(buf) - n) / (len(ts) - i)
+		if share == 0 {
+			continue
+		}
+		got := t.ReadRandomNodes(buf[n : n+share])
+		if got > share {
+			got = share
+		}
+		n += got
+	}
+	return n
+}
+
 // Start starts running the server.
 // Servers can not be re-used after stopping.
 func (srv *Server) Start() (err error) {
@@ -430,7 +518,7 @@ func (srv *Server) Start() (err error) {
 		srv.newTransport = newRLPX
 	}
 	if srv.Dialer == nil {
-		srv.Dialer = TCPDialer{&net.Dialer{Timeout: defaultDialTimeout}}
+		srv.Dialer = TransportDialer{"tcp": TCPDialer{&net.Dialer{Timeout: defaultDialTimeout}}}
 	}
 	srv.quit = make(chan struct{})
 	srv.addpeer = make(chan *conn)
@@ -446,7 +534,7 @@ func (srv *Server) Start() (err error) {
 	if err := srv.setupLocalNode(); err != nil {
 		return err
 	}
-	if srv.ListenAddr != "" {
+	if srv.ListenAddr != "" || srv.Listener != nil {
 		if err := srv.setupListening(); err != nil {
 			return err
 		}
@@ -457,6 +545,10 @@ func (srv *Server) Start() (err error) {
 
 	dynPeers := srv.maxDialedConns()
 	dialer := newDialState(srv.localnode.ID(), srv.StaticNodes, srv.BootstrapNodes, srv.ntab, dynPeers, srv.NetRestrict)
+	dialer.rep = srv.rep
+	dialer.bans = srv.bans
+	dialer.netBlacklist = srv.NetBlacklist
+	dialer.maxPerSubnet = srv.MaxPeersPerSubnet
 	srv.loopWG.Add(1)
 	go srv.run(dialer)
 	return nil
@@ -477,6 +569,8 @@ func (srv *Server) setupLocalNode() error {
 		return err
 	}
 	srv.nodedb = db
+	srv.rep = newReputation(db)
+	srv.bans = newBanList(db)
 	srv.localnode = enode.NewLocalNode(db, srv.PrivateKey)
 	srv.localnode.SetFallbackIP(net.IP{127, 0, 0, 1})
 	srv.localnode.Set(capsByNameAndVersion(srv.ourHandshake.Caps))
@@ -508,7 +602,19 @@ func (srv *Server) setupLocalNode() error {
 }
 
 func (srv *Server) setupDiscovery() error {
-	if srv.NoDiscovery && !srv.DiscoveryV5 {
+	var tables discoverTables
+
+	// DNS discovery works without the UDP listener.
+	if len(srv.DNSDiscovery) > 0 {
+		client := dnsdisc.NewClient(dnsdisc.Config{Logger: srv.log})
+		src, err := client.NewSource(srv.DNSDiscovery...)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, src)
+	}
+	if srv.NoDiscovery && !srv.DiscoveryV5 && !srv.DiscoveryV51 {
+		srv.setDiscoverTables(tables)
 		return nil
 	}
 
@@ -533,7 +639,7 @@ func (srv *Server) setupDiscovery() error {
 	var unhandled chan discover.ReadPacket
 	var sconn *sharedUDPConn
 	if !srv.NoDiscovery {
-		if srv.DiscoveryV5 {
+		if srv.DiscoveryV5 || srv.DiscoveryV51 {
 			unhandled = make(chan discover.ReadPacket, 100)
 			sconn = &sharedUDPConn{conn, unhandled}
 		}
@@ -547,8 +653,37 @@ func (srv *Server) setupDiscovery() error {
 		if err != nil {
 			return err
 		}
-		srv.ntab = ntab
+		tables = append(tables, ntab)
 	}
+	// Discovery V5.1, reading the packets left over by V4.
+	if srv.DiscoveryV51 {
+		var next chan discover.ReadPacket
+		if srv.DiscoveryV5 {
+			next = make(chan discover.ReadPacket, 100)
+		}
+		cfg := discover.Config{
+			PrivateKey:  srv.PrivateKey,
+			NetRestrict: srv.NetRestrict,
+			Bootnodes:   srv.BootstrapNodes,
+			Unhandled:   next,
+		}
+		var ntab *discover.UDPv5
+		var err error
+		if sconn != nil {
+			ntab, err = discover.ListenV5(sconn, srv.localnode, cfg)
+		} else {
+			ntab, err = discover.ListenV5(conn, srv.localnode, cfg)
+		}
+		if err != nil {
+			return err
+		}
+		tables = append(tables, ntab)
+		if next != nil {
+			sconn = &sharedUDPConn{conn, next}
+		}
+	}
+	srv.setDiscoverTables(tables)
+
 	// Discovery V5
 	if srv.DiscoveryV5 {
 		var ntab *discv5.Network
@@ -569,22 +704,39 @@ func (srv *Server) setupDiscovery() error {
 	return nil
 }
 
+// setDiscoverTables makes the given tables the node source of the dialer.
+func (srv *Server) setDiscoverTables(tables discoverTables) {
+	switch len(tables) {
+	case 0:
+	case 1:
+		srv.ntab = tables[0]
+	default:
+		srv.ntab = tables
+	}
+}
+
 func (srv *Server) setupListening() error {
-	// Launch the TCP listener.
-	listener, err := net.Listen("tcp", srv.ListenAddr)
-	if err != nil {
-		return err
+	// Launch the TCP listener unless a custom transport is configured.
+	listener := srv.Listener
+	if listener == nil {
+		var err error
+		if listener, err = net.Listen("tcp", srv.ListenAddr); err != nil {
+			return err
+		}
+	}
+	laddr, isTCP := listener.Addr().(*net.TCPAddr)
+	if isTCP {
+		srv.ListenAddr = laddr.String()
+		srv.localnode.Set(enr.TCP(laddr.Port))
 	}
-	laddr := listener.Addr().(*net.TCPAddr)
-	srv.ListenAddr = laddr.String()
 	srv.listener = listener
-	srv.localnode.Set(enr.TCP(laddr.Port))
+	srv.localnode.Set(Transports{listener.Addr().Network()})
 
 	srv.loopWG.Add(1)
 	go srv.listenLoop()
 
 	// Map the TCP listening port if NAT is configured.
-	if !laddr.IP.IsLoopback() && srv.NAT != nil {
+	if isTCP && !laddr.IP.IsLoopback() && srv.NAT != nil {
 		srv.loopWG.Add(1)
 		go func() {
 			nat.Map(srv.NAT, srv.quit, "tcp", laddr.Port, laddr.Port, "PaloAltoAi p2p")
@@ -609,11 +761,15 @@ func (srv *Server) run(dialstate dialer) {
 	var (
 		peers        = make(map[enode.ID]*Peer)
 		inboundCount = 0
+		evicting     = make(map[enode.ID]bool) // inbound peers disconnecting to make room
 		trusted      = make(map[enode.ID]bool, len(srv.TrustedNodes))
 		taskdone     = make(chan task, maxActiveDialTasks)
 		runningTasks []task
 		queuedTasks  []task // tasks that can't run yet
+		scoreFlush   = time.NewTicker(scoreFlushInterval)
 	)
+	defer scoreFlush.Stop()
+
 	// Put trusted nodes into a map to speed up checks.
 	// Trusted peers are loaded on startup or added via AddTrustedPeer RPC.
 	for _, n := range srv.TrustedNodes {
@@ -658,6 +814,11 @@ running:
 		case <-srv.quit:
 			// The server was stopped. Run the cleanup logic.
 			break running
+		case <-scoreFlush.C:
+			// Write the reputation changes out to the node database.
+			if err := srv.rep.flush(); err != nil {
+				srv.log.Warn("Failed to store peer reputation", "err", err)
+			}
 		case n := <-srv.addstatic:
 			// This channel is used by AddPeer to add to the
 			// ephemeral static peer list. Add it to the dialer,
@@ -712,15 +873,22 @@ running:
 				c.flags |= trustedConn
 			}
 			// TODO: track in-progress inbound node IDs (pre-Peer) to avoid dialing them.
+			err := srv.encHandshakeChecks(peers, evicting, inboundCount, c)
+			if err == DiscTooManyPeers && srv.evictWorstInbound(peers, evicting, c) {
+				err = nil
+			}
 			select {
-			case c.cont <- srv.encHandshakeChecks(peers, inboundCount, c):
+			case c.cont <- err:
 			case <-srv.quit:
 				break running
 			}
 		case c := <-srv.addpeer:
 			// At this point the connection is past the protocol handshake.
 			// Its capabilities are known and the remote identity is verified.
-			err := srv.protoHandshakeChecks(peers, inboundCount, c)
+			err := srv.protoHandshakeChecks(peers, evicting, inboundCount, c)
+			if err == DiscTooManyPeers && srv.evictWorstInbound(peers, evicting, c) {
+				err = nil
+			}
 			if err == nil {
 				// The handshakes are done and it passed all checks.
 				p := newPeer(c, srv.Protocols)
@@ -729,6 +897,7 @@ running:
 				if srv.EnableMsgEvents {
 					p.events = &srv.peerFeed
 				}
+				p.rep = srv.rep
 				name := truncateName(c.name)
 				srv.log.Debug("Adding p2p peer", "name", name, "addr", c.fd.RemoteAddr(), "peers", len(peers)+1)
 				go srv.runPeer(p)
@@ -750,9 +919,14 @@ running:
 			d := common.PrettyDuration(mclock.Now() - pd.created)
 			pd.log.Debug("Removing p2p peer", "duration", d, "peers", len(peers)-1, "req", pd.requested, "err", pd.err)
 			delete(peers, pd.ID())
+			delete(evicting, pd.ID())
 			if pd.Inbound() {
 				inboundCount--
 			}
+			if penalty := disconnectPenalty(pd.err, pd.requested); penalty != 0 {
+				score := srv.rep.adjust(pd.ID(), penalty)
+				pd.log.Debug("Charged peer reputation", "penalty", penalty, "score", score)
+			}
 		}
 	}
 
@@ -777,38 +951,83 @@ running:
 		p.log.Trace("<-delpeer (spindown)", "remainingTasks", len(runningTasks))
 		delete(peers, p.ID())
 	}
+	// Persist the final scores before the node database is closed.
+	if err := srv.rep.flush(); err != nil {
+		srv.log.Warn("Failed to store peer reputation", "err", err)
+	}
 }
 
-func (srv *Server) protoHandshakeChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
+func (srv *Server) protoHandshakeChecks(peers map[enode.ID]*Peer, evicting map[enode.ID]bool, inboundCount int, c *conn) error {
 	// Drop connections with no matching protocols.
 	if len(srv.Protocols) > 0 && countMatchingProtocols(srv.Protocols, c.caps) == 0 {
 		return DiscUselessPeer
 	}
 	// Repeat the encryption handshake checks because the
 	// peer set might have changed between the handshakes.
-	return srv.encHandshakeChecks(peers, inboundCount, c)
+	return srv.encHandshakeChecks(peers, evicting, inboundCount, c)
 }
 
-func (srv *Server) encHandshakeChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
+func (srv *Server) encHandshakeChecks(peers map[enode.ID]*Peer, evicting map[enode.ID]bool, inboundCount int, c *conn) error {
+	// The capacity checks come last because a full server may make room for
+	// the connection by evicting a peer. Peers being evicted are on their way
+	// out, their slots are already free.
+	banned, banErr := srv.bans.banned(c.node.ID(), time.Now())
 	switch {
-	case !c.is(trustedConn|staticDialedConn) && len(peers) >= srv.MaxPeers:
-		return DiscTooManyPeers
-	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns():
-		return DiscTooManyPeers
 	case peers[c.node.ID()] != nil:
 		return DiscAlreadyConnected
 	case c.node.ID() == srv.localnode.ID():
 		return DiscSelf
+	case banErr != nil:
+		return banErr
+	case banned:
+		return errBanned
+	case !c.is(trustedConn|staticDialedConn) && subnetFull(peers, c.node.IP(), srv.MaxPeersPerSubnet):
+		return errSubnetFull
+	case !c.is(trustedConn|staticDialedConn) && len(peers)-len(evicting) >= srv.MaxPeers:
+		return DiscTooManyPeers
+	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount-len(evicting) >= srv.maxInboundConns():
+		return DiscTooManyPeers
 	default:
 		return nil
 	}
 }
 
+// evictWorstInbound disconnects the inbound peer with the lowest reputation to
+// make room for the given connection, provided the connecting node scores
+// sufficiently better. Trusted peers are never evicted. It reports whether a
+// peer was evicted.
+func (srv *Server) evictWorstInbound(peers map[enode.ID]*Peer, evicting map[enode.ID]bool, c *conn) bool {
+	// Never make room for a connection that would be rejected anyway, adding
+	// it would replace the existing entry in the peer set.
+	if peers[c.node.ID()] != nil || c.node.ID() == srv.localnode.ID() {
+		return false
+	}
+	var (
+		worst      *Peer
+		worstScore int64
+	)
+	for id, p := range peers {
+		if !p.Inbound() || p.rw.is(trustedConn) || evicting[id] {
+			continue
+		}
+		if score := srv.rep.score(id); worst == nil || score < worstScore {
+			worst, worstScore = p, score
+		}
+	}
+	if worst == nil || worstScore+scoreEvictMargin > srv.rep.score(c.node.ID()) {
+		return false
+	}
+	worst.log.Debug("Evicting low reputation peer", "score", worstScore, "for", c.node.ID())
+	evicting[worst.ID()] = true
+	worst.Disconnect(DiscTooManyPeers)
+	return true
+}
+
 func (srv *Server) maxInboundConns() int {
 	return srv.MaxPeers - srv.maxDialedConns()
 }
 func (srv *Server) maxDialedConns() int {
-	if srv.NoDiscovery || srv.NoDial {
+	if (srv.NoDiscovery && !srv.DiscoveryV51 && len(srv.DNSDiscovery) == 0) || srv.NoDial {
 		return 0
 	}
 	r := srv.DialRatio
@@ -822,7 +1041,7 @@ func (srv *Server) maxDialedConns() int {
 // inbound connections.
 func (srv *Server) listenLoop() {
 	defer srv.loopWG.Done()
-	srv.log.Debug("TCP listener up", "addr", srv.listener.Addr())
+	srv.log.Debug("Listener up", "network", srv.listener.Addr().Network(), "addr", srv.listener.Addr())
 
 	tokens := defaultMaxPendingPeers
 	if srv.MaxPendingPeers > 0 {
@@ -853,19 +1072,14 @@ func (srv *Server) listenLoop() {
 			break
 		}
 
-		// Reject connections that do not match NetRestrict.
-		if srv.NetRestrict != nil {
-			if tcp, ok := fd.RemoteAddr().(*net.TCPAddr); ok && !srv.NetRestrict.Contains(tcp.IP) {
-				srv.log.Debug("Rejected conn (not whitelisted in NetRestrict)", "addr", fd.RemoteAddr())
-				fd.Close()
-				slots <- struct{}{}
-				continue
-			}
-		}
-
-		var ip net.IP
-		if tcp, ok := fd.RemoteAddr().(*net.TCPAddr); ok {
-			ip = tcp.IP
+		// Reject connections that don't pass the IP filters. Connections without
+		// an IP address can't be checked, so they are rejected if any is set.
+		ip := addrIP(fd.RemoteAddr())
+		if reason := srv.filterInbound(ip); reason != "" {
+			srv.log.Debug("Rejected conn", "reason", reason, "addr", fd.RemoteAddr())
+			fd.Close()
+			slots <- struct{}{}
+			continue
 		}
 		fd = newMeteredConn(fd, true, ip)
 		srv.log.Trace("Accepted connection", "addr", fd.RemoteAddr())
@@ -876,6 +1090,38 @@ func (srv *Server) listenLoop() {
 	}
 }
 
+// filterInbound checks the IP of an inbound connection against NetRestrict and
+// NetBlacklist, returning the reason for rejecting it or "" if it is accepted.
+func (srv *Server) filterInbound(ip net.IP) string {
+	switch {
+	case ip == nil && (srv.NetRestrict != nil || srv.NetBlacklist != nil):
+		return "no IP address to check against net filters"
+	case srv.NetRestrict != nil && !srv.NetRestrict.Contains(ip):
+		return "not whitelisted in NetRestrict"
+	case srv.NetBlacklist != nil && srv.NetBlacklist.Contains(ip):
+		return "blacklisted in NetBlacklist"
+	default:
+		return ""
+	}
+}
+
+// addrIP returns the IP address of a network address, or nil if it has none.
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP
+	case *net.UDPAddr:
+		return a.IP
+	case *net.IPAddr:
+		return a.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	return net.ParseIP(host)
+}
+
 // SetupConn runs the handshakes and attempts to add the connection
 // as a peer. It returns when the connection has been added as a peer
 // or the handshakes have failed.
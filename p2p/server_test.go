@@ -19,6 +19,7 @@ package p2p
 import (
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"math/rand"
 	"net"
 	"reflect"
@@ -27,8 +28,11 @@ import (
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/discover"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/memtransport"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/netutil"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -414,6 +418,345 @@ func TestServerAtCap(t *testing.T) {
 	}
 }
 
+// This test checks that a full server makes room for new connections by evicting
+// the inbound peer with the worst reputation, but only if the difference in score
+// is large enough.
+func TestServerEvictLowReputation(t *testing.T) {
+	remote := newkey()
+	srv := &Server{
+		Config: Config{
+			PrivateKey: newkey(),
+			MaxPeers:   10,
+			NoDial:     true,
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	newconn := func(id enode.ID) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&remote.PublicKey, fd)
+		node := enode.SignNull(new(enr.Record), id)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+
+	// Fill up the peer set, giving one of the peers a bad reputation.
+	var victim enode.ID
+	for i := 0; i < 10; i++ {
+		id := randomID()
+		if i == 3 {
+			victim = id
+			srv.rep.adjust(id, scoreProtocolViolation)
+		}
+		if err := srv.checkpoint(newconn(id), srv.addpeer); err != nil {
+			t.Fatalf("could not add conn %d: %v", i, err)
+		}
+	}
+	// A newcomer with a neutral score should replace the victim.
+	c := newconn(randomID())
+	if err := srv.checkpoint(c, srv.posthandshake); err != nil {
+		t.Fatal("unexpected error for insert @posthandshake:", err)
+	}
+	if err := srv.checkpoint(c, srv.addpeer); err != nil {
+		t.Fatal("unexpected error for insert @addpeer:", err)
+	}
+	// Another newcomer doesn't beat the remaining peers by the margin.
+	if err := srv.checkpoint(newconn(randomID()), srv.posthandshake); err != DiscTooManyPeers {
+		t.Error("wrong error for insert:", err)
+	}
+	// The victim should be gone eventually.
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+		found := false
+		for _, p := range srv.Peers() {
+			if p.ID() == victim {
+				found = true
+			}
+		}
+		if !found {
+			return
+		}
+	}
+	t.Error("low reputation peer was not evicted")
+}
+
+// This test checks that a full server doesn't evict anyone for a connection
+// from a node that is already connected, even if that node scores well.
+func TestServerEvictAlreadyConnected(t *testing.T) {
+	remote := newkey()
+	srv := &Server{
+		Config: Config{
+			PrivateKey: newkey(),
+			MaxPeers:   10,
+			NoDial:     true,
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	newconn := func(id enode.ID) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&remote.PublicKey, fd)
+		node := enode.SignNull(new(enr.Record), id)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+
+	// Fill up the peer set with one bad and one good peer.
+	var victim, dup enode.ID
+	for i := 0; i < 10; i++ {
+		id := randomID()
+		switch i {
+		case 3:
+			victim = id
+			srv.rep.adjust(id, scoreProtocolViolation)
+		case 5:
+			dup = id
+			srv.rep.adjust(id, -scoreProtocolViolation)
+		}
+		if err := srv.checkpoint(newconn(id), srv.addpeer); err != nil {
+			t.Fatalf("could not add conn %d: %v", i, err)
+		}
+	}
+	// A second connection from the good peer must not evict the bad one.
+	if err := srv.checkpoint(newconn(dup), srv.posthandshake); err != DiscAlreadyConnected {
+		t.Error("wrong error for insert @posthandshake:", err)
+	}
+	if err := srv.checkpoint(newconn(dup), srv.addpeer); err != DiscAlreadyConnected {
+		t.Error("wrong error for insert @addpeer:", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := srv.PeerCount(); n != 10 {
+		t.Errorf("wrong peer count: got %d, want 10", n)
+	}
+	for _, p := range srv.Peers() {
+		if p.ID() == victim {
+			return
+		}
+	}
+	t.Error("peer was evicted for an already connected node")
+}
+
+// This test checks that banned nodes are rejected until the ban is lifted.
+func TestServerBanList(t *testing.T) {
+	remote := newkey()
+	srv := &Server{
+		Config: Config{
+			PrivateKey: newkey(),
+			MaxPeers:   10,
+			NoDial:     true,
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	newconn := func(id enode.ID) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&remote.PublicKey, fd)
+		node := enode.SignNull(new(enr.Record), id)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+
+	// A connected peer is dropped when it gets banned.
+	id := randomID()
+	if err := srv.checkpoint(newconn(id), srv.addpeer); err != nil {
+		t.Fatal("could not add conn:", err)
+	}
+	expiry, err := srv.BanPeer(id, time.Hour)
+	if err != nil {
+		t.Fatal("could not ban:", err)
+	}
+	if bans := srv.Bans(); len(bans) != 1 || bans[0].ID != id || !bans[0].Expires.Equal(expiry) {
+		t.Errorf("wrong ban list: %v", bans)
+	}
+	for start := time.Now(); srv.PeerCount() > 0; time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > time.Second {
+			t.Fatal("banned peer was not disconnected")
+		}
+	}
+	// Reconnecting is refused, even for trusted nodes.
+	if err := srv.checkpoint(newconn(id), srv.posthandshake); err != errBanned {
+		t.Error("wrong error for banned conn:", err)
+	}
+	srv.AddTrustedPeer(newNode(id, nil))
+	if err := srv.checkpoint(newconn(id), srv.posthandshake); err != errBanned {
+		t.Error("wrong error for banned trusted conn:", err)
+	}
+	// Lifting the ban allows the node to connect again.
+	if unbanned, err := srv.UnbanPeer(id); !unbanned || err != nil {
+		t.Fatalf("could not unban: %v %v", unbanned, err)
+	}
+	if err := srv.checkpoint(newconn(id), srv.posthandshake); err != nil {
+		t.Error("unexpected error for unbanned conn:", err)
+	}
+	if bans := srv.Bans(); len(bans) != 0 {
+		t.Errorf("ban list not empty: %v", bans)
+	}
+}
+
+// This test checks that the number of non-trusted peers from a single subnet is
+// limited by MaxPeersPerSubnet.
+func TestServerSubnetLimit(t *testing.T) {
+	remote := newkey()
+	trustedID := randomID()
+	srv := &Server{
+		Config: Config{
+			PrivateKey:        newkey(),
+			MaxPeers:          10,
+			MaxPeersPerSubnet: 2,
+			NoDial:            true,
+			TrustedNodes:      []*enode.Node{newNode(trustedID, nil)},
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	newconn := func(id enode.ID, ip net.IP) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&remote.PublicKey, fd)
+		var r enr.Record
+		r.Set(enr.IP(ip))
+		node := enode.SignNull(&r, id)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+
+	for i := 0; i < 2; i++ {
+		c := newconn(randomID(), net.IP{88, 99, 1, byte(i + 1)})
+		if err := srv.checkpoint(c, srv.addpeer); err != nil {
+			t.Fatalf("could not add conn %d: %v", i, err)
+		}
+	}
+	if err := srv.checkpoint(newconn(randomID(), net.IP{88, 99, 1, 10}), srv.posthandshake); err != errSubnetFull {
+		t.Error("wrong error for conn from full subnet:", err)
+	}
+	if err := srv.checkpoint(newconn(randomID(), net.IP{88, 99, 2, 10}), srv.posthandshake); err != nil {
+		t.Error("unexpected error for conn from other subnet:", err)
+	}
+	if err := srv.checkpoint(newconn(trustedID, net.IP{88, 99, 1, 11}), srv.posthandshake); err != nil {
+		t.Error("unexpected error for trusted conn from full subnet:", err)
+	}
+	if err := srv.checkpoint(newconn(randomID(), net.IP{192, 168, 1, 10}), srv.posthandshake); err != nil {
+		t.Error("unexpected error for LAN conn:", err)
+	}
+	// IPv6 addresses are grouped by their /48 prefix.
+	for i := 0; i < 2; i++ {
+		c := newconn(randomID(), net.ParseIP(fmt.Sprintf("2001:db8:1:%d::1", i+1)))
+		if err := srv.checkpoint(c, srv.addpeer); err != nil {
+			t.Fatalf("could not add IPv6 conn %d: %v", i, err)
+		}
+	}
+	if err := srv.checkpoint(newconn(randomID(), net.ParseIP("2001:db8:1:10::1")), srv.posthandshake); err != errSubnetFull {
+		t.Error("wrong error for IPv6 conn from full subnet:", err)
+	}
+	if err := srv.checkpoint(newconn(randomID(), net.ParseIP("2001:db8:2::1")), srv.posthandshake); err != nil {
+		t.Error("unexpected error for IPv6 conn from other subnet:", err)
+	}
+}
+
+// This test checks that servers can connect through a custom transport, and that
+// the transport is advertised in the node record.
+func TestServerCustomTransport(t *testing.T) {
+	var (
+		tr          = memtransport.New()
+		key0, key1  = newkey(), newkey()
+		listener, _ = tr.Listen(enode.PubkeyToIDV4(&key0.PublicKey))
+		connected   = make(chan *Peer, 2)
+		newPeerHook = func(p *Peer) { connected <- p }
+	)
+	srv0 := &Server{
+		Config:      Config{PrivateKey: key0, MaxPeers: 10, NoDiscovery: true, Listener: listener},
+		newPeerHook: newPeerHook,
+	}
+	srv1 := &Server{
+		Config: Config{
+			PrivateKey:  key1,
+			MaxPeers:    10,
+			NoDiscovery: true,
+			Dialer:      TransportDialer{"tcp": TCPDialer{new(net.Dialer)}, memtransport.Network: tr},
+		},
+		newPeerHook: newPeerHook,
+	}
+	if err := srv0.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv0.Stop()
+	if err := srv1.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv1.Stop()
+
+	self := srv0.Self()
+	if ts := NodeTransports(self); !reflect.DeepEqual(ts, Transports{memtransport.Network}) {
+		t.Errorf("wrong transports in record: %v", ts)
+	}
+	if self.TCP() != 0 {
+		t.Errorf("TCP port advertised without TCP listener: %d", self.TCP())
+	}
+	srv1.AddPeer(self)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-connected:
+		case <-time.After(time.Second):
+			t.Fatal("servers did not connect within one second")
+		}
+	}
+}
+
+// This test checks that the default dialer doesn't dial nodes over TCP unless
+// they advertise the TCP transport.
+func TestServerDefaultDialerTransports(t *testing.T) {
+	srv := &Server{Config: Config{PrivateKey: newkey(), MaxPeers: 10, NoDiscovery: true, NoDial: true}}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	var r enr.Record
+	r.Set(enr.IP{127, 0, 0, 1})
+	r.Set(enr.TCP(30303))
+	r.Set(Transports{memtransport.Network})
+	dest := enode.SignNull(&r, randomID())
+	if fd, err := srv.Dialer.Dial(dest); err == nil {
+		fd.Close()
+		t.Fatal("dialed node without TCP transport")
+	}
+}
+
+// This test checks that the net filters apply to inbound connections of any
+// transport, and that connections without an IP are rejected if they are set.
+func TestServerInboundNetFilters(t *testing.T) {
+	restrict, _ := netutil.ParseNetlist("10.0.0.0/8")
+	blacklist, _ := netutil.ParseNetlist("10.1.0.0/16")
+	tests := []struct {
+		addr     net.Addr
+		restrict bool
+		accept   bool
+	}{
+		{addr: &net.TCPAddr{IP: net.IP{10, 0, 0, 1}}, restrict: true, accept: true},
+		{addr: &net.TCPAddr{IP: net.IP{10, 1, 0, 1}}, restrict: true, accept: false},
+		{addr: &net.TCPAddr{IP: net.IP{192, 168, 0, 1}}, restrict: true, accept: false},
+		{addr: &net.UDPAddr{IP: net.IP{192, 168, 0, 1}}, restrict: true, accept: false},
+		{addr: &net.IPAddr{IP: net.IP{10, 0, 0, 1}}, restrict: true, accept: true},
+		{addr: memtransport.Addr{}, restrict: true, accept: false},
+		{addr: memtransport.Addr{}, restrict: false, accept: true},
+	}
+	for i, test := range tests {
+		srv := &Server{}
+		if test.restrict {
+			srv.NetRestrict, srv.NetBlacklist = restrict, blacklist
+		}
+		if reason := srv.filterInbound(addrIP(test.addr)); (reason == "") != test.accept {
+			t.Errorf("test %d (%v): wrong result %q, want accept=%v", i, test.addr, reason, test.accept)
+		}
+	}
+}
+
 func TestServerPeerLimits(t *testing.T) {
 	srvkey := newkey()
 	clientkey := newkey()
@@ -602,6 +945,77 @@ func (c *setupTransport) ReadMsg() (Msg, error) {
 	panic("ReadMsg called on setupTransport")
 }
 
+// This test checks that discovery v5.1 is reachable when it shares the UDP
+// socket with discovery v4.
+func TestServerDiscoveryV51(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			PrivateKey:   newkey(),
+			MaxPeers:     10,
+			NoDial:       true,
+			ListenAddr:   "127.0.0.1:0",
+			DiscoveryV51: true,
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+	if tables, ok := srv.ntab.(discoverTables); !ok || len(tables) != 2 {
+		t.Fatalf("wrong discovery tables: %T", srv.ntab)
+	}
+
+	// Ping the server's v5.1 endpoint from a standalone node.
+	key := newkey()
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+	socket, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln := enode.NewLocalNode(db, key)
+	ln.SetStaticIP(net.IP{127, 0, 0, 1})
+	ln.SetFallbackUDP(socket.LocalAddr().(*net.UDPAddr).Port)
+	client, err := discover.ListenV5(socket, ln, discover.Config{PrivateKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	n, err := client.RequestENR(srv.Self())
+	if err != nil {
+		t.Fatalf("v5.1 request failed: %v", err)
+	}
+	if n.ID() != srv.Self().ID() {
+		t.Fatalf("wrong node in response: %v", n.ID())
+	}
+}
+
+type randomNodesMock []*enode.Node
+
+func (t randomNodesMock) Close()                                {}
+func (t randomNodesMock) Resolve(*enode.Node) *enode.Node       { return nil }
+func (t randomNodesMock) LookupRandom() []*enode.Node           { return t }
+func (t randomNodesMock) ReadRandomNodes(buf []*enode.Node) int { return copy(buf, t) }
+
+func TestDiscoverTablesReadRandomNodes(t *testing.T) {
+	a := randomNodesMock{newNode(randomID(), nil), newNode(randomID(), nil), newNode(randomID(), nil)}
+	b := randomNodesMock{newNode(randomID(), nil), newNode(randomID(), nil)}
+	tables := discoverTables{a, b}
+
+	buf := make([]*enode.Node, 4)
+	if n := tables.ReadRandomNodes(buf); n != 4 {
+		t.Fatalf("wrong count %d", n)
+	}
+	want := []*enode.Node{a[0], a[1], b[0], b[1]}
+	if !reflect.DeepEqual(buf, want) {
+		t.Errorf("wrong nodes: got %v, want %v", buf, want)
+	}
+	if nodes := tables.LookupRandom(); len(nodes) != 5 {
+		t.Errorf("wrong lookup result count %d", len(nodes))
+	}
+}
+
 func newkey() *ecdsa.PrivateKey {
 	key, err := crypto.GenerateKey()
 	if err != nil {
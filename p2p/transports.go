@@ -0,0 +1,57 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+// Transports is the "transports" node record entry. It lists the networks of the
+// stream transports a node accepts connections on, e.g. "tcp".
+type Transports []string
+
+// ENRKey implements enr.Entry.
+func (Transports) ENRKey() string { return "transports" }
+
+// NodeTransports returns the transports advertised by the given node. Nodes
+// without a "transports" entry are assumed to accept TCP connections.
+func NodeTransports(n *enode.Node) Transports {
+	var ts Transports
+	if err := n.Load(&ts); err != nil {
+		return Transports{"tcp"}
+	}
+	return ts
+}
+
+// TransportDialer implements NodeDialer by dispatching to the dialer of the first
+// transport that the destination node advertises. Dialers are keyed by the
+// network name of their transport.
+type TransportDialer map[string]NodeDialer
+
+// Dial connects to the node using one of its transports.
+func (td TransportDialer) Dial(dest *enode.Node) (net.Conn, error) {
+	ts := NodeTransports(dest)
+	for _, network := range ts {
+		if d := td[network]; d != nil {
+			return d.Dial(dest)
+		}
+	}
+	return nil, fmt.Errorf("no dialer for transports %v", ts)
+}
@@ -19,6 +19,7 @@ package paa
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/accounts"
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
@@ -34,6 +35,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/event"
 	"github.com/PaloAltoAi/go-PaloAltoAi/params"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
 )
 
 // PaaAPIBackend implements paaapi.Backend for full nodes
@@ -57,6 +59,9 @@ func (b *PaaAPIBackend) SetHead(number uint64) {
 }
 
 func (b *PaaAPIBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	ctx, span := tracing.StartSpan(ctx, "paaapi/headerByNumber")
+	defer span.Finish()
+
 	// Pending block is only known by the miner
 	if blockNr == rpc.PendingBlockNumber {
 		block := b.paa.miner.PendingBlock()
@@ -66,14 +71,17 @@ func (b *PaaAPIBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNum
 	if blockNr == rpc.LatestBlockNumber {
 		return b.paa.blockchain.CurrentBlock().Header(), nil
 	}
-	return b.paa.blockchain.GetHeaderByNumber(uint64(blockNr)), nil
+	return b.paa.blockchain.GetHeaderByNumberContext(ctx, uint64(blockNr)), nil
 }
 
 func (b *PaaAPIBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
-	return b.paa.blockchain.GetHeaderByHash(hash), nil
+	return b.paa.blockchain.GetHeaderByHashContext(ctx, hash), nil
 }
 
 func (b *PaaAPIBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	ctx, span := tracing.StartSpan(ctx, "paaapi/blockByNumber")
+	defer span.Finish()
+
 	// Pending block is only known by the miner
 	if blockNr == rpc.PendingBlockNumber {
 		block := b.paa.miner.PendingBlock()
@@ -83,34 +91,52 @@ func (b *PaaAPIBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumb
 	if blockNr == rpc.LatestBlockNumber {
 		return b.paa.blockchain.CurrentBlock(), nil
 	}
-	return b.paa.blockchain.GetBlockByNumber(uint64(blockNr)), nil
+	return b.paa.blockchain.GetBlockByNumberContext(ctx, uint64(blockNr)), nil
 }
 
 func (b *PaaAPIBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	lookupCtx, span := tracing.StartSpan(ctx, "paaapi/stateAndHeaderByNumber")
+	defer span.Finish()
+
 	// Pending state is only known by the miner
 	if blockNr == rpc.PendingBlockNumber {
 		block, state := b.paa.miner.Pending()
+		state.SetContext(ctx)
 		return state, block.Header(), nil
 	}
 	// Otherwise resolve the block number and return its state
-	header, err := b.HeaderByNumber(ctx, blockNr)
+	header, err := b.HeaderByNumber(lookupCtx, blockNr)
 	if header == nil || err != nil {
 		return nil, nil, err
 	}
 	stateDb, err := b.paa.BlockChain().StateAt(header.Root)
-	return stateDb, header, err
+	if err != nil {
+		return nil, nil, err
+	}
+	// Trace the state reads as part of the request, not of this lookup
+	stateDb.SetContext(ctx)
+	return stateDb, header, nil
 }
 
 func (b *PaaAPIBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
-	return b.paa.blockchain.GetBlockByHash(hash), nil
+	ctx, span := tracing.StartSpan(ctx, "paaapi/getBlock")
+	defer span.Finish()
+
+	return b.paa.blockchain.GetBlockByHashContext(ctx, hash), nil
 }
 
 func (b *PaaAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return b.paa.blockchain.GetReceiptsByHash(hash), nil
+	ctx, span := tracing.StartSpan(ctx, "paaapi/getReceipts")
+	defer span.Finish()
+
+	return b.paa.blockchain.GetReceiptsByHashContext(ctx, hash), nil
 }
 
 func (b *PaaAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
-	receipts := b.paa.blockchain.GetReceiptsByHash(hash)
+	ctx, span := tracing.StartSpan(ctx, "paaapi/getLogs")
+	defer span.Finish()
+
+	receipts := b.paa.blockchain.GetReceiptsByHashContext(ctx, hash)
 	if receipts == nil {
 		return nil, nil
 	}
@@ -213,6 +239,22 @@ func (b *PaaAPIBackend) AccountManager() *accounts.Manager {
 	return b.paa.AccountManager()
 }
 
+func (b *PaaAPIBackend) RPCGasCap() uint64 {
+	return b.paa.config.RPCGasCap
+}
+
+func (b *PaaAPIBackend) RPCEVMTimeout() time.Duration {
+	return b.paa.config.RPCEVMTimeout
+}
+
+func (b *PaaAPIBackend) RPCLogsLimit() int {
+	return b.paa.config.RPCLogsLimit
+}
+
+func (b *PaaAPIBackend) RPCLogsBlockRange() uint64 {
+	return b.paa.config.RPCLogsBlockRange
+}
+
 func (b *PaaAPIBackend) BloomStatus() (uint64, uint64) {
 	sections, _, _ := b.paa.bloomIndexer.Sections()
 	return params.BloomBitsBlocks, sections
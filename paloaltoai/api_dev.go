@@ -0,0 +1,193 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package paa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/state"
+)
+
+// maxDevMineBlocks is the maximum number of blocks dev_mine produces in a call.
+const maxDevMineBlocks = 1024
+
+// errTooManyDevBlocks is returned if dev_mine is asked for more blocks than it
+// produces in a single call.
+var errTooManyDevBlocks = fmt.Errorf("too many blocks requested, at most %d per call", maxDevMineBlocks)
+
+// errTimeOffsetOverflow is returned if dev_increaseTime would shift the block
+// timestamps beyond the range of a signed 64 bit integer.
+var errTimeOffsetOverflow = errors.New("time offset overflows block timestamps")
+
+// devSnapshot is a chain position saved by dev_snapshot that can be returned to.
+type devSnapshot struct {
+	id     uint64
+	number uint64 // Head block number at the time of the snapshot
+	offset int64  // Miner time offset at the time of the snapshot
+}
+
+// PrivateDevAPI provides control over block production, time and state of a
+// developer chain, allowing contract test suites to run against a real node.
+type PrivateDevAPI struct {
+	paa *PaloAltoAi
+
+	snapshots []devSnapshot // Taken snapshots, ordered by id
+	nextID    uint64        // Identifier of the next snapshot
+	lock      sync.Mutex    // Serializes chain manipulations
+}
+
+// NewPrivateDevAPI creates a new API definition for the developer mode RPCs.
+func NewPrivateDevAPI(paa *PaloAltoAi) *PrivateDevAPI {
+	return &PrivateDevAPI{paa: paa, nextID: 1}
+}
+
+// Mine mines the given number of blocks (one if omitted) on demand, even if no
+// transactions are pending, and returns their hashes.
+func (api *PrivateDevAPI) Mine(blocks *uint64) ([]common.Hash, error) {
+	n := uint64(1)
+	if blocks != nil {
+		n = *blocks
+	}
+	if n > maxDevMineBlocks {
+		return nil, errTooManyDevBlocks
+	}
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	hashes := []common.Hash{}
+	for i := uint64(0); i < n; i++ {
+		block, err := api.paa.miner.MineBlock(nil)
+		if err != nil {
+			return hashes, err
+		}
+		hashes = append(hashes, block.Hash())
+	}
+	return hashes, nil
+}
+
+// SetNextBlockTimestamp forces the timestamp of the next mined block. Blocks
+// afterwards continue counting from there.
+func (api *PrivateDevAPI) SetNextBlockTimestamp(timestamp uint64) error {
+	return api.paa.miner.SetNextBlockTimestamp(timestamp)
+}
+
+// IncreaseTime shifts the timestamp of all future blocks by the given number of
+// seconds, returning the total time offset from the wall clock.
+func (api *PrivateDevAPI) IncreaseTime(seconds uint64) (int64, error) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	offset, err := increaseTimeOffset(api.paa.miner.TimeOffset(), seconds, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+	api.paa.miner.SetTimeOffset(offset)
+	return offset, nil
+}
+
+// increaseTimeOffset adds seconds to a time offset, failing if the timestamps of
+// blocks mined at the given wall clock time would overflow.
+func increaseTimeOffset(offset int64, seconds uint64, now int64) (int64, error) {
+	timestamp := new(big.Int).SetUint64(seconds)
+	timestamp.Add(timestamp, big.NewInt(offset))
+	timestamp.Add(timestamp, big.NewInt(now))
+	if !timestamp.IsInt64() {
+		return 0, errTimeOffsetOverflow
+	}
+	return offset + int64(seconds), nil
+}
+
+// Snapshot saves the current head and time offset, returning an identifier that
+// can be passed to Revert to return to this point.
+func (api *PrivateDevAPI) Snapshot() hexutil.Uint64 {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	snap := devSnapshot{
+		id:     api.nextID,
+		number: api.paa.blockchain.CurrentBlock().NumberU64(),
+		offset: api.paa.miner.TimeOffset(),
+	}
+	api.snapshots = append(api.snapshots, snap)
+	api.nextID++
+
+	return hexutil.Uint64(snap.id)
+}
+
+// Revert rewinds the chain to the given snapshot, dropping all blocks mined
+// since. The snapshot and all later ones are invalidated. It returns false if
+// the snapshot is unknown.
+func (api *PrivateDevAPI) Revert(id hexutil.Uint64) (bool, error) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	for i, snap := range api.snapshots {
+		if snap.id != uint64(id) {
+			continue
+		}
+		api.snapshots = api.snapshots[:i]
+
+		chain := api.paa.blockchain
+		if err := chain.SetHead(snap.number); err != nil {
+			return false, err
+		}
+		api.paa.miner.SetTimeOffset(snap.offset)
+
+		// Let the transaction pool and the miner catch up with the rewound head
+		chain.PostChainEvents([]interface{}{core.ChainHeadEvent{Block: chain.CurrentBlock()}}, nil)
+		return true, nil
+	}
+	return false, nil
+}
+
+// SetBalance mines a block setting the balance of the given account.
+func (api *PrivateDevAPI) SetBalance(address common.Address, balance hexutil.Big) error {
+	return api.modify(func(statedb *state.StateDB) {
+		statedb.SetBalance(address, (*big.Int)(&balance))
+	})
+}
+
+// SetCode mines a block replacing the code of the given account.
+func (api *PrivateDevAPI) SetCode(address common.Address, code hexutil.Bytes) error {
+	return api.modify(func(statedb *state.StateDB) {
+		statedb.SetCode(address, code)
+	})
+}
+
+// SetStorageAt mines a block setting a single storage slot of the given account.
+func (api *PrivateDevAPI) SetStorageAt(address common.Address, slot common.Hash, value common.Hash) error {
+	return api.modify(func(statedb *state.StateDB) {
+		statedb.SetState(address, slot, value)
+	})
+}
+
+// modify mines a single block applying the given state modification before any
+// pending transactions, as state can only change through new blocks.
+func (api *PrivateDevAPI) modify(apply func(*state.StateDB)) error {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	_, err := api.paa.miner.MineBlock(apply)
+	return err
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package paa
+
+import (
+	"math"
+	"testing"
+)
+
+// Tests that time offsets can be increased as long as the resulting block
+// timestamps fit into a signed 64 bit integer.
+func TestIncreaseTimeOffset(t *testing.T) {
+	now := int64(1500000000)
+
+	tests := []struct {
+		offset  int64
+		seconds uint64
+		want    int64
+		err     error
+	}{
+		{0, 3600, 3600, nil},
+		{3600, 60, 3660, nil},
+		{-60, 60, 0, nil},
+		{0, math.MaxInt64 - uint64(now), math.MaxInt64 - now, nil},
+		{0, math.MaxInt64 - uint64(now) + 1, 0, errTimeOffsetOverflow},
+		{0, math.MaxUint64, 0, errTimeOffsetOverflow},
+		{math.MaxInt64 - now, 1, 0, errTimeOffsetOverflow},
+		{3600, math.MaxInt64, 0, errTimeOffsetOverflow},
+	}
+	for i, tt := range tests {
+		offset, err := increaseTimeOffset(tt.offset, tt.seconds, now)
+		if offset != tt.want || err != tt.err {
+			t.Errorf("test %d: have %d/%v, want %d/%v", i, offset, err, tt.want, tt.err)
+		}
+	}
+}
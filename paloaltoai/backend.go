@@ -30,6 +30,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/clique"
+	istanbulBackend "github.com/PaloAltoAi/go-PaloAltoAi/consensus/istanbul/backend"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/paaash"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/bloombits"
@@ -46,6 +47,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/miner"
 	"github.com/PaloAltoAi/go-PaloAltoAi/node"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enr"
 	"github.com/PaloAltoAi/go-PaloAltoAi/params"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
@@ -91,6 +93,10 @@ type PaloAltoAi struct {
 	networkID     uint64
 	netRPCService *paaapi.PublicNetAPI
 
+	bftStarted   bool       // Whether the consensus protocol of a BFT engine is running
+	bftValidator bool       // Whether the BFT protocol runs as the node is a validator, not just mining
+	bftLock      sync.Mutex // Protects the BFT protocol state
+
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and paaerbase)
 }
 
@@ -124,13 +130,17 @@ func New(ctx *node.ServiceContext, config *Config) (*PaloAltoAi, error) {
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	engine, err := CreateConsensusEngine(ctx, chainConfig, &config.Paaash, config.MinerNotify, config.MinerNoverify, chainDb)
+	if err != nil {
+		return nil, err
+	}
 	paa := &PaloAltoAi{
 		config:         config,
 		chainDb:        chainDb,
 		chainConfig:    chainConfig,
 		eventMux:       ctx.EventMux,
 		accountManager: ctx.AccountManager,
-		engine:         CreateConsensusEngine(ctx, chainConfig, &config.Paaash, config.MinerNotify, config.MinerNoverify, chainDb),
+		engine:         engine,
 		shutdownChan:   make(chan bool),
 		networkID:      config.NetworkId,
 		gasPrice:       config.MinerGasPrice,
@@ -181,6 +191,11 @@ func New(ctx *node.ServiceContext, config *Config) (*PaloAltoAi, error) {
 
 	paa.miner = miner.New(paa, paa.chainConfig, paa.EventMux(), paa.engine, config.MinerRecommit, config.MinerGasFloor, config.MinerGasCeil, paa.isLocalBlock)
 	paa.miner.SetExtra(makeExtraData(config.MinerExtraData))
+	if config.DevMode {
+		if err := paa.miner.EnableDevMode(); err != nil {
+			return nil, err
+		}
+	}
 
 	paa.APIBackend = &PaaAPIBackend{paa, nil}
 	gpoParams := config.GPO
@@ -222,33 +237,41 @@ func CreateDB(ctx *node.ServiceContext, config *Config, name string) (paadb.Data
 }
 
 // CreateConsensusEngine creates the required type of consensus engine instance for an PaloAltoAi service
-func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainConfig, config *paaash.Config, notify []string, noverify bool, db paadb.Database) consensus.Engine {
+func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainConfig, config *paaash.Config, notify []string, noverify bool, db paadb.Database) (consensus.Engine, error) {
 	// If proof-of-authority is requested, set it up
 	if chainConfig.Clique != nil {
-		return clique.New(chainConfig.Clique, db)
+		return clique.New(chainConfig.Clique, db), nil
+	}
+	// If byzantine fault tolerance is requested, validate with the node key
+	if chainConfig.Istanbul != nil {
+		return istanbulBackend.New(chainConfig.Istanbul, ctx.NodeKey(), db), nil
 	}
 	// Otherwise assume proof-of-work
 	switch config.PowMode {
 	case paaash.ModeFake:
 		log.Warn("Paaash used in fake mode")
-		return paaash.NewFaker()
+		return paaash.NewFaker(), nil
 	case paaash.ModeTest:
 		log.Warn("Paaash used in test mode")
-		return paaash.NewTester(nil, noverify)
+		return paaash.NewTester(nil, noverify), nil
 	case paaash.ModeShared:
 		log.Warn("Paaash used in shared mode")
-		return paaash.NewShared()
+		return paaash.NewShared(), nil
 	default:
-		engine := paaash.New(paaash.Config{
+		engine, err := paaash.New(paaash.Config{
 			CacheDir:       ctx.ResolvePath(config.CacheDir),
 			CachesInMem:    config.CachesInMem,
 			CachesOnDisk:   config.CachesOnDisk,
 			DatasetDir:     config.DatasetDir,
 			DatasetsInMem:  config.DatasetsInMem,
 			DatasetsOnDisk: config.DatasetsOnDisk,
+			StratumAddr:    config.StratumAddr,
 		}, notify, noverify)
+		if err != nil {
+			return nil, err
+		}
 		engine.SetThreads(-1) // Disable CPU mining
-		return engine
+		return engine, nil
 	}
 }
 
@@ -260,6 +283,15 @@ func (s *PaloAltoAi) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append the chain manipulation APIs if running a developer chain
+	if s.config.DevMode {
+		apis = append(apis, rpc.API{
+			Namespace: "dev",
+			Version:   "1.0",
+			Service:   NewPrivateDevAPI(s),
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -436,6 +468,11 @@ func (s *PaloAltoAi) StartMining(threads int) error {
 			}
 			clique.Authorize(eb, wallet.SignHash)
 		}
+		// Blocks are sealed through the agreement of BFT engines, make sure it runs
+		if err := s.startBFT(); err != nil {
+			log.Error("Cannot start consensus protocol", "err", err)
+			return fmt.Errorf("consensus protocol failed: %v", err)
+		}
 		// If mining is started, we can disable the transaction rejection mechanism
 		// introduced to speed sync times.
 		atomic.StoreUint32(&s.protocolManager.acceptTxs, 1)
@@ -457,6 +494,51 @@ func (s *PaloAltoAi) StopMining() {
 	}
 	// Stop the block creating itself
 	s.miner.Stop()
+
+	// Stop agreeing on blocks unless the node takes part as a validator
+	s.bftLock.Lock()
+	validator := s.bftValidator
+	s.bftLock.Unlock()
+	if !validator {
+		s.stopBFT()
+	}
+}
+
+// startBFT launches the consensus protocol of byzantine fault tolerant engines,
+// unless already running.
+func (s *PaloAltoAi) startBFT() error {
+	bft, ok := s.engine.(consensus.BFT)
+	if !ok {
+		return nil
+	}
+	s.bftLock.Lock()
+	defer s.bftLock.Unlock()
+
+	if s.bftStarted {
+		return nil
+	}
+	if err := bft.Start(s.blockchain, s.blockchain.InsertChain); err != nil {
+		return err
+	}
+	s.bftStarted = true
+	return nil
+}
+
+// stopBFT terminates the consensus protocol of byzantine fault tolerant engines,
+// if running.
+func (s *PaloAltoAi) stopBFT() {
+	bft, ok := s.engine.(consensus.BFT)
+	if !ok {
+		return
+	}
+	s.bftLock.Lock()
+	defer s.bftLock.Unlock()
+
+	if !s.bftStarted {
+		return
+	}
+	bft.Stop()
+	s.bftStarted, s.bftValidator = false, false
 }
 
 func (s *PaloAltoAi) IsMining() bool      { return s.miner.Mining() }
@@ -476,15 +558,25 @@ func (s *PaloAltoAi) Downloader() *downloader.Downloader { return s.protocolMana
 // Protocols implements node.Service, returning all the currently configured
 // network protocols to start.
 func (s *PaloAltoAi) Protocols() []p2p.Protocol {
+	protos := make([]p2p.Protocol, len(s.protocolManager.SubProtocols))
+	for i, proto := range s.protocolManager.SubProtocols {
+		proto.Attributes = []enr.Entry{s.currentPaaEntry()}
+		protos[i] = proto
+	}
+	if bft, ok := s.engine.(consensus.BFT); ok {
+		protos = append(protos, bft.Protocols()...)
+	}
 	if s.lesServer == nil {
-		return s.protocolManager.SubProtocols
+		return protos
 	}
-	return append(s.protocolManager.SubProtocols, s.lesServer.Protocols()...)
+	return append(protos, s.lesServer.Protocols()...)
 }
 
 // Start implements node.Service, starting all internal goroutines needed by the
 // PaloAltoAi protocol implementation.
 func (s *PaloAltoAi) Start(srvr *p2p.Server) error {
+	s.startPaaEntryUpdate(srvr.LocalNode())
+
 	// Start the bloom bits servicing goroutines
 	s.startBloomHandlers(params.BloomBitsBlocks)
 
@@ -504,6 +596,16 @@ func (s *PaloAltoAi) Start(srvr *p2p.Server) error {
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
+	// Start agreeing on blocks if the engine is byzantine fault tolerant and the
+	// node is a validator. Other nodes only do so while mining.
+	if bft, ok := s.engine.(consensus.BFT); ok && bft.IsValidator(s.blockchain) {
+		if err := s.startBFT(); err != nil {
+			return err
+		}
+		s.bftLock.Lock()
+		s.bftValidator = true
+		s.bftLock.Unlock()
+	}
 	return nil
 }
 
@@ -511,6 +613,7 @@ func (s *PaloAltoAi) Start(srvr *p2p.Server) error {
 // PaloAltoAi protocol.
 func (s *PaloAltoAi) Stop() error {
 	s.bloomIndexer.Close()
+	s.stopBFT()
 	s.blockchain.Stop()
 	s.engine.Close()
 	s.protocolManager.Stop()
@@ -59,6 +59,8 @@ var DefaultConfig = Config{
 		Blocks:     20,
 		Percentile: 60,
 	},
+	RPCEVMTimeout: 5 * time.Second,
+	RPCLogsLimit:  10000,
 }
 
 func init() {
@@ -121,12 +123,21 @@ type Config struct {
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
+	// RPC options, limiting the resources a single API request may consume
+	RPCGasCap         uint64        `toml:",omitempty"` // Maximum gas of paa_call and paa_estimateGas executions (0 = no cap)
+	RPCEVMTimeout     time.Duration // Maximum execution time of paa_call and paa_estimateGas (0 = no timeout)
+	RPCLogsLimit      int           `toml:",omitempty"` // Maximum number of logs returned by a filter query (0 = no limit)
+	RPCLogsBlockRange uint64        `toml:",omitempty"` // Maximum number of blocks a filter query may span (0 = no limit)
+
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 
+	// Enables on-demand mining and the dev RPC namespace for developer chains
+	DevMode bool `toml:"-"`
+
 	// Type of the EWASM interpreter ("" for default)
 	EWASMInterpreter string
 
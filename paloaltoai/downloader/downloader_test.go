@@ -31,6 +31,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
 	"github.com/PaloAltoAi/go-PaloAltoAi/paadb"
 	"github.com/PaloAltoAi/go-PaloAltoAi/event"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 	"github.com/PaloAltoAi/go-PaloAltoAi/trie"
 )
 
@@ -310,8 +311,17 @@ type downloadTesterPeer struct {
 	lock          sync.RWMutex
 	chain         *testChain
 	missingStates map[common.Hash]bool // State entries that fast sync should not return
+
+	timeouts   int32 // Number of times the peer's reputation was charged for a timeout
+	violations int32 // Number of times the peer's reputation was charged for a violation
 }
 
+// RecordTimeout counts the reputation charges for timeouts.
+func (dlp *downloadTesterPeer) RecordTimeout() { atomic.AddInt32(&dlp.timeouts, 1) }
+
+// RecordViolation counts the reputation charges for protocol violations.
+func (dlp *downloadTesterPeer) RecordViolation() { atomic.AddInt32(&dlp.violations, 1) }
+
 // Head constructs a function to retrieve a peer's current head hash
 // and total difficulty.
 func (dlp *downloadTesterPeer) Head() (common.Hash, *big.Int) {
@@ -322,51 +332,51 @@ func (dlp *downloadTesterPeer) Head() (common.Hash, *big.Int) {
 // RequestHeadersByHash constructs a GetBlockHeaders function based on a hashed
 // origin; associated with a particular peer in the download tester. The returned
 // function can be used to retrieve batches of headers from the particular peer.
-func (dlp *downloadTesterPeer) RequestHeadersByHash(origin common.Hash, amount int, skip int, reverse bool) error {
+func (dlp *downloadTesterPeer) RequestHeadersByHash(id uint64, origin common.Hash, amount int, skip int, reverse bool) error {
 	if reverse {
 		panic("reverse header requests not supported")
 	}
 
 	result := dlp.chain.headersByHash(origin, amount, skip)
-	go dlp.dl.downloader.DeliverHeaders(dlp.id, result)
+	go dlp.dl.downloader.DeliverHeaders(dlp.id, id, result)
 	return nil
 }
 
 // RequestHeadersByNumber constructs a GetBlockHeaders function based on a numbered
 // origin; associated with a particular peer in the download tester. The returned
 // function can be used to retrieve batches of headers from the particular peer.
-func (dlp *downloadTesterPeer) RequestHeadersByNumber(origin uint64, amount int, skip int, reverse bool) error {
+func (dlp *downloadTesterPeer) RequestHeadersByNumber(id uint64, origin uint64, amount int, skip int, reverse bool) error {
 	if reverse {
 		panic("reverse header requests not supported")
 	}
 
 	result := dlp.chain.headersByNumber(origin, amount, skip)
-	go dlp.dl.downloader.DeliverHeaders(dlp.id, result)
+	go dlp.dl.downloader.DeliverHeaders(dlp.id, id, result)
 	return nil
 }
 
 // RequestBodies constructs a getBlockBodies method associated with a particular
 // peer in the download tester. The returned function can be used to retrieve
 // batches of block bodies from the particularly requested peer.
-func (dlp *downloadTesterPeer) RequestBodies(hashes []common.Hash) error {
+func (dlp *downloadTesterPeer) RequestBodies(id uint64, hashes []common.Hash) error {
 	txs, uncles := dlp.chain.bodies(hashes)
-	go dlp.dl.downloader.DeliverBodies(dlp.id, txs, uncles)
+	go dlp.dl.downloader.DeliverBodies(dlp.id, id, txs, uncles)
 	return nil
 }
 
 // RequestReceipts constructs a getReceipts method associated with a particular
 // peer in the download tester. The returned function can be used to retrieve
 // batches of block receipts from the particularly requested peer.
-func (dlp *downloadTesterPeer) RequestReceipts(hashes []common.Hash) error {
+func (dlp *downloadTesterPeer) RequestReceipts(id uint64, hashes []common.Hash) error {
 	receipts := dlp.chain.receipts(hashes)
-	go dlp.dl.downloader.DeliverReceipts(dlp.id, receipts)
+	go dlp.dl.downloader.DeliverReceipts(dlp.id, id, receipts)
 	return nil
 }
 
 // RequestNodeData constructs a getNodeData method associated with a particular
 // peer in the download tester. The returned function can be used to retrieve
 // batches of node state data from the particularly requested peer.
-func (dlp *downloadTesterPeer) RequestNodeData(hashes []common.Hash) error {
+func (dlp *downloadTesterPeer) RequestNodeData(id uint64, hashes []common.Hash) error {
 	dlp.dl.lock.RLock()
 	defer dlp.dl.lock.RUnlock()
 
@@ -378,7 +388,7 @@ func (dlp *downloadTesterPeer) RequestNodeData(hashes []common.Hash) error {
 			}
 		}
 	}
-	go dlp.dl.downloader.DeliverNodeData(dlp.id, results)
+	go dlp.dl.downloader.DeliverNodeData(dlp.id, id, results)
 	return nil
 }
 
@@ -429,6 +439,12 @@ func TestCanonicalSynchronisation63Fast(t *testing.T)  { testCanonicalSynchronis
 func TestCanonicalSynchronisation64Full(t *testing.T)  { testCanonicalSynchronisation(t, 64, FullSync) }
 func TestCanonicalSynchronisation64Fast(t *testing.T)  { testCanonicalSynchronisation(t, 64, FastSync) }
 func TestCanonicalSynchronisation64Light(t *testing.T) { testCanonicalSynchronisation(t, 64, LightSync) }
+func TestCanonicalSynchronisation65Full(t *testing.T)  { testCanonicalSynchronisation(t, 65, FullSync) }
+func TestCanonicalSynchronisation65Fast(t *testing.T)  { testCanonicalSynchronisation(t, 65, FastSync) }
+func TestCanonicalSynchronisation65Light(t *testing.T) { testCanonicalSynchronisation(t, 65, LightSync) }
+func TestCanonicalSynchronisation66Full(t *testing.T)  { testCanonicalSynchronisation(t, 66, FullSync) }
+func TestCanonicalSynchronisation66Fast(t *testing.T)  { testCanonicalSynchronisation(t, 66, FastSync) }
+func TestCanonicalSynchronisation66Light(t *testing.T) { testCanonicalSynchronisation(t, 66, LightSync) }
 
 func testCanonicalSynchronisation(t *testing.T, protocol int, mode SyncMode) {
 	t.Parallel()
@@ -671,10 +687,10 @@ func TestInactiveDownloader62(t *testing.T) {
 	defer tester.terminate()
 
 	// Check that neither block headers nor bodies are accepted
-	if err := tester.downloader.DeliverHeaders("bad peer", []*types.Header{}); err != errNoSyncActive {
+	if err := tester.downloader.DeliverHeaders("bad peer", 0, []*types.Header{}); err != errNoSyncActive {
 		t.Errorf("error mismatch: have %v, want %v", err, errNoSyncActive)
 	}
-	if err := tester.downloader.DeliverBodies("bad peer", [][]*types.Transaction{}, [][]*types.Header{}); err != errNoSyncActive {
+	if err := tester.downloader.DeliverBodies("bad peer", 0, [][]*types.Transaction{}, [][]*types.Header{}); err != errNoSyncActive {
 		t.Errorf("error mismatch: have %v, want  %v", err, errNoSyncActive)
 	}
 }
@@ -688,13 +704,13 @@ func TestInactiveDownloader63(t *testing.T) {
 	defer tester.terminate()
 
 	// Check that neither block headers nor bodies are accepted
-	if err := tester.downloader.DeliverHeaders("bad peer", []*types.Header{}); err != errNoSyncActive {
+	if err := tester.downloader.DeliverHeaders("bad peer", 0, []*types.Header{}); err != errNoSyncActive {
 		t.Errorf("error mismatch: have %v, want %v", err, errNoSyncActive)
 	}
-	if err := tester.downloader.DeliverBodies("bad peer", [][]*types.Transaction{}, [][]*types.Header{}); err != errNoSyncActive {
+	if err := tester.downloader.DeliverBodies("bad peer", 0, [][]*types.Transaction{}, [][]*types.Header{}); err != errNoSyncActive {
 		t.Errorf("error mismatch: have %v, want %v", err, errNoSyncActive)
 	}
-	if err := tester.downloader.DeliverReceipts("bad peer", [][]*types.Receipt{}); err != errNoSyncActive {
+	if err := tester.downloader.DeliverReceipts("bad peer", 0, [][]*types.Receipt{}); err != errNoSyncActive {
 		t.Errorf("error mismatch: have %v, want %v", err, errNoSyncActive)
 	}
 }
@@ -767,6 +783,12 @@ func TestMultiProtoSynchronisation63Fast(t *testing.T)  { testMultiProtoSync(t,
 func TestMultiProtoSynchronisation64Full(t *testing.T)  { testMultiProtoSync(t, 64, FullSync) }
 func TestMultiProtoSynchronisation64Fast(t *testing.T)  { testMultiProtoSync(t, 64, FastSync) }
 func TestMultiProtoSynchronisation64Light(t *testing.T) { testMultiProtoSync(t, 64, LightSync) }
+func TestMultiProtoSynchronisation65Full(t *testing.T)  { testMultiProtoSync(t, 65, FullSync) }
+func TestMultiProtoSynchronisation65Fast(t *testing.T)  { testMultiProtoSync(t, 65, FastSync) }
+func TestMultiProtoSynchronisation65Light(t *testing.T) { testMultiProtoSync(t, 65, LightSync) }
+func TestMultiProtoSynchronisation66Full(t *testing.T)  { testMultiProtoSync(t, 66, FullSync) }
+func TestMultiProtoSynchronisation66Fast(t *testing.T)  { testMultiProtoSync(t, 66, FastSync) }
+func TestMultiProtoSynchronisation66Light(t *testing.T) { testMultiProtoSync(t, 66, LightSync) }
 
 func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	t.Parallel()
@@ -781,6 +803,8 @@ func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	tester.newPeer("peer 62", 62, chain)
 	tester.newPeer("peer 63", 63, chain)
 	tester.newPeer("peer 64", 64, chain)
+	tester.newPeer("peer 65", 65, chain)
+	tester.newPeer("peer 66", 66, chain)
 
 	// Synchronise with the requested peer and make sure all blocks were retrieved
 	if err := tester.sync(fmt.Sprintf("peer %d", protocol), nil, mode); err != nil {
@@ -789,7 +813,7 @@ func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	assertOwnChain(t, tester, chain.len())
 
 	// Check that no peers have been dropped off
-	for _, version := range []int{62, 63, 64} {
+	for _, version := range []int{62, 63, 64, 65, 66} {
 		peer := fmt.Sprintf("peer %d", version)
 		if _, ok := tester.peers[peer]; !ok {
 			t.Errorf("%s dropped", peer)
@@ -797,6 +821,100 @@ func testMultiProtoSync(t *testing.T, protocol int, mode SyncMode) {
 	}
 }
 
+// Tests that peers tagging their requests (paa/66+) are assigned several requests
+// at once, and that deliveries are only accepted for the request they answer.
+func TestConcurrentRequests65(t *testing.T) { testConcurrentRequests(t, 65, 1) }
+func TestConcurrentRequests66(t *testing.T) { testConcurrentRequests(t, 66, maxTaggedRequests) }
+
+func testConcurrentRequests(t *testing.T, protocol int, limit int) {
+	t.Parallel()
+
+	chain := testChainBase.shorten(blockCacheItems - 15)
+
+	q := newQueue()
+	q.Prepare(1, FullSync)
+	q.Schedule(chain.headersByNumber(1, chain.len()-1, 0), 1)
+
+	peers := newPeerSet()
+	p := newPeerConnection("peer", protocol, silentTestPeer{}, log.New("peer", "peer"))
+	peers.Register(p)
+
+	// Reserve and send body requests until the peer is busy
+	var requests []*fetchRequest
+	for {
+		request, _, err := q.ReserveBodies(p, 2)
+		if err != nil {
+			t.Fatalf("failed to reserve bodies: %v", err)
+		}
+		if request == nil {
+			break
+		}
+		if err := p.FetchBodies(request); err != nil {
+			t.Fatalf("failed to fetch bodies: %v", err)
+		}
+		requests = append(requests, request)
+	}
+	if len(requests) != limit {
+		t.Fatalf("in-flight request count mismatch: have %d, want %d", len(requests), limit)
+	}
+	if idles, _ := peers.BodyIdlePeers(); len(idles) != 0 {
+		t.Fatalf("busy peer reported idle")
+	}
+	// Deliveries tagged with unknown request IDs must be rejected
+	last := requests[len(requests)-1]
+	txs, uncles := chain.bodies([]common.Hash{last.Headers[0].Hash(), last.Headers[1].Hash()})
+	if _, err := q.DeliverBodies(p.id, last.ID+1, txs, uncles); err != errNoFetchesPending {
+		t.Fatalf("unknown request delivery error mismatch: have %v, want %v", err, errNoFetchesPending)
+	}
+	// Deliver all requests out of order, each with its own ID
+	for i := len(requests) - 1; i >= 0; i-- {
+		request := requests[i]
+		txs, uncles := chain.bodies([]common.Hash{request.Headers[0].Hash(), request.Headers[1].Hash()})
+
+		accepted, err := q.DeliverBodies(p.id, request.ID, txs, uncles)
+		if err != nil || accepted != len(request.Headers) {
+			t.Fatalf("request %d: delivery mismatch: have %d/%v, want %d/nil", i, accepted, err, len(request.Headers))
+		}
+		p.SetBodiesIdle(request.ID, accepted)
+	}
+	if idles, _ := peers.BodyIdlePeers(); len(idles) != 1 {
+		t.Fatalf("idle peer reported busy")
+	}
+}
+
+// silentTestPeer is a downloader peer ignoring all body requests.
+type silentTestPeer struct {
+	Peer
+}
+
+func (silentTestPeer) RequestBodies(uint64, []common.Hash) error { return nil }
+
+// Tests that peers allocating request IDs hand out the IDs of the downloader's
+// requests too, so they can't collide with the peer's own requests.
+func TestPeerRequestIDs(t *testing.T) {
+	alloc := &allocatingTestPeer{next: 100}
+	p := newPeerConnection("peer", 66, alloc, log.New("peer", "peer"))
+	for want := uint64(100); want < 103; want++ {
+		if id := p.newRequestID(); id != want {
+			t.Fatalf("request ID mismatch: have %d, want %d", id, want)
+		}
+	}
+	if alloc.next != 103 {
+		t.Fatalf("peer allocator not used: next ID %d, want 103", alloc.next)
+	}
+}
+
+// allocatingTestPeer is a downloader peer allocating its own request IDs.
+type allocatingTestPeer struct {
+	silentTestPeer
+	next uint64
+}
+
+func (p *allocatingTestPeer) NewRequestID() uint64 {
+	p.next++
+	return p.next - 1
+}
+
 // Tests that if a block is empty (e.g. header only), no body request should be
 // made, and instead the header should be assembled into a whole block in itself.
 func TestEmptyShortCircuit62(t *testing.T)      { testEmptyShortCircuit(t, 62, FullSync) }
@@ -1089,6 +1207,48 @@ func testBlockHeaderAttackerDropping(t *testing.T, protocol int) {
 	}
 }
 
+// Tests that peers dropped for failing a synchronisation have their reputation
+// charged for the failure.
+func TestDroppedPeerPenalties(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		result     error
+		timeouts   int32
+		violations int32
+	}{
+		{errBadPeer, 0, 1},
+		{errStallingPeer, 1, 0},
+		{errTimeout, 1, 0},
+		{errEmptyHeaderSet, 0, 1},
+		{errPeersUnavailable, 0, 0},
+		{errInvalidAncestor, 0, 1},
+		{errInvalidChain, 0, 1},
+		{errCancelHeaderFetch, 0, 0},
+	}
+	tester := newTester()
+	defer tester.terminate()
+	chain := testChainBase.shorten(1)
+
+	for i, tt := range tests {
+		id := fmt.Sprintf("test %d", i)
+		if err := tester.newPeer(id, 63, chain); err != nil {
+			t.Fatalf("test %d: failed to register new peer: %v", i, err)
+		}
+		peer := tester.peers[id]
+
+		tester.downloader.synchroniseMock = func(string, common.Hash) error { return tt.result }
+		tester.downloader.Synchronise(id, tester.genesis.Hash(), big.NewInt(1000), FullSync)
+
+		if timeouts := atomic.LoadInt32(&peer.timeouts); timeouts != tt.timeouts {
+			t.Errorf("test %d: timeout charges mismatch for %v: have %d, want %d", i, tt.result, timeouts, tt.timeouts)
+		}
+		if violations := atomic.LoadInt32(&peer.violations); violations != tt.violations {
+			t.Errorf("test %d: violation charges mismatch for %v: have %d, want %d", i, tt.result, violations, tt.violations)
+		}
+	}
+}
+
 // Tests that synchronisation progress (origin block number, current block number
 // and highest block number) is tracked and updated correctly.
 func TestSyncProgress62(t *testing.T)      { testSyncProgress(t, 62, FullSync) }
@@ -1448,25 +1608,25 @@ type floodingTestPeer struct {
 }
 
 func (ftp *floodingTestPeer) Head() (common.Hash, *big.Int) { return ftp.peer.Head() }
-func (ftp *floodingTestPeer) RequestHeadersByHash(hash common.Hash, count int, skip int, reverse bool) error {
-	return ftp.peer.RequestHeadersByHash(hash, count, skip, reverse)
+func (ftp *floodingTestPeer) RequestHeadersByHash(id uint64, hash common.Hash, count int, skip int, reverse bool) error {
+	return ftp.peer.RequestHeadersByHash(id, hash, count, skip, reverse)
 }
-func (ftp *floodingTestPeer) RequestBodies(hashes []common.Hash) error {
-	return ftp.peer.RequestBodies(hashes)
+func (ftp *floodingTestPeer) RequestBodies(id uint64, hashes []common.Hash) error {
+	return ftp.peer.RequestBodies(id, hashes)
 }
-func (ftp *floodingTestPeer) RequestReceipts(hashes []common.Hash) error {
-	return ftp.peer.RequestReceipts(hashes)
+func (ftp *floodingTestPeer) RequestReceipts(id uint64, hashes []common.Hash) error {
+	return ftp.peer.RequestReceipts(id, hashes)
 }
-func (ftp *floodingTestPeer) RequestNodeData(hashes []common.Hash) error {
-	return ftp.peer.RequestNodeData(hashes)
+func (ftp *floodingTestPeer) RequestNodeData(id uint64, hashes []common.Hash) error {
+	return ftp.peer.RequestNodeData(id, hashes)
 }
 
-func (ftp *floodingTestPeer) RequestHeadersByNumber(from uint64, count, skip int, reverse bool) error {
+func (ftp *floodingTestPeer) RequestHeadersByNumber(id uint64, from uint64, count, skip int, reverse bool) error {
 	deliveriesDone := make(chan struct{}, 500)
 	for i := 0; i < cap(deliveriesDone)-1; i++ {
 		peer := fmt.Sprintf("fake-peer%d", i)
 		go func() {
-			ftp.tester.downloader.DeliverHeaders(peer, []*types.Header{{}, {}, {}, {}})
+			ftp.tester.downloader.DeliverHeaders(peer, 0, []*types.Header{{}, {}, {}, {}})
 			deliveriesDone <- struct{}{}
 		}()
 	}
@@ -1481,7 +1641,7 @@ func (ftp *floodingTestPeer) RequestHeadersByNumber(from uint64, count, skip int
 				// Start delivering the requested headers
 				// after one of the flooding responses has arrived.
 				go func() {
-					ftp.peer.RequestHeadersByNumber(from, count, skip, reverse)
+					ftp.peer.RequestHeadersByNumber(id, from, count, skip, reverse)
 					deliveriesDone <- struct{}{}
 				}()
 				launched = true
@@ -24,9 +24,9 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"math/rand"
 	"sort"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
@@ -37,6 +37,7 @@ import (
 const (
 	maxLackingHashes  = 4096 // Maximum number of entries allowed on the list or lacking items
 	measurementImpact = 0.1  // The impact a single measurement has on a peer's final throughput value.
+	maxTaggedRequests = 4    // Maximum number of requests of a kind in flight to a peer tagging them (paa/66+)
 )
 
 var (
@@ -49,10 +50,11 @@ var (
 type peerConnection struct {
 	id string // Unique identifier of the peer
 
-	headerIdle  int32 // Current header activity state of the peer (idle = 0, active = 1)
-	blockIdle   int32 // Current block activity state of the peer (idle = 0, active = 1)
-	receiptIdle int32 // Current receipt activity state of the peer (idle = 0, active = 1)
-	stateIdle   int32 // Current node data activity state of the peer (idle = 0, active = 1)
+	headerReqs  map[uint64]time.Time // Header requests in flight, mapped to the time they were started
+	blockReqs   map[uint64]time.Time // Block (body) requests in flight, mapped to the time they were started
+	receiptReqs map[uint64]time.Time // Receipt requests in flight, mapped to the time they were started
+	stateReqs   map[uint64]time.Time // Node data requests in flight, mapped to the time they were started
+	nextReqID   uint64               // Request ID to assign to the next tagged request, unless the peer allocates them
 
 	headerThroughput  float64 // Number of headers measured to be retrievable per second
 	blockThroughput   float64 // Number of blocks (bodies) measured to be retrievable per second
@@ -61,11 +63,6 @@ type peerConnection struct {
 
 	rtt time.Duration // Request round trip time to track responsiveness (QoS)
 
-	headerStarted  time.Time // Time instance when the last header fetch was started
-	blockStarted   time.Time // Time instance when the last block (body) fetch was started
-	receiptStarted time.Time // Time instance when the last receipt fetch was started
-	stateStarted   time.Time // Time instance when the last node data fetch was started
-
 	lacking map[common.Hash]struct{} // Set of hashes not to request (didn't have previously)
 
 	peer Peer
@@ -76,18 +73,36 @@ type peerConnection struct {
 }
 
 // LightPeer encapsulates the methods required to synchronise with a remote light peer.
+//
+// Every request carries an ID allocated by the downloader, which peers tagging
+// their requests (paa/66+) must send along and return with the delivery. Other
+// peers ignore it and deliver with ID zero.
 type LightPeer interface {
 	Head() (common.Hash, *big.Int)
-	RequestHeadersByHash(common.Hash, int, int, bool) error
-	RequestHeadersByNumber(uint64, int, int, bool) error
+	RequestHeadersByHash(uint64, common.Hash, int, int, bool) error
+	RequestHeadersByNumber(uint64, uint64, int, int, bool) error
 }
 
 // Peer encapsulates the methods required to synchronise with a remote full peer.
 type Peer interface {
 	LightPeer
-	RequestBodies([]common.Hash) error
-	RequestReceipts([]common.Hash) error
-	RequestNodeData([]common.Hash) error
+	RequestBodies(uint64, []common.Hash) error
+	RequestReceipts(uint64, []common.Hash) error
+	RequestNodeData(uint64, []common.Hash) error
+}
+
+// reputedPeer is implemented by peers tracking a reputation, which is charged
+// for the misbehaviour they are dropped for.
+type reputedPeer interface {
+	RecordTimeout()
+	RecordViolation()
+}
+
+// requestIDAllocator is implemented by peers issuing requests of their own next
+// to the downloader's, handing out the IDs of all requests to the peer so that
+// they don't collide.
+type requestIDAllocator interface {
+	NewRequestID() uint64
 }
 
 // lightPeerWrapper wraps a LightPeer struct, stubbing out the Peer-only methods.
@@ -96,27 +111,32 @@ type lightPeerWrapper struct {
 }
 
 func (w *lightPeerWrapper) Head() (common.Hash, *big.Int) { return w.peer.Head() }
-func (w *lightPeerWrapper) RequestHeadersByHash(h common.Hash, amount int, skip int, reverse bool) error {
-	return w.peer.RequestHeadersByHash(h, amount, skip, reverse)
+func (w *lightPeerWrapper) RequestHeadersByHash(id uint64, h common.Hash, amount int, skip int, reverse bool) error {
+	return w.peer.RequestHeadersByHash(id, h, amount, skip, reverse)
 }
-func (w *lightPeerWrapper) RequestHeadersByNumber(i uint64, amount int, skip int, reverse bool) error {
-	return w.peer.RequestHeadersByNumber(i, amount, skip, reverse)
+func (w *lightPeerWrapper) RequestHeadersByNumber(id uint64, i uint64, amount int, skip int, reverse bool) error {
+	return w.peer.RequestHeadersByNumber(id, i, amount, skip, reverse)
 }
-func (w *lightPeerWrapper) RequestBodies([]common.Hash) error {
+func (w *lightPeerWrapper) RequestBodies(uint64, []common.Hash) error {
 	panic("RequestBodies not supported in light client mode sync")
 }
-func (w *lightPeerWrapper) RequestReceipts([]common.Hash) error {
+func (w *lightPeerWrapper) RequestReceipts(uint64, []common.Hash) error {
 	panic("RequestReceipts not supported in light client mode sync")
 }
-func (w *lightPeerWrapper) RequestNodeData([]common.Hash) error {
+func (w *lightPeerWrapper) RequestNodeData(uint64, []common.Hash) error {
 	panic("RequestNodeData not supported in light client mode sync")
 }
 
 // newPeerConnection creates a new downloader peer.
 func newPeerConnection(id string, version int, peer Peer, logger log.Logger) *peerConnection {
 	return &peerConnection{
-		id:      id,
-		lacking: make(map[common.Hash]struct{}),
+		id:          id,
+		headerReqs:  make(map[uint64]time.Time),
+		blockReqs:   make(map[uint64]time.Time),
+		receiptReqs: make(map[uint64]time.Time),
+		stateReqs:   make(map[uint64]time.Time),
+		nextReqID:   rand.Uint64(),
+		lacking:     make(map[common.Hash]struct{}),
 
 		peer: peer,
 
@@ -130,10 +150,11 @@ func (p *peerConnection) Reset() {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	atomic.StoreInt32(&p.headerIdle, 0)
-	atomic.StoreInt32(&p.blockIdle, 0)
-	atomic.StoreInt32(&p.receiptIdle, 0)
-	atomic.StoreInt32(&p.stateIdle, 0)
+	for _, reqs := range []map[uint64]time.Time{p.headerReqs, p.blockReqs, p.receiptReqs, p.stateReqs} {
+		for id := range reqs {
+			delete(reqs, id)
+		}
+	}
 
 	p.headerThroughput = 0
 	p.blockThroughput = 0
@@ -143,20 +164,73 @@ func (p *peerConnection) Reset() {
 	p.lacking = make(map[common.Hash]struct{})
 }
 
+// tagged reports whether the peer tags its requests with IDs (paa/66+), which
+// allows several requests of each kind to be in flight at once.
+func (p *peerConnection) tagged() bool {
+	return p.version >= 66
+}
+
+// maxRequests returns the number of requests of each kind that may be in flight
+// to the peer at once.
+func (p *peerConnection) maxRequests() int {
+	if p.tagged() {
+		return maxTaggedRequests
+	}
+	return 1
+}
+
+// newRequestID allocates the ID of a new request to the peer, by the peer itself
+// if it allocates IDs. Peers not tagging their requests always deliver with ID
+// zero.
+func (p *peerConnection) newRequestID() uint64 {
+	if !p.tagged() {
+		return 0
+	}
+	if alloc, ok := p.peer.(requestIDAllocator); ok {
+		return alloc.NewRequestID()
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	id := p.nextReqID
+	p.nextReqID++
+	return id
+}
+
+// idle reports whether the peer may be assigned another request of the kind
+// tracked by reqs.
+func (p *peerConnection) idle(reqs map[uint64]time.Time) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return len(reqs) < p.maxRequests()
+}
+
+// startRequest marks a request of the kind tracked by reqs as in flight, unless
+// the peer is already fetching as much as it may.
+func (p *peerConnection) startRequest(reqs map[uint64]time.Time, id uint64) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := reqs[id]; ok || len(reqs) >= p.maxRequests() {
+		return errAlreadyFetching
+	}
+	reqs[id] = time.Now()
+	return nil
+}
+
 // FetchHeaders sends a header retrieval request to the remote peer.
-func (p *peerConnection) FetchHeaders(from uint64, count int) error {
+func (p *peerConnection) FetchHeaders(id uint64, from uint64, count int) error {
 	// Sanity check the protocol version
 	if p.version < 62 {
 		panic(fmt.Sprintf("header fetch [paa/62+] requested on paa/%d", p.version))
 	}
 	// Short circuit if the peer is already fetching
-	if !atomic.CompareAndSwapInt32(&p.headerIdle, 0, 1) {
-		return errAlreadyFetching
+	if err := p.startRequest(p.headerReqs, id); err != nil {
+		return err
 	}
-	p.headerStarted = time.Now()
-
 	// Issue the header retrieval request (absolut upwards without gaps)
-	go p.peer.RequestHeadersByNumber(from, count, 0, false)
+	go p.peer.RequestHeadersByNumber(id, from, count, 0, false)
 
 	return nil
 }
@@ -168,17 +242,15 @@ func (p *peerConnection) FetchBodies(request *fetchRequest) error {
 		panic(fmt.Sprintf("body fetch [paa/62+] requested on paa/%d", p.version))
 	}
 	// Short circuit if the peer is already fetching
-	if !atomic.CompareAndSwapInt32(&p.blockIdle, 0, 1) {
-		return errAlreadyFetching
+	if err := p.startRequest(p.blockReqs, request.ID); err != nil {
+		return err
 	}
-	p.blockStarted = time.Now()
-
 	// Convert the header set to a retrievable slice
 	hashes := make([]common.Hash, 0, len(request.Headers))
 	for _, header := range request.Headers {
 		hashes = append(hashes, header.Hash())
 	}
-	go p.peer.RequestBodies(hashes)
+	go p.peer.RequestBodies(request.ID, hashes)
 
 	return nil
 }
@@ -190,75 +262,75 @@ func (p *peerConnection) FetchReceipts(request *fetchRequest) error {
 		panic(fmt.Sprintf("body fetch [paa/63+] requested on paa/%d", p.version))
 	}
 	// Short circuit if the peer is already fetching
-	if !atomic.CompareAndSwapInt32(&p.receiptIdle, 0, 1) {
-		return errAlreadyFetching
+	if err := p.startRequest(p.receiptReqs, request.ID); err != nil {
+		return err
 	}
-	p.receiptStarted = time.Now()
-
 	// Convert the header set to a retrievable slice
 	hashes := make([]common.Hash, 0, len(request.Headers))
 	for _, header := range request.Headers {
 		hashes = append(hashes, header.Hash())
 	}
-	go p.peer.RequestReceipts(hashes)
+	go p.peer.RequestReceipts(request.ID, hashes)
 
 	return nil
 }
 
 // FetchNodeData sends a node state data retrieval request to the remote peer.
-func (p *peerConnection) FetchNodeData(hashes []common.Hash) error {
+func (p *peerConnection) FetchNodeData(id uint64, hashes []common.Hash) error {
 	// Sanity check the protocol version
 	if p.version < 63 {
 		panic(fmt.Sprintf("node data fetch [paa/63+] requested on paa/%d", p.version))
 	}
 	// Short circuit if the peer is already fetching
-	if !atomic.CompareAndSwapInt32(&p.stateIdle, 0, 1) {
-		return errAlreadyFetching
+	if err := p.startRequest(p.stateReqs, id); err != nil {
+		return err
 	}
-	p.stateStarted = time.Now()
-
-	go p.peer.RequestNodeData(hashes)
+	go p.peer.RequestNodeData(id, hashes)
 
 	return nil
 }
 
-// SetHeadersIdle sets the peer to idle, allowing it to execute new header retrieval
-// requests. Its estimated header retrieval throughput is updated with that measured
-// just now.
-func (p *peerConnection) SetHeadersIdle(delivered int) {
-	p.setIdle(p.headerStarted, delivered, &p.headerThroughput, &p.headerIdle)
+// SetHeadersIdle marks a header retrieval request as finished, allowing the peer
+// to execute new header retrieval requests. Its estimated header retrieval
+// throughput is updated with that measured just now.
+func (p *peerConnection) SetHeadersIdle(id uint64, delivered int) {
+	p.setIdle(p.headerReqs, id, delivered, &p.headerThroughput)
 }
 
-// SetBodiesIdle sets the peer to idle, allowing it to execute block body retrieval
-// requests. Its estimated body retrieval throughput is updated with that measured
-// just now.
-func (p *peerConnection) SetBodiesIdle(delivered int) {
-	p.setIdle(p.blockStarted, delivered, &p.blockThroughput, &p.blockIdle)
+// SetBodiesIdle marks a block body retrieval request as finished, allowing the
+// peer to execute new block body retrieval requests. Its estimated body retrieval
+// throughput is updated with that measured just now.
+func (p *peerConnection) SetBodiesIdle(id uint64, delivered int) {
+	p.setIdle(p.blockReqs, id, delivered, &p.blockThroughput)
 }
 
-// SetReceiptsIdle sets the peer to idle, allowing it to execute new receipt
-// retrieval requests. Its estimated receipt retrieval throughput is updated
-// with that measured just now.
-func (p *peerConnection) SetReceiptsIdle(delivered int) {
-	p.setIdle(p.receiptStarted, delivered, &p.receiptThroughput, &p.receiptIdle)
+// SetReceiptsIdle marks a receipt retrieval request as finished, allowing the
+// peer to execute new receipt retrieval requests. Its estimated receipt retrieval
+// throughput is updated with that measured just now.
+func (p *peerConnection) SetReceiptsIdle(id uint64, delivered int) {
+	p.setIdle(p.receiptReqs, id, delivered, &p.receiptThroughput)
 }
 
-// SetNodeDataIdle sets the peer to idle, allowing it to execute new state trie
-// data retrieval requests. Its estimated state retrieval throughput is updated
-// with that measured just now.
-func (p *peerConnection) SetNodeDataIdle(delivered int) {
-	p.setIdle(p.stateStarted, delivered, &p.stateThroughput, &p.stateIdle)
+// SetNodeDataIdle marks a state trie data retrieval request as finished, allowing
+// the peer to execute new state trie data retrieval requests. Its estimated state
+// retrieval throughput is updated with that measured just now.
+func (p *peerConnection) SetNodeDataIdle(id uint64, delivered int) {
+	p.setIdle(p.stateReqs, id, delivered, &p.stateThroughput)
 }
 
-// setIdle sets the peer to idle, allowing it to execute new retrieval requests.
-// Its estimated retrieval throughput is updated with that measured just now.
-func (p *peerConnection) setIdle(started time.Time, delivered int, throughput *float64, idle *int32) {
-	// Irrelevant of the scaling, make sure the peer ends up idle
-	defer atomic.StoreInt32(idle, 0)
-
+// setIdle marks a request as finished, allowing the peer to execute new retrieval
+// requests. Its estimated retrieval throughput is updated with that measured just
+// now. Requests no longer in flight (e.g. already reset) are ignored.
+func (p *peerConnection) setIdle(reqs map[uint64]time.Time, id uint64, delivered int, throughput *float64) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
+	started, ok := reqs[id]
+	if !ok {
+		return
+	}
+	delete(reqs, id)
+
 	// If nothing was delivered (hard timeout / unavailable data), reduce throughput to minimum
 	if delivered == 0 {
 		*throughput = 0
@@ -463,56 +535,56 @@ func (ps *peerSet) AllPeers() []*peerConnection {
 // within the active peer set, ordered by their reputation.
 func (ps *peerSet) HeaderIdlePeers() ([]*peerConnection, int) {
 	idle := func(p *peerConnection) bool {
-		return atomic.LoadInt32(&p.headerIdle) == 0
+		return p.idle(p.headerReqs)
 	}
 	throughput := func(p *peerConnection) float64 {
 		p.lock.RLock()
 		defer p.lock.RUnlock()
 		return p.headerThroughput
 	}
-	return ps.idlePeers(62, 64, idle, throughput)
+	return ps.idlePeers(62, 66, idle, throughput)
 }
 
 // BodyIdlePeers retrieves a flat list of all the currently body-idle peers within
 // the active peer set, ordered by their reputation.
 func (ps *peerSet) BodyIdlePeers() ([]*peerConnection, int) {
 	idle := func(p *peerConnection) bool {
-		return atomic.LoadInt32(&p.blockIdle) == 0
+		return p.idle(p.blockReqs)
 	}
 	throughput := func(p *peerConnection) float64 {
 		p.lock.RLock()
 		defer p.lock.RUnlock()
 		return p.blockThroughput
 	}
-	return ps.idlePeers(62, 64, idle, throughput)
+	return ps.idlePeers(62, 66, idle, throughput)
 }
 
 // ReceiptIdlePeers retrieves a flat list of all the currently receipt-idle peers
 // within the active peer set, ordered by their reputation.
 func (ps *peerSet) ReceiptIdlePeers() ([]*peerConnection, int) {
 	idle := func(p *peerConnection) bool {
-		return atomic.LoadInt32(&p.receiptIdle) == 0
+		return p.idle(p.receiptReqs)
 	}
 	throughput := func(p *peerConnection) float64 {
 		p.lock.RLock()
 		defer p.lock.RUnlock()
 		return p.receiptThroughput
 	}
-	return ps.idlePeers(63, 64, idle, throughput)
+	return ps.idlePeers(63, 66, idle, throughput)
 }
 
 // NodeDataIdlePeers retrieves a flat list of all the currently node-data-idle
 // peers within the active peer set, ordered by their reputation.
 func (ps *peerSet) NodeDataIdlePeers() ([]*peerConnection, int) {
 	idle := func(p *peerConnection) bool {
-		return atomic.LoadInt32(&p.stateIdle) == 0
+		return p.idle(p.stateReqs)
 	}
 	throughput := func(p *peerConnection) float64 {
 		p.lock.RLock()
 		defer p.lock.RUnlock()
 		return p.stateThroughput
 	}
-	return ps.idlePeers(63, 64, idle, throughput)
+	return ps.idlePeers(63, 66, idle, throughput)
 }
 
 // idlePeers retrieves a flat list of all currently idle peers satisfying the
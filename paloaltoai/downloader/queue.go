@@ -46,11 +46,24 @@ var (
 // fetchRequest is a currently running data retrieval operation.
 type fetchRequest struct {
 	Peer    *peerConnection // Peer to which the request was sent
+	ID      uint64          // [paa/66] Request ID echoed in the delivery (zero for earlier versions)
 	From    uint64          // [paa/62] Requested chain element index (used for skeleton fills only)
 	Headers []*types.Header // [paa/62] Requested headers, sorted by request order
 	Time    time.Time       // Time when the request was made
 }
 
+// key returns the key identifying the request in a pending pool.
+func (r *fetchRequest) key() requestKey {
+	return requestKey{peer: r.Peer.id, id: r.ID}
+}
+
+// requestKey identifies an in-flight request by the peer it was sent to and the
+// request ID it was tagged with, allowing several requests per peer.
+type requestKey struct {
+	peer string // Identifier of the peer the request was sent to
+	id   uint64 // Request ID echoed in the delivery (zero before paa/66)
+}
+
 // fetchResult is a struct collecting partial results from data fetchers until
 // all outstanding pieces complete and the result as a whole can be processed.
 type fetchResult struct {
@@ -72,7 +85,7 @@ type queue struct {
 	headerTaskPool  map[uint64]*types.Header       // [paa/62] Pending header retrieval tasks, mapping starting indexes to skeleton headers
 	headerTaskQueue *prque.Prque                   // [paa/62] Priority queue of the skeleton indexes to fetch the filling headers for
 	headerPeerMiss  map[string]map[uint64]struct{} // [paa/62] Set of per-peer header batches known to be unavailable
-	headerPendPool  map[string]*fetchRequest       // [paa/62] Currently pending header retrieval operations
+	headerPendPool  map[requestKey]*fetchRequest   // [paa/62] Currently pending header retrieval operations
 	headerResults   []*types.Header                // [paa/62] Result cache accumulating the completed headers
 	headerProced    int                            // [paa/62] Number of headers already processed from the results
 	headerOffset    uint64                         // [paa/62] Number of the first header in the result cache
@@ -81,12 +94,12 @@ type queue struct {
 	// All data retrievals below are based on an already assembles header chain
 	blockTaskPool  map[common.Hash]*types.Header // [paa/62] Pending block (body) retrieval tasks, mapping hashes to headers
 	blockTaskQueue *prque.Prque                  // [paa/62] Priority queue of the headers to fetch the blocks (bodies) for
-	blockPendPool  map[string]*fetchRequest      // [paa/62] Currently pending block (body) retrieval operations
+	blockPendPool  map[requestKey]*fetchRequest  // [paa/62] Currently pending block (body) retrieval operations
 	blockDonePool  map[common.Hash]struct{}      // [paa/62] Set of the completed block (body) fetches
 
 	receiptTaskPool  map[common.Hash]*types.Header // [paa/63] Pending receipt retrieval tasks, mapping hashes to headers
 	receiptTaskQueue *prque.Prque                  // [paa/63] Priority queue of the headers to fetch the receipts for
-	receiptPendPool  map[string]*fetchRequest      // [paa/63] Currently pending receipt retrieval operations
+	receiptPendPool  map[requestKey]*fetchRequest  // [paa/63] Currently pending receipt retrieval operations
 	receiptDonePool  map[common.Hash]struct{}      // [paa/63] Set of the completed receipt fetches
 
 	resultCache  []*fetchResult     // Downloaded but not yet delivered fetch results
@@ -102,15 +115,15 @@ type queue struct {
 func newQueue() *queue {
 	lock := new(sync.Mutex)
 	return &queue{
-		headerPendPool:   make(map[string]*fetchRequest),
+		headerPendPool:   make(map[requestKey]*fetchRequest),
 		headerContCh:     make(chan bool),
 		blockTaskPool:    make(map[common.Hash]*types.Header),
 		blockTaskQueue:   prque.New(nil),
-		blockPendPool:    make(map[string]*fetchRequest),
+		blockPendPool:    make(map[requestKey]*fetchRequest),
 		blockDonePool:    make(map[common.Hash]struct{}),
 		receiptTaskPool:  make(map[common.Hash]*types.Header),
 		receiptTaskQueue: prque.New(nil),
-		receiptPendPool:  make(map[string]*fetchRequest),
+		receiptPendPool:  make(map[requestKey]*fetchRequest),
 		receiptDonePool:  make(map[common.Hash]struct{}),
 		resultCache:      make([]*fetchResult, blockCacheItems),
 		active:           sync.NewCond(lock),
@@ -127,16 +140,16 @@ func (q *queue) Reset() {
 	q.mode = FullSync
 
 	q.headerHead = common.Hash{}
-	q.headerPendPool = make(map[string]*fetchRequest)
+	q.headerPendPool = make(map[requestKey]*fetchRequest)
 
 	q.blockTaskPool = make(map[common.Hash]*types.Header)
 	q.blockTaskQueue.Reset()
-	q.blockPendPool = make(map[string]*fetchRequest)
+	q.blockPendPool = make(map[requestKey]*fetchRequest)
 	q.blockDonePool = make(map[common.Hash]struct{})
 
 	q.receiptTaskPool = make(map[common.Hash]*types.Header)
 	q.receiptTaskQueue.Reset()
-	q.receiptPendPool = make(map[string]*fetchRequest)
+	q.receiptPendPool = make(map[requestKey]*fetchRequest)
 	q.receiptDonePool = make(map[common.Hash]struct{})
 
 	q.resultCache = make([]*fetchResult, blockCacheItems)
@@ -236,7 +249,7 @@ func (q *queue) ShouldThrottleReceipts() bool {
 // resultSlots calculates the number of results slots available for requests
 // whilst adhering to both the item and the memory limit too of the results
 // cache.
-func (q *queue) resultSlots(pendPool map[string]*fetchRequest, donePool map[common.Hash]struct{}) int {
+func (q *queue) resultSlots(pendPool map[requestKey]*fetchRequest, donePool map[common.Hash]struct{}) int {
 	// Calculate the maximum length capped by the memory limit
 	limit := len(q.resultCache)
 	if common.StorageSize(len(q.resultCache))*q.resultSize > common.StorageSize(blockCacheMemory) {
@@ -417,9 +430,9 @@ func (q *queue) ReserveHeaders(p *peerConnection, count int) *fetchRequest {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
-	// Short circuit if the peer's already downloading sompaaing (sanity check to
-	// not corrupt state)
-	if _, ok := q.headerPendPool[p.id]; ok {
+	// Short circuit if the peer's already downloading as much as it may (sanity
+	// check to not corrupt state)
+	if pending(q.headerPendPool, p.id) >= p.maxRequests() {
 		return nil
 	}
 	// Retrieve a batch of hashes, skipping previously failed ones
@@ -444,10 +457,11 @@ func (q *queue) ReserveHeaders(p *peerConnection, count int) *fetchRequest {
 	}
 	request := &fetchRequest{
 		Peer: p,
+		ID:   p.newRequestID(),
 		From: send,
 		Time: time.Now(),
 	}
-	q.headerPendPool[p.id] = request
+	q.headerPendPool[request.key()] = request
 	return request
 }
 
@@ -485,13 +499,13 @@ func (q *queue) ReserveReceipts(p *peerConnection, count int) (*fetchRequest, bo
 // reason the lock is not obtained in here is because the parameters already need
 // to access the queue, so they already need a lock anyway.
 func (q *queue) reserveHeaders(p *peerConnection, count int, taskPool map[common.Hash]*types.Header, taskQueue *prque.Prque,
-	pendPool map[string]*fetchRequest, donePool map[common.Hash]struct{}, isNoop func(*types.Header) bool) (*fetchRequest, bool, error) {
+	pendPool map[requestKey]*fetchRequest, donePool map[common.Hash]struct{}, isNoop func(*types.Header) bool) (*fetchRequest, bool, error) {
 	// Short circuit if the pool has been depleted, or if the peer's already
-	// downloading sompaaing (sanity check not to corrupt state)
+	// downloading as much as it may (sanity check not to corrupt state)
 	if taskQueue.Empty() {
 		return nil, false, nil
 	}
-	if _, ok := pendPool[p.id]; ok {
+	if pending(pendPool, p.id) >= p.maxRequests() {
 		return nil, false, nil
 	}
 	// Calculate an upper limit on the items we might fetch (i.e. throttling)
@@ -554,14 +568,26 @@ func (q *queue) reserveHeaders(p *peerConnection, count int, taskPool map[common
 	}
 	request := &fetchRequest{
 		Peer:    p,
+		ID:      p.newRequestID(),
 		Headers: send,
 		Time:    time.Now(),
 	}
-	pendPool[p.id] = request
+	pendPool[request.key()] = request
 
 	return request, progress, nil
 }
 
+// pending counts the requests in flight to the given peer in a pending pool.
+func pending(pendPool map[requestKey]*fetchRequest, peerID string) int {
+	count := 0
+	for key := range pendPool {
+		if key.peer == peerID {
+			count++
+		}
+	}
+	return count
+}
+
 // CancelHeaders aborts a fetch request, returning all pending skeleton indexes to the queue.
 func (q *queue) CancelHeaders(request *fetchRequest) {
 	q.cancel(request, q.headerTaskQueue, q.headerPendPool)
@@ -580,7 +606,7 @@ func (q *queue) CancelReceipts(request *fetchRequest) {
 }
 
 // Cancel aborts a fetch request, returning all pending hashes to the task queue.
-func (q *queue) cancel(request *fetchRequest, taskQueue *prque.Prque, pendPool map[string]*fetchRequest) {
+func (q *queue) cancel(request *fetchRequest, taskQueue *prque.Prque, pendPool map[requestKey]*fetchRequest) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -590,7 +616,7 @@ func (q *queue) cancel(request *fetchRequest, taskQueue *prque.Prque, pendPool m
 	for _, header := range request.Headers {
 		taskQueue.Push(header, -int64(header.Number.Uint64()))
 	}
-	delete(pendPool, request.Peer.id)
+	delete(pendPool, request.key())
 }
 
 // Revoke cancels all pending requests belonging to a given peer. This method is
@@ -600,23 +626,29 @@ func (q *queue) Revoke(peerID string) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
-	if request, ok := q.blockPendPool[peerID]; ok {
+	for key, request := range q.blockPendPool {
+		if key.peer != peerID {
+			continue
+		}
 		for _, header := range request.Headers {
 			q.blockTaskQueue.Push(header, -int64(header.Number.Uint64()))
 		}
-		delete(q.blockPendPool, peerID)
+		delete(q.blockPendPool, key)
 	}
-	if request, ok := q.receiptPendPool[peerID]; ok {
+	for key, request := range q.receiptPendPool {
+		if key.peer != peerID {
+			continue
+		}
 		for _, header := range request.Headers {
 			q.receiptTaskQueue.Push(header, -int64(header.Number.Uint64()))
 		}
-		delete(q.receiptPendPool, peerID)
+		delete(q.receiptPendPool, key)
 	}
 }
 
 // ExpireHeaders checks for in flight requests that exceeded a timeout allowance,
-// canceling them and returning the responsible peers for penalisation.
-func (q *queue) ExpireHeaders(timeout time.Duration) map[string]int {
+// canceling them and returning them for the responsible peers' penalisation.
+func (q *queue) ExpireHeaders(timeout time.Duration) []*fetchRequest {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -624,8 +656,9 @@ func (q *queue) ExpireHeaders(timeout time.Duration) map[string]int {
 }
 
 // ExpireBodies checks for in flight block body requests that exceeded a timeout
-// allowance, canceling them and returning the responsible peers for penalisation.
-func (q *queue) ExpireBodies(timeout time.Duration) map[string]int {
+// allowance, canceling them and returning them for the responsible peers'
+// penalisation.
+func (q *queue) ExpireBodies(timeout time.Duration) []*fetchRequest {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -633,8 +666,9 @@ func (q *queue) ExpireBodies(timeout time.Duration) map[string]int {
 }
 
 // ExpireReceipts checks for in flight receipt requests that exceeded a timeout
-// allowance, canceling them and returning the responsible peers for penalisation.
-func (q *queue) ExpireReceipts(timeout time.Duration) map[string]int {
+// allowance, canceling them and returning them for the responsible peers'
+// penalisation.
+func (q *queue) ExpireReceipts(timeout time.Duration) []*fetchRequest {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -642,15 +676,15 @@ func (q *queue) ExpireReceipts(timeout time.Duration) map[string]int {
 }
 
 // expire is the generic check that move expired tasks from a pending pool back
-// into a task pool, returning all entities caught with expired tasks.
+// into a task pool, returning all the expired requests.
 //
 // Note, this method expects the queue lock to be already held. The
 // reason the lock is not obtained in here is because the parameters already need
 // to access the queue, so they already need a lock anyway.
-func (q *queue) expire(timeout time.Duration, pendPool map[string]*fetchRequest, taskQueue *prque.Prque, timeoutMeter metrics.Meter) map[string]int {
+func (q *queue) expire(timeout time.Duration, pendPool map[requestKey]*fetchRequest, taskQueue *prque.Prque, timeoutMeter metrics.Meter) []*fetchRequest {
 	// Iterate over the expired requests and return each to the queue
-	expiries := make(map[string]int)
-	for id, request := range pendPool {
+	var expiries []*fetchRequest
+	for key, request := range pendPool {
 		if time.Since(request.Time) > timeout {
 			// Update the metrics with the timeout
 			timeoutMeter.Mark(1)
@@ -662,11 +696,11 @@ func (q *queue) expire(timeout time.Duration, pendPool map[string]*fetchRequest,
 			for _, header := range request.Headers {
 				taskQueue.Push(header, -int64(header.Number.Uint64()))
 			}
-			// Add the peer to the expiry report along the number of failed requests
-			expiries[id] = len(request.Headers)
+			// Add the request to the expiry report for penalising the peer
+			expiries = append(expiries, request)
 
 			// Remove the expired requests from the pending pool directly
-			delete(pendPool, id)
+			delete(pendPool, key)
 		}
 	}
 	return expiries
@@ -679,17 +713,18 @@ func (q *queue) expire(timeout time.Duration, pendPool map[string]*fetchRequest,
 // If the headers are accepted, the method makes an attempt to deliver the set
 // of ready headers to the processor to keep the pipeline full. However it will
 // not block to prevent stalling other pending deliveries.
-func (q *queue) DeliverHeaders(id string, headers []*types.Header, headerProcCh chan []*types.Header) (int, error) {
+func (q *queue) DeliverHeaders(id string, reqID uint64, headers []*types.Header, headerProcCh chan []*types.Header) (int, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
 	// Short circuit if the data was never requested
-	request := q.headerPendPool[id]
+	key := requestKey{peer: id, id: reqID}
+	request := q.headerPendPool[key]
 	if request == nil {
 		return 0, errNoFetchesPending
 	}
 	headerReqTimer.UpdateSince(request.Time)
-	delete(q.headerPendPool, id)
+	delete(q.headerPendPool, key)
 
 	// Ensure headers can be mapped onto the skeleton chain
 	target := q.headerTaskPool[request.From].Hash()
@@ -763,7 +798,7 @@ func (q *queue) DeliverHeaders(id string, headers []*types.Header, headerProcCh
 // DeliverBodies injects a block body retrieval response into the results queue.
 // The method returns the number of blocks bodies accepted from the delivery and
 // also wakes any threads waiting for data delivery.
-func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, uncleLists [][]*types.Header) (int, error) {
+func (q *queue) DeliverBodies(id string, reqID uint64, txLists [][]*types.Transaction, uncleLists [][]*types.Header) (int, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -775,13 +810,13 @@ func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, uncleLi
 		result.Uncles = uncleLists[index]
 		return nil
 	}
-	return q.deliver(id, q.blockTaskPool, q.blockTaskQueue, q.blockPendPool, q.blockDonePool, bodyReqTimer, len(txLists), reconstruct)
+	return q.deliver(requestKey{peer: id, id: reqID}, q.blockTaskPool, q.blockTaskQueue, q.blockPendPool, q.blockDonePool, bodyReqTimer, len(txLists), reconstruct)
 }
 
 // DeliverReceipts injects a receipt retrieval response into the results queue.
 // The method returns the number of transaction receipts accepted from the delivery
 // and also wakes any threads waiting for data delivery.
-func (q *queue) DeliverReceipts(id string, receiptList [][]*types.Receipt) (int, error) {
+func (q *queue) DeliverReceipts(id string, reqID uint64, receiptList [][]*types.Receipt) (int, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -792,7 +827,7 @@ func (q *queue) DeliverReceipts(id string, receiptList [][]*types.Receipt) (int,
 		result.Receipts = receiptList[index]
 		return nil
 	}
-	return q.deliver(id, q.receiptTaskPool, q.receiptTaskQueue, q.receiptPendPool, q.receiptDonePool, receiptReqTimer, len(receiptList), reconstruct)
+	return q.deliver(requestKey{peer: id, id: reqID}, q.receiptTaskPool, q.receiptTaskQueue, q.receiptPendPool, q.receiptDonePool, receiptReqTimer, len(receiptList), reconstruct)
 }
 
 // deliver injects a data retrieval response into the results queue.
@@ -800,17 +835,17 @@ func (q *queue) DeliverReceipts(id string, receiptList [][]*types.Receipt) (int,
 // Note, this method expects the queue lock to be already held for writing. The
 // reason the lock is not obtained in here is because the parameters already need
 // to access the queue, so they already need a lock anyway.
-func (q *queue) deliver(id string, taskPool map[common.Hash]*types.Header, taskQueue *prque.Prque,
-	pendPool map[string]*fetchRequest, donePool map[common.Hash]struct{}, reqTimer metrics.Timer,
+func (q *queue) deliver(key requestKey, taskPool map[common.Hash]*types.Header, taskQueue *prque.Prque,
+	pendPool map[requestKey]*fetchRequest, donePool map[common.Hash]struct{}, reqTimer metrics.Timer,
 	results int, reconstruct func(header *types.Header, index int, result *fetchResult) error) (int, error) {
 
 	// Short circuit if the data was never requested
-	request := pendPool[id]
+	request := pendPool[key]
 	if request == nil {
 		return 0, errNoFetchesPending
 	}
 	reqTimer.UpdateSince(request.Time)
-	delete(pendPool, id)
+	delete(pendPool, key)
 
 	// If no data items were retrieved, mark them as unavailable for the origin peer
 	if results == 0 {
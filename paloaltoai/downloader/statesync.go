@@ -34,6 +34,7 @@ import (
 // stateReq represents a batch of state fetch requests grouped togpaaer into
 // a single data retrieval network packet.
 type stateReq struct {
+	id       uint64                     // Request ID echoed in the delivery (paa/66+, zero otherwise)
 	items    []common.Hash              // Hashes of the state items to download
 	tasks    map[common.Hash]*stateTask // Download tasks to track previous attempts
 	timeout  time.Duration              // Maximum round trip time for this to complete
@@ -43,6 +44,11 @@ type stateReq struct {
 	dropped  bool                       // Flag whpaaer the peer dropped off early
 }
 
+// key returns the key identifying the request among the in-flight ones.
+func (req *stateReq) key() requestKey {
+	return requestKey{peer: req.peer.id, id: req.id}
+}
+
 // timedOut returns if this request timed out.
 func (req *stateReq) timedOut() bool {
 	return req.response == nil
@@ -90,16 +96,16 @@ func (d *Downloader) stateFetcher() {
 // hash is requested to be switched over to.
 func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 	var (
-		active   = make(map[string]*stateReq) // Currently in-flight requests
-		finished []*stateReq                  // Completed or failed requests
-		timeout  = make(chan *stateReq)       // Timed out active requests
+		active   = make(map[requestKey]*stateReq) // Currently in-flight requests
+		finished []*stateReq                      // Completed or failed requests
+		timeout  = make(chan *stateReq)           // Timed out active requests
 	)
 	defer func() {
 		// Cancel active request timers on exit. Also set peers to idle so they're
 		// available for the next sync.
 		for _, req := range active {
 			req.timer.Stop()
-			req.peer.SetNodeDataIdle(len(req.items))
+			req.peer.SetNodeDataIdle(req.id, len(req.items))
 		}
 	}()
 	// Run the state sync.
@@ -140,9 +146,10 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 		// Handle incoming state packs:
 		case pack := <-d.stateCh:
 			// Discard any data not requested (or previously timed out)
-			req := active[pack.PeerId()]
+			key := requestKey{peer: pack.PeerId(), id: pack.RequestId()}
+			req := active[key]
 			if req == nil {
-				log.Debug("Unrequested node data", "peer", pack.PeerId(), "len", pack.Items())
+				log.Debug("Unrequested node data", "peer", pack.PeerId(), "id", pack.RequestId(), "len", pack.Items())
 				continue
 			}
 			// Finalize the request and queue up for processing
@@ -150,43 +157,44 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 			req.response = pack.(*statePack).states
 
 			finished = append(finished, req)
-			delete(active, pack.PeerId())
+			delete(active, key)
 
 		// Handle dropped peer connections:
 		case p := <-peerDrop:
-			// Skip if no request is currently pending
-			req := active[p.id]
-			if req == nil {
-				continue
-			}
-			// Finalize the request and queue up for processing
-			req.timer.Stop()
-			req.dropped = true
+			// Finalize all the requests pending from the peer and queue up for processing
+			for key, req := range active {
+				if key.peer != p.id {
+					continue
+				}
+				req.timer.Stop()
+				req.dropped = true
 
-			finished = append(finished, req)
-			delete(active, p.id)
+				finished = append(finished, req)
+				delete(active, key)
+			}
 
 		// Handle timed-out requests:
 		case req := <-timeout:
 			// If the peer is already requesting sompaaing else, ignore the stale timeout.
 			// This can happen when the timeout and the delivery happens simultaneously,
 			// causing both pathways to trigger.
-			if active[req.peer.id] != req {
+			if active[req.key()] != req {
 				continue
 			}
 			// Move the timed out data back into the download queue
 			finished = append(finished, req)
-			delete(active, req.peer.id)
+			delete(active, req.key())
 
 		// Track outgoing state requests:
 		case req := <-d.trackStateReq:
-			// If an active request already exists for this peer, we have a problem. In
-			// theory the trie node schedule must never assign two requests to the same
-			// peer. In practice however, a peer might receive a request, disconnect and
-			// immediately reconnect before the previous times out. In this case the first
-			// request is never honored, alas we must not silently overwrite it, as that
-			// causes valid requests to go missing and sync to get stuck.
-			if old := active[req.peer.id]; old != nil {
+			// If an active request already exists with this key, we have a problem. In
+			// theory the trie node schedule must never assign two requests the same ID
+			// on the same peer. In practice however, a peer not tagging requests might
+			// receive a request, disconnect and immediately reconnect before the previous
+			// times out. In this case the first request is never honored, alas we must
+			// not silently overwrite it, as that causes valid requests to go missing and
+			// sync to get stuck.
+			if old := active[req.key()]; old != nil {
 				log.Warn("Busy peer assigned new state fetch", "peer", old.peer.id)
 
 				// Make sure the previous one doesn't get siletly lost
@@ -204,7 +212,7 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 					// timer is fired just before exiting runStateSync.
 				}
 			})
-			active[req.peer.id] = req
+			active[req.key()] = req
 		}
 	}
 }
@@ -310,6 +318,7 @@ func (s *stateSync) loop() (err error) {
 				// 2 items are the minimum requested, if even that times out, we've no use of
 				// this peer at the moment.
 				log.Warn("Stalling state sync, dropping peer", "peer", req.peer.id)
+				s.d.penalise(req.peer.id, errStallingPeer)
 				s.d.dropPeer(req.peer.id)
 			}
 			// Process all the received blobs and check for stale delivery
@@ -318,7 +327,7 @@ func (s *stateSync) loop() (err error) {
 				log.Warn("Node data write error", "err", err)
 				return err
 			}
-			req.peer.SetNodeDataIdle(delivered)
+			req.peer.SetNodeDataIdle(req.id, delivered)
 		}
 	}
 	return nil
@@ -350,7 +359,7 @@ func (s *stateSync) assignTasks() {
 	for _, p := range peers {
 		// Assign a batch of fetches proportional to the estimated latency/bandwidth
 		cap := p.NodeDataCapacity(s.d.requestRTT())
-		req := &stateReq{peer: p, timeout: s.d.requestTTL()}
+		req := &stateReq{id: p.newRequestID(), peer: p, timeout: s.d.requestTTL()}
 		s.fillTasks(cap, req)
 
 		// If the peer was assigned tasks to fetch, send the network request
@@ -358,7 +367,7 @@ func (s *stateSync) assignTasks() {
 			req.peer.log.Trace("Requesting new batch of data", "type", "state", "count", len(req.items))
 			select {
 			case s.d.trackStateReq <- req:
-				req.peer.FetchNodeData(req.items)
+				req.peer.FetchNodeData(req.id, req.items)
 			case <-s.cancel:
 			case <-s.d.cancelCh:
 			}
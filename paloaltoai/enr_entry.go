@@ -0,0 +1,63 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package paa
+
+import (
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/forkid"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
+)
+
+// paaEntry is the "paa" ENR entry which advertises paa protocol
+// on the discovery network.
+type paaEntry struct {
+	ForkID forkid.ID // Fork identifier per EIP-2124
+
+	// Ignore additional fields (for forward compatibility).
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+// ENRKey implements enr.Entry.
+func (e paaEntry) ENRKey() string {
+	return "paa"
+}
+
+// startPaaEntryUpdate keeps the "paa" entry of the local node record up to
+// date as the chain head passes fork blocks.
+func (s *PaloAltoAi) startPaaEntryUpdate(ln *enode.LocalNode) {
+	var newHead = make(chan core.ChainHeadEvent, 10)
+	sub := s.blockchain.SubscribeChainHeadEvent(newHead)
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-newHead:
+				ln.Set(s.currentPaaEntry())
+			case <-sub.Err():
+				// Would be nice to sync with s.Stop, but there is no
+				// good way to do that.
+				return
+			}
+		}
+	}()
+}
+
+func (s *PaloAltoAi) currentPaaEntry() *paaEntry {
+	return &paaEntry{ForkID: forkid.NewID(s.blockchain)}
+}
@@ -40,4 +40,15 @@ var (
 	headerFilterOutMeter = metrics.NewRegisteredMeter("paa/fetcher/filter/headers/out", nil)
 	bodyFilterInMeter    = metrics.NewRegisteredMeter("paa/fetcher/filter/bodies/in", nil)
 	bodyFilterOutMeter   = metrics.NewRegisteredMeter("paa/fetcher/filter/bodies/out", nil)
+
+	txAnnounceInMeter    = metrics.NewRegisteredMeter("paa/fetcher/transaction/announces/in", nil)
+	txAnnounceKnownMeter = metrics.NewRegisteredMeter("paa/fetcher/transaction/announces/known", nil)
+	txAnnounceDOSMeter   = metrics.NewRegisteredMeter("paa/fetcher/transaction/announces/dos", nil)
+
+	txBroadcastInMeter = metrics.NewRegisteredMeter("paa/fetcher/transaction/broadcasts/in", nil)
+	txReplyInMeter     = metrics.NewRegisteredMeter("paa/fetcher/transaction/replies/in", nil)
+
+	txRequestOutMeter   = metrics.NewRegisteredMeter("paa/fetcher/transaction/request/out", nil)
+	txRequestFailMeter  = metrics.NewRegisteredMeter("paa/fetcher/transaction/request/fail", nil)
+	txFetchTimeoutMeter = metrics.NewRegisteredMeter("paa/fetcher/transaction/request/timeout", nil)
 )
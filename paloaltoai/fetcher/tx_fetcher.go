@@ -0,0 +1,509 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+)
+
+const (
+	// maxTxAnnounces is the maximum number of unique transactions a peer may
+	// have announced and not yet delivered (prevent memory exhaustion).
+	maxTxAnnounces = 4096
+
+	// maxTxRetrievals is the maximum number of transactions that can be fetched
+	// in one request.
+	maxTxRetrievals = 256
+
+	// txArriveTimeout is the time allowance before an announced transaction is
+	// explicitly requested. The delay gives the peers that broadcast full
+	// transactions a chance to deliver them without an extra round trip.
+	txArriveTimeout = 500 * time.Millisecond
+
+	// txGatherSlack is the interval used to collate almost-expired announces
+	// with network fetches.
+	txGatherSlack = 100 * time.Millisecond
+
+	// txFetchTimeout is the maximum allotted time to return an explicitly
+	// requested transaction.
+	txFetchTimeout = 5 * time.Second
+)
+
+// txAnnounce is the notification of the availability of a batch of new
+// transactions in the network.
+type txAnnounce struct {
+	origin string        // Identifier of the peer originating the notification
+	hashes []common.Hash // Batch of transaction hashes being announced
+}
+
+// txDelivery is the notification that a batch of transactions have been added
+// to the pool and should be untracked.
+type txDelivery struct {
+	origin string        // Identifier of the peer originating the notification
+	hashes []common.Hash // Batch of transaction hashes having been delivered
+	direct bool          // Whether this is a direct reply or a broadcast
+}
+
+// txRequest represents an in-flight transaction retrieval request.
+type txRequest struct {
+	hashes []common.Hash  // Transactions having been requested
+	time   mclock.AbsTime // Timestamp of the request
+}
+
+// TxFetcher is responsible for retrieving new transactions based on
+// announcements.
+//
+// The fetcher operates in 3 stages:
+//   - Transactions that are newly announced wait in a waitlist for a short
+//     time, giving peers that broadcast full transactions a chance to deliver
+//     them without a dedicated request.
+//   - After the wait expires, the announcements are queued per announcing
+//     peer. Every transaction hash is remembered with all peers that have it.
+//   - Idle peers are assigned a batch of queued transactions which are not yet
+//     being fetched from someone else. If a request times out or the peer
+//     doesn't deliver some of the requested transactions, the hashes go back
+//     to the queue and are retrieved from one of the alternate announcers.
+type TxFetcher struct {
+	notify  chan *txAnnounce
+	cleanup chan *txDelivery
+	drop    chan string
+	quit    chan struct{}
+
+	// Stage 1: Waiting for a broadcast to arrive before fetching
+	waitlist  map[common.Hash]map[string]struct{} // Announcers of transactions waiting for broadcast arrival
+	waittime  map[common.Hash]mclock.AbsTime      // Timestamps when transactions were first announced
+	waitslots map[string]map[common.Hash]struct{} // Waiting announcements grouped by peer (DoS protection)
+
+	// Stage 2: Queued for fetching, or being fetched
+	announces map[string]map[common.Hash]struct{} // Set of announced transactions, grouped by origin peer
+	announced map[common.Hash]map[string]struct{} // Set of download locations, grouped by transaction hash
+
+	// Stage 3: Transactions being fetched
+	fetching map[common.Hash]string // Transactions currently being retrieved, and the peer retrieving them
+	requests map[string]*txRequest  // In-flight transaction retrievals
+
+	// Callbacks
+	hasTx    func(common.Hash) bool             // Retrieves a tx from the local txpool
+	addTxs   func([]*types.Transaction) []error // Insert a batch of transactions into local txpool
+	fetchTxs func(string, []common.Hash) error  // Retrieves a set of txs from a remote peer
+
+	clock mclock.Clock  // Time wrapper to simulate in tests
+	step  chan struct{} // Notification channel when the fetcher loop iterates (testing)
+}
+
+// NewTxFetcher creates a transaction fetcher to retrieve transactions
+// based on hash announcements.
+func NewTxFetcher(hasTx func(common.Hash) bool, addTxs func([]*types.Transaction) []error, fetchTxs func(string, []common.Hash) error) *TxFetcher {
+	return NewTxFetcherForTests(hasTx, addTxs, fetchTxs, mclock.System{})
+}
+
+// NewTxFetcherForTests is a testing method to mock out the realtime clock with
+// a simulated version.
+func NewTxFetcherForTests(
+	hasTx func(common.Hash) bool, addTxs func([]*types.Transaction) []error, fetchTxs func(string, []common.Hash) error,
+	clock mclock.Clock) *TxFetcher {
+	return &TxFetcher{
+		notify:    make(chan *txAnnounce),
+		cleanup:   make(chan *txDelivery),
+		drop:      make(chan string),
+		quit:      make(chan struct{}),
+		waitlist:  make(map[common.Hash]map[string]struct{}),
+		waittime:  make(map[common.Hash]mclock.AbsTime),
+		waitslots: make(map[string]map[common.Hash]struct{}),
+		announces: make(map[string]map[common.Hash]struct{}),
+		announced: make(map[common.Hash]map[string]struct{}),
+		fetching:  make(map[common.Hash]string),
+		requests:  make(map[string]*txRequest),
+		hasTx:     hasTx,
+		addTxs:    addTxs,
+		fetchTxs:  fetchTxs,
+		clock:     clock,
+	}
+}
+
+// Notify announces the fetcher of the potential availability of a new batch of
+// transactions in the network.
+func (f *TxFetcher) Notify(peer string, hashes []common.Hash) error {
+	// Skip any transaction announcements that we already know of. This check is
+	// racy as the transaction might arrive meanwhile, so the loop filters again.
+	unknown := make([]common.Hash, 0, len(hashes))
+	for _, hash := range hashes {
+		if !f.hasTx(hash) {
+			unknown = append(unknown, hash)
+		}
+	}
+	txAnnounceInMeter.Mark(int64(len(hashes)))
+	txAnnounceKnownMeter.Mark(int64(len(hashes) - len(unknown)))
+	if len(unknown) == 0 {
+		return nil
+	}
+	select {
+	case f.notify <- &txAnnounce{origin: peer, hashes: unknown}:
+		return nil
+	case <-f.quit:
+		return errTerminated
+	}
+}
+
+// Enqueue imports a batch of received transactions into the transaction pool
+// and the fetcher. This method may be called by both transaction broadcasts and
+// direct request replies. The differentiation is important so the fetcher can
+// re-schedule missing transactions as soon as possible.
+func (f *TxFetcher) Enqueue(peer string, txs []*types.Transaction, direct bool) error {
+	if direct {
+		txReplyInMeter.Mark(int64(len(txs)))
+	} else {
+		txBroadcastInMeter.Mark(int64(len(txs)))
+	}
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	f.addTxs(txs)
+
+	select {
+	case f.cleanup <- &txDelivery{origin: peer, hashes: hashes, direct: direct}:
+		return nil
+	case <-f.quit:
+		return errTerminated
+	}
+}
+
+// Drop should be called when a peer disconnects. It cleans up all the internal
+// data structures of the given node.
+func (f *TxFetcher) Drop(peer string) error {
+	select {
+	case f.drop <- peer:
+		return nil
+	case <-f.quit:
+		return errTerminated
+	}
+}
+
+// Start boots up the announcement based synchroniser, accepting and processing
+// hash notifications and transaction fetches until termination requested.
+func (f *TxFetcher) Start() {
+	go f.loop()
+}
+
+// Stop terminates the announcement based synchroniser, canceling all pending
+// operations.
+func (f *TxFetcher) Stop() {
+	close(f.quit)
+}
+
+func (f *TxFetcher) loop() {
+	var (
+		waitTrigger    <-chan time.Time // Fires when the oldest waiting announcement is due
+		timeoutTrigger <-chan time.Time // Fires when the oldest request may have timed out
+	)
+	for {
+		select {
+		case ann := <-f.notify:
+			f.handleAnnounce(ann)
+
+		case <-waitTrigger:
+			waitTrigger = nil
+			f.handleWaitExpiry()
+
+		case <-timeoutTrigger:
+			timeoutTrigger = nil
+			f.handleTimeouts()
+
+		case delivery := <-f.cleanup:
+			f.handleDelivery(delivery)
+
+		case peer := <-f.drop:
+			f.handleDrop(peer)
+
+		case <-f.quit:
+			return
+		}
+		// Hand out new work and rearm the timers if they are not running.
+		f.scheduleFetches()
+		if waitTrigger == nil {
+			if earliest, ok := f.earliestWait(); ok {
+				waitTrigger = f.clock.After(f.untilDue(earliest, txArriveTimeout))
+			}
+		}
+		if timeoutTrigger == nil {
+			if earliest, ok := f.earliestRequest(); ok {
+				timeoutTrigger = f.clock.After(f.untilDue(earliest, txFetchTimeout))
+			}
+		}
+		if f.step != nil {
+			f.step <- struct{}{}
+		}
+	}
+}
+
+// handleAnnounce adds newly announced transactions to the waitlist, or records
+// the announcer as an alternate source if the transaction is already queued.
+func (f *TxFetcher) handleAnnounce(ann *txAnnounce) {
+	used := len(f.waitslots[ann.origin]) + len(f.announces[ann.origin])
+	for _, hash := range ann.hashes {
+		if used >= maxTxAnnounces {
+			txAnnounceDOSMeter.Mark(1)
+			break
+		}
+		switch {
+		case f.hasTx(hash):
+			continue
+
+		case f.announced[hash] != nil:
+			// Already past the waitlist, add the peer as a download location.
+			if _, ok := f.announced[hash][ann.origin]; ok {
+				continue
+			}
+			f.addAnnounce(ann.origin, hash)
+
+		case f.waitlist[hash] != nil:
+			// Waiting for a broadcast, add the peer as a future download location.
+			if _, ok := f.waitlist[hash][ann.origin]; ok {
+				continue
+			}
+			f.waitlist[hash][ann.origin] = struct{}{}
+			f.addWaitslot(ann.origin, hash)
+
+		default:
+			// Brand new transaction, put it into the waitlist.
+			f.waitlist[hash] = map[string]struct{}{ann.origin: {}}
+			f.waittime[hash] = f.clock.Now()
+			f.addWaitslot(ann.origin, hash)
+		}
+		used++
+	}
+}
+
+// handleWaitExpiry moves all transactions whose wait time is (almost) over to
+// the fetch queue.
+func (f *TxFetcher) handleWaitExpiry() {
+	now := f.clock.Now()
+	for hash, instance := range f.waittime {
+		if time.Duration(now-instance)+txGatherSlack < txArriveTimeout {
+			continue
+		}
+		for peer := range f.waitlist[hash] {
+			f.removeWaitslot(peer, hash)
+			f.addAnnounce(peer, hash)
+		}
+		delete(f.waitlist, hash)
+		delete(f.waittime, hash)
+	}
+}
+
+// handleTimeouts expires requests which weren't answered in time. The peer is
+// no longer considered a source for the requested transactions, which allows
+// scheduling them from alternate peers.
+func (f *TxFetcher) handleTimeouts() {
+	now := f.clock.Now()
+	for peer, req := range f.requests {
+		if time.Duration(now-req.time)+txGatherSlack < txFetchTimeout {
+			continue
+		}
+		txFetchTimeoutMeter.Mark(int64(len(req.hashes)))
+		log.Trace("Transaction retrieval timed out", "peer", peer, "count", len(req.hashes))
+		for _, hash := range req.hashes {
+			if f.fetching[hash] == peer {
+				delete(f.fetching, hash)
+			}
+			f.removeAnnounce(peer, hash)
+		}
+		delete(f.requests, peer)
+	}
+}
+
+// handleDelivery untracks transactions which arrived, regardless of the source.
+// If the delivery is a reply to a request, transactions missing from the reply
+// are rescheduled from alternate peers.
+func (f *TxFetcher) handleDelivery(delivery *txDelivery) {
+	for _, hash := range delivery.hashes {
+		for peer := range f.waitlist[hash] {
+			f.removeWaitslot(peer, hash)
+		}
+		delete(f.waitlist, hash)
+		delete(f.waittime, hash)
+
+		for peer := range f.announced[hash] {
+			f.removeAnnounce(peer, hash)
+		}
+		delete(f.fetching, hash)
+	}
+	if !delivery.direct {
+		return
+	}
+	req := f.requests[delivery.origin]
+	if req == nil {
+		return // Late reply to an already expired request
+	}
+	delivered := make(map[common.Hash]struct{}, len(delivery.hashes))
+	for _, hash := range delivery.hashes {
+		delivered[hash] = struct{}{}
+	}
+	for _, hash := range req.hashes {
+		if _, ok := delivered[hash]; ok {
+			continue
+		}
+		// The peer didn't have the transaction, don't ask it again.
+		if f.fetching[hash] == delivery.origin {
+			delete(f.fetching, hash)
+		}
+		f.removeAnnounce(delivery.origin, hash)
+	}
+	delete(f.requests, delivery.origin)
+}
+
+// handleDrop removes all state associated with a disconnected peer. Its
+// in-flight retrievals are rescheduled from alternate peers.
+func (f *TxFetcher) handleDrop(peer string) {
+	for hash := range f.waitslots[peer] {
+		delete(f.waitlist[hash], peer)
+		if len(f.waitlist[hash]) == 0 {
+			delete(f.waitlist, hash)
+			delete(f.waittime, hash)
+		}
+	}
+	delete(f.waitslots, peer)
+
+	if req := f.requests[peer]; req != nil {
+		for _, hash := range req.hashes {
+			if f.fetching[hash] == peer {
+				delete(f.fetching, hash)
+			}
+		}
+		delete(f.requests, peer)
+	}
+	for hash := range f.announces[peer] {
+		f.removeAnnounce(peer, hash)
+	}
+}
+
+// scheduleFetches assigns queued transactions to idle peers which announced
+// them, making sure no transaction is requested from two peers at once.
+func (f *TxFetcher) scheduleFetches() {
+	now := f.clock.Now()
+	for peer, hashes := range f.announces {
+		if f.requests[peer] != nil {
+			continue // Peer is busy with a previous request
+		}
+		var batch []common.Hash
+		for hash := range hashes {
+			if _, ok := f.fetching[hash]; ok {
+				continue
+			}
+			batch = append(batch, hash)
+			if len(batch) == maxTxRetrievals {
+				break
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		for _, hash := range batch {
+			f.fetching[hash] = peer
+		}
+		f.requests[peer] = &txRequest{hashes: batch, time: now}
+		txRequestOutMeter.Mark(int64(len(batch)))
+
+		go func(peer string, hashes []common.Hash) {
+			if err := f.fetchTxs(peer, hashes); err != nil {
+				txRequestFailMeter.Mark(int64(len(hashes)))
+				log.Debug("Transaction retrieval failed", "peer", peer, "err", err)
+				f.Drop(peer)
+			}
+		}(peer, batch)
+	}
+}
+
+func (f *TxFetcher) addWaitslot(peer string, hash common.Hash) {
+	if f.waitslots[peer] == nil {
+		f.waitslots[peer] = make(map[common.Hash]struct{})
+	}
+	f.waitslots[peer][hash] = struct{}{}
+}
+
+func (f *TxFetcher) removeWaitslot(peer string, hash common.Hash) {
+	delete(f.waitslots[peer], hash)
+	if len(f.waitslots[peer]) == 0 {
+		delete(f.waitslots, peer)
+	}
+}
+
+func (f *TxFetcher) addAnnounce(peer string, hash common.Hash) {
+	if f.announces[peer] == nil {
+		f.announces[peer] = make(map[common.Hash]struct{})
+	}
+	f.announces[peer][hash] = struct{}{}
+	if f.announced[hash] == nil {
+		f.announced[hash] = make(map[string]struct{})
+	}
+	f.announced[hash][peer] = struct{}{}
+}
+
+func (f *TxFetcher) removeAnnounce(peer string, hash common.Hash) {
+	delete(f.announces[peer], hash)
+	if len(f.announces[peer]) == 0 {
+		delete(f.announces, peer)
+	}
+	delete(f.announced[hash], peer)
+	if len(f.announced[hash]) == 0 {
+		delete(f.announced, hash)
+	}
+}
+
+// earliestWait returns the announcement time of the oldest waiting transaction.
+func (f *TxFetcher) earliestWait() (mclock.AbsTime, bool) {
+	var (
+		earliest mclock.AbsTime
+		found    bool
+	)
+	for _, instance := range f.waittime {
+		if !found || instance < earliest {
+			earliest, found = instance, true
+		}
+	}
+	return earliest, found
+}
+
+// earliestRequest returns the start time of the oldest in-flight request.
+func (f *TxFetcher) earliestRequest() (mclock.AbsTime, bool) {
+	var (
+		earliest mclock.AbsTime
+		found    bool
+	)
+	for _, req := range f.requests {
+		if !found || req.time < earliest {
+			earliest, found = req.time, true
+		}
+	}
+	return earliest, found
+}
+
+// untilDue returns the time remaining until an event started at instance has
+// been pending for timeout, minus the gather slack.
+func (f *TxFetcher) untilDue(instance mclock.AbsTime, timeout time.Duration) time.Duration {
+	due := timeout - txGatherSlack - time.Duration(f.clock.Now()-instance)
+	if due < 0 {
+		due = 0
+	}
+	return due
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+)
+
+// txFetchRequest is a transaction retrieval issued by the fetcher.
+type txFetchRequest struct {
+	peer   string
+	hashes []common.Hash
+}
+
+// txFetcherTester is a test simulator for mocking out the transaction pool and
+// the network.
+type txFetcherTester struct {
+	fetcher  *TxFetcher
+	clock    *mclock.Simulated
+	requests chan txFetchRequest
+
+	lock sync.Mutex
+	pool map[common.Hash]*types.Transaction
+}
+
+func newTxFetcherTester() *txFetcherTester {
+	tester := &txFetcherTester{
+		clock:    new(mclock.Simulated),
+		requests: make(chan txFetchRequest, 100),
+		pool:     make(map[common.Hash]*types.Transaction),
+	}
+	tester.fetcher = NewTxFetcherForTests(tester.hasTx, tester.addTxs, tester.fetchTxs, tester.clock)
+	tester.fetcher.step = make(chan struct{})
+	tester.fetcher.Start()
+	return tester
+}
+
+func (f *txFetcherTester) hasTx(hash common.Hash) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.pool[hash] != nil
+}
+
+func (f *txFetcherTester) addTxs(txs []*types.Transaction) []error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, tx := range txs {
+		f.pool[tx.Hash()] = tx
+	}
+	return make([]error, len(txs))
+}
+
+func (f *txFetcherTester) fetchTxs(peer string, hashes []common.Hash) error {
+	f.requests <- txFetchRequest{peer, hashes}
+	return nil
+}
+
+// notify announces hashes and waits for the fetcher to process them.
+func (f *txFetcherTester) notify(t *testing.T, peer string, hashes ...common.Hash) {
+	if err := f.fetcher.Notify(peer, hashes); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	<-f.fetcher.step
+}
+
+// enqueue delivers transactions and waits for the fetcher to process them.
+func (f *txFetcherTester) enqueue(t *testing.T, peer string, txs []*types.Transaction, direct bool) {
+	if err := f.fetcher.Enqueue(peer, txs, direct); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+	<-f.fetcher.step
+}
+
+// run advances the simulated clock and waits for the fetcher to process the
+// fired timer.
+func (f *txFetcherTester) run(d time.Duration) {
+	f.clock.Run(d)
+	<-f.fetcher.step
+}
+
+// expectRequest waits for a retrieval request and checks its contents.
+func (f *txFetcherTester) expectRequest(t *testing.T, peer string, hashes ...common.Hash) txFetchRequest {
+	t.Helper()
+	select {
+	case req := <-f.requests:
+		if peer != "" && req.peer != peer {
+			t.Fatalf("request sent to wrong peer: have %s, want %s", req.peer, peer)
+		}
+		if !sameHashes(req.hashes, hashes) {
+			t.Fatalf("wrong request hashes: have %x, want %x", req.hashes, hashes)
+		}
+		return req
+	case <-time.After(time.Second):
+		t.Fatalf("no request sent")
+	}
+	return txFetchRequest{}
+}
+
+// expectNoRequest checks that no retrieval request was sent.
+func (f *txFetcherTester) expectNoRequest(t *testing.T) {
+	t.Helper()
+	select {
+	case req := <-f.requests:
+		t.Fatalf("unexpected request to %s: %x", req.peer, req.hashes)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// expectIdle checks that the fetcher doesn't track any transactions anymore.
+func (f *txFetcherTester) expectIdle(t *testing.T) {
+	t.Helper()
+	fetcher := f.fetcher
+	if len(fetcher.waitlist)+len(fetcher.waittime)+len(fetcher.waitslots) != 0 {
+		t.Fatalf("waitlist not empty: %d waiting", len(fetcher.waitlist))
+	}
+	if len(fetcher.announces)+len(fetcher.announced) != 0 {
+		t.Fatalf("announces not empty: %d queued", len(fetcher.announced))
+	}
+	if len(fetcher.fetching)+len(fetcher.requests) != 0 {
+		t.Fatalf("requests not empty: %d fetching", len(fetcher.fetching))
+	}
+}
+
+func sameHashes(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]common.Hash{}, a...), append([]common.Hash{}, b...)
+	sort.Slice(a, func(i, j int) bool { return a[i].Big().Cmp(a[j].Big()) < 0 })
+	sort.Slice(b, func(i, j int) bool { return b[i].Big().Cmp(b[j].Big()) < 0 })
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func makeTxs(n int) []*types.Transaction {
+	txs := make([]*types.Transaction, n)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	}
+	return txs
+}
+
+// Tests that announced transactions are only requested after the arrival
+// timeout, and that the fetcher cleans up after the delivery.
+func TestTxFetcherWaitAndFetch(t *testing.T) {
+	tester := newTxFetcherTester()
+	defer tester.fetcher.Stop()
+
+	txs := makeTxs(2)
+	tester.notify(t, "A", txs[0].Hash(), txs[1].Hash())
+	tester.expectNoRequest(t)
+
+	tester.run(txArriveTimeout)
+	tester.expectRequest(t, "A", txs[0].Hash(), txs[1].Hash())
+
+	tester.enqueue(t, "A", txs, true)
+	tester.expectIdle(t)
+}
+
+// Tests that transactions which arrive via broadcast during the wait period
+// are not requested.
+func TestTxFetcherBroadcastArrival(t *testing.T) {
+	tester := newTxFetcherTester()
+	defer tester.fetcher.Stop()
+
+	txs := makeTxs(2)
+	tester.notify(t, "A", txs[0].Hash(), txs[1].Hash())
+	tester.enqueue(t, "B", txs[:1], false)
+
+	tester.run(txArriveTimeout)
+	tester.expectRequest(t, "A", txs[1].Hash())
+
+	// Already known transactions are not even tracked.
+	if err := tester.fetcher.Notify("C", []common.Hash{txs[0].Hash()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tester.fetcher.waitlist[txs[0].Hash()]; ok {
+		t.Fatal("known transaction added to the waitlist")
+	}
+}
+
+// Tests that a transaction announced by multiple peers is only requested once,
+// and that it's rescheduled from an alternate peer if the request times out.
+func TestTxFetcherTimeoutReschedule(t *testing.T) {
+	tester := newTxFetcherTester()
+	defer tester.fetcher.Stop()
+
+	txs := makeTxs(1)
+	tester.notify(t, "A", txs[0].Hash())
+	tester.notify(t, "B", txs[0].Hash())
+
+	tester.run(txArriveTimeout)
+	first := tester.expectRequest(t, "", txs[0].Hash())
+	tester.expectNoRequest(t)
+
+	tester.run(txFetchTimeout)
+	second := tester.expectRequest(t, "", txs[0].Hash())
+	if first.peer == second.peer {
+		t.Fatalf("timed out transaction requested from the same peer %s", first.peer)
+	}
+	// A late reply to the expired request is still accepted. The pending request
+	// is only finished by the reply of the second peer.
+	tester.enqueue(t, first.peer, txs, true)
+	tester.enqueue(t, second.peer, nil, true)
+	tester.expectIdle(t)
+}
+
+// Tests that transactions missing from a reply are requested from alternate
+// peers right away.
+func TestTxFetcherMissingReschedule(t *testing.T) {
+	tester := newTxFetcherTester()
+	defer tester.fetcher.Stop()
+
+	txs := makeTxs(2)
+	tester.notify(t, "A", txs[0].Hash(), txs[1].Hash())
+	tester.run(txArriveTimeout)
+	tester.expectRequest(t, "A", txs[0].Hash(), txs[1].Hash())
+
+	// B announces after the wait, so it's only an alternate.
+	tester.notify(t, "B", txs[1].Hash())
+	tester.expectNoRequest(t)
+
+	tester.enqueue(t, "A", txs[:1], true)
+	tester.expectRequest(t, "B", txs[1].Hash())
+
+	tester.enqueue(t, "B", txs[1:], true)
+	tester.expectIdle(t)
+}
+
+// Tests that dropping a peer reschedules its in-flight retrievals and removes
+// all its announcements.
+func TestTxFetcherDrop(t *testing.T) {
+	tester := newTxFetcherTester()
+	defer tester.fetcher.Stop()
+
+	txs := makeTxs(2)
+	tester.notify(t, "A", txs[0].Hash(), txs[1].Hash())
+	tester.run(txArriveTimeout)
+	tester.expectRequest(t, "A", txs[0].Hash(), txs[1].Hash())
+	tester.notify(t, "B", txs[0].Hash())
+	tester.notify(t, "C", txs[1].Hash())
+
+	if err := tester.fetcher.Drop("A"); err != nil {
+		t.Fatal(err)
+	}
+	<-tester.fetcher.step
+	for i := 0; i < 2; i++ {
+		select {
+		case req := <-tester.requests:
+			want := map[string]common.Hash{"B": txs[0].Hash(), "C": txs[1].Hash()}[req.peer]
+			if !sameHashes(req.hashes, []common.Hash{want}) {
+				t.Fatalf("wrong request to %s: %x", req.peer, req.hashes)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no request sent")
+		}
+	}
+
+	for _, peer := range []string{"B", "C"} {
+		if err := tester.fetcher.Drop(peer); err != nil {
+			t.Fatal(err)
+		}
+		<-tester.fetcher.step
+	}
+	tester.expectIdle(t)
+}
+
+// Tests that a peer can't make the fetcher track an unbounded number of
+// transactions.
+func TestTxFetcherDOSProtection(t *testing.T) {
+	tester := newTxFetcherTester()
+	defer tester.fetcher.Stop()
+
+	hashes := make([]common.Hash, maxTxAnnounces+10)
+	for i := range hashes {
+		hashes[i] = common.BigToHash(big.NewInt(int64(i + 1)))
+	}
+	tester.notify(t, "A", hashes...)
+	if n := len(tester.fetcher.waitslots["A"]); n != maxTxAnnounces {
+		t.Fatalf("wrong number of tracked announcements: have %d, want %d", n, maxTxAnnounces)
+	}
+}
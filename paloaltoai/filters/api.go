@@ -234,6 +234,10 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+//
+// If a non-negative "fromBlock" is given, the matching logs already in the chain are
+// replayed first, after which the subscription switches over to the new (or removed)
+// logs without gaps or duplicates.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -249,15 +253,39 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 	if err != nil {
 		return nil, err
 	}
+	// Replay the historical logs if requested, notifications are buffered until
+	// the subscription is activated
+	var backfill *logsBackfill
+	if crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 {
+		var logs []*types.Log
+		if logs, backfill, err = api.backfillLogs(ctx, crit, matchedLogs); err != nil {
+			logsSub.Unsubscribe()
+			return nil, err
+		}
+		for _, log := range logs {
+			notifier.Notify(rpcSub.ID, log)
+		}
+	}
 
 	go func() {
-
+		notify := func(logs []*types.Log) {
+			if backfill != nil {
+				logs = backfill.filter(logs)
+			}
+			for _, log := range logs {
+				notifier.Notify(rpcSub.ID, log)
+			}
+		}
+		if backfill != nil {
+			for _, logs := range backfill.live {
+				notify(logs)
+			}
+			backfill.live = nil
+		}
 		for {
 			select {
 			case logs := <-matchedLogs:
-				for _, log := range logs {
-					notifier.Notify(rpcSub.ID, &log)
-				}
+				notify(logs)
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				logsSub.Unsubscribe()
 				return
@@ -271,6 +299,94 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 	return rpcSub, nil
 }
 
+// logsBackfill tracks the historical logs replayed to a subscription, so the
+// live logs overlapping with them can be deduplicated.
+type logsBackfill struct {
+	head      uint64                   // Number of the chain head when the history was retrieved
+	delivered map[common.Hash]struct{} // Blocks up to head with delivered logs
+	live      [][]*types.Log           // Live logs received while the history was retrieved
+}
+
+// backfillLogs retrieves the logs matching the criteria up to the current chain
+// head. The live logs arriving meanwhile are buffered to avoid blocking the
+// event system.
+func (api *PublicFilterAPI) backfillLogs(ctx context.Context, crit FilterCriteria, live chan []*types.Log) ([]*types.Log, *logsBackfill, error) {
+	var (
+		backfill = &logsBackfill{delivered: make(map[common.Hash]struct{})}
+		quit     = make(chan struct{})
+		done     = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case logs := <-live:
+				backfill.live = append(backfill.live, logs)
+			case <-quit:
+				return
+			}
+		}
+	}()
+	defer func() {
+		close(quit)
+		<-done
+	}()
+
+	header, err := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header == nil {
+		return nil, nil, errors.New("unknown head block")
+	}
+	backfill.head = header.Number.Uint64()
+
+	end := backfill.head
+	if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 && crit.ToBlock.Uint64() < end {
+		end = crit.ToBlock.Uint64()
+	}
+	if crit.FromBlock.Uint64() > end {
+		return nil, backfill, nil
+	}
+	logs, err := NewRangeFilter(api.backend, crit.FromBlock.Int64(), int64(end), crit.Addresses, crit.Topics).Logs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, log := range logs {
+		backfill.delivered[log.BlockHash] = struct{}{}
+	}
+	return logs, backfill, nil
+}
+
+// filter drops the live logs already replayed from the history, along with the
+// removals of logs that were never delivered.
+func (bf *logsBackfill) filter(logs []*types.Log) []*types.Log {
+	var (
+		ret     []*types.Log
+		touched = make(map[common.Hash]bool)
+	)
+	for _, log := range logs {
+		if log.BlockNumber > bf.head {
+			ret = append(ret, log)
+			continue
+		}
+		if _, known := bf.delivered[log.BlockHash]; known == log.Removed {
+			ret = append(ret, log)
+			touched[log.BlockHash] = log.Removed
+		}
+	}
+	// Update the delivered blocks only after the whole batch, since it may hold
+	// multiple logs of the same block
+	for hash, removed := range touched {
+		if removed {
+			delete(bf.delivered, hash)
+		} else {
+			bf.delivered[hash] = struct{}{}
+		}
+	}
+	return ret
+}
+
 // FilterCriteria represents a request to create a new filter.
 // Same as PaloAltoAi.FilterQuery but with UnmarshalJSON() method.
 type FilterCriteria PaloAltoAi.FilterQuery
@@ -19,6 +19,7 @@ package filters
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
@@ -45,6 +46,9 @@ type Backend interface {
 
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
+
+	RPCLogsLimit() int         // Maximum number of logs returned by a query, 0 if unlimited
+	RPCLogsBlockRange() uint64 // Maximum number of blocks a query may span, 0 if unlimited
 }
 
 // Filter can be used to retrieve and filter logs.
@@ -59,6 +63,9 @@ type Filter struct {
 	begin, end int64       // Range interval if filtering multiple blocks
 
 	matcher *bloombits.Matcher
+
+	limit      int    // Maximum number of logs to return, 0 if unlimited
+	blockRange uint64 // Maximum number of blocks to search, 0 if unlimited
 }
 
 // NewRangeFilter creates a new filter which uses a bloom filter on blocks to
@@ -107,10 +114,12 @@ func NewBlockFilter(backend Backend, block common.Hash, addresses []common.Addre
 // or based on range queries. The search criteria needs to be explicitly set.
 func newFilter(backend Backend, addresses []common.Address, topics [][]common.Hash) *Filter {
 	return &Filter{
-		backend:   backend,
-		addresses: addresses,
-		topics:    topics,
-		db:        backend.ChainDb(),
+		backend:    backend,
+		addresses:  addresses,
+		topics:     topics,
+		db:         backend.ChainDb(),
+		limit:      backend.RPCLogsLimit(),
+		blockRange: backend.RPCLogsBlockRange(),
 	}
 }
 
@@ -142,6 +151,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end == -1 {
 		end = head
 	}
+	if f.blockRange > 0 && end >= uint64(f.begin) && end-uint64(f.begin) >= f.blockRange {
+		return nil, fmt.Errorf("query spans more than %d blocks", f.blockRange)
+	}
 	// Gather all indexed logs, and finish with non indexed ones
 	var (
 		logs []*types.Log
@@ -160,9 +172,20 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	}
 	rest, err := f.unindexedLogs(ctx, end)
 	logs = append(logs, rest...)
+	if err == nil {
+		err = f.checkLimit(logs)
+	}
 	return logs, err
 }
 
+// checkLimit returns an error if the logs gathered exceed the result limit.
+func (f *Filter) checkLimit(logs []*types.Log) error {
+	if f.limit > 0 && len(logs) > f.limit {
+		return fmt.Errorf("query returned more than %d results", f.limit)
+	}
+	return nil
+}
+
 // indexedLogs returns the logs matching the filter criteria based on the bloom
 // bits indexed available locally or via the network.
 func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
@@ -203,6 +226,9 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 				return logs, err
 			}
 			logs = append(logs, found...)
+			if err := f.checkLimit(logs); err != nil {
+				return nil, err
+			}
 
 		case <-ctx.Done():
 			return logs, ctx.Err()
@@ -225,6 +251,9 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, e
 			return logs, err
 		}
 		logs = append(logs, found...)
+		if err := f.checkLimit(logs); err != nil {
+			return nil, err
+		}
 	}
 	return logs, nil
 }
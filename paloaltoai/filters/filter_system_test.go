@@ -27,6 +27,7 @@ import (
 
 	PaloAltoAi "github.com/PaloAltoAi/go-PaloAltoAi"
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/paaash"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/bloombits"
@@ -124,6 +125,14 @@ func (b *testBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, b.sections
 }
 
+func (b *testBackend) RPCLogsLimit() int {
+	return 0
+}
+
+func (b *testBackend) RPCLogsBlockRange() uint64 {
+	return 0
+}
+
 func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	requests := make(chan chan *bloombits.Retrieval)
 
@@ -614,3 +623,99 @@ func TestPendingLogsSubscription(t *testing.T) {
 		}
 	}
 }
+
+// TestLogsSubscriptionBackfill tests that a log subscription with a starting
+// block first replays the matching logs of the chain, then switches over to
+// the live ones without duplicating the already delivered blocks.
+func TestLogsSubscriptionBackfill(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db         = paadb.NewMemDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+
+		addrs = []common.Address{
+			common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			common.HexToAddress("0x3333333333333333333333333333333333333333"),
+		}
+	)
+	// Generate a chain with a log in every block, cycling through the addresses
+	genesis := core.GenesisBlockForTesting(db, addrs[0], big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, paaash.NewFaker(), db, 6, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: addrs[i%len(addrs)], Topics: []common.Hash{}, Data: []byte{}}}
+		gen.AddUncheckedReceipt(receipt)
+	})
+	var chainLogs []*types.Log
+	for i, block := range chain {
+		for _, log := range receipts[i][0].Logs {
+			log.BlockNumber, log.BlockHash = block.NumberU64(), block.Hash()
+			chainLogs = append(chainLogs, log)
+		}
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	// Subscribe to the first two addresses from block 2 onwards
+	server := rpc.NewServer()
+	if err := server.RegisterName("paa", api); err != nil {
+		t.Fatalf("failed to register filter api: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan *types.Log)
+	crit := map[string]interface{}{
+		"fromBlock": "0x2",
+		"address":   []string{hexutil.Encode(addrs[0][:]), hexutil.Encode(addrs[1][:])},
+	}
+	sub, err := client.Subscribe(context.Background(), "paa", logs, "logs", crit)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	// Blocks 2, 4 and 5 match from the chain, post a duplicate of block 5, a new
+	// block 7, the removal of block 5 and the removal of an unknown block
+	var (
+		newLog     = &types.Log{Address: addrs[0], Topics: []common.Hash{}, Data: []byte{}, BlockNumber: 7, BlockHash: common.HexToHash("0x07")}
+		removedLog = *chainLogs[4]
+		unknownLog = &types.Log{Address: addrs[0], Topics: []common.Hash{}, Data: []byte{}, BlockNumber: 4, BlockHash: common.HexToHash("0x04"), Removed: true}
+	)
+	removedLog.Removed = true
+
+	expect := func(expected ...*types.Log) {
+		for i, want := range expected {
+			select {
+			case have := <-logs:
+				if have.Address != want.Address || have.BlockNumber != want.BlockNumber || have.BlockHash != want.BlockHash || have.Removed != want.Removed {
+					t.Fatalf("log %d mismatch: have %+v, want %+v", i, have, want)
+				}
+			case err := <-sub.Err():
+				t.Fatalf("subscription failed: %v", err)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for log %d", i)
+			}
+		}
+	}
+	logsFeed.Send([]*types.Log{chainLogs[4]})
+	logsFeed.Send([]*types.Log{newLog})
+	expect(chainLogs[1], chainLogs[3], chainLogs[4], newLog)
+
+	rmLogsFeed.Send(core.RemovedLogsEvent{Logs: []*types.Log{unknownLog, &removedLog}})
+	expect(&removedLog)
+
+	select {
+	case have := <-logs:
+		t.Fatalf("unexpected log: %+v", have)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
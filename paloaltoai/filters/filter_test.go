@@ -230,3 +230,55 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+// Tests that queries exceeding the result or block range limits are rejected.
+func TestFilterLimits(t *testing.T) {
+	var (
+		db         = paadb.NewMemDatabase()
+		mux        = new(event.TypeMux)
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		key1, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr       = crypto.PubkeyToAddress(key1.PublicKey)
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, paaash.NewFaker(), db, 10, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: addr}}
+		gen.AddUncheckedReceipt(receipt)
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	tests := []struct {
+		begin, end int64
+		limit      int
+		blockRange uint64
+		fail       bool
+	}{
+		{begin: 1, end: 10, limit: 10},
+		{begin: 1, end: 10, limit: 9, fail: true},
+		{begin: 1, end: 10, blockRange: 10},
+		{begin: 1, end: 10, blockRange: 9, fail: true},
+		{begin: 0, end: -1, blockRange: 11},
+		{begin: 0, end: -1, blockRange: 10, fail: true},
+	}
+	for i, tt := range tests {
+		filter := NewRangeFilter(backend, tt.begin, tt.end, []common.Address{addr}, nil)
+		filter.limit, filter.blockRange = tt.limit, tt.blockRange
+
+		logs, err := filter.Logs(context.Background())
+		if tt.fail && err == nil {
+			t.Errorf("test %d: query exceeding limits succeeded with %d logs", i, len(logs))
+		}
+		if !tt.fail && (err != nil || len(logs) != 10) {
+			t.Errorf("test %d: query failed: %d logs, err %v", i, len(logs), err)
+		}
+	}
+}
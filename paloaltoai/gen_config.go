@@ -42,8 +42,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Paaash                  paaash.Config
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
+		RPCGasCap               uint64 `toml:",omitempty"`
+		RPCEVMTimeout           time.Duration
+		RPCLogsLimit            int    `toml:",omitempty"`
+		RPCLogsBlockRange       uint64 `toml:",omitempty"`
 		EnablePreimageRecording bool
 		DocRoot                 string `toml:"-"`
+		DevMode                 bool   `toml:"-"`
 		EWASMInterpreter        string
 		EVMInterpreter          string
 	}
@@ -71,8 +76,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Paaash = c.Paaash
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
+	enc.RPCGasCap = c.RPCGasCap
+	enc.RPCEVMTimeout = c.RPCEVMTimeout
+	enc.RPCLogsLimit = c.RPCLogsLimit
+	enc.RPCLogsBlockRange = c.RPCLogsBlockRange
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.DocRoot = c.DocRoot
+	enc.DevMode = c.DevMode
 	enc.EWASMInterpreter = c.EWASMInterpreter
 	enc.EVMInterpreter = c.EVMInterpreter
 	return &enc, nil
@@ -104,8 +114,13 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Paaash                  *paaash.Config
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
+		RPCGasCap               *uint64 `toml:",omitempty"`
+		RPCEVMTimeout           *time.Duration
+		RPCLogsLimit            *int    `toml:",omitempty"`
+		RPCLogsBlockRange       *uint64 `toml:",omitempty"`
 		EnablePreimageRecording *bool
 		DocRoot                 *string `toml:"-"`
+		DevMode                 *bool   `toml:"-"`
 		EWASMInterpreter        *string
 		EVMInterpreter          *string
 	}
@@ -182,12 +197,27 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.GPO != nil {
 		c.GPO = *dec.GPO
 	}
+	if dec.RPCGasCap != nil {
+		c.RPCGasCap = *dec.RPCGasCap
+	}
+	if dec.RPCEVMTimeout != nil {
+		c.RPCEVMTimeout = *dec.RPCEVMTimeout
+	}
+	if dec.RPCLogsLimit != nil {
+		c.RPCLogsLimit = *dec.RPCLogsLimit
+	}
+	if dec.RPCLogsBlockRange != nil {
+		c.RPCLogsBlockRange = *dec.RPCLogsBlockRange
+	}
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
+	if dec.DevMode != nil {
+		c.DevMode = *dec.DevMode
+	}
 	if dec.EWASMInterpreter != nil {
 		c.EWASMInterpreter = *dec.EWASMInterpreter
 	}
@@ -30,6 +30,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/misc"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/forkid"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
 	"github.com/PaloAltoAi/go-PaloAltoAi/paa/downloader"
 	"github.com/PaloAltoAi/go-PaloAltoAi/paa/fetcher"
@@ -62,12 +63,24 @@ var (
 // not compatible (low protocol version restrictions and high requirements).
 var errIncompatibleConfig = errors.New("incompatible configuration")
 
+// protocolError is a breach of the protocol by the remote peer, such as sending
+// an invalid message, for which the peer's reputation is charged.
+type protocolError struct {
+	code errCode
+	msg  string
+}
+
+func (e *protocolError) Error() string {
+	return fmt.Sprintf("%v - %v", e.code, e.msg)
+}
+
 func errResp(code errCode, format string, v ...interface{}) error {
-	return fmt.Errorf("%v - %v", code, fmt.Sprintf(format, v...))
+	return &protocolError{code: code, msg: fmt.Sprintf(format, v...)}
 }
 
 type ProtocolManager struct {
-	networkID uint64
+	networkID  uint64
+	forkFilter forkid.Filter // Fork ID filter, constant across the lifetime of the node
 
 	fastSync  uint32 // Flag whpaaer fast sync is enabled (gets disabled if we already have blocks)
 	acceptTxs uint32 // Flag whpaaer we're considered synchronised (enables transaction processing)
@@ -79,6 +92,7 @@ type ProtocolManager struct {
 
 	downloader *downloader.Downloader
 	fetcher    *fetcher.Fetcher
+	txFetcher  *fetcher.TxFetcher
 	peers      *peerSet
 
 	SubProtocols []p2p.Protocol
@@ -107,6 +121,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 	// Create the protocol manager with the base fields
 	manager := &ProtocolManager{
 		networkID:   networkID,
+		forkFilter:  forkid.NewFilter(blockchain),
 		eventMux:    mux,
 		txpool:      txpool,
 		blockchain:  blockchain,
@@ -123,6 +138,12 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		log.Warn("Blockchain not empty, fast sync disabled")
 		mode = downloader.FullSync
 	}
+	// Checkpoints of contract governed clique networks can only be verified against
+	// the state, which fast sync skips
+	if mode == downloader.FastSync && config.Clique != nil && config.Clique.SignerContract != nil {
+		log.Warn("Clique signer contract in use, fast sync disabled")
+		mode = downloader.FullSync
+	}
 	if mode == downloader.FastSync {
 		manager.fastSync = uint32(1)
 	}
@@ -182,7 +203,19 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		atomic.StoreUint32(&manager.acceptTxs, 1) // Mark initial sync done on any fetcher import
 		return manager.blockchain.InsertChain(blocks)
 	}
-	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.BroadcastBlock, heighter, inserter, manager.removePeer)
+	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.BroadcastBlock, heighter, inserter, manager.removeViolatingPeer)
+
+	hasTx := func(hash common.Hash) bool {
+		return manager.txpool.Get(hash) != nil
+	}
+	fetchTxs := func(peer string, hashes []common.Hash) error {
+		p := manager.peers.Peer(peer)
+		if p == nil {
+			return errNotRegistered
+		}
+		return p.RequestTxs(hashes)
+	}
+	manager.txFetcher = fetcher.NewTxFetcher(hasTx, txpool.AddRemotes, fetchTxs)
 
 	return manager, nil
 }
@@ -195,8 +228,9 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 	log.Debug("Removing PaloAltoAi peer", "peer", id)
 
-	// Unregister the peer from the downloader and PaloAltoAi peer set
+	// Unregister the peer from the downloader, tx fetcher and PaloAltoAi peer set
 	pm.downloader.UnregisterPeer(id)
+	pm.txFetcher.Drop(id)
 	if err := pm.peers.Unregister(id); err != nil {
 		log.Error("Peer removal failed", "peer", id, "err", err)
 	}
@@ -206,6 +240,15 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 }
 
+// removeViolatingPeer charges the reputation of a peer caught delivering invalid
+// data (e.g. by the block fetcher) before disconnecting it.
+func (pm *ProtocolManager) removeViolatingPeer(id string) {
+	if peer := pm.peers.Peer(id); peer != nil {
+		peer.RecordViolation()
+	}
+	pm.removePeer(id)
+}
+
 func (pm *ProtocolManager) Start(maxPeers int) {
 	pm.maxPeers = maxPeers
 
@@ -269,7 +312,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 		number  = head.Number.Uint64()
 		td      = pm.blockchain.GetTd(hash, number)
 	)
-	if err := p.Handshake(pm.networkID, td, hash, genesis.Hash()); err != nil {
+	if err := p.Handshake(pm.networkID, td, hash, genesis.Hash(), forkid.NewID(pm.blockchain), pm.forkFilter); err != nil {
 		p.Log().Debug("PaloAltoAi handshake failed", "err", err)
 		return err
 	}
@@ -294,12 +337,13 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	// If we're DAO hard-fork aware, validate any remote peer with regard to the hard-fork
 	if daoBlock := pm.chainconfig.DAOForkBlock; daoBlock != nil {
 		// Request the peer's DAO fork header for extra-data validation
-		if err := p.RequestHeadersByNumber(daoBlock.Uint64(), 1, 0, false); err != nil {
+		if err := p.RequestCheckHeader(daoBlock.Uint64()); err != nil {
 			return err
 		}
 		// Start a timer to disconnect if the peer doesn't reply in time
 		p.forkDrop = time.AfterFunc(daoChallengeTimeout, func() {
 			p.Log().Debug("Timed out DAO fork-check, dropping")
+			p.RecordTimeout()
 			pm.removePeer(p.id)
 		})
 		// Make sure it's cleaned up if the peer dies off
@@ -312,7 +356,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 	// If we have any explicit whitelist block hashes, request them
 	for number := range pm.whitelist {
-		if err := p.RequestHeadersByNumber(number, 1, 0, false); err != nil {
+		if err := p.RequestCheckHeader(number); err != nil {
 			return err
 		}
 	}
@@ -320,6 +364,9 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	for {
 		if err := pm.handleMsg(p); err != nil {
 			p.Log().Debug("PaloAltoAi message handling failed", "err", err)
+			if _, ok := err.(*protocolError); ok {
+				p.RecordViolation()
+			}
 			return err
 		}
 	}
@@ -347,8 +394,20 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	// Block header query, collect the requested headers and reply
 	case msg.Code == GetBlockHeadersMsg:
 		// Decode the complex header query
-		var query getBlockHeadersData
-		if err := msg.Decode(&query); err != nil {
+		var (
+			query getBlockHeadersData
+			reqID uint64
+		)
+		if p.version >= paa66 {
+			var packet getBlockHeadersPacket66
+			if err := msg.Decode(&packet); err != nil {
+				return errResp(ErrDecode, "%v: %v", msg, err)
+			}
+			if packet.Query == nil {
+				return errResp(ErrDecode, "%v: missing header query", msg)
+			}
+			query, reqID = *packet.Query, packet.RequestId
+		} else if err := msg.Decode(&query); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
 		hashMode := query.Origin.Hash != (common.Hash{})
@@ -430,16 +489,34 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				query.Origin.Number += query.Skip + 1
 			}
 		}
-		return p.SendBlockHeaders(headers)
+		return p.ReplyBlockHeaders(reqID, headers)
 
 	case msg.Code == BlockHeadersMsg:
 		// A batch of headers arrived to one of our previous requests
-		var headers []*types.Header
-		if err := msg.Decode(&headers); err != nil {
+		var (
+			headers []*types.Header
+			owner   = ownerUnknown
+			reqID   uint64 // Untagged replies (before paa/66) match request ID zero
+		)
+		if p.version >= paa66 {
+			var packet blockHeadersPacket66
+			if err := msg.Decode(&packet); err != nil {
+				return errResp(ErrDecode, "msg %v: %v", msg, err)
+			}
+			var ok bool
+			if owner, ok = p.claimRequest(packet.RequestId, BlockHeadersMsg); !ok {
+				p.Log().Debug("Dropping unsolicited headers", "id", packet.RequestId, "count", len(packet.Headers))
+				return nil
+			}
+			headers, reqID = packet.Headers, packet.RequestId
+		} else if err := msg.Decode(&headers); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		if p.version < paa66 {
+			p.claimUntagged(BlockHeadersMsg)
+		}
 		// If no headers were received, but we're expending a DAO fork check, maybe it's that
-		if len(headers) == 0 && p.forkDrop != nil {
+		if len(headers) == 0 && p.forkDrop != nil && (owner == ownerUnknown || owner == ownerHandler) {
 			// Possibly an empty reply to the fork header checks, sanity check TDs
 			verifyDAO := true
 
@@ -458,8 +535,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				return nil
 			}
 		}
-		// Filter out any explicitly requested headers, deliver the rest to the downloader
-		filter := len(headers) == 1
+		// Filter out any explicitly requested headers, deliver the rest to the downloader.
+		// Tagged replies are known to belong to the downloader, skip the filter.
+		filter := len(headers) == 1 && owner != ownerDownloader
 		if filter {
 			// If it's a potential DAO fork check, validate against the rules
 			if p.forkDrop != nil && pm.chainconfig.DAOForkBlock.Cmp(headers[0].Number) == 0 {
@@ -483,11 +561,15 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				}
 				p.Log().Debug("Whitelist block verified", "number", headers[0].Number.Uint64(), "hash", want)
 			}
+			// Replies to the checks above are of no use to anyone else
+			if owner == ownerHandler {
+				return nil
+			}
 			// Irrelevant of the fork checks, send the header to the fetcher just in case
 			headers = pm.fetcher.FilterHeaders(p.id, headers, time.Now())
 		}
-		if len(headers) > 0 || !filter {
-			err := pm.downloader.DeliverHeaders(p.id, headers)
+		if (len(headers) > 0 || !filter) && (owner == ownerUnknown || owner == ownerDownloader) {
+			err := pm.downloader.DeliverHeaders(p.id, reqID, headers)
 			if err != nil {
 				log.Debug("Failed to deliver headers", "err", err)
 			}
@@ -495,8 +577,8 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 	case msg.Code == GetBlockBodiesMsg:
 		// Decode the retrieval message
-		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
-		if _, err := msgStream.List(); err != nil {
+		msgStream, reqID, err := openHashesStream(p, msg)
+		if err != nil {
 			return err
 		}
 		// Gather blocks until the fetch or network limits is reached
@@ -518,14 +600,32 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				bytes += len(data)
 			}
 		}
-		return p.SendBlockBodiesRLP(bodies)
+		return p.ReplyBlockBodiesRLP(reqID, bodies)
 
 	case msg.Code == BlockBodiesMsg:
 		// A batch of block bodies arrived to one of our previous requests
-		var request blockBodiesData
-		if err := msg.Decode(&request); err != nil {
+		var (
+			request blockBodiesData
+			owner   = ownerUnknown
+			reqID   uint64
+		)
+		if p.version >= paa66 {
+			var packet blockBodiesPacket66
+			if err := msg.Decode(&packet); err != nil {
+				return errResp(ErrDecode, "msg %v: %v", msg, err)
+			}
+			var ok bool
+			if owner, ok = p.claimRequest(packet.RequestId, BlockBodiesMsg); !ok {
+				p.Log().Debug("Dropping unsolicited block bodies", "id", packet.RequestId, "count", len(packet.Bodies))
+				return nil
+			}
+			request, reqID = packet.Bodies, packet.RequestId
+		} else if err := msg.Decode(&request); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		if p.version < paa66 {
+			p.claimUntagged(BlockBodiesMsg)
+		}
 		// Deliver them all to the downloader for queuing
 		transactions := make([][]*types.Transaction, len(request))
 		uncles := make([][]*types.Header, len(request))
@@ -534,13 +634,14 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			transactions[i] = body.Transactions
 			uncles[i] = body.Uncles
 		}
-		// Filter out any explicitly requested bodies, deliver the rest to the downloader
-		filter := len(transactions) > 0 || len(uncles) > 0
+		// Filter out any explicitly requested bodies, deliver the rest to the downloader.
+		// Tagged replies are known to belong to the downloader, skip the filter.
+		filter := (len(transactions) > 0 || len(uncles) > 0) && owner != ownerDownloader
 		if filter {
 			transactions, uncles = pm.fetcher.FilterBodies(p.id, transactions, uncles, time.Now())
 		}
-		if len(transactions) > 0 || len(uncles) > 0 || !filter {
-			err := pm.downloader.DeliverBodies(p.id, transactions, uncles)
+		if (len(transactions) > 0 || len(uncles) > 0 || !filter) && owner != ownerFetcher {
+			err := pm.downloader.DeliverBodies(p.id, reqID, transactions, uncles)
 			if err != nil {
 				log.Debug("Failed to deliver bodies", "err", err)
 			}
@@ -548,8 +649,8 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 	case p.version >= paa63 && msg.Code == GetNodeDataMsg:
 		// Decode the retrieval message
-		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
-		if _, err := msgStream.List(); err != nil {
+		msgStream, reqID, err := openHashesStream(p, msg)
+		if err != nil {
 			return err
 		}
 		// Gather state data until the fetch or network limits is reached
@@ -571,23 +672,39 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				bytes += len(entry)
 			}
 		}
-		return p.SendNodeData(data)
+		return p.ReplyNodeData(reqID, data)
 
 	case p.version >= paa63 && msg.Code == NodeDataMsg:
 		// A batch of node state data arrived to one of our previous requests
-		var data [][]byte
-		if err := msg.Decode(&data); err != nil {
+		var (
+			data  [][]byte
+			reqID uint64
+		)
+		if p.version >= paa66 {
+			var packet nodeDataPacket66
+			if err := msg.Decode(&packet); err != nil {
+				return errResp(ErrDecode, "msg %v: %v", msg, err)
+			}
+			if _, ok := p.claimRequest(packet.RequestId, NodeDataMsg); !ok {
+				p.Log().Debug("Dropping unsolicited node data", "id", packet.RequestId, "count", len(packet.Data))
+				return nil
+			}
+			data, reqID = packet.Data, packet.RequestId
+		} else if err := msg.Decode(&data); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		if p.version < paa66 {
+			p.claimUntagged(NodeDataMsg)
+		}
 		// Deliver all to the downloader
-		if err := pm.downloader.DeliverNodeData(p.id, data); err != nil {
+		if err := pm.downloader.DeliverNodeData(p.id, reqID, data); err != nil {
 			log.Debug("Failed to deliver node state data", "err", err)
 		}
 
 	case p.version >= paa63 && msg.Code == GetReceiptsMsg:
 		// Decode the retrieval message
-		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
-		if _, err := msgStream.List(); err != nil {
+		msgStream, reqID, err := openHashesStream(p, msg)
+		if err != nil {
 			return err
 		}
 		// Gather state data until the fetch or network limits is reached
@@ -618,16 +735,32 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				bytes += len(encoded)
 			}
 		}
-		return p.SendReceiptsRLP(receipts)
+		return p.ReplyReceiptsRLP(reqID, receipts)
 
 	case p.version >= paa63 && msg.Code == ReceiptsMsg:
 		// A batch of receipts arrived to one of our previous requests
-		var receipts [][]*types.Receipt
-		if err := msg.Decode(&receipts); err != nil {
+		var (
+			receipts [][]*types.Receipt
+			reqID    uint64
+		)
+		if p.version >= paa66 {
+			var packet receiptsPacket66
+			if err := msg.Decode(&packet); err != nil {
+				return errResp(ErrDecode, "msg %v: %v", msg, err)
+			}
+			if _, ok := p.claimRequest(packet.RequestId, ReceiptsMsg); !ok {
+				p.Log().Debug("Dropping unsolicited receipts", "id", packet.RequestId, "count", len(packet.Receipts))
+				return nil
+			}
+			receipts, reqID = packet.Receipts, packet.RequestId
+		} else if err := msg.Decode(&receipts); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		if p.version < paa66 {
+			p.claimUntagged(ReceiptsMsg)
+		}
 		// Deliver all to the downloader
-		if err := pm.downloader.DeliverReceipts(p.id, receipts); err != nil {
+		if err := pm.downloader.DeliverReceipts(p.id, reqID, receipts); err != nil {
 			log.Debug("Failed to deliver receipts", "err", err)
 		}
 
@@ -648,7 +781,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			}
 		}
 		for _, block := range unknown {
-			pm.fetcher.Notify(p.id, block.Hash, block.Number, time.Now(), p.RequestOneHeader, p.RequestBodies)
+			pm.fetcher.Notify(p.id, block.Hash, block.Number, time.Now(), p.RequestOneHeader, p.RequestAnnouncedBodies)
 		}
 
 	case msg.Code == NewBlockMsg:
@@ -683,7 +816,59 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			}
 		}
 
-	case msg.Code == TxMsg:
+	case p.version >= paa65 && msg.Code == NewPooledTransactionHashesMsg:
+		// New transaction announcement arrived, make sure we have a valid and fresh
+		// chain to handle them
+		if atomic.LoadUint32(&pm.acceptTxs) == 0 {
+			break
+		}
+		var hashes []common.Hash
+		if err := msg.Decode(&hashes); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		// Schedule all the unknown hashes for retrieval
+		for _, hash := range hashes {
+			p.MarkTransaction(hash)
+		}
+		pm.txFetcher.Notify(p.id, hashes)
+
+	case p.version >= paa65 && msg.Code == GetPooledTransactionsMsg:
+		// Decode the retrieval message
+		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
+		if _, err := msgStream.List(); err != nil {
+			return err
+		}
+		// Gather transactions until the fetch or network limits is reached
+		var (
+			hash   common.Hash
+			bytes  int
+			hashes []common.Hash
+			txs    []rlp.RawValue
+		)
+		for bytes < softResponseLimit {
+			// Retrieve the hash of the next transaction
+			if err := msgStream.Decode(&hash); err == rlp.EOL {
+				break
+			} else if err != nil {
+				return errResp(ErrDecode, "msg %v: %v", msg, err)
+			}
+			// Retrieve the requested transaction, skipping if unknown to us
+			tx := pm.txpool.Get(hash)
+			if tx == nil {
+				continue
+			}
+			// If known, encode and queue for response packet
+			if encoded, err := rlp.EncodeToBytes(tx); err != nil {
+				log.Error("Failed to encode transaction", "err", err)
+			} else {
+				hashes = append(hashes, hash)
+				txs = append(txs, encoded)
+				bytes += len(encoded)
+			}
+		}
+		return p.SendPooledTransactionsRLP(hashes, txs)
+
+	case msg.Code == TxMsg || (p.version >= paa65 && msg.Code == PooledTransactionsMsg):
 		// Transactions arrived, make sure we have a valid and fresh chain to handle them
 		if atomic.LoadUint32(&pm.acceptTxs) == 0 {
 			break
@@ -700,7 +885,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			}
 			p.MarkTransaction(tx.Hash())
 		}
-		pm.txpool.AddRemotes(txs)
+		pm.txFetcher.Enqueue(p.id, txs, msg.Code == PooledTransactionsMsg)
 
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
@@ -708,6 +893,26 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	return nil
 }
 
+// openHashesStream opens the hash list of a retrieval request for streaming,
+// returning the request ID it was tagged with on paa/66 and later.
+func openHashesStream(p *peer, msg p2p.Msg) (*rlp.Stream, uint64, error) {
+	msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
+	if _, err := msgStream.List(); err != nil {
+		return nil, 0, err
+	}
+	if p.version < paa66 {
+		return msgStream, 0, nil
+	}
+	id, err := msgStream.Uint()
+	if err != nil {
+		return nil, 0, errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	if _, err := msgStream.List(); err != nil {
+		return nil, 0, err
+	}
+	return msgStream, id, nil
+}
+
 // BroadcastBlock will either propagate a block to a subset of it's peers, or
 // will only announce it's availability (depending what's requested).
 func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
@@ -748,23 +953,41 @@ func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
 	}
 }
 
-// BroadcastTxs will propagate a batch of transactions to all peers which are not known to
-// already have the given transaction.
+// BroadcastTxs will propagate a batch of transactions to a subset of the peers
+// which are not known to already have the given transaction, and announce the
+// transaction hashes to the rest of the paa/65 peers. Peers on older protocol
+// versions can't retrieve announced transactions, so they get all of them.
 func (pm *ProtocolManager) BroadcastTxs(txs types.Transactions) {
-	var txset = make(map[*peer]types.Transactions)
-
+	var (
+		txset  = make(map[*peer]types.Transactions)
+		annset = make(map[*peer][]common.Hash)
+	)
 	// Broadcast transactions to a batch of peers not knowing about it
 	for _, tx := range txs {
 		peers := pm.peers.PeersWithoutTx(tx.Hash())
+
+		transferLen := int(math.Sqrt(float64(len(peers))))
+		if transferLen < minBroadcastPeers {
+			transferLen = minBroadcastPeers
+		}
+		var direct, announced int
 		for _, peer := range peers {
-			txset[peer] = append(txset[peer], tx)
+			if peer.version < paa65 || direct < transferLen {
+				txset[peer] = append(txset[peer], tx)
+				direct++
+				continue
+			}
+			annset[peer] = append(annset[peer], tx.Hash())
+			announced++
 		}
-		log.Trace("Broadcast transaction", "hash", tx.Hash(), "recipients", len(peers))
+		log.Trace("Broadcast transaction", "hash", tx.Hash(), "recipients", direct, "announced", announced)
 	}
-	// FIXME include this again: peers = peers[:int(math.Sqrt(float64(len(peers))))]
 	for peer, txs := range txset {
 		peer.AsyncSendTransactions(txs)
 	}
+	for peer, hashes := range annset {
+		peer.AsyncSendPooledTransactionHashes(hashes)
+	}
 }
 
 // Mined broadcast loop
@@ -70,6 +70,9 @@ func TestProtocolCompatibility(t *testing.T) {
 // Tests that block headers can be retrieved from a remote chain based on user queries.
 func TestGetBlockHeaders62(t *testing.T) { testGetBlockHeaders(t, 62) }
 func TestGetBlockHeaders63(t *testing.T) { testGetBlockHeaders(t, 63) }
+func TestGetBlockHeaders64(t *testing.T) { testGetBlockHeaders(t, 64) }
+func TestGetBlockHeaders65(t *testing.T) { testGetBlockHeaders(t, 65) }
+func TestGetBlockHeaders66(t *testing.T) { testGetBlockHeaders(t, 66) }
 
 func testGetBlockHeaders(t *testing.T, protocol int) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, downloader.MaxHashFetch+15, nil, nil)
@@ -208,8 +211,8 @@ func testGetBlockHeaders(t *testing.T, protocol int) {
 			headers = append(headers, pm.blockchain.GetBlockByHash(hash).Header())
 		}
 		// Send the hash request and verify the response
-		p2p.Send(peer.app, 0x03, tt.query)
-		if err := p2p.ExpectMsg(peer.app, 0x04, headers); err != nil {
+		peer.sendRequest(0x03, uint64(i), tt.query)
+		if err := peer.expectReply(0x04, uint64(i), headers); err != nil {
 			t.Errorf("test %d: headers mismatch: %v", i, err)
 		}
 		// If the test used number origins, repeat with hashes as the too
@@ -217,8 +220,8 @@ func testGetBlockHeaders(t *testing.T, protocol int) {
 			if origin := pm.blockchain.GetBlockByNumber(tt.query.Origin.Number); origin != nil {
 				tt.query.Origin.Hash, tt.query.Origin.Number = origin.Hash(), 0
 
-				p2p.Send(peer.app, 0x03, tt.query)
-				if err := p2p.ExpectMsg(peer.app, 0x04, headers); err != nil {
+				peer.sendRequest(0x03, uint64(i), tt.query)
+				if err := peer.expectReply(0x04, uint64(i), headers); err != nil {
 					t.Errorf("test %d: headers mismatch: %v", i, err)
 				}
 			}
@@ -229,6 +232,9 @@ func testGetBlockHeaders(t *testing.T, protocol int) {
 // Tests that block contents can be retrieved from a remote chain based on their hashes.
 func TestGetBlockBodies62(t *testing.T) { testGetBlockBodies(t, 62) }
 func TestGetBlockBodies63(t *testing.T) { testGetBlockBodies(t, 63) }
+func TestGetBlockBodies64(t *testing.T) { testGetBlockBodies(t, 64) }
+func TestGetBlockBodies65(t *testing.T) { testGetBlockBodies(t, 65) }
+func TestGetBlockBodies66(t *testing.T) { testGetBlockBodies(t, 66) }
 
 func testGetBlockBodies(t *testing.T, protocol int) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, downloader.MaxBlockFetch+15, nil, nil)
@@ -291,8 +297,8 @@ func testGetBlockBodies(t *testing.T, protocol int) {
 			}
 		}
 		// Send the hash request and verify the response
-		p2p.Send(peer.app, 0x05, hashes)
-		if err := p2p.ExpectMsg(peer.app, 0x06, bodies); err != nil {
+		peer.sendRequest(0x05, uint64(i), hashes)
+		if err := peer.expectReply(0x06, uint64(i), bodies); err != nil {
 			t.Errorf("test %d: bodies mismatch: %v", i, err)
 		}
 	}
@@ -300,6 +306,9 @@ func testGetBlockBodies(t *testing.T, protocol int) {
 
 // Tests that the node state database can be retrieved based on hashes.
 func TestGetNodeData63(t *testing.T) { testGetNodeData(t, 63) }
+func TestGetNodeData64(t *testing.T) { testGetNodeData(t, 64) }
+func TestGetNodeData65(t *testing.T) { testGetNodeData(t, 65) }
+func TestGetNodeData66(t *testing.T) { testGetNodeData(t, 66) }
 
 func testGetNodeData(t *testing.T, protocol int) {
 	// Define three accounts to simulate transactions with
@@ -349,7 +358,7 @@ func testGetNodeData(t *testing.T, protocol int) {
 			hashes = append(hashes, common.BytesToHash(key))
 		}
 	}
-	p2p.Send(peer.app, 0x0d, hashes)
+	peer.sendRequest(0x0d, 1, hashes)
 	msg, err := peer.app.ReadMsg()
 	if err != nil {
 		t.Fatalf("failed to read node data response: %v", err)
@@ -358,7 +367,16 @@ func testGetNodeData(t *testing.T, protocol int) {
 		t.Fatalf("response packet code mismatch: have %x, want %x", msg.Code, 0x0c)
 	}
 	var data [][]byte
-	if err := msg.Decode(&data); err != nil {
+	if protocol >= paa66 {
+		var packet nodeDataPacket66
+		if err := msg.Decode(&packet); err != nil {
+			t.Fatalf("failed to decode response node data: %v", err)
+		}
+		if packet.RequestId != 1 {
+			t.Fatalf("response request id mismatch: have %d, want %d", packet.RequestId, 1)
+		}
+		data = packet.Data
+	} else if err := msg.Decode(&data); err != nil {
 		t.Fatalf("failed to decode response node data: %v", err)
 	}
 	// Verify that all hashes correspond to the requested data, and reconstruct a state tree
@@ -392,6 +410,9 @@ func testGetNodeData(t *testing.T, protocol int) {
 
 // Tests that the transaction receipts can be retrieved based on hashes.
 func TestGetReceipt63(t *testing.T) { testGetReceipt(t, 63) }
+func TestGetReceipt64(t *testing.T) { testGetReceipt(t, 64) }
+func TestGetReceipt65(t *testing.T) { testGetReceipt(t, 65) }
+func TestGetReceipt66(t *testing.T) { testGetReceipt(t, 66) }
 
 func testGetReceipt(t *testing.T, protocol int) {
 	// Define three accounts to simulate transactions with
@@ -443,12 +464,130 @@ func testGetReceipt(t *testing.T, protocol int) {
 		receipts = append(receipts, pm.blockchain.GetReceiptsByHash(block.Hash()))
 	}
 	// Send the hash request and verify the response
-	p2p.Send(peer.app, 0x0f, hashes)
-	if err := p2p.ExpectMsg(peer.app, 0x10, receipts); err != nil {
+	peer.sendRequest(0x0f, 1, hashes)
+	if err := peer.expectReply(0x10, 1, receipts); err != nil {
 		t.Errorf("receipts mismatch: %v", err)
 	}
 }
 
+// Tests that pooled transactions can be retrieved based on hashes.
+func TestGetPooledTransactions65(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+	peer, _ := newTestPeer("peer", 65, pm, true)
+	defer peer.close()
+
+	txs := make([]*types.Transaction, 3)
+	for nonce := range txs {
+		txs[nonce] = newTestTransaction(testAccount, uint64(nonce), 0)
+	}
+	pm.txpool.AddRemotes(txs[:2])
+
+	// Request both known and unknown transactions, only the known ones are returned
+	hashes := []common.Hash{txs[0].Hash(), txs[2].Hash(), txs[1].Hash()}
+	p2p.Send(peer.app, GetPooledTransactionsMsg, hashes)
+	if err := p2p.ExpectMsg(peer.app, PooledTransactionsMsg, []*types.Transaction{txs[0], txs[1]}); err != nil {
+		t.Errorf("pooled transactions mismatch: %v", err)
+	}
+}
+
+// Tests that paa/66 requests are tagged with request IDs, and that replies are
+// only accepted for requests still in flight.
+func TestRequestTracking66(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+	peer, _ := newTestPeer("peer", 66, pm, true)
+	defer pm.Stop()
+	defer peer.close()
+
+	// request issues a header query through the given method and returns the
+	// request ID it was tagged with.
+	request := func(send func() error) uint64 {
+		errc := make(chan error, 1)
+		go func() { errc <- send() }()
+
+		msg, err := peer.app.ReadMsg()
+		if err != nil {
+			t.Fatalf("failed to read request: %v", err)
+		}
+		defer msg.Discard()
+		if msg.Code != GetBlockHeadersMsg {
+			t.Fatalf("request packet code mismatch: have %x, want %x", msg.Code, GetBlockHeadersMsg)
+		}
+		var packet getBlockHeadersPacket66
+		if err := msg.Decode(&packet); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if err := <-errc; err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+		return packet.RequestId
+	}
+	first := request(func() error { return peer.RequestHeadersByNumber(100, 1, 1, 0, false) })
+	fetch := request(func() error { return peer.RequestOneHeader(common.Hash{1}) })
+	check := request(func() error { return peer.RequestCheckHeader(1) })
+	second := request(func() error { return peer.RequestHeadersByNumber(101, 2, 1, 0, false) })
+
+	if first != 100 || second != 101 {
+		t.Fatalf("downloader request ids not kept: have %d, %d, want 100, 101", first, second)
+	}
+	if fetch == check || fetch == first || fetch == second || check == first || check == second {
+		t.Fatalf("request ids not unique: %d, %d, %d, %d", first, fetch, check, second)
+	}
+	// Request IDs still awaiting a reply can't be reused
+	if err := peer.RequestHeadersByNumber(first, 3, 1, 0, false); err != errRequestInFlight {
+		t.Errorf("duplicate request id error mismatch: have %v, want %v", err, errRequestInFlight)
+	}
+	if _, ok := peer.claimRequest(second, BlockBodiesMsg); ok {
+		t.Errorf("reply accepted with mismatching code")
+	}
+	// Several downloader requests stay in flight at once
+	for _, want := range []struct {
+		id    uint64
+		owner requestOwner
+	}{{first, ownerDownloader}, {second, ownerDownloader}, {fetch, ownerFetcher}, {check, ownerHandler}} {
+		if owner, ok := peer.claimRequest(want.id, BlockHeadersMsg); !ok || owner != want.owner {
+			t.Errorf("request %d: claim mismatch: have %v/%v, want %v/true", want.id, owner, ok, want.owner)
+		}
+		if _, ok := peer.claimRequest(want.id, BlockHeadersMsg); ok {
+			t.Errorf("request %d: reply accepted twice", want.id)
+		}
+	}
+}
+
+// Tests that replies to untagged requests of peers before paa/66 are credited to
+// their reputation and latency statistics.
+func TestResponseCredit65(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+	peer, _ := newTestPeer("peer", 65, pm, true)
+	defer pm.Stop()
+	defer peer.close()
+
+	errc := make(chan error, 1)
+	go func() { errc <- peer.RequestHeadersByNumber(0, 1, 1, 0, false) }()
+
+	if err := p2p.ExpectMsg(peer.app, GetBlockHeadersMsg, &getBlockHeadersData{Origin: hashOrNumber{Number: 1}, Amount: 1}); err != nil {
+		t.Fatalf("request mismatch: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	if score := peer.Score(); score != 0 {
+		t.Fatalf("score credited before reply: have %d, want 0", score)
+	}
+	if err := p2p.Send(peer.app, BlockHeadersMsg, []*types.Header{}); err != nil {
+		t.Fatalf("failed to send reply: %v", err)
+	}
+	for i := 0; i < 100 && peer.Score() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if score := peer.Score(); score <= 0 {
+		t.Fatalf("score not credited: have %d, want > 0", score)
+	}
+	latency := peer.Stats().Protocols[ProtocolName].Latency[BlockHeadersMsg]
+	if latency == nil || latency.Responses != 1 {
+		t.Fatalf("latency not recorded: have %+v, want 1 response", latency)
+	}
+}
+
 // Tests that post paa protocol handshake, DAO fork-enabled clients also execute
 // a DAO "challenge" verifying each others' DAO fork headers to ensure they're on
 // compatible chains.
@@ -30,6 +30,7 @@ import (
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
 	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/paaash"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/forkid"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/vm"
 	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
@@ -108,6 +109,19 @@ func (p *testTxPool) AddRemotes(txs []*types.Transaction) []error {
 	return make([]error, len(txs))
 }
 
+// Get retrieves the transaction from the pool with the given hash.
+func (p *testTxPool) Get(hash common.Hash) *types.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	for _, tx := range p.pool {
+		if tx.Hash() == hash {
+			return tx
+		}
+	}
+	return nil
+}
+
 // Pending returns all the transactions known to the pool
 func (p *testTxPool) Pending() (map[common.Address]types.Transactions, error) {
 	p.lock.RLock()
@@ -171,20 +185,33 @@ func newTestPeer(name string, version int, pm *ProtocolManager, shake bool) (*te
 			head    = pm.blockchain.CurrentHeader()
 			td      = pm.blockchain.GetTd(head.Hash(), head.Number.Uint64())
 		)
-		tp.handshake(nil, td, head.Hash(), genesis.Hash())
+		tp.handshake(nil, td, head.Hash(), genesis.Hash(), forkid.NewID(pm.blockchain))
 	}
 	return tp, errc
 }
 
 // handshake simulates a trivial handshake that expects the same state from the
 // remote side as we are simulating locally.
-func (p *testPeer) handshake(t *testing.T, td *big.Int, head common.Hash, genesis common.Hash) {
-	msg := &statusData{
-		ProtocolVersion: uint32(p.version),
-		NetworkId:       DefaultConfig.NetworkId,
-		TD:              td,
-		CurrentBlock:    head,
-		GenesisBlock:    genesis,
+func (p *testPeer) handshake(t *testing.T, td *big.Int, head common.Hash, genesis common.Hash, forkID forkid.ID) {
+	var msg interface{}
+	switch {
+	case p.version >= paa64:
+		msg = &statusData64{
+			ProtocolVersion: uint32(p.version),
+			NetworkID:       DefaultConfig.NetworkId,
+			TD:              td,
+			Head:            head,
+			Genesis:         genesis,
+			ForkID:          forkID,
+		}
+	default:
+		msg = &statusData{
+			ProtocolVersion: uint32(p.version),
+			NetworkId:       DefaultConfig.NetworkId,
+			TD:              td,
+			CurrentBlock:    head,
+			GenesisBlock:    genesis,
+		}
 	}
 	if err := p2p.ExpectMsg(p.app, StatusMsg, msg); err != nil {
 		t.Fatalf("status recv: %v", err)
@@ -194,6 +221,24 @@ func (p *testPeer) handshake(t *testing.T, td *big.Int, head common.Hash, genesi
 	}
 }
 
+// sendRequest sends a retrieval request to the remote protocol manager, tagging
+// it with the given request ID on paa/66 and later.
+func (p *testPeer) sendRequest(code uint64, id uint64, data interface{}) error {
+	if p.version >= paa66 {
+		return p2p.Send(p.app, code, []interface{}{id, data})
+	}
+	return p2p.Send(p.app, code, data)
+}
+
+// expectReply reads the next message and checks that it's the reply with the
+// given content to the request tagged with the given ID.
+func (p *testPeer) expectReply(code uint64, id uint64, data interface{}) error {
+	if p.version >= paa66 {
+		return p2p.ExpectMsg(p.app, code, []interface{}{id, data})
+	}
+	return p2p.ExpectMsg(p.app, code, data)
+}
+
 // close terminates the local side of the peer, notifying the remote protocol
 // manager of termination.
 func (p *testPeer) close() {
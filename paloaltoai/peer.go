@@ -20,11 +20,13 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"math/rand"
 	"sync"
 	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/forkid"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
 	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
@@ -34,6 +36,7 @@ var (
 	errClosed            = errors.New("peer set is closed")
 	errAlreadyRegistered = errors.New("peer is already registered")
 	errNotRegistered     = errors.New("peer is not registered")
+	errRequestInFlight   = errors.New("request ID already in flight")
 )
 
 const (
@@ -45,6 +48,11 @@ const (
 	// contain a single transaction, or thousands.
 	maxQueuedTxs = 128
 
+	// maxQueuedTxAnns is the maximum number of transaction announcement lists to
+	// queue up before dropping broadcasts. Announcements are only hashes, so the
+	// same allowance as for full transaction lists is plenty.
+	maxQueuedTxAnns = 128
+
 	// maxQueuedProps is the maximum number of block propagations to queue up before
 	// dropping broadcasts. There's not much point in queueing stale blocks, so a few
 	// that might cover uncles should be enough.
@@ -55,6 +63,11 @@ const (
 	// above some healthy uncle limit, so use that.
 	maxQueuedAnns = 4
 
+	// maxPendingRequests is the maximum number of in-flight paa/66 requests to
+	// track per peer. Requests beyond this evict the oldest ones, the replies of
+	// which will be dropped as unsolicited.
+	maxPendingRequests = 256
+
 	handshakeTimeout = 5 * time.Second
 )
 
@@ -66,6 +79,23 @@ type PeerInfo struct {
 	Head       string   `json:"head"`       // SHA3 hash of the peer's best owned block
 }
 
+// requestOwner identifies the mechanism waiting for the reply to a request.
+type requestOwner int
+
+const (
+	ownerUnknown    requestOwner = iota // Reply to an untagged request (before paa/66)
+	ownerDownloader                     // Chain synchronisation
+	ownerFetcher                        // Retrieval of announced blocks
+	ownerHandler                        // DAO fork and whitelist checks
+)
+
+// pendingRequest is an in-flight request tagged with a request ID.
+type pendingRequest struct {
+	code  uint64       // Message code of the expected reply
+	owner requestOwner // Mechanism waiting for the reply
+	sent  time.Time    // Time the request was sent, used to evict the oldest
+}
+
 // propEvent is a block propagation, waiting for its turn in the broadcast queue.
 type propEvent struct {
 	block *types.Block
@@ -85,26 +115,36 @@ type peer struct {
 	td   *big.Int
 	lock sync.RWMutex
 
-	knownTxs    mapset.Set                // Set of transaction hashes known to be known by this peer
-	knownBlocks mapset.Set                // Set of block hashes known to be known by this peer
-	queuedTxs   chan []*types.Transaction // Queue of transactions to broadcast to the peer
-	queuedProps chan *propEvent           // Queue of blocks to broadcast to the peer
-	queuedAnns  chan *types.Block         // Queue of blocks to announce to the peer
-	term        chan struct{}             // Termination channel to stop the broadcaster
+	knownTxs     mapset.Set                // Set of transaction hashes known to be known by this peer
+	knownBlocks  mapset.Set                // Set of block hashes known to be known by this peer
+	queuedTxs    chan []*types.Transaction // Queue of transactions to broadcast to the peer
+	queuedTxAnns chan []common.Hash        // Queue of transaction hashes to announce to the peer
+	queuedProps  chan *propEvent           // Queue of blocks to broadcast to the peer
+	queuedAnns   chan *types.Block         // Queue of blocks to announce to the peer
+	term         chan struct{}             // Termination channel to stop the broadcaster
+
+	nextRequest uint64                     // Request ID to assign to the next tagged request
+	requests    map[uint64]*pendingRequest // In-flight tagged requests (paa/66+)
+	untagged    map[uint64][]time.Time     // Send times of in-flight untagged requests (before paa/66), keyed by reply code
+	reqLock     sync.Mutex
 }
 
 func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
 	return &peer{
-		Peer:        p,
-		rw:          rw,
-		version:     version,
-		id:          fmt.Sprintf("%x", p.ID().Bytes()[:8]),
-		knownTxs:    mapset.NewSet(),
-		knownBlocks: mapset.NewSet(),
-		queuedTxs:   make(chan []*types.Transaction, maxQueuedTxs),
-		queuedProps: make(chan *propEvent, maxQueuedProps),
-		queuedAnns:  make(chan *types.Block, maxQueuedAnns),
-		term:        make(chan struct{}),
+		Peer:         p,
+		rw:           rw,
+		version:      version,
+		id:           fmt.Sprintf("%x", p.ID().Bytes()[:8]),
+		knownTxs:     mapset.NewSet(),
+		knownBlocks:  mapset.NewSet(),
+		queuedTxs:    make(chan []*types.Transaction, maxQueuedTxs),
+		queuedTxAnns: make(chan []common.Hash, maxQueuedTxAnns),
+		queuedProps:  make(chan *propEvent, maxQueuedProps),
+		queuedAnns:   make(chan *types.Block, maxQueuedAnns),
+		term:         make(chan struct{}),
+		nextRequest:  rand.Uint64(),
+		requests:     make(map[uint64]*pendingRequest),
+		untagged:     make(map[uint64][]time.Time),
 	}
 }
 
@@ -120,6 +160,12 @@ func (p *peer) broadcast() {
 			}
 			p.Log().Trace("Broadcast transactions", "count", len(txs))
 
+		case hashes := <-p.queuedTxAnns:
+			if err := p.SendPooledTransactionHashes(hashes); err != nil {
+				return
+			}
+			p.Log().Trace("Announced transactions", "count", len(hashes))
+
 		case prop := <-p.queuedProps:
 			if err := p.SendNewBlock(prop.block, prop.td); err != nil {
 				return
@@ -215,6 +261,39 @@ func (p *peer) AsyncSendTransactions(txs []*types.Transaction) {
 	}
 }
 
+// SendPooledTransactionHashes announces the availability of a number of
+// transactions to the peer and includes the hashes in its transaction hash set
+// for future reference.
+func (p *peer) SendPooledTransactionHashes(hashes []common.Hash) error {
+	for _, hash := range hashes {
+		p.knownTxs.Add(hash)
+	}
+	return p2p.Send(p.rw, NewPooledTransactionHashesMsg, hashes)
+}
+
+// AsyncSendPooledTransactionHashes queues a list of transaction hashes to
+// announce to a remote peer. If the peer's broadcast queue is full, the event
+// is silently dropped.
+func (p *peer) AsyncSendPooledTransactionHashes(hashes []common.Hash) {
+	select {
+	case p.queuedTxAnns <- hashes:
+		for _, hash := range hashes {
+			p.knownTxs.Add(hash)
+		}
+	default:
+		p.Log().Debug("Dropping transaction announcement", "count", len(hashes))
+	}
+}
+
+// SendPooledTransactionsRLP sends a batch of pooled transactions to the peer,
+// corresponding to the ones requested from an already RLP encoded format.
+func (p *peer) SendPooledTransactionsRLP(hashes []common.Hash, txs []rlp.RawValue) error {
+	for _, hash := range hashes {
+		p.knownTxs.Add(hash)
+	}
+	return p2p.Send(p.rw, PooledTransactionsMsg, txs)
+}
+
 // SendNewBlockHashes announces the availability of a number of blocks through
 // a hash notification.
 func (p *peer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error {
@@ -258,11 +337,106 @@ func (p *peer) AsyncSendNewBlock(block *types.Block, td *big.Int) {
 	}
 }
 
+// NewRequestID allocates the ID of a new request to the peer. Both the protocol
+// manager and the downloader draw their IDs from it, so that their requests in
+// flight never collide.
+func (p *peer) NewRequestID() uint64 {
+	p.reqLock.Lock()
+	defer p.reqLock.Unlock()
+
+	id := p.nextRequest
+	p.nextRequest++
+	return id
+}
+
+// trackRequest registers an outbound request tagged with the given ID, expecting
+// a reply with the given code. IDs still awaiting a reply are rejected, as their
+// replies could not be told apart.
+func (p *peer) trackRequest(id uint64, code uint64, owner requestOwner) error {
+	p.reqLock.Lock()
+	defer p.reqLock.Unlock()
+
+	if _, ok := p.requests[id]; ok {
+		return errRequestInFlight
+	}
+	for len(p.requests) >= maxPendingRequests {
+		var (
+			oldest   uint64
+			earliest time.Time
+		)
+		for id, req := range p.requests {
+			if earliest.IsZero() || req.sent.Before(earliest) {
+				oldest, earliest = id, req.sent
+			}
+		}
+		delete(p.requests, oldest)
+	}
+	p.requests[id] = &pendingRequest{code: code, owner: owner, sent: time.Now()}
+	return nil
+}
+
+// claimRequest matches a reply against the in-flight requests, returning the
+// mechanism waiting for it. The request is considered answered afterwards and
+// the peer's reputation is credited with the response.
+func (p *peer) claimRequest(id uint64, code uint64) (requestOwner, bool) {
+	p.reqLock.Lock()
+	req := p.requests[id]
+	if req == nil || req.code != code {
+		p.reqLock.Unlock()
+		return ownerUnknown, false
+	}
+	delete(p.requests, id)
+	p.reqLock.Unlock()
+
+	p.RecordResponse(ProtocolName, code, time.Since(req.sent))
+	return req.owner, true
+}
+
+// trackUntagged registers an outbound request without ID, expecting a reply with
+// the given code. Untagged replies are answered in order, so they're matched to
+// the oldest request awaiting the same code.
+func (p *peer) trackUntagged(code uint64) {
+	p.reqLock.Lock()
+	defer p.reqLock.Unlock()
+
+	sent := p.untagged[code]
+	if len(sent) >= maxPendingRequests {
+		sent = sent[1:]
+	}
+	p.untagged[code] = append(sent, time.Now())
+}
+
+// claimUntagged matches a reply without ID against the oldest untagged request
+// awaiting it, crediting the peer's reputation with the response. It reports
+// whether a request was waiting for the reply.
+func (p *peer) claimUntagged(code uint64) bool {
+	p.reqLock.Lock()
+	sent := p.untagged[code]
+	if len(sent) == 0 {
+		p.reqLock.Unlock()
+		return false
+	}
+	p.untagged[code] = sent[1:]
+	p.reqLock.Unlock()
+
+	p.RecordResponse(ProtocolName, code, time.Since(sent[0]))
+	return true
+}
+
 // SendBlockHeaders sends a batch of block headers to the remote peer.
 func (p *peer) SendBlockHeaders(headers []*types.Header) error {
 	return p2p.Send(p.rw, BlockHeadersMsg, headers)
 }
 
+// ReplyBlockHeaders sends a batch of block headers to the remote peer in reply
+// to the request with the given ID. The ID is ignored before paa/66.
+func (p *peer) ReplyBlockHeaders(id uint64, headers []*types.Header) error {
+	if p.version >= paa66 {
+		return p2p.Send(p.rw, BlockHeadersMsg, &blockHeadersPacket66{RequestId: id, Headers: headers})
+	}
+	return p.SendBlockHeaders(headers)
+}
+
 // SendBlockBodies sends a batch of block contents to the remote peer.
 func (p *peer) SendBlockBodies(bodies []*blockBody) error {
 	return p2p.Send(p.rw, BlockBodiesMsg, blockBodiesData(bodies))
@@ -274,77 +448,177 @@ func (p *peer) SendBlockBodiesRLP(bodies []rlp.RawValue) error {
 	return p2p.Send(p.rw, BlockBodiesMsg, bodies)
 }
 
+// ReplyBlockBodiesRLP sends a batch of block contents in reply to the request
+// with the given ID from an already RLP encoded format. The ID is ignored before
+// paa/66.
+func (p *peer) ReplyBlockBodiesRLP(id uint64, bodies []rlp.RawValue) error {
+	if p.version >= paa66 {
+		return p2p.Send(p.rw, BlockBodiesMsg, &rawPacket66{RequestId: id, Items: bodies})
+	}
+	return p.SendBlockBodiesRLP(bodies)
+}
+
 // SendNodeDataRLP sends a batch of arbitrary internal data, corresponding to the
 // hashes requested.
 func (p *peer) SendNodeData(data [][]byte) error {
 	return p2p.Send(p.rw, NodeDataMsg, data)
 }
 
+// ReplyNodeData sends a batch of arbitrary internal data in reply to the request
+// with the given ID. The ID is ignored before paa/66.
+func (p *peer) ReplyNodeData(id uint64, data [][]byte) error {
+	if p.version >= paa66 {
+		return p2p.Send(p.rw, NodeDataMsg, &nodeDataPacket66{RequestId: id, Data: data})
+	}
+	return p.SendNodeData(data)
+}
+
 // SendReceiptsRLP sends a batch of transaction receipts, corresponding to the
 // ones requested from an already RLP encoded format.
 func (p *peer) SendReceiptsRLP(receipts []rlp.RawValue) error {
 	return p2p.Send(p.rw, ReceiptsMsg, receipts)
 }
 
+// ReplyReceiptsRLP sends a batch of transaction receipts in reply to the request
+// with the given ID from an already RLP encoded format. The ID is ignored before
+// paa/66.
+func (p *peer) ReplyReceiptsRLP(id uint64, receipts []rlp.RawValue) error {
+	if p.version >= paa66 {
+		return p2p.Send(p.rw, ReceiptsMsg, &rawPacket66{RequestId: id, Items: receipts})
+	}
+	return p.SendReceiptsRLP(receipts)
+}
+
+// requestHeaders sends a header query to the remote peer, tagging it with the
+// given request ID on paa/66 and later.
+func (p *peer) requestHeaders(id uint64, query *getBlockHeadersData, owner requestOwner) error {
+	if p.version >= paa66 {
+		if err := p.trackRequest(id, BlockHeadersMsg, owner); err != nil {
+			return err
+		}
+		return p2p.Send(p.rw, GetBlockHeadersMsg, &getBlockHeadersPacket66{RequestId: id, Query: query})
+	}
+	p.trackUntagged(BlockHeadersMsg)
+	return p2p.Send(p.rw, GetBlockHeadersMsg, query)
+}
+
+// requestHashes sends a hash based retrieval request to the remote peer, tagging
+// it with the given request ID on paa/66 and later.
+func (p *peer) requestHashes(id uint64, code uint64, reply uint64, hashes []common.Hash, owner requestOwner) error {
+	if p.version >= paa66 {
+		if err := p.trackRequest(id, reply, owner); err != nil {
+			return err
+		}
+		return p2p.Send(p.rw, code, &hashesPacket66{RequestId: id, Hashes: hashes})
+	}
+	p.trackUntagged(reply)
+	return p2p.Send(p.rw, code, hashes)
+}
+
 // RequestOneHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *peer) RequestOneHeader(hash common.Hash) error {
 	p.Log().Debug("Fetching single header", "hash", hash)
-	return p2p.Send(p.rw, GetBlockHeadersMsg, &getBlockHeadersData{Origin: hashOrNumber{Hash: hash}, Amount: uint64(1), Skip: uint64(0), Reverse: false})
+	return p.requestHeaders(p.NewRequestID(), &getBlockHeadersData{Origin: hashOrNumber{Hash: hash}, Amount: uint64(1), Skip: uint64(0), Reverse: false}, ownerFetcher)
+}
+
+// RequestCheckHeader fetches a single header by number to validate the remote
+// chain against. It is used solely by the DAO fork and whitelist checks.
+func (p *peer) RequestCheckHeader(number uint64) error {
+	p.Log().Debug("Fetching check header", "number", number)
+	return p.requestHeaders(p.NewRequestID(), &getBlockHeadersData{Origin: hashOrNumber{Number: number}, Amount: uint64(1), Skip: uint64(0), Reverse: false}, ownerHandler)
 }
 
 // RequestHeadersByHash fetches a batch of blocks' headers corresponding to the
-// specified header query, based on the hash of an origin block.
-func (p *peer) RequestHeadersByHash(origin common.Hash, amount int, skip int, reverse bool) error {
+// specified header query, based on the hash of an origin block. The request ID
+// is ignored before paa/66.
+func (p *peer) RequestHeadersByHash(id uint64, origin common.Hash, amount int, skip int, reverse bool) error {
 	p.Log().Debug("Fetching batch of headers", "count", amount, "fromhash", origin, "skip", skip, "reverse", reverse)
-	return p2p.Send(p.rw, GetBlockHeadersMsg, &getBlockHeadersData{Origin: hashOrNumber{Hash: origin}, Amount: uint64(amount), Skip: uint64(skip), Reverse: reverse})
+	return p.requestHeaders(id, &getBlockHeadersData{Origin: hashOrNumber{Hash: origin}, Amount: uint64(amount), Skip: uint64(skip), Reverse: reverse}, ownerDownloader)
 }
 
 // RequestHeadersByNumber fetches a batch of blocks' headers corresponding to the
-// specified header query, based on the number of an origin block.
-func (p *peer) RequestHeadersByNumber(origin uint64, amount int, skip int, reverse bool) error {
+// specified header query, based on the number of an origin block. The request
+// ID is ignored before paa/66.
+func (p *peer) RequestHeadersByNumber(id uint64, origin uint64, amount int, skip int, reverse bool) error {
 	p.Log().Debug("Fetching batch of headers", "count", amount, "fromnum", origin, "skip", skip, "reverse", reverse)
-	return p2p.Send(p.rw, GetBlockHeadersMsg, &getBlockHeadersData{Origin: hashOrNumber{Number: origin}, Amount: uint64(amount), Skip: uint64(skip), Reverse: reverse})
+	return p.requestHeaders(id, &getBlockHeadersData{Origin: hashOrNumber{Number: origin}, Amount: uint64(amount), Skip: uint64(skip), Reverse: reverse}, ownerDownloader)
 }
 
 // RequestBodies fetches a batch of blocks' bodies corresponding to the hashes
-// specified.
-func (p *peer) RequestBodies(hashes []common.Hash) error {
+// specified, tagged with the downloader's request ID on paa/66 and later.
+func (p *peer) RequestBodies(id uint64, hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of block bodies", "count", len(hashes))
-	return p2p.Send(p.rw, GetBlockBodiesMsg, hashes)
+	return p.requestHashes(id, GetBlockBodiesMsg, BlockBodiesMsg, hashes, ownerDownloader)
+}
+
+// RequestAnnouncedBodies fetches the bodies of a batch of announced blocks. It
+// is used solely by the fetcher.
+func (p *peer) RequestAnnouncedBodies(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of announced block bodies", "count", len(hashes))
+	return p.requestHashes(p.NewRequestID(), GetBlockBodiesMsg, BlockBodiesMsg, hashes, ownerFetcher)
 }
 
 // RequestNodeData fetches a batch of arbitrary data from a node's known state
-// data, corresponding to the specified hashes.
-func (p *peer) RequestNodeData(hashes []common.Hash) error {
+// data, corresponding to the specified hashes, tagged with the downloader's
+// request ID on paa/66 and later.
+func (p *peer) RequestNodeData(id uint64, hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of state data", "count", len(hashes))
-	return p2p.Send(p.rw, GetNodeDataMsg, hashes)
+	return p.requestHashes(id, GetNodeDataMsg, NodeDataMsg, hashes, ownerDownloader)
 }
 
-// RequestReceipts fetches a batch of transaction receipts from a remote node.
-func (p *peer) RequestReceipts(hashes []common.Hash) error {
+// RequestTxs fetches a batch of transactions from a remote node's pool.
+func (p *peer) RequestTxs(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of transactions", "count", len(hashes))
+	return p2p.Send(p.rw, GetPooledTransactionsMsg, hashes)
+}
+
+// RequestReceipts fetches a batch of transaction receipts from a remote node,
+// tagged with the downloader's request ID on paa/66 and later.
+func (p *peer) RequestReceipts(id uint64, hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of receipts", "count", len(hashes))
-	return p2p.Send(p.rw, GetReceiptsMsg, hashes)
+	return p.requestHashes(id, GetReceiptsMsg, ReceiptsMsg, hashes, ownerDownloader)
 }
 
 // Handshake executes the paa protocol handshake, negotiating version number,
-// network IDs, difficulties, head and genesis blocks.
-func (p *peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis common.Hash) error {
+// network IDs, difficulties, head and genesis blocks. Peers speaking paa/64 or
+// later also exchange fork IDs, which are validated with forkFilter.
+func (p *peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter) error {
 	// Send out own handshake in a new thread
 	errc := make(chan error, 2)
-	var status statusData // safe to read after two values have been received from errc
 
+	var (
+		status62 statusData   // safe to read after two values have been received from errc
+		status64 statusData64 // safe to read after two values have been received from errc
+	)
 	go func() {
-		errc <- p2p.Send(p.rw, StatusMsg, &statusData{
-			ProtocolVersion: uint32(p.version),
-			NetworkId:       network,
-			TD:              td,
-			CurrentBlock:    head,
-			GenesisBlock:    genesis,
-		})
+		switch {
+		case p.version >= paa64:
+			errc <- p2p.Send(p.rw, StatusMsg, &statusData64{
+				ProtocolVersion: uint32(p.version),
+				NetworkID:       network,
+				TD:              td,
+				Head:            head,
+				Genesis:         genesis,
+				ForkID:          forkID,
+			})
+		default:
+			errc <- p2p.Send(p.rw, StatusMsg, &statusData{
+				ProtocolVersion: uint32(p.version),
+				NetworkId:       network,
+				TD:              td,
+				CurrentBlock:    head,
+				GenesisBlock:    genesis,
+			})
+		}
 	}()
 	go func() {
-		errc <- p.readStatus(network, &status, genesis)
+		switch {
+		case p.version >= paa64:
+			errc <- p.readStatus64(network, &status64, genesis, forkFilter)
+		default:
+			errc <- p.readStatus(network, &status62, genesis)
+		}
 	}()
 	timeout := time.NewTimer(handshakeTimeout)
 	defer timeout.Stop()
@@ -358,11 +632,53 @@ func (p *peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis
 			return p2p.DiscReadTimeout
 		}
 	}
-	p.td, p.head = status.TD, status.CurrentBlock
+	switch {
+	case p.version >= paa64:
+		p.td, p.head = status64.TD, status64.Head
+	default:
+		p.td, p.head = status62.TD, status62.CurrentBlock
+	}
+	return nil
+}
+
+func (p *peer) readStatus(network uint64, status *statusData, genesis common.Hash) error {
+	if err := p.readStatusMsg(status); err != nil {
+		return err
+	}
+	if status.GenesisBlock != genesis {
+		return errResp(ErrGenesisBlockMismatch, "%x (!= %x)", status.GenesisBlock[:8], genesis[:8])
+	}
+	if status.NetworkId != network {
+		return errResp(ErrNetworkIdMismatch, "%d (!= %d)", status.NetworkId, network)
+	}
+	if int(status.ProtocolVersion) != p.version {
+		return errResp(ErrProtocolVersionMismatch, "%d (!= %d)", status.ProtocolVersion, p.version)
+	}
+	return nil
+}
+
+func (p *peer) readStatus64(network uint64, status *statusData64, genesis common.Hash, forkFilter forkid.Filter) error {
+	if err := p.readStatusMsg(status); err != nil {
+		return err
+	}
+	if status.Genesis != genesis {
+		return errResp(ErrGenesisBlockMismatch, "%x (!= %x)", status.Genesis[:8], genesis[:8])
+	}
+	if status.NetworkID != network {
+		return errResp(ErrNetworkIdMismatch, "%d (!= %d)", status.NetworkID, network)
+	}
+	if int(status.ProtocolVersion) != p.version {
+		return errResp(ErrProtocolVersionMismatch, "%d (!= %d)", status.ProtocolVersion, p.version)
+	}
+	if err := forkFilter(status.ForkID); err != nil {
+		return errResp(ErrForkIDRejected, "%v", err)
+	}
 	return nil
 }
 
-func (p *peer) readStatus(network uint64, status *statusData, genesis common.Hash) (err error) {
+// readStatusMsg reads the first message from the peer and decodes it into
+// status, which must be a pointer to one of the status packet types.
+func (p *peer) readStatusMsg(status interface{}) error {
 	msg, err := p.rw.ReadMsg()
 	if err != nil {
 		return err
@@ -374,18 +690,9 @@ func (p *peer) readStatus(network uint64, status *statusData, genesis common.Has
 		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
 	}
 	// Decode the handshake and make sure everything matches
-	if err := msg.Decode(&status); err != nil {
+	if err := msg.Decode(status); err != nil {
 		return errResp(ErrDecode, "msg %v: %v", msg, err)
 	}
-	if status.GenesisBlock != genesis {
-		return errResp(ErrGenesisBlockMismatch, "%x (!= %x)", status.GenesisBlock[:8], genesis[:8])
-	}
-	if status.NetworkId != network {
-		return errResp(ErrNetworkIdMismatch, "%d (!= %d)", status.NetworkId, network)
-	}
-	if int(status.ProtocolVersion) != p.version {
-		return errResp(ErrProtocolVersionMismatch, "%d (!= %d)", status.ProtocolVersion, p.version)
-	}
 	return nil
 }
 
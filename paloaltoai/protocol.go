@@ -23,6 +23,7 @@ import (
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/common"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/forkid"
 	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
 	"github.com/PaloAltoAi/go-PaloAltoAi/event"
 	"github.com/PaloAltoAi/go-PaloAltoAi/rlp"
@@ -32,16 +33,19 @@ import (
 const (
 	paa62 = 62
 	paa63 = 63
+	paa64 = 64
+	paa65 = 65
+	paa66 = 66
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "paa"
 
 // ProtocolVersions are the supported versions of the paa protocol (first is primary).
-var ProtocolVersions = []uint{paa63, paa62}
+var ProtocolVersions = []uint{paa66, paa65, paa64, paa63, paa62}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{17, 8}
+var ProtocolLengths = []uint64{17, 17, 17, 17, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -57,6 +61,11 @@ const (
 	BlockBodiesMsg     = 0x06
 	NewBlockMsg        = 0x07
 
+	// Protocol messages belonging to paa/65
+	NewPooledTransactionHashesMsg = 0x08
+	GetPooledTransactionsMsg      = 0x09
+	PooledTransactionsMsg         = 0x0a
+
 	// Protocol messages belonging to paa/63
 	GetNodeDataMsg = 0x0d
 	NodeDataMsg    = 0x0e
@@ -76,6 +85,7 @@ const (
 	ErrNoStatusMsg
 	ErrExtraStatusMsg
 	ErrSuspendedPeer
+	ErrForkIDRejected
 )
 
 func (e errCode) String() string {
@@ -93,12 +103,17 @@ var errorToString = map[int]string{
 	ErrNoStatusMsg:             "No status message",
 	ErrExtraStatusMsg:          "Extra status message",
 	ErrSuspendedPeer:           "Suspended peer",
+	ErrForkIDRejected:          "Fork ID rejected",
 }
 
 type txPool interface {
 	// AddRemotes should add the given transactions to the pool.
 	AddRemotes([]*types.Transaction) []error
 
+	// Get should return the transaction with the given hash if it's in the
+	// pool, nil otherwise.
+	Get(hash common.Hash) *types.Transaction
+
 	// Pending should return pending transactions.
 	// The slice should be modifiable by the caller.
 	Pending() (map[common.Address]types.Transactions, error)
@@ -117,6 +132,18 @@ type statusData struct {
 	GenesisBlock    common.Hash
 }
 
+// statusData64 is the network packet for the status message for paa/64 and later.
+// Next to the network ID, it carries the fork ID, which is checked in addition
+// to reject peers on a different fork schedule early.
+type statusData64 struct {
+	ProtocolVersion uint32
+	NetworkID       uint64
+	TD              *big.Int
+	Head            common.Hash
+	Genesis         common.Hash
+	ForkID          forkid.ID
+}
+
 // newBlockHashesData is the network packet for the block announcements.
 type newBlockHashesData []struct {
 	Hash   common.Hash // Hash of one particular block being announced
@@ -167,6 +194,51 @@ func (hn *hashOrNumber) DecodeRLP(s *rlp.Stream) error {
 	return err
 }
 
+// getBlockHeadersPacket66 is a block header query tagged with a request ID,
+// used by paa/66 and later.
+type getBlockHeadersPacket66 struct {
+	RequestId uint64
+	Query     *getBlockHeadersData
+}
+
+// blockHeadersPacket66 is the reply to a paa/66 block header query.
+type blockHeadersPacket66 struct {
+	RequestId uint64
+	Headers   []*types.Header
+}
+
+// blockBodiesPacket66 is the reply to a paa/66 block body query.
+type blockBodiesPacket66 struct {
+	RequestId uint64
+	Bodies    blockBodiesData
+}
+
+// nodeDataPacket66 is the reply to a paa/66 state data query.
+type nodeDataPacket66 struct {
+	RequestId uint64
+	Data      [][]byte
+}
+
+// receiptsPacket66 is the reply to a paa/66 receipt query.
+type receiptsPacket66 struct {
+	RequestId uint64
+	Receipts  [][]*types.Receipt
+}
+
+// hashesPacket66 is a hash based retrieval request tagged with a request ID,
+// used by paa/66 and later for block bodies, state data and receipts.
+type hashesPacket66 struct {
+	RequestId uint64
+	Hashes    []common.Hash
+}
+
+// rawPacket66 is a reply tagged with a request ID, carrying already RLP
+// encoded items.
+type rawPacket66 struct {
+	RequestId uint64
+	Items     []rlp.RawValue
+}
+
 // newBlockData is the network packet for the block propagation message.
 type newBlockData struct {
 	Block *types.Block
@@ -72,13 +72,18 @@ func (pm *ProtocolManager) txsyncLoop() {
 
 	// send starts a sending a pack of transactions from the sync.
 	send := func(s *txsync) {
-		// Fill pack with transactions up to the target size.
+		// Fill pack with transactions up to the target size. Peers on paa/65 and
+		// above only get the hashes announced, so count those instead.
 		size := common.StorageSize(0)
 		pack.p = s.p
 		pack.txs = pack.txs[:0]
 		for i := 0; i < len(s.txs) && size < txsyncPackSize; i++ {
 			pack.txs = append(pack.txs, s.txs[i])
-			size += s.txs[i].Size()
+			if s.p.version >= paa65 {
+				size += common.HashLength
+			} else {
+				size += s.txs[i].Size()
+			}
 		}
 		// Remove the transactions that will be sent.
 		s.txs = s.txs[:copy(s.txs, s.txs[len(pack.txs):])]
@@ -88,7 +93,15 @@ func (pm *ProtocolManager) txsyncLoop() {
 		// Send the pack in the background.
 		s.p.Log().Trace("Sending batch of transactions", "count", len(pack.txs), "bytes", size)
 		sending = true
-		go func() { done <- pack.p.SendTransactions(pack.txs) }()
+		if pack.p.version >= paa65 {
+			hashes := make([]common.Hash, len(pack.txs))
+			for i, tx := range pack.txs {
+				hashes[i] = tx.Hash()
+			}
+			go func() { done <- pack.p.SendPooledTransactionHashes(hashes) }()
+		} else {
+			go func() { done <- pack.p.SendTransactions(pack.txs) }()
+		}
 	}
 
 	// pick chooses the next pending sync.
@@ -135,6 +148,8 @@ func (pm *ProtocolManager) syncer() {
 	// Start and ensure cleanup of sync mechanisms
 	pm.fetcher.Start()
 	defer pm.fetcher.Stop()
+	pm.txFetcher.Start()
+	defer pm.txFetcher.Stop()
 	defer pm.downloader.Terminate()
 
 	// Wait for different events to fire synchronisation operations
@@ -0,0 +1,75 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package gpaaclient
+
+import (
+	"context"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+)
+
+// NodeInfo retrieves information about the node, as known by its P2P server.
+func (gc *Client) NodeInfo(ctx context.Context) (*p2p.NodeInfo, error) {
+	var info *p2p.NodeInfo
+	err := gc.c.CallContext(ctx, &info, "admin_nodeInfo")
+	return info, err
+}
+
+// Peers retrieves information about the peers the node is connected to.
+func (gc *Client) Peers(ctx context.Context) ([]*p2p.PeerInfo, error) {
+	var peers []*p2p.PeerInfo
+	err := gc.c.CallContext(ctx, &peers, "admin_peers")
+	return peers, err
+}
+
+// AddPeer requests the node to connect to a remote peer, maintaining the
+// connection at all times.
+func (gc *Client) AddPeer(ctx context.Context, node *enode.Node) error {
+	return gc.c.CallContext(ctx, nil, "admin_addPeer", node.String())
+}
+
+// RemovePeer requests the node to disconnect from a remote peer.
+func (gc *Client) RemovePeer(ctx context.Context, node *enode.Node) error {
+	return gc.c.CallContext(ctx, nil, "admin_removePeer", node.String())
+}
+
+// AddTrustedPeer marks a remote peer as trusted, allowing it to connect even
+// above the peer limit.
+func (gc *Client) AddTrustedPeer(ctx context.Context, node *enode.Node) error {
+	return gc.c.CallContext(ctx, nil, "admin_addTrustedPeer", node.String())
+}
+
+// RemoveTrustedPeer removes a remote peer from the trusted set. It does not
+// disconnect it.
+func (gc *Client) RemoveTrustedPeer(ctx context.Context, node *enode.Node) error {
+	return gc.c.CallContext(ctx, nil, "admin_removeTrustedPeer", node.String())
+}
+
+// Datadir retrieves the data directory of the node.
+func (gc *Client) Datadir(ctx context.Context) (string, error) {
+	var dir string
+	err := gc.c.CallContext(ctx, &dir, "admin_datadir")
+	return dir, err
+}
+
+// SubscribePeerEvents subscribes to peer connection and message events of the
+// node's P2P server.
+func (gc *Client) SubscribePeerEvents(ctx context.Context, ch chan<- *p2p.PeerEvent) (PaloAltoAi.Subscription, error) {
+	return gc.c.Subscribe(ctx, "admin", ch, "peerEvents")
+}
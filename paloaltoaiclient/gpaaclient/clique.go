@@ -0,0 +1,82 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package gpaaclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/clique"
+)
+
+// CliqueSnapshot retrieves the clique voting state at the given block, nil
+// being the latest one.
+func (gc *Client) CliqueSnapshot(ctx context.Context, number *big.Int) (*clique.Snapshot, error) {
+	var snap *clique.Snapshot
+	err := gc.c.CallContext(ctx, &snap, "clique_getSnapshot", toBlockNumArg(number))
+	return snap, err
+}
+
+// CliqueSnapshotAtHash retrieves the clique voting state at the given block.
+func (gc *Client) CliqueSnapshotAtHash(ctx context.Context, hash common.Hash) (*clique.Snapshot, error) {
+	var snap *clique.Snapshot
+	err := gc.c.CallContext(ctx, &snap, "clique_getSnapshotAtHash", hash)
+	return snap, err
+}
+
+// CliqueSigners retrieves the signers authorized at the given block, nil being
+// the latest one.
+func (gc *Client) CliqueSigners(ctx context.Context, number *big.Int) ([]common.Address, error) {
+	var signers []common.Address
+	err := gc.c.CallContext(ctx, &signers, "clique_getSigners", toBlockNumArg(number))
+	return signers, err
+}
+
+// CliqueSignersAtHash retrieves the signers authorized at the given block.
+func (gc *Client) CliqueSignersAtHash(ctx context.Context, hash common.Hash) ([]common.Address, error) {
+	var signers []common.Address
+	err := gc.c.CallContext(ctx, &signers, "clique_getSignersAtHash", hash)
+	return signers, err
+}
+
+// CliqueProposals retrieves the votes the node casts when sealing blocks: true
+// to authorize a signer, false to deauthorize it.
+func (gc *Client) CliqueProposals(ctx context.Context) (map[common.Address]bool, error) {
+	var proposals map[common.Address]bool
+	err := gc.c.CallContext(ctx, &proposals, "clique_proposals")
+	return proposals, err
+}
+
+// CliquePropose adds a vote for the node to cast when sealing blocks, either to
+// authorize or to deauthorize a signer.
+func (gc *Client) CliquePropose(ctx context.Context, signer common.Address, auth bool) error {
+	return gc.c.CallContext(ctx, nil, "clique_propose", signer, auth)
+}
+
+// CliqueDiscard drops a vote of the node.
+func (gc *Client) CliqueDiscard(ctx context.Context, signer common.Address) error {
+	return gc.c.CallContext(ctx, nil, "clique_discard", signer)
+}
+
+// CliqueStatus retrieves the sealing activity of the signers over the recent
+// blocks.
+func (gc *Client) CliqueStatus(ctx context.Context) (*clique.Status, error) {
+	var status *clique.Status
+	err := gc.c.CallContext(ctx, &status, "clique_status")
+	return status, err
+}
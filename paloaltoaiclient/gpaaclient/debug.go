@@ -0,0 +1,111 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package gpaaclient
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+)
+
+// TraceConfig configures the structured logger tracing transactions.
+type TraceConfig struct {
+	DisableMemory  bool    `json:"disableMemory,omitempty"`  // Disable memory capture
+	DisableStack   bool    `json:"disableStack,omitempty"`   // Disable stack capture
+	DisableStorage bool    `json:"disableStorage,omitempty"` // Disable storage capture
+	Limit          int     `json:"limit,omitempty"`          // Maximum number of logs to capture (0 = unlimited)
+	Timeout        *string `json:"timeout,omitempty"`        // Maximum duration of a trace (e.g. "5s")
+	Reexec         *uint64 `json:"reexec,omitempty"`         // Number of blocks to reexecute to regenerate missing state
+}
+
+// ExecutionResult is the result of tracing a transaction with the structured
+// logger: the outcome of the execution and the EVM state at each step.
+type ExecutionResult struct {
+	Gas         uint64      `json:"gas"`
+	Failed      bool        `json:"failed"`
+	ReturnValue string      `json:"returnValue"`
+	StructLogs  []StructLog `json:"structLogs"`
+}
+
+// StructLog is the EVM state at a single step of a traced execution.
+type StructLog struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// TraceTransaction replays a transaction with the structured logger, returning
+// the EVM state at each step of its execution.
+func (gc *Client) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (*ExecutionResult, error) {
+	var result *ExecutionResult
+	err := gc.c.CallContext(ctx, &result, "debug_traceTransaction", hash, config)
+	return result, err
+}
+
+// TraceTransactionWithTracer replays a transaction with a JavaScript tracer,
+// either one of the built-in tracers (e.g. "callTracer") or the source code of
+// a custom one. The result is the JSON value returned by the tracer.
+func (gc *Client) TraceTransactionWithTracer(ctx context.Context, hash common.Hash, tracer string, config *TraceConfig) (json.RawMessage, error) {
+	arg := map[string]interface{}{"tracer": tracer}
+	if config != nil {
+		if config.Timeout != nil {
+			arg["timeout"] = *config.Timeout
+		}
+		if config.Reexec != nil {
+			arg["reexec"] = *config.Reexec
+		}
+	}
+	var result json.RawMessage
+	err := gc.c.CallContext(ctx, &result, "debug_traceTransaction", hash, arg)
+	return result, err
+}
+
+// Preimage retrieves the preimage of a hash from the node's preimage store.
+func (gc *Client) Preimage(ctx context.Context, hash common.Hash) ([]byte, error) {
+	var preimage hexutil.Bytes
+	err := gc.c.CallContext(ctx, &preimage, "debug_preimage", hash)
+	return preimage, err
+}
+
+// ModifiedAccountsByNumber retrieves the accounts modified between two blocks,
+// or in the block itself if end is nil.
+func (gc *Client) ModifiedAccountsByNumber(ctx context.Context, start uint64, end *uint64) ([]common.Address, error) {
+	var accounts []common.Address
+	err := gc.c.CallContext(ctx, &accounts, "debug_getModifiedAccountsByNumber", start, end)
+	return accounts, err
+}
+
+// ModifiedAccountsByHash retrieves the accounts modified between two blocks,
+// or in the block itself if end is nil.
+func (gc *Client) ModifiedAccountsByHash(ctx context.Context, start common.Hash, end *common.Hash) ([]common.Address, error) {
+	var accounts []common.Address
+	err := gc.c.CallContext(ctx, &accounts, "debug_getModifiedAccountsByHash", start, end)
+	return accounts, err
+}
+
+// SetHead rewinds the local chain of the node to the given block.
+func (gc *Client) SetHead(ctx context.Context, number uint64) error {
+	return gc.c.CallContext(ctx, nil, "debug_setHead", hexutil.Uint64(number))
+}
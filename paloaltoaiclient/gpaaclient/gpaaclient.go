@@ -0,0 +1,107 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package gpaaclient provides a client for the node specific RPC namespaces of
+// gpaloaltoai: admin, debug, txpool, miner, personal and clique. The standard
+// PaloAltoAi API is covered by package paaclient.
+package gpaaclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/rpc"
+)
+
+// Client defines typed wrappers for the node specific RPC namespaces.
+type Client struct {
+	c *rpc.Client
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connects a client to the given URL, aborting if the context is
+// canceled before the connection is established.
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client that uses the given RPC client.
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c}
+}
+
+// Close closes the underlying RPC connection.
+func (gc *Client) Close() {
+	gc.c.Close()
+}
+
+// toBlockNumArg converts a block number into its RPC representation, nil being
+// the latest block.
+func toBlockNumArg(number *big.Int) *string {
+	if number == nil {
+		return nil
+	}
+	arg := hexutil.EncodeBig(number)
+	return &arg
+}
+
+// toCallArg converts a call message into the transaction arguments accepted by
+// the node.
+func toCallArg(msg PaloAltoAi.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
+// parseAddress parses an address used as a JSON object key, which the node
+// formats in its checksummed display form.
+func parseAddress(key string) (common.Address, error) {
+	if strings.HasPrefix(key, "pa") {
+		key = "0x" + key[2:]
+	}
+	var addr common.Address
+	if err := addr.UnmarshalText([]byte(key)); err != nil {
+		return common.Address{}, fmt.Errorf("invalid account %q: %v", key, err)
+	}
+	return addr, nil
+}
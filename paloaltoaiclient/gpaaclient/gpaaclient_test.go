@@ -0,0 +1,340 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package gpaaclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/consensus/paaash"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+	"github.com/PaloAltoAi/go-PaloAltoAi/node"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p"
+	"github.com/PaloAltoAi/go-PaloAltoAi/p2p/enode"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paa"
+	"github.com/PaloAltoAi/go-PaloAltoAi/paaclient"
+	"github.com/PaloAltoAi/go-PaloAltoAi/params"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+	testFunds   = big.NewInt(1000000000000000000)
+	testRecv    = common.HexToAddress("0x1234")
+
+	// testInitCode emits an empty log during contract creation
+	testInitCode = []byte{0x60, 0x00, 0x60, 0x00, 0xa0, 0x00} // PUSH1 0, PUSH1 0, LOG0, STOP
+)
+
+// newTestNode starts an in-process node with the given genesis, returning it
+// along with a client attached to it.
+func newTestNode(t *testing.T, genesis *core.Genesis) (*node.Node, *paa.PaloAltoAi, *Client) {
+	stack, err := node.New(&node.Config{
+		P2P:               p2p.Config{NoDiscovery: true, MaxPeers: 1},
+		UseLightweightKDF: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	config := paa.DefaultConfig
+	config.Genesis = genesis
+	config.Paaash.PowMode = paaash.ModeFake
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return paa.New(ctx, &config)
+	}); err != nil {
+		t.Fatalf("failed to register paa service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	var paaServ *paa.PaloAltoAi
+	if err := stack.Service(&paaServ); err != nil {
+		t.Fatalf("failed to retrieve paa service: %v", err)
+	}
+	rpcClient, err := stack.Attach()
+	if err != nil {
+		t.Fatalf("failed to attach to node: %v", err)
+	}
+	return stack, paaServ, NewClient(rpcClient)
+}
+
+// newTestChain starts a proof-of-work node with a single block, which creates
+// a log emitting contract. The hash of the creating transaction is returned.
+func newTestChain(t *testing.T) (*node.Node, *paa.PaloAltoAi, *Client, common.Hash) {
+	stack, paaServ, client := newTestNode(t, &core.Genesis{
+		Config: params.AllPaaashProtocolChanges,
+		Alloc:  core.GenesisAlloc{testAddress: {Balance: testFunds}},
+	})
+	chain := paaServ.BlockChain()
+
+	tx, _ := types.SignTx(types.NewContractCreation(0, new(big.Int), 100000, nil, testInitCode), types.HomesteadSigner{}, testKey)
+	blocks, _ := core.GenerateChain(chain.Config(), chain.Genesis(), paaash.NewFaker(), paaServ.ChainDb(), 1, func(i int, b *core.BlockGen) {
+		b.AddTx(tx)
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return stack, paaServ, client, tx.Hash()
+}
+
+func TestAdmin(t *testing.T) {
+	stack, _, client := newTestNode(t, &core.Genesis{Config: params.AllPaaashProtocolChanges})
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx := context.Background()
+	info, err := client.NodeInfo(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve node info: %v", err)
+	}
+	if want := stack.Server().Self().ID().String(); info.ID != want {
+		t.Errorf("node id mismatch: have %s, want %s", info.ID, want)
+	}
+	if _, ok := info.Protocols["paa"]; !ok {
+		t.Errorf("paa protocol missing from node info: %v", info.Protocols)
+	}
+	peers, err := client.Peers(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve peers: %v", err)
+	}
+	if len(peers) != 0 {
+		t.Errorf("peer count mismatch: have %d, want 0", len(peers))
+	}
+	key, _ := crypto.GenerateKey()
+	remote := enode.NewV4(&key.PublicKey, []byte{127, 0, 0, 1}, 30303, 30303)
+	if err := client.AddPeer(ctx, remote); err != nil {
+		t.Errorf("failed to add peer: %v", err)
+	}
+	if err := client.RemovePeer(ctx, remote); err != nil {
+		t.Errorf("failed to remove peer: %v", err)
+	}
+}
+
+func TestDebug(t *testing.T) {
+	stack, _, client, hash := newTestChain(t)
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx := context.Background()
+	result, err := client.TraceTransaction(ctx, hash, &TraceConfig{DisableMemory: true})
+	if err != nil {
+		t.Fatalf("failed to trace transaction: %v", err)
+	}
+	if result.Failed || len(result.StructLogs) != 4 {
+		t.Fatalf("trace mismatch: %+v", result)
+	}
+	if log := result.StructLogs[2]; log.Op != "LOG0" || len(log.Stack) != 2 || log.Memory != nil {
+		t.Errorf("log step mismatch: %+v", log)
+	}
+	count, err := client.TraceTransactionWithTracer(ctx, hash, "opcountTracer", nil)
+	if err != nil {
+		t.Fatalf("failed to trace transaction with tracer: %v", err)
+	}
+	if string(count) != "4" {
+		t.Errorf("opcode count mismatch: have %s, want 4", count)
+	}
+	accounts, err := client.ModifiedAccountsByNumber(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve modified accounts: %v", err)
+	}
+	found := false
+	for _, account := range accounts {
+		found = found || account == testAddress
+	}
+	if !found {
+		t.Errorf("sender missing from modified accounts %v", accounts)
+	}
+}
+
+func TestTxPool(t *testing.T) {
+	stack, _, client, _ := newTestChain(t)
+	defer stack.Stop()
+	defer client.Close()
+
+	// Submit an executable and a gapped transaction
+	ctx := context.Background()
+	sender := paaclient.NewClient(client.c)
+	for _, nonce := range []uint64{1, 3} {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, testRecv, big.NewInt(1), params.TxGas, big.NewInt(params.GWei), nil), types.HomesteadSigner{}, testKey)
+		if err := sender.SendTransaction(ctx, tx); err != nil {
+			t.Fatalf("failed to send transaction %d: %v", nonce, err)
+		}
+	}
+	pending, queued, err := client.TxPoolContent(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve pool content: %v", err)
+	}
+	if txs := pending[testAddress]; len(pending) != 1 || len(txs) != 1 || txs[0].Nonce() != 1 {
+		t.Errorf("pending content mismatch: %v", pending)
+	}
+	if txs := queued[testAddress]; len(queued) != 1 || len(txs) != 1 || txs[0].Nonce() != 3 {
+		t.Errorf("queued content mismatch: %v", queued)
+	}
+	npending, nqueued, err := client.TxPoolStatus(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve pool status: %v", err)
+	}
+	if npending != 1 || nqueued != 1 {
+		t.Errorf("pool status mismatch: have %d/%d, want 1/1", npending, nqueued)
+	}
+}
+
+func TestMiner(t *testing.T) {
+	stack, paaServ, client := newTestNode(t, &core.Genesis{Config: params.AllPaaashProtocolChanges})
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.SetPaaerbase(ctx, testRecv); err != nil {
+		t.Fatalf("failed to set paaerbase: %v", err)
+	}
+	if err := client.SetExtra(ctx, "gpaaclient"); err != nil {
+		t.Fatalf("failed to set extra: %v", err)
+	}
+	if err := client.SetGasPrice(ctx, big.NewInt(params.GWei)); err != nil {
+		t.Fatalf("failed to set gas price: %v", err)
+	}
+	if err := client.StartMining(ctx, 1); err != nil {
+		t.Fatalf("failed to start mining: %v", err)
+	}
+	if !paaServ.IsMining() {
+		t.Errorf("node not mining after start")
+	}
+	if err := client.StopMining(ctx); err != nil {
+		t.Fatalf("failed to stop mining: %v", err)
+	}
+	if paaServ.IsMining() {
+		t.Errorf("node still mining after stop")
+	}
+	if base, _ := paaServ.Paaerbase(); base != testRecv {
+		t.Errorf("paaerbase mismatch: have %x, want %x", base, testRecv)
+	}
+}
+
+func TestPersonal(t *testing.T) {
+	stack, _, client, _ := newTestChain(t)
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx := context.Background()
+	created, err := client.NewAccount(ctx, "secret")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	imported, err := client.ImportRawKey(ctx, testKey, "secret")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	if imported != testAddress {
+		t.Errorf("imported account mismatch: have %x, want %x", imported, testAddress)
+	}
+	// Wallet events are delivered asynchronously, wait for both accounts
+	var accounts []common.Address
+	for i := 0; i < 100; i++ {
+		if accounts, err = client.ListAccounts(ctx); err != nil {
+			t.Fatalf("failed to list accounts: %v", err)
+		}
+		if len(accounts) == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(accounts) != 2 || (accounts[0] != created && accounts[1] != created) {
+		t.Errorf("account list mismatch: %x", accounts)
+	}
+	// Sign with the passphrase and recover the signer
+	data := []byte("hello")
+	sig, err := client.Sign(ctx, data, testAddress, "secret")
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if signer, err := client.EcRecover(ctx, data, sig); err != nil || signer != testAddress {
+		t.Errorf("recovered signer mismatch: have %x (%v), want %x", signer, err, testAddress)
+	}
+	if _, err := client.Sign(ctx, data, testAddress, "wrong"); err == nil {
+		t.Errorf("signing with wrong passphrase succeeded")
+	}
+	// Send a transaction and ensure it gets pooled
+	hash, err := client.SendTransaction(ctx, PaloAltoAi.CallMsg{From: testAddress, To: &testRecv, Value: big.NewInt(1)}, "secret")
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	pending, _, err := client.TxPoolContent(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve pool content: %v", err)
+	}
+	if txs := pending[testAddress]; len(txs) != 1 || txs[0].Hash() != hash {
+		t.Errorf("sent transaction not pooled: %v", pending)
+	}
+	if err := client.UnlockAccount(ctx, testAddress, "secret", time.Minute); err != nil {
+		t.Errorf("failed to unlock account: %v", err)
+	}
+	if err := client.LockAccount(ctx, testAddress); err != nil {
+		t.Errorf("failed to lock account: %v", err)
+	}
+}
+
+func TestClique(t *testing.T) {
+	signer := testAddress
+	extra := make([]byte, 32+common.AddressLength+65)
+	copy(extra[32:], signer[:])
+
+	stack, _, client := newTestNode(t, &core.Genesis{
+		Config:    params.AllCliqueProtocolChanges,
+		ExtraData: extra,
+	})
+	defer stack.Stop()
+	defer client.Close()
+
+	ctx := context.Background()
+	signers, err := client.CliqueSigners(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve signers: %v", err)
+	}
+	if len(signers) != 1 || signers[0] != signer {
+		t.Errorf("signers mismatch: have %x, want [%x]", signers, signer)
+	}
+	snap, err := client.CliqueSnapshot(ctx, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("failed to retrieve snapshot: %v", err)
+	}
+	if _, ok := snap.Signers[signer]; snap.Number != 0 || !ok || len(snap.Signers) != 1 {
+		t.Errorf("snapshot mismatch: %+v", snap)
+	}
+	if err := client.CliquePropose(ctx, testRecv, true); err != nil {
+		t.Fatalf("failed to propose: %v", err)
+	}
+	proposals, err := client.CliqueProposals(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve proposals: %v", err)
+	}
+	if len(proposals) != 1 || !proposals[testRecv] {
+		t.Errorf("proposals mismatch: %v", proposals)
+	}
+	if err := client.CliqueDiscard(ctx, testRecv); err != nil {
+		t.Fatalf("failed to discard proposal: %v", err)
+	}
+	if proposals, _ = client.CliqueProposals(ctx); len(proposals) != 0 {
+		t.Errorf("proposal not discarded: %v", proposals)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package gpaaclient
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+)
+
+// StartMining starts sealing blocks with the given number of threads. Zero
+// threads uses all CPUs, a negative number only seals with external miners.
+func (gc *Client) StartMining(ctx context.Context, threads int) error {
+	var arg *int
+	if threads != 0 {
+		arg = &threads
+	}
+	return gc.c.CallContext(ctx, nil, "miner_start", arg)
+}
+
+// StopMining stops sealing blocks.
+func (gc *Client) StopMining(ctx context.Context) error {
+	return gc.c.CallContext(ctx, nil, "miner_stop")
+}
+
+// SetPaaerbase sets the account the rewards of sealed blocks are paid to.
+func (gc *Client) SetPaaerbase(ctx context.Context, paaerbase common.Address) error {
+	return gc.c.CallContext(ctx, nil, "miner_setPaaerbase", paaerbase)
+}
+
+// SetExtra sets the extra data included in sealed blocks.
+func (gc *Client) SetExtra(ctx context.Context, extra string) error {
+	return gc.c.CallContext(ctx, nil, "miner_setExtra", extra)
+}
+
+// SetGasPrice sets the minimum gas price of transactions included in sealed
+// blocks.
+func (gc *Client) SetGasPrice(ctx context.Context, gasPrice *big.Int) error {
+	return gc.c.CallContext(ctx, nil, "miner_setGasPrice", (*hexutil.Big)(gasPrice))
+}
+
+// SetRecommitInterval sets the interval at which the block being sealed is
+// recreated to include new transactions.
+func (gc *Client) SetRecommitInterval(ctx context.Context, interval time.Duration) error {
+	return gc.c.CallContext(ctx, nil, "miner_setRecommitInterval", int(interval/time.Millisecond))
+}
+
+// Hashrate retrieves the hash rate of the node's local miner.
+func (gc *Client) Hashrate(ctx context.Context) (uint64, error) {
+	var rate uint64
+	err := gc.c.CallContext(ctx, &rate, "miner_getHashrate")
+	return rate, err
+}
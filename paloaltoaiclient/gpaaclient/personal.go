@@ -0,0 +1,88 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package gpaaclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/crypto"
+)
+
+// ListAccounts retrieves the addresses of all accounts managed by the node.
+func (gc *Client) ListAccounts(ctx context.Context) ([]common.Address, error) {
+	var accounts []common.Address
+	err := gc.c.CallContext(ctx, &accounts, "personal_listAccounts")
+	return accounts, err
+}
+
+// NewAccount creates a new account in the node's key store, encrypted with the
+// given passphrase.
+func (gc *Client) NewAccount(ctx context.Context, passphrase string) (common.Address, error) {
+	var account common.Address
+	err := gc.c.CallContext(ctx, &account, "personal_newAccount", passphrase)
+	return account, err
+}
+
+// ImportRawKey stores a private key in the node's key store, encrypted with the
+// given passphrase.
+func (gc *Client) ImportRawKey(ctx context.Context, key *ecdsa.PrivateKey, passphrase string) (common.Address, error) {
+	var account common.Address
+	err := gc.c.CallContext(ctx, &account, "personal_importRawKey", hex.EncodeToString(crypto.FromECDSA(key)), passphrase)
+	return account, err
+}
+
+// UnlockAccount decrypts the key of an account for the given duration, allowing
+// the node to sign with it without a passphrase. Zero duration unlocks the
+// account until the node exits.
+func (gc *Client) UnlockAccount(ctx context.Context, account common.Address, passphrase string, duration time.Duration) error {
+	return gc.c.CallContext(ctx, nil, "personal_unlockAccount", account, passphrase, uint64(duration/time.Second))
+}
+
+// LockAccount removes the decrypted key of an account from memory.
+func (gc *Client) LockAccount(ctx context.Context, account common.Address) error {
+	return gc.c.CallContext(ctx, nil, "personal_lockAccount", account)
+}
+
+// SendTransaction creates a transaction from the given call message, signs it
+// with the key of the sender, decrypted with the passphrase, and submits it to
+// the node's transaction pool.
+func (gc *Client) SendTransaction(ctx context.Context, msg PaloAltoAi.CallMsg, passphrase string) (common.Hash, error) {
+	var hash common.Hash
+	err := gc.c.CallContext(ctx, &hash, "personal_sendTransaction", toCallArg(msg), passphrase)
+	return hash, err
+}
+
+// Sign calculates a PaloAltoAi specific signature of the data with the key of
+// the account, decrypted with the passphrase.
+func (gc *Client) Sign(ctx context.Context, data []byte, account common.Address, passphrase string) ([]byte, error) {
+	var signature hexutil.Bytes
+	err := gc.c.CallContext(ctx, &signature, "personal_sign", hexutil.Bytes(data), account, passphrase)
+	return signature, err
+}
+
+// EcRecover returns the account that created a signature with Sign.
+func (gc *Client) EcRecover(ctx context.Context, data, signature []byte) (common.Address, error) {
+	var account common.Address
+	err := gc.c.CallContext(ctx, &account, "personal_ecRecover", hexutil.Bytes(data), hexutil.Bytes(signature))
+	return account, err
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package gpaaclient
+
+import (
+	"context"
+	"sort"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common"
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/hexutil"
+	"github.com/PaloAltoAi/go-PaloAltoAi/core/types"
+)
+
+// TxPoolContent retrieves the transactions of the node's transaction pool,
+// grouped by sender and sorted by nonce. Pending transactions are executable,
+// queued ones are waiting for a nonce gap to be filled.
+func (gc *Client) TxPoolContent(ctx context.Context) (pending, queued map[common.Address]types.Transactions, err error) {
+	var content map[string]map[string]map[string]*types.Transaction
+	if err := gc.c.CallContext(ctx, &content, "txpool_content"); err != nil {
+		return nil, nil, err
+	}
+	if pending, err = flattenPool(content["pending"]); err != nil {
+		return nil, nil, err
+	}
+	if queued, err = flattenPool(content["queued"]); err != nil {
+		return nil, nil, err
+	}
+	return pending, queued, nil
+}
+
+// flattenPool converts the per account transactions of the pool, keyed by
+// nonce, into nonce sorted lists.
+func flattenPool(accounts map[string]map[string]*types.Transaction) (map[common.Address]types.Transactions, error) {
+	flat := make(map[common.Address]types.Transactions, len(accounts))
+	for key, txs := range accounts {
+		addr, err := parseAddress(key)
+		if err != nil {
+			return nil, err
+		}
+		list := make(types.Transactions, 0, len(txs))
+		for _, tx := range txs {
+			list = append(list, tx)
+		}
+		sort.Sort(types.TxByNonce(list))
+		flat[addr] = list
+	}
+	return flat, nil
+}
+
+// TxPoolStatus retrieves the number of pending and queued transactions in the
+// node's transaction pool.
+func (gc *Client) TxPoolStatus(ctx context.Context) (pending, queued uint, err error) {
+	var status map[string]hexutil.Uint
+	if err := gc.c.CallContext(ctx, &status, "txpool_status"); err != nil {
+		return 0, 0, err
+	}
+	return uint(status["pending"]), uint(status["queued"]), nil
+}
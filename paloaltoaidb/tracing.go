@@ -0,0 +1,57 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package paadb
+
+import (
+	"context"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
+)
+
+// tracedDatabase is a database tracing its reads as part of a request.
+type tracedDatabase struct {
+	Database
+	ctx context.Context
+}
+
+// WithContext wraps a database so that its reads are traced as children of the
+// span carried by the context. If tracing is disabled, the database is returned
+// unwrapped.
+func WithContext(ctx context.Context, db Database) Database {
+	if !tracing.Enabled {
+		return db
+	}
+	return &tracedDatabase{Database: db, ctx: ctx}
+}
+
+// Get retrieves the given key, tracing the database access.
+func (db *tracedDatabase) Get(key []byte) ([]byte, error) {
+	_, span := tracing.StartSpan(db.ctx, "paadb/get")
+	defer span.Finish()
+
+	value, err := db.Database.Get(key)
+	span.SetTag("size", len(value))
+	return value, err
+}
+
+// Has checks whether the given key exists, tracing the database access.
+func (db *tracedDatabase) Has(key []byte) (bool, error) {
+	_, span := tracing.StartSpan(db.ctx, "paadb/has")
+	defer span.Finish()
+
+	return db.Database.Has(key)
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// AllowAll is the permission entry granting access to every method.
+const AllowAll = "*"
+
+// Claims are the claims of an access token accepted by the authenticated RPC
+// endpoints. Besides the standard JWT claims (exp, iat, nbf, ...), which are
+// validated if present, a token may restrict the methods it grants access to.
+type Claims struct {
+	// Allow is the list of namespaces (e.g. "paa") and fully qualified methods
+	// (e.g. "admin_peers") the token grants access to. A missing list or the
+	// AllowAll entry grants access to everything exposed on the endpoint. HTTP
+	// handlers served next to the API are namespaces named after their path
+	// (e.g. "graphql").
+	Allow []string `json:"allow,omitempty"`
+
+	jwt.StandardClaims
+}
+
+// permissions is the parsed set of methods an authenticated client may call.
+type permissions struct {
+	all        bool
+	namespaces map[string]bool
+	methods    map[string]bool
+}
+
+// permissionsKey is the context key the permissions of a client are stored at.
+type permissionsKey struct{}
+
+func newPermissions(allow []string) *permissions {
+	perms := &permissions{
+		all:        allow == nil,
+		namespaces: make(map[string]bool),
+		methods:    make(map[string]bool),
+	}
+	for _, entry := range allow {
+		switch {
+		case entry == AllowAll:
+			perms.all = true
+		case strings.Contains(entry, serviceMethodSeparator):
+			perms.methods[entry] = true
+		default:
+			perms.namespaces[entry] = true
+		}
+	}
+	return perms
+}
+
+// allowed reports whether the given method may be called. The metadata API is
+// always accessible.
+func (p *permissions) allowed(service, method string) bool {
+	if p.all || service == MetadataApi {
+		return true
+	}
+	return p.namespaces[service] || p.methods[service+serviceMethodSeparator+method]
+}
+
+// permitted reports whether the client a request context belongs to may call
+// the given method. Unauthenticated contexts are unrestricted, access to their
+// transport being guarded by other means.
+func permitted(ctx context.Context, service, method string) bool {
+	perms, ok := ctx.Value(permissionsKey{}).(*permissions)
+	if !ok {
+		return true
+	}
+	return perms.allowed(service, method)
+}
+
+// jwtHandler is a handler authenticating requests by HS256 signed bearer tokens.
+type jwtHandler struct {
+	secret []byte
+	next   http.Handler
+}
+
+// NewJWTHandler returns a handler which only passes on requests carrying a valid
+// HS256 bearer token signed with the given shared secret. The methods permitted
+// by the token are attached to the request context, to be enforced by the RPC
+// server before dispatching calls. Requests are rate limited per token rather
+// than per remote address. For websocket connections the token is only checked
+// during the handshake.
+func NewJWTHandler(secret []byte, next http.Handler) http.Handler {
+	return &jwtHandler{secret: secret, next: next}
+}
+
+// ServeHTTP implements http.Handler, validating the access token of the request.
+func (h *jwtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		http.Error(w, "missing access token", http.StatusUnauthorized)
+		return
+	}
+	claims := new(Claims)
+	token, err := jwt.ParseWithClaims(strings.TrimPrefix(auth, "Bearer "), claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return h.secret, nil
+	})
+	if err != nil || !token.Valid {
+		log.Debug("Rejected RPC access token", "remote", r.RemoteAddr, "err", err)
+		http.Error(w, "invalid access token", http.StatusUnauthorized)
+		return
+	}
+	ctx := context.WithValue(r.Context(), permissionsKey{}, newPermissions(claims.Allow))
+	ctx = context.WithValue(ctx, clientKey{}, tokenIdentity(claims, token.Raw))
+	h.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// tokenIdentity returns the identity requests authenticated by a token are
+// accounted to: the subject of the token if set, its hash otherwise.
+func tokenIdentity(claims *Claims, raw string) string {
+	if claims.Subject != "" {
+		return "jwt:" + claims.Subject
+	}
+	hash := sha256.Sum256([]byte(raw))
+	return "jwt:" + hex.EncodeToString(hash[:8])
+}
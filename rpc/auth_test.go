@@ -0,0 +1,180 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testJWTSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// bearerTransport is an HTTP transport injecting a bearer token into requests.
+type bearerTransport struct {
+	token string
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestToken creates a token with the given claims, signed by the test secret.
+func newTestToken(t *testing.T, method jwt.SigningMethod, claims *Claims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(testJWTSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+// Tests that the HTTP endpoint rejects missing and invalid tokens.
+func TestJWTHandlerRejectsInvalidTokens(t *testing.T) {
+	server := newTestServer("test", new(Service))
+	defer server.Stop()
+
+	httpsrv := httptest.NewServer(NewJWTHandler(testJWTSecret, server))
+	defer httpsrv.Close()
+
+	expired := &Claims{StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(-time.Minute).Unix()}}
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{}).SignedString([]byte("wrong secret"))
+
+	tests := map[string]string{
+		"missing": "",
+		"garbage": "garbage",
+		"expired": newTestToken(t, jwt.SigningMethodHS256, expired),
+		"alg":     newTestToken(t, jwt.SigningMethodHS512, &Claims{}),
+		"forged":  forged,
+	}
+	for name, token := range tests {
+		req, _ := http.NewRequest(http.MethodPost, httpsrv.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1,{}]}`))
+		req.Header.Set("Content-Type", contentType)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", name, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: status mismatch: have %d, want %d", name, res.StatusCode, http.StatusUnauthorized)
+		}
+	}
+}
+
+// Tests that the permissions carried by a token are enforced over HTTP.
+func TestJWTPermissionsHTTP(t *testing.T) {
+	server := newTestServer("test", new(Service))
+	defer server.Stop()
+
+	httpsrv := httptest.NewServer(NewJWTHandler(testJWTSecret, server))
+	defer httpsrv.Close()
+
+	token := newTestToken(t, jwt.SigningMethodHS256, &Claims{Allow: []string{"test_echo"}})
+	client, err := DialHTTPWithClient(httpsrv.URL, &http.Client{Transport: &bearerTransport{token}})
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	checkPermissions(t, client)
+}
+
+// Tests that the permissions carried by a token are enforced over websockets,
+// and that the handshake requires a valid token.
+func TestJWTPermissionsWebsocket(t *testing.T) {
+	server := newTestServer("test", new(Service))
+	defer server.Stop()
+
+	httpsrv := httptest.NewServer(NewJWTHandler(testJWTSecret, server.WebsocketHandler([]string{"*"})))
+	defer httpsrv.Close()
+	endpoint := "ws" + strings.TrimPrefix(httpsrv.URL, "http")
+
+	if _, err := DialWebsocket(context.Background(), endpoint, ""); err == nil {
+		t.Fatalf("unauthenticated handshake succeeded")
+	}
+	config, err := wsGetConfig(endpoint, "")
+	if err != nil {
+		t.Fatalf("failed to create websocket config: %v", err)
+	}
+	config.Header.Set("Authorization", "Bearer "+newTestToken(t, jwt.SigningMethodHS256, &Claims{Allow: []string{"test_echo"}}))
+
+	client, err := newClient(context.Background(), func(ctx context.Context) (net.Conn, error) {
+		return wsDialContext(ctx, config)
+	})
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	checkPermissions(t, client)
+}
+
+// checkPermissions ensures a client authenticated with access to test_echo only
+// is allowed to call that and the metadata API, but nothing else.
+func checkPermissions(t *testing.T, client *Client) {
+	var res Result
+	if err := client.Call(&res, "test_echo", "hello", 10, &Args{"world"}); err != nil {
+		t.Fatalf("permitted call failed: %v", err)
+	}
+	if res.String != "hello" || res.Int != 10 {
+		t.Errorf("result mismatch: %+v", res)
+	}
+	var modules map[string]string
+	if err := client.Call(&modules, "rpc_modules"); err != nil {
+		t.Errorf("metadata call failed: %v", err)
+	}
+	err := client.Call(nil, "test_noArgsRets")
+	if err == nil {
+		t.Fatalf("forbidden call succeeded")
+	}
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != (&unauthorizedError{}).ErrorCode() {
+		t.Errorf("error mismatch: have %v, want unauthorized error", err)
+	}
+}
+
+// Tests the parsing of the permission lists carried by tokens.
+func TestPermissions(t *testing.T) {
+	tests := []struct {
+		allow   []string
+		service string
+		method  string
+		want    bool
+	}{
+		{nil, "admin", "peers", true},
+		{[]string{}, "admin", "peers", false},
+		{[]string{}, MetadataApi, "modules", true},
+		{[]string{AllowAll}, "admin", "peers", true},
+		{[]string{"paa"}, "paa", "blockNumber", true},
+		{[]string{"paa"}, "admin", "peers", false},
+		{[]string{"admin_peers"}, "admin", "peers", true},
+		{[]string{"admin_peers"}, "admin", "addPeer", false},
+	}
+	for i, tt := range tests {
+		if have := newPermissions(tt.allow).allowed(tt.service, tt.method); have != tt.want {
+			t.Errorf("test %d: %v allowing %s_%s: have %v, want %v", i, tt.allow, tt.service, tt.method, have, tt.want)
+		}
+	}
+}
@@ -18,12 +18,60 @@ package rpc
 
 import (
 	"net"
+	"net/http"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
 )
 
-// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules
-func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string, timeouts HTTPTimeouts) (net.Listener, *Server, error) {
+// EndpointConfig holds the access restrictions and limits shared by the HTTP and
+// websocket endpoints. The zero value disables all of them.
+type EndpointConfig struct {
+	JWTSecret         []byte           // Secret of the bearer tokens authenticating requests, if set
+	RateLimit         *RateLimitConfig // Per-client request limits, if set
+	BatchLimit        int              // Maximum number of requests in a batch, unlimited if zero
+	ResponseSizeLimit int              // Maximum size of a response in bytes, unlimited if zero
+}
+
+// HTTPEndpointConfig configures an HTTP endpoint started by StartHTTPEndpoint.
+type HTTPEndpointConfig struct {
+	EndpointConfig
+
+	Modules  []string     // API namespaces exposed, all public ones if empty
+	Cors     []string     // Domains allowed to send cross-origin requests
+	Vhosts   []string     // Virtual hostnames requests are accepted for
+	Timeouts HTTPTimeouts // Timeouts of the HTTP server
+
+	// Handlers are served on their own paths next to the RPC API, enforcing their
+	// own CORS and vhosts restrictions but sharing authentication and limits.
+	Handlers map[string]http.Handler
+}
+
+// StartHTTPEndpoint starts the HTTP RPC endpoint, configured by config.
+func StartHTTPEndpoint(endpoint string, apis []API, config HTTPEndpointConfig) (net.Listener, *Server, error) {
+	rpcHandler, handler, err := NewHTTPEndpoint(apis, config.Modules, config.Cors, config.Vhosts, config.EndpointConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(config.Handlers) > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/", rpcHandler)
+		for path, h := range config.Handlers {
+			mux.Handle(path, NewServiceHandler(path, config.JWTSecret, config.RateLimit, h))
+		}
+		rpcHandler = mux
+	}
+	// All APIs registered, start the HTTP listener
+	listener, err := StartHTTPServer(endpoint, config.Timeouts, rpcHandler)
+	if err != nil {
+		return nil, nil, err
+	}
+	return listener, handler, err
+}
+
+// NewHTTPEndpoint creates an RPC server exposing the APIs permitted by the modules
+// over HTTP, without starting a listener. The returned handler enforces the cors
+// and vhosts restrictions, as well as the restrictions and limits of config.
+func NewHTTPEndpoint(apis []API, modules []string, cors []string, vhosts []string, config EndpointConfig) (http.Handler, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -39,21 +87,39 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 			log.Debug("HTTP registered", "namespace", api.Namespace)
 		}
 	}
+	if config.RateLimit != nil {
+		handler.SetRateLimits(*config.RateLimit)
+	}
+	handler.SetBatchLimits(config.BatchLimit, config.ResponseSizeLimit)
+
+	var rpcHandler http.Handler = handler
+	if len(config.JWTSecret) > 0 {
+		rpcHandler = NewJWTHandler(config.JWTSecret, handler)
+	}
+	return NewHTTPHandlerStack(rpcHandler, cors, vhosts), handler, nil
+}
+
+// StartWSEndpoint starts a websocket endpoint, enforcing the restrictions and
+// limits of config.
+func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool, config EndpointConfig) (net.Listener, *Server, error) {
+	wsHandler, handler, err := NewWSEndpoint(apis, modules, wsOrigins, exposeAll, config)
+	if err != nil {
+		return nil, nil, err
+	}
 	// All APIs registered, start the HTTP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
 		return nil, nil, err
 	}
-	go NewHTTPServer(cors, vhosts, timeouts, handler).Serve(listener)
+	go (&http.Server{Handler: wsHandler}).Serve(listener)
 	return listener, handler, err
 }
 
-// StartWSEndpoint starts a websocket endpoint
-func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool) (net.Listener, *Server, error) {
-
+// NewWSEndpoint creates an RPC server exposing the APIs permitted by the modules
+// over websockets, without starting a listener. The returned handler checks the
+// origin of connections, and enforces the restrictions and limits of config. A
+// JWT secret is checked during the handshake.
+func NewWSEndpoint(apis []API, modules []string, wsOrigins []string, exposeAll bool, config EndpointConfig) (http.Handler, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -69,17 +135,16 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 			log.Debug("WebSocket registered", "service", api.Service, "namespace", api.Namespace)
 		}
 	}
-	// All APIs registered, start the HTTP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
-		return nil, nil, err
+	if config.RateLimit != nil {
+		handler.SetRateLimits(*config.RateLimit)
 	}
-	go NewWSServer(wsOrigins, handler).Serve(listener)
-	return listener, handler, err
+	handler.SetBatchLimits(config.BatchLimit, config.ResponseSizeLimit)
 
+	wsHandler := handler.WebsocketHandler(wsOrigins)
+	if len(config.JWTSecret) > 0 {
+		wsHandler = NewJWTHandler(config.JWTSecret, wsHandler)
+	}
+	return wsHandler, handler, nil
 }
 
 // StartIPCEndpoint starts an IPC endpoint.
@@ -16,7 +16,10 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // request is for an unknown service
 type methodNotFoundError struct {
@@ -58,6 +61,48 @@ func (e *callbackError) ErrorCode() int { return -32000 }
 
 func (e *callbackError) Error() string { return e.message }
 
+// the access token of the client doesn't grant access to the requested method
+type unauthorizedError struct {
+	service string
+	method  string
+}
+
+func (e *unauthorizedError) ErrorCode() int { return -32001 }
+
+func (e *unauthorizedError) Error() string {
+	return fmt.Sprintf("The method %s%s%s is not permitted by the access token", e.service, serviceMethodSeparator, e.method)
+}
+
+// the client exceeded its request allowance for the method
+type limitExceededError struct {
+	method string
+	retry  time.Duration
+}
+
+func (e *limitExceededError) ErrorCode() int { return -32005 }
+
+func (e *limitExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s, retry in %v", e.method, e.retry)
+}
+
+// the batch contains more requests than the server allows
+type batchLimitError struct{ limit int }
+
+func (e *batchLimitError) ErrorCode() int { return -32006 }
+
+func (e *batchLimitError) Error() string {
+	return fmt.Sprintf("batch too large, at most %d requests allowed", e.limit)
+}
+
+// the response exceeds the size the server allows
+type responseLimitError struct{ limit int }
+
+func (e *responseLimitError) ErrorCode() int { return -32007 }
+
+func (e *responseLimitError) Error() string {
+	return fmt.Sprintf("response too large, at most %d bytes allowed", e.limit)
+}
+
 // issued when a request is received after the server is issued to stop.
 type shutdownError struct{}
 
@@ -32,6 +32,7 @@ import (
 	"time"
 
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
 	"github.com/rs/cors"
 )
 
@@ -198,11 +199,24 @@ func (t *httpReadWriteNopCloser) Close() error {
 // NewHTTPServer creates a new HTTP RPC server around an API provider.
 //
 // Deprecated: Server implements http.Handler
-func NewHTTPServer(cors []string, vhosts []string, timeouts HTTPTimeouts, srv *Server) *http.Server {
-	// Wrap the CORS-handler within a host-handler
-	handler := newCorsHandler(srv, cors)
-	handler = newVHostHandler(vhosts, handler)
+func NewHTTPServer(cors []string, vhosts []string, timeouts HTTPTimeouts, srv http.Handler) *http.Server {
+	return newHTTPServer(timeouts, NewHTTPHandlerStack(srv, cors, vhosts))
+}
+
+// StartHTTPServer starts serving the given handler on the endpoint, enforcing
+// the given timeouts. It allows composing the RPC handlers with other ones on
+// a single listener.
+func StartHTTPServer(endpoint string, timeouts HTTPTimeouts, handler http.Handler) (net.Listener, error) {
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	go newHTTPServer(timeouts, handler).Serve(listener)
+	return listener, nil
+}
 
+// newHTTPServer creates an HTTP server around a handler, sanitizing the timeouts.
+func newHTTPServer(timeouts HTTPTimeouts, handler http.Handler) *http.Server {
 	// Make sure timeout values are meaningful
 	if timeouts.ReadTimeout < time.Second {
 		log.Warn("Sanitizing invalid HTTP read timeout", "provided", timeouts.ReadTimeout, "updated", DefaultHTTPTimeouts.ReadTimeout)
@@ -240,6 +254,8 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// single request.
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, "remote", r.RemoteAddr)
+	ctx = withClient(ctx, r.RemoteAddr)
+	ctx = tracing.Extract(ctx, r.Header)
 	ctx = context.WithValue(ctx, "scheme", r.Proto)
 	ctx = context.WithValue(ctx, "local", r.Host)
 	if ua := r.Header.Get("User-Agent"); ua != "" {
@@ -284,7 +300,70 @@ func validateRequest(r *http.Request) (int, error) {
 	return http.StatusUnsupportedMediaType, err
 }
 
-func newCorsHandler(srv *Server, allowedOrigins []string) http.Handler {
+// NewHTTPHandlerStack returns wrapped http-related handlers enforcing the given
+// CORS and virtual host restrictions.
+func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string) http.Handler {
+	// Wrap the CORS-handler within a host-handler
+	handler := newCorsHandler(srv, cors)
+	return newVHostHandler(vhosts, handler)
+}
+
+// serviceHandler applies the access restrictions of the RPC API to a plain HTTP
+// handler served next to it.
+type serviceHandler struct {
+	namespace string
+	limiter   *rateLimiter
+	next      http.Handler
+}
+
+// NewServiceHandler wraps a plain HTTP handler served next to the RPC API (e.g.
+// GraphQL at /graphql) into the same access restrictions as the API. The handler
+// is treated as a namespace named after its path: if a JWT secret is given,
+// requests need an access token permitting the namespace; if limits are given,
+// clients are throttled as if calling a method of that name. Request bodies are
+// capped to the size accepted by the RPC server. CORS preflight requests are
+// passed on unchecked, to be answered by the wrapped handler.
+func NewServiceHandler(path string, jwtSecret []byte, limits *RateLimitConfig, next http.Handler) http.Handler {
+	h := &serviceHandler{namespace: strings.Trim(path, "/"), next: next}
+	if limits != nil {
+		h.limiter = newRateLimiter(*limits)
+	}
+	var checked http.Handler = h
+	if len(jwtSecret) > 0 {
+		checked = NewJWTHandler(jwtSecret, h)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		checked.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP implements http.Handler, enforcing the permissions and limits of the
+// client before passing the request on.
+func (h *serviceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := withClient(r.Context(), r.RemoteAddr)
+	if !permitted(ctx, h.namespace, "") {
+		http.Error(w, fmt.Sprintf("access to %s not permitted", h.namespace), http.StatusForbidden)
+		return
+	}
+	done, err := h.limiter.throttle(ctx, h.namespace)
+	if err != nil {
+		if limit, ok := err.(*limitExceededError); ok {
+			w.Header().Set("Retry-After", fmt.Sprint(int(limit.retry.Seconds())+1))
+		}
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	defer done()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestContentLength)
+	h.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func newCorsHandler(srv http.Handler, allowedOrigins []string) http.Handler {
 	// disable CORS support if user has not specified a custom CORS configuration
 	if len(allowedOrigins) == 0 {
 		return srv
@@ -17,10 +17,14 @@
 package rpc
 
 import (
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/dgrijalva/jwt-go"
 )
 
 func TestHTTPErrorResponseWithDelete(t *testing.T) {
@@ -52,3 +56,43 @@ func testHTTPErrorResponse(t *testing.T, method, contentType, body string, expec
 		t.Fatalf("response code should be %d not %d", expected, code)
 	}
 }
+
+// Tests that plain HTTP handlers served next to the API share its authentication,
+// rate limits and request size cap.
+func TestServiceHandler(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.Copy(ioutil.Discard, r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	})
+	limits := &RateLimitConfig{Default: RateLimit{Rate: 0.001, Burst: 1}}
+	srv := httptest.NewServer(NewServiceHandler("/graphql", testJWTSecret, limits, echo))
+	defer srv.Close()
+
+	post := func(token, body string) int {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	if code := post("", "{}"); code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated request: status mismatch: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	denied := newTestToken(t, jwt.SigningMethodHS256, &Claims{Allow: []string{"paa"}})
+	if code := post(denied, "{}"); code != http.StatusForbidden {
+		t.Errorf("unpermitted request: status mismatch: have %d, want %d", code, http.StatusForbidden)
+	}
+	allowed := newTestToken(t, jwt.SigningMethodHS256, &Claims{Allow: []string{"graphql"}})
+	if code := post(allowed, strings.Repeat("x", maxRequestContentLength+1)); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized request: status mismatch: have %d, want %d", code, http.StatusRequestEntityTooLarge)
+	}
+	if code := post(allowed, "{}"); code != http.StatusTooManyRequests {
+		t.Errorf("throttled request: status mismatch: have %d, want %d", code, http.StatusTooManyRequests)
+	}
+}
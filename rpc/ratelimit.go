@@ -0,0 +1,222 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/metrics"
+)
+
+const (
+	// clientIdleTimeout is the time after which the accounting of a client that
+	// didn't send any requests is dropped.
+	clientIdleTimeout = 10 * time.Minute
+
+	// clientSweepInterval is the minimum time between two sweeps of idle clients.
+	clientSweepInterval = time.Minute
+)
+
+var (
+	rpcRequestMeter   = metrics.NewRegisteredMeter("rpc/requests", nil)
+	rpcThrottledMeter = metrics.NewRegisteredMeter("rpc/throttled", nil)
+	rpcClientsGauge   = metrics.NewRegisteredGauge("rpc/clients", nil)
+)
+
+// RateLimit is the token bucket configuration of a method. Each client gets a
+// bucket of Burst tokens refilled at Rate tokens per second, every request taking
+// one token. A zero rate disables limiting.
+type RateLimit struct {
+	Rate  float64 // Tokens refilled per second
+	Burst int     // Maximum number of tokens a bucket can hold
+}
+
+// RateLimitConfig configures the per-client request limits of an RPC server.
+// Clients are identified by their access token if authenticated, by their remote
+// IP address otherwise. Requests over transports not carrying a client identity
+// (IPC, in-process) are never limited. HTTP handlers served next to the RPC API
+// are limited as a method named after their path (e.g. "graphql").
+type RateLimitConfig struct {
+	// Default is the limit applied to all methods without a dedicated one.
+	Default RateLimit
+
+	// Methods overrides the default limit for individual methods (e.g.
+	// "paa_getLogs") or whole namespaces (e.g. "debug"). Subscriptions are
+	// limited as the subscribe method of their namespace.
+	Methods map[string]RateLimit `toml:",omitempty"`
+
+	// CostUnit makes long running requests more expensive: each full unit of
+	// execution time takes an additional token from the bucket of the method
+	// after the request completes. Zero disables cost accounting.
+	CostUnit time.Duration `toml:",omitempty"`
+}
+
+// limit returns the token bucket configuration of a method.
+func (c *RateLimitConfig) limit(method string) RateLimit {
+	if limit, ok := c.Methods[method]; ok {
+		return limit
+	}
+	if i := strings.Index(method, serviceMethodSeparator); i >= 0 {
+		if limit, ok := c.Methods[method[:i]]; ok {
+			return limit
+		}
+	}
+	return c.Default
+}
+
+// clientKey is the context key the identity of a remote client is stored at.
+type clientKey struct{}
+
+// withClient annotates a context with the identity of the remote client, unless
+// it has already been identified (e.g. by its access token).
+func withClient(ctx context.Context, remote string) context.Context {
+	if _, ok := ctx.Value(clientKey{}).(string); ok {
+		return ctx
+	}
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		host = remote
+	}
+	return context.WithValue(ctx, clientKey{}, host)
+}
+
+// tokenBucket is the request allowance of a client for a single method.
+type tokenBucket struct {
+	tokens  float64
+	updated mclock.AbsTime
+}
+
+// refill adds the tokens accumulated since the last update to the bucket.
+func (b *tokenBucket) refill(limit RateLimit, now mclock.AbsTime) {
+	elapsed := time.Duration(now - b.updated).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.updated = now
+}
+
+// clientStats is the request accounting of a single remote client.
+type clientStats struct {
+	buckets  map[string]*tokenBucket
+	requests uint64        // Total number of requests served
+	busy     time.Duration // Total execution time of the requests served
+	seen     mclock.AbsTime
+}
+
+// rateLimiter tracks the requests of remote clients and throttles them when
+// they exceed their allowance.
+type rateLimiter struct {
+	config  RateLimitConfig
+	clock   mclock.Clock
+	clients map[string]*clientStats
+	swept   mclock.AbsTime
+	lock    sync.Mutex
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		config:  config,
+		clock:   mclock.System{},
+		clients: make(map[string]*clientStats),
+	}
+}
+
+// SetRateLimits enables per-client request accounting and rate limiting on the
+// server. It must be called before the server starts serving requests.
+func (s *Server) SetRateLimits(config RateLimitConfig) {
+	s.limiter = newRateLimiter(config)
+}
+
+// throttle checks whether the calling client may execute the given method,
+// returning an error if it exceeded its allowance. On success a function is
+// returned which must be invoked once the request completed, to account its
+// execution time.
+func (l *rateLimiter) throttle(ctx context.Context, method string) (func(), Error) {
+	rpcRequestMeter.Mark(1)
+
+	start := time.Now()
+	timer := metrics.GetOrRegisterTimer("rpc/duration/"+method, nil)
+
+	id, ok := ctx.Value(clientKey{}).(string)
+	if l == nil || !ok {
+		return func() { timer.UpdateSince(start) }, nil
+	}
+	limit := l.config.limit(method)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.clock.Now()
+	l.sweep(now)
+
+	client := l.clients[id]
+	if client == nil {
+		client = &clientStats{buckets: make(map[string]*tokenBucket)}
+		l.clients[id] = client
+		rpcClientsGauge.Update(int64(len(l.clients)))
+	}
+	client.seen = now
+
+	var bucket *tokenBucket
+	if limit.Rate > 0 {
+		if bucket = client.buckets[method]; bucket == nil {
+			bucket = &tokenBucket{tokens: float64(limit.Burst), updated: now}
+			client.buckets[method] = bucket
+		}
+		bucket.refill(limit, now)
+		if bucket.tokens < 1 {
+			rpcThrottledMeter.Mark(1)
+			retry := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+			log.Debug("Throttled RPC client", "client", id, "method", method, "requests", client.requests, "busy", client.busy, "retry", retry)
+			return nil, &limitExceededError{method: method, retry: retry}
+		}
+		bucket.tokens--
+	}
+	client.requests++
+
+	return func() {
+		elapsed := time.Since(start)
+		timer.Update(elapsed)
+
+		l.lock.Lock()
+		defer l.lock.Unlock()
+
+		client.busy += elapsed
+		if bucket != nil && l.config.CostUnit > 0 {
+			bucket.tokens -= float64(elapsed / l.config.CostUnit)
+		}
+	}, nil
+}
+
+// sweep drops the accounting of clients idle for too long. The lock must be held.
+func (l *rateLimiter) sweep(now mclock.AbsTime) {
+	if time.Duration(now-l.swept) < clientSweepInterval {
+		return
+	}
+	l.swept = now
+	for id, client := range l.clients {
+		if time.Duration(now-client.seen) > clientIdleTimeout {
+			delete(l.clients, id)
+		}
+	}
+	rpcClientsGauge.Update(int64(len(l.clients)))
+}
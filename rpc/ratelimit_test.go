@@ -0,0 +1,150 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/common/mclock"
+)
+
+// newLimitedTestServer creates a test server limited by the given config, its
+// limiter driven by a simulated clock.
+func newLimitedTestServer(config RateLimitConfig) (*Server, *mclock.Simulated) {
+	server := newTestServer("test", new(Service))
+	server.SetRateLimits(config)
+
+	clock := new(mclock.Simulated)
+	server.limiter.clock = clock
+	return server, clock
+}
+
+// Tests that remote clients are throttled once their buckets run dry, and that
+// the buckets are refilled over time.
+func TestRateLimitThrottling(t *testing.T) {
+	server, clock := newLimitedTestServer(RateLimitConfig{
+		Default: RateLimit{Rate: 1, Burst: 2},
+		Methods: map[string]RateLimit{"test_noArgsRets": {}},
+	})
+	defer server.Stop()
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	echo := func() error {
+		var res Result
+		return client.Call(&res, "test_echo", "hello", 10, &Args{"world"})
+	}
+	for i := 0; i < 2; i++ {
+		if err := echo(); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+	}
+	err = echo()
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != (&limitExceededError{}).ErrorCode() {
+		t.Fatalf("error mismatch: have %v, want rate limit error", err)
+	}
+	// Unlimited methods must not be affected by the exhausted bucket
+	for i := 0; i < 5; i++ {
+		if err := client.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("unlimited call %d failed: %v", i, err)
+		}
+	}
+	// In-process clients carry no identity and must never be throttled
+	inproc := DialInProc(server)
+	defer inproc.Close()
+	for i := 0; i < 5; i++ {
+		var res Result
+		if err := inproc.Call(&res, "test_echo", "hello", 10, &Args{"world"}); err != nil {
+			t.Fatalf("in-process call %d failed: %v", i, err)
+		}
+	}
+	// Wait for a token to be refilled and ensure the client may call again
+	clock.Run(time.Second)
+	if err := echo(); err != nil {
+		t.Fatalf("call after refill failed: %v", err)
+	}
+	if err := echo(); err == nil {
+		t.Fatalf("call over refilled allowance succeeded")
+	}
+}
+
+// Tests that long running requests are charged extra tokens.
+func TestRateLimitCostAccounting(t *testing.T) {
+	server, _ := newLimitedTestServer(RateLimitConfig{
+		Default:  RateLimit{Rate: 1, Burst: 10},
+		CostUnit: time.Millisecond,
+	})
+	defer server.Stop()
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Call(nil, "test_sleep", 5*time.Millisecond); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	server.limiter.lock.Lock()
+	defer server.limiter.lock.Unlock()
+
+	if len(server.limiter.clients) != 1 {
+		t.Fatalf("client count mismatch: have %d, want 1", len(server.limiter.clients))
+	}
+	for id, client := range server.limiter.clients {
+		if client.requests != 1 || client.busy < 5*time.Millisecond {
+			t.Errorf("client %s stats mismatch: requests %d, busy %v", id, client.requests, client.busy)
+		}
+		if tokens := client.buckets["test_sleep"].tokens; tokens > 4 {
+			t.Errorf("client %s charged too little: %v tokens left, want at most 4", id, tokens)
+		}
+	}
+}
+
+// Tests that method limits override namespace limits, which override the default.
+func TestRateLimitLookup(t *testing.T) {
+	config := RateLimitConfig{
+		Default: RateLimit{Rate: 1, Burst: 1},
+		Methods: map[string]RateLimit{
+			"debug":        {Rate: 2, Burst: 2},
+			"debug_stacks": {Rate: 3, Burst: 3},
+		},
+	}
+	tests := map[string]float64{
+		"paa_blockNumber": 1,
+		"debug_gcStats":   2,
+		"debug_stacks":    3,
+		"debug_subscribe": 2,
+	}
+	for method, want := range tests {
+		if have := config.limit(method).Rate; have != want {
+			t.Errorf("%s: rate mismatch: have %v, want %v", method, have, want)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -27,6 +28,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	"github.com/PaloAltoAi/go-PaloAltoAi/tracing"
 )
 
 const MetadataApi = "rpc"
@@ -157,7 +159,7 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 
 	// test if the server is ordered to stop
 	for atomic.LoadInt32(&s.run) == 1 {
-		reqs, batch, err := s.readRequest(codec)
+		reqs, batch, err := s.readRequest(ctx, codec)
 		if err != nil {
 			// If a parsing error occurred, send an error
 			if err.Error() != "EOF" {
@@ -208,6 +210,15 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 	return nil
 }
 
+// SetBatchLimits restricts the number of requests a batch may contain and the
+// size of the responses sent back, a (batch) request exceeding either failing
+// with a limit exceeded error. Zero values disable the respective limit. It must
+// be called before the server starts serving requests.
+func (s *Server) SetBatchLimits(items, responseSize int) {
+	s.batchLimit = items
+	s.responseLimit = responseSize
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes the
 // response back using the given codec. It will block until the codec is closed or the server is
 // stopped. In either case the codec is closed.
@@ -274,6 +285,17 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, &invalidParamsError{"Expected subscription id as first argument"}), nil
 	}
 
+	// account the request against the allowance of the client
+	method := req.svcname + serviceMethodSeparator + formatName(req.callb.method.Name)
+	if req.callb.isSubscribe {
+		method = req.svcname + subscribeMethodSuffix
+	}
+	done, err := s.limiter.throttle(ctx, method)
+	if err != nil {
+		return codec.CreateErrorResponse(&req.id, err), nil
+	}
+	defer done()
+
 	if req.callb.isSubscribe {
 		subid, err := s.createSubscription(ctx, codec, req)
 		if err != nil {
@@ -289,7 +311,11 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateResponse(req.id, subid), activateSub
 	}
 
-	// regular RPC call, prepare arguments
+	// regular RPC call, trace it down to the backends through the context
+	ctx, span := tracing.StartSpan(ctx, "rpc/"+method)
+	defer span.Finish()
+
+	// prepare arguments
 	if len(req.args) != len(req.callb.argTypes) {
 		rpcErr := &invalidParamsError{fmt.Sprintf("%s%s%s expects %d parameters, got %d",
 			req.svcname, serviceMethodSeparator, req.callb.method.Name,
@@ -313,6 +339,8 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 	if req.callb.errPos >= 0 { // test if method returned an error
 		if !reply[req.callb.errPos].IsNil() {
 			e := reply[req.callb.errPos].Interface().(error)
+			span.SetTag("error", true)
+			span.LogKV("message", e.Error())
 			res := codec.CreateErrorResponse(&req.id, &callbackError{e.Error()})
 			return res, nil
 		}
@@ -329,6 +357,9 @@ func (s *Server) exec(ctx context.Context, codec ServerCodec, req *serverRequest
 	} else {
 		response, callback = s.handle(ctx, codec, req)
 	}
+	if s.responseLimit > 0 {
+		response, _ = s.limitResponse(codec, req, response, 0)
+	}
 
 	if err := codec.Write(response); err != nil {
 		log.Error(fmt.Sprintf("%v\n", err))
@@ -346,14 +377,24 @@ func (s *Server) exec(ctx context.Context, codec ServerCodec, req *serverRequest
 func (s *Server) execBatch(ctx context.Context, codec ServerCodec, requests []*serverRequest) {
 	responses := make([]interface{}, len(requests))
 	var callbacks []func()
+	size := 0
 	for i, req := range requests {
+		// requests rejected up front keep their own error, not counting to the size
 		if req.err != nil {
 			responses[i] = codec.CreateErrorResponse(&req.id, req.err)
-		} else {
-			var callback func()
-			if responses[i], callback = s.handle(ctx, codec, req); callback != nil {
-				callbacks = append(callbacks, callback)
-			}
+			continue
+		}
+		// don't execute the remaining requests once the response is too large
+		if s.responseLimit > 0 && size > s.responseLimit {
+			responses[i] = codec.CreateErrorResponse(&req.id, &responseLimitError{s.responseLimit})
+			continue
+		}
+		var callback func()
+		if responses[i], callback = s.handle(ctx, codec, req); callback != nil {
+			callbacks = append(callbacks, callback)
+		}
+		if s.responseLimit > 0 {
+			responses[i], size = s.limitResponse(codec, req, responses[i], size)
 		}
 	}
 
@@ -368,10 +409,25 @@ func (s *Server) execBatch(ctx context.Context, codec ServerCodec, requests []*s
 	}
 }
 
+// limitResponse encodes a response, replacing it with an error if it would grow
+// the size of the (batch) response over the limit. The encoded response and the
+// total size of the responses are returned.
+func (s *Server) limitResponse(codec ServerCodec, req *serverRequest, response interface{}, size int) (interface{}, int) {
+	blob, err := json.Marshal(response)
+	if err != nil {
+		return response, size // let the codec report the failure
+	}
+	if size += len(blob); size > s.responseLimit {
+		return codec.CreateErrorResponse(&req.id, &responseLimitError{s.responseLimit}), size
+	}
+	return json.RawMessage(blob), size
+}
+
 // readRequest requests the next (batch) request from the codec. It will return the collection
 // of requests, an indication if the request was a batch, the invalid request identifier and an
-// error when the request could not be read/parsed.
-func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error) {
+// error when the request could not be read/parsed. Requests for methods not permitted to the
+// client are marked as failed.
+func (s *Server) readRequest(ctx context.Context, codec ServerCodec) ([]*serverRequest, bool, Error) {
 	reqs, batch, err := codec.ReadRequestHeaders()
 	if err != nil {
 		return nil, batch, err
@@ -379,6 +435,14 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 
 	requests := make([]*serverRequest, len(reqs))
 
+	// reject oversized batches without executing any of the requests
+	if batch && s.batchLimit > 0 && len(reqs) > s.batchLimit {
+		for i, r := range reqs {
+			requests[i] = &serverRequest{id: r.id, err: &batchLimitError{s.batchLimit}}
+		}
+		return requests, batch, nil
+	}
+
 	// verify requests
 	for i, r := range reqs {
 		var ok bool
@@ -405,6 +469,16 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 			continue
 		}
 
+		if r.isPubSub { // subscriptions are permitted through the subscribe method of the namespace
+			if !permitted(ctx, r.service, "subscribe") {
+				requests[i] = &serverRequest{id: r.id, err: &unauthorizedError{r.service, "subscribe"}}
+				continue
+			}
+		} else if !permitted(ctx, r.service, r.method) {
+			requests[i] = &serverRequest{id: r.id, err: &unauthorizedError{r.service, r.method}}
+			continue
+		}
+
 		if r.isPubSub { // paa_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
 				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -160,3 +161,54 @@ func TestServerMethodExecution(t *testing.T) {
 func TestServerMethodWithCtx(t *testing.T) {
 	testServerMethodExecution(t, "echoWithCtx")
 }
+
+// Tests that oversized batches are rejected and that responses growing over the
+// size limit are replaced by errors.
+func TestServerBatchLimits(t *testing.T) {
+	server := newTestServer("test", new(Service))
+	server.SetBatchLimits(3, 200)
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	newBatch := func(n int) []BatchElem {
+		batch := make([]BatchElem, n)
+		for i := range batch {
+			batch[i] = BatchElem{Method: "test_echo", Args: []interface{}{"hello", i, &Args{"world"}}, Result: new(Result)}
+		}
+		return batch
+	}
+	isLimitError := func(err error, code int) bool {
+		rpcErr, ok := err.(Error)
+		return ok && rpcErr.ErrorCode() == code
+	}
+	// Batches over the item limit must fail as a whole
+	batch := newBatch(4)
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatalf("batch call failed: %v", err)
+	}
+	for i, elem := range batch {
+		if !isLimitError(elem.Error, -32006) {
+			t.Errorf("oversized batch item %d: error mismatch: have %v, want limit error", i, elem.Error)
+		}
+	}
+	// Batches within the item limit must be cut off at the response size limit
+	batch = newBatch(3)
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatalf("batch call failed: %v", err)
+	}
+	for i, elem := range batch[:2] {
+		if elem.Error != nil {
+			t.Errorf("batch item %d failed: %v", i, elem.Error)
+		}
+	}
+	if !isLimitError(batch[2].Error, -32007) {
+		t.Errorf("oversized response: error mismatch: have %v, want limit error", batch[2].Error)
+	}
+	// Single requests must also be subject to the response size limit
+	var res Result
+	if err := client.Call(&res, "test_echo", strings.Repeat("x", 200), 1, &Args{"world"}); !isLimitError(err, -32007) {
+		t.Errorf("oversized single response: error mismatch: have %v, want limit error", err)
+	}
+}
@@ -74,6 +74,11 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   mapset.Set
+
+	limiter *rateLimiter // Per-client request accounting, nil if not limited
+
+	batchLimit    int // Maximum number of requests in a batch, 0 if unlimited
+	responseLimit int // Maximum size of a (batch) response in bytes, 0 if unlimited
 }
 
 // rpcRequest represents a raw incoming RPC request
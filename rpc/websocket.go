@@ -63,17 +63,51 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			// Create a custom encode/decode pair to enforce payload size and number encoding
 			conn.MaxPayloadBytes = maxRequestContentLength
 
+			// Drop any deadlines inherited from the HTTP server the connection was
+			// upgraded on, websocket connections being long lived
+			conn.SetDeadline(time.Time{})
+
 			encoder := func(v interface{}) error {
 				return websocketJSONCodec.Send(conn, v)
 			}
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			// Carry over the permissions and identity of authenticated connections
+			ctx := context.Background()
+			if perms := conn.Request().Context().Value(permissionsKey{}); perms != nil {
+				ctx = context.WithValue(ctx, permissionsKey{}, perms)
+			}
+			if id := conn.Request().Context().Value(clientKey{}); id != nil {
+				ctx = context.WithValue(ctx, clientKey{}, id)
+			}
+			ctx = withClient(ctx, conn.Request().RemoteAddr)
+			codec := NewCodec(conn, encoder, decoder)
+			defer codec.Close()
+
+			srv.serveRequest(ctx, codec, false, OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
 
+// NewUpgradeHandler returns a handler serving websocket upgrade requests by ws
+// and all other requests by h, allowing HTTP and websocket RPC to share a port.
+func NewUpgradeHandler(h http.Handler, ws http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebsocket(r) {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// isWebsocket reports whether a request asks for a websocket upgrade.
+func isWebsocket(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get("Upgrade")) == "websocket" &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
 // NewWSServer creates a new websocket RPC server around an API provider.
 //
 // Deprecated: use Server.WebsocketHandler
@@ -0,0 +1,129 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package tracing implements distributed tracing of node internals, following
+// requests from the RPC layer down to state and database access.
+//
+// Spans are propagated through contexts. Tracing is disabled by default, in
+// which case starting a span is a no-op.
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/PaloAltoAi/go-PaloAltoAi/log"
+	opentracing "github.com/opentracing/opentracing-go"
+	jaeger "github.com/uber/jaeger-client-go"
+	jaegercfg "github.com/uber/jaeger-client-go/config"
+)
+
+// Enabled is checked by the hot code paths to skip span bookkeeping altogether
+// if no tracer is configured.
+var Enabled = false
+
+// Config contains the settings of the Jaeger exporter spans are reported to.
+type Config struct {
+	Endpoint    string  // Address of the Jaeger agent (host:port)
+	ServiceName string  // Name of the service spans are reported under
+	SampleRate  float64 // Fraction of traces to record (1 = all)
+}
+
+// DefaultConfig contains the default exporter settings.
+var DefaultConfig = Config{
+	Endpoint:    "127.0.0.1:6831",
+	ServiceName: "gpaloaltoai",
+	SampleRate:  1,
+}
+
+var (
+	closer io.Closer
+	lock   sync.Mutex
+)
+
+// Setup installs a Jaeger tracer as the global tracer, enabling tracing.
+func Setup(config Config) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	sampler := &jaegercfg.SamplerConfig{Type: jaeger.SamplerTypeConst, Param: 1}
+	if config.SampleRate < 1 {
+		sampler = &jaegercfg.SamplerConfig{Type: jaeger.SamplerTypeProbabilistic, Param: config.SampleRate}
+	}
+	cfg := jaegercfg.Configuration{
+		ServiceName: config.ServiceName,
+		Sampler:     sampler,
+		Reporter:    &jaegercfg.ReporterConfig{LocalAgentHostPort: config.Endpoint},
+	}
+	tracer, c, err := cfg.NewTracer()
+	if err != nil {
+		return err
+	}
+	log.Info("Enabling tracing", "endpoint", config.Endpoint, "service", config.ServiceName, "rate", config.SampleRate)
+	opentracing.SetGlobalTracer(tracer)
+	closer, Enabled = c, true
+	return nil
+}
+
+// Exit flushes all pending spans and shuts the tracer down.
+func Exit() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if closer != nil {
+		closer.Close()
+		closer = nil
+	}
+}
+
+// remoteKey is the context key the span context of a remote caller is stored at.
+type remoteKey struct{}
+
+// StartSpan starts a new span with the given operation name, as a child of the
+// span found in the context, or of the remote caller's span if the context only
+// carries that. The returned context carries the new span.
+func StartSpan(ctx context.Context, name string) (context.Context, opentracing.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !Enabled {
+		return ctx, opentracing.NoopTracer{}.StartSpan(name)
+	}
+	var opts []opentracing.StartSpanOption
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	} else if remote, ok := ctx.Value(remoteKey{}).(opentracing.SpanContext); ok {
+		opts = append(opts, opentracing.ChildOf(remote))
+	}
+	span := opentracing.GlobalTracer().StartSpan(name, opts...)
+	return opentracing.ContextWithSpan(ctx, span), span
+}
+
+// Extract annotates a context with the span context of a remote caller, if the
+// headers of its HTTP request carry one. Spans started from the context become
+// part of the caller's trace.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if !Enabled {
+		return ctx
+	}
+	remote, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteKey{}, remote)
+}
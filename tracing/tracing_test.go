@@ -0,0 +1,101 @@
+// Copyright 2026 The go-PaloAltoAi Authors
+// This file is part of the go-PaloAltoAi library.
+//
+// The go-PaloAltoAi library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-PaloAltoAi library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-PaloAltoAi library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	jaeger "github.com/uber/jaeger-client-go"
+)
+
+// withTestTracer installs a tracer recording spans in memory for the duration
+// of a test.
+func withTestTracer(t *testing.T, fn func(tracer opentracing.Tracer, reporter *jaeger.InMemoryReporter)) {
+	reporter := jaeger.NewInMemoryReporter()
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), reporter)
+	defer closer.Close()
+
+	opentracing.SetGlobalTracer(tracer)
+	Enabled = true
+	defer func() {
+		opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+		Enabled = false
+	}()
+	fn(tracer, reporter)
+}
+
+// Tests that no spans are recorded while tracing is disabled.
+func TestDisabled(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "test")
+	span.Finish()
+
+	if _, noop := span.Tracer().(opentracing.NoopTracer); !noop {
+		t.Errorf("span recorded while disabled: %T", span)
+	}
+	if opentracing.SpanFromContext(ctx) != nil {
+		t.Errorf("disabled span propagated through context")
+	}
+}
+
+// Tests that spans started from a context become children of the span carried
+// by it.
+func TestSpanPropagation(t *testing.T) {
+	withTestTracer(t, func(tracer opentracing.Tracer, reporter *jaeger.InMemoryReporter) {
+		ctx, parent := StartSpan(context.Background(), "parent")
+		_, child := StartSpan(ctx, "child")
+		child.Finish()
+		parent.Finish()
+
+		spans := reporter.GetSpans()
+		if len(spans) != 2 {
+			t.Fatalf("span count mismatch: have %d, want 2", len(spans))
+		}
+		childCtx := spans[0].Context().(jaeger.SpanContext)
+		parentCtx := spans[1].Context().(jaeger.SpanContext)
+		if childCtx.ParentID() != parentCtx.SpanID() || childCtx.TraceID() != parentCtx.TraceID() {
+			t.Errorf("child not linked to parent: child %v, parent %v", childCtx, parentCtx)
+		}
+	})
+}
+
+// Tests that the span context of a remote caller is picked up from its HTTP
+// headers, spans started from the context joining the caller's trace.
+func TestExtract(t *testing.T) {
+	withTestTracer(t, func(tracer opentracing.Tracer, reporter *jaeger.InMemoryReporter) {
+		remote := tracer.StartSpan("remote")
+		header := make(http.Header)
+		if err := tracer.Inject(remote.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header)); err != nil {
+			t.Fatalf("failed to inject span context: %v", err)
+		}
+		_, span := StartSpan(Extract(context.Background(), header), "local")
+		span.Finish()
+
+		have := span.Context().(jaeger.SpanContext)
+		want := remote.Context().(jaeger.SpanContext)
+		if have.TraceID() != want.TraceID() || have.ParentID() != want.SpanID() {
+			t.Errorf("span not linked to remote caller: have %v, want child of %v", have, want)
+		}
+		// Requests without trace headers must start a new trace
+		_, span = StartSpan(Extract(context.Background(), make(http.Header)), "root")
+		if span.Context().(jaeger.SpanContext).ParentID() != 0 {
+			t.Errorf("span without remote caller has a parent")
+		}
+	})
+}